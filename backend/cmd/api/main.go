@@ -30,6 +30,7 @@ import (
 	"backend/docs"
 	"backend/internal/handler"
 	"backend/internal/infra/database"
+	"backend/internal/infra/storage"
 	"backend/internal/repository/postgres"
 	"backend/internal/routes"
 	"backend/internal/service"
@@ -82,19 +83,34 @@ func main() {
 	// 	loggerInstance.Warn("Redis non disponible, cache désactivé", logger.Error(err))
 	// }
 
-	// Initialisation du stockage - TODO: implement storage
-	// fileStorage := storage.NewLocalStorage(cfg.Storage.LocalPath)
+	// Initialisation du stockage des fichiers (local ou S3)
+	fileStorage, err := storage.NewStorage(cfg.Storage)
+	if err != nil {
+		loggerInstance.Fatal("Erreur initialisation du stockage", logger.Error(err))
+	}
 
 	// Injection des dépendances - Repositories
 	userRepo := postgres.NewUserRepository(db)
 	taskRepo := postgres.NewTaskRepository(db)
 	transactionRepo := postgres.NewTransactionRepository(db)
+	recurringTransactionRepo := postgres.NewRecurringTransactionRepository(db)
+	importBatchRepo := postgres.NewImportBatchRepository(db)
+	attachmentRepo := postgres.NewAttachmentRepository(db)
+	tagRepo := postgres.NewTagRepository(db)
+	transactionVersionRepo := postgres.NewTransactionVersionRepository(db)
 	accountRepo := postgres.NewAccountRepository(db)
+	accountHistoryRepo := postgres.NewAccountBalanceHistoryRepository(db)
 	budgetRepo := postgres.NewBudgetRepository(db)
 	savingGoalRepo := postgres.NewSavingGoalRepository(db)
 	categoryRepo := postgres.NewCategoryRepository(db, loggerInstance)
 	preferencesRepo := postgres.NewPreferencesRepository(db)
-	// fileRepo := postgres.NewFileRepository(db) // TODO: implement file repository
+	trashRepo := postgres.NewTrashRepository(db)
+	reconciliationRepo := postgres.NewReconciliationRepository(db)
+	exchangeRateRepo := postgres.NewExchangeRateRepository(db)
+	loanRepo := postgres.NewLoanRepository(db)
+	tontineRepo := postgres.NewTontineRepository(db)
+	notificationRepo := postgres.NewNotificationRepository(db)
+	txManager := postgres.NewTxManager(db)
 	// externalService := service.NewExternalService(cfg.ExternalAPI.BaseURL) // TODO: implement external service
 
 	// Services
@@ -111,16 +127,26 @@ func main() {
 	authService := service.NewAuthService(userRepo, jwtService, initializationService, preferencesRepo, preferencesAIService, loggerInstance)
 	taskService := service.NewTaskService(taskRepo, loggerInstance)
 	aiService := ai.NewAIService(cfg.AI, loggerInstance)
-	accountService := service.NewAccountService(accountRepo, transactionRepo, savingGoalRepo, loggerInstance)
-	transactionService := service.NewTransactionService(transactionRepo, accountRepo, categoryRepo, aiService, accountService, loggerInstance)
-	budgetService := service.NewBudgetService(budgetRepo, loggerInstance)
+	exchangeRateService := service.NewExchangeRateService(exchangeRateRepo, userRepo, loggerInstance)
+	accountService := service.NewAccountService(accountRepo, transactionRepo, savingGoalRepo, accountHistoryRepo, txManager, loggerInstance)
+	transactionService := service.NewTransactionService(transactionRepo, accountRepo, categoryRepo, savingGoalRepo, tagRepo, transactionVersionRepo, txManager, aiService, accountService, exchangeRateService, loggerInstance)
+	recurringTransactionService := service.NewRecurringTransactionService(recurringTransactionRepo, accountRepo, categoryRepo, transactionService, txManager, loggerInstance)
+	attachmentService := service.NewAttachmentService(attachmentRepo, transactionRepo, fileStorage, int64(cfg.Storage.MaxFileSizeMB)<<20, loggerInstance)
+	tagService := service.NewTagService(tagRepo, transactionRepo, loggerInstance)
+	importService := service.NewImportService(importBatchRepo, transactionRepo, accountRepo, transactionService, txManager, loggerInstance)
+	budgetService := service.NewBudgetService(budgetRepo, exchangeRateService, loggerInstance)
 	savingGoalService := service.NewSavingGoalService(savingGoalRepo, loggerInstance)
 	categoryService := service.NewCategoryService(categoryRepo, aiService, loggerInstance)
 	preferencesService := service.NewPreferencesService(preferencesRepo, aiService, loggerInstance)
+	reconciliationService := service.NewReconciliationService(reconciliationRepo, accountRepo, transactionRepo, transactionService, txManager, loggerInstance)
+	notificationService := service.NewNotificationService(notificationRepo, loggerInstance)
+	loanService := service.NewLoanService(loanRepo, accountRepo, categoryRepo, transactionService, exchangeRateService, notificationService, txManager, loggerInstance)
+	tontineService := service.NewTontineService(tontineRepo, accountRepo, transactionService, exchangeRateService, notificationService, txManager, loggerInstance)
+	accountClosureService := service.NewAccountClosureService(accountRepo, transactionRepo, recurringTransactionRepo, transactionService, txManager, loggerInstance)
+	trashService := service.NewTrashService(trashRepo, transactionService, accountService, budgetService, savingGoalService, fileStorage, txManager, time.Duration(cfg.Trash.RetentionDays)*24*time.Hour, loggerInstance)
 
 	// Usecases
 	userUsecase := usecase.NewUserUsecase(userRepo, loggerInstance)
-	// fileUsecase := usecase.NewFileUsecase(fileRepo, fileStorage, loggerInstance) // TODO: implement file usecase
 
 	// Middlewares
 	authMiddleware := middleware.NewAuthMiddleware(jwtService, loggerInstance)
@@ -129,14 +155,22 @@ func main() {
 	// authHandler := handler.NewAuthHandler(authService, loggerInstance) // TODO: implement auth routes
 	taskHandler := handler.NewTaskHandler(taskService, loggerInstance)
 	transactionHandler := handler.NewTransactionHandler(transactionService, loggerInstance)
-	accountHandler := handler.NewAccountHandler(accountService, transactionService, loggerInstance)
+	recurringTransactionHandler := handler.NewRecurringTransactionHandler(recurringTransactionService, loggerInstance)
+	importHandler := handler.NewImportHandler(importService, loggerInstance)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentService, loggerInstance)
+	tagHandler := handler.NewTagHandler(tagService, loggerInstance)
+	accountHandler := handler.NewAccountHandler(accountService, transactionService, accountClosureService, loggerInstance)
 	budgetHandler := handler.NewBudgetHandler(budgetService, loggerInstance)
 	savingGoalHandler := handler.NewSavingGoalHandler(savingGoalService, loggerInstance)
 	categoryHandler := handler.NewCategoryHandler(categoryService, loggerInstance)
 	preferencesHandler := handler.NewPreferencesHandler(preferencesService, loggerInstance)
-	financeDashboardHandler := handler.NewFinanceDashboardHandler(accountService, transactionService, budgetService, savingGoalService, loggerInstance)
+	financeDashboardHandler := handler.NewFinanceDashboardHandler(accountService, transactionService, budgetService, savingGoalService, exchangeRateService, loanService, tontineService, loggerInstance)
+	trashHandler := handler.NewTrashHandler(trashService, loggerInstance)
+	reconciliationHandler := handler.NewReconciliationHandler(reconciliationService, loggerInstance)
+	exchangeRateHandler := handler.NewExchangeRateHandler(exchangeRateService, loggerInstance)
+	loanHandler := handler.NewLoanHandler(loanService, loggerInstance)
+	tontineHandler := handler.NewTontineHandler(tontineService, loggerInstance)
 	// userHandler := handler.NewUserHandler(userUsecase, loggerInstance)
-	// fileHandler := handler.NewFileHandler(fileUsecase, loggerInstance) // TODO: implement file handler
 	// healthHandler := handler.NewHealthHandler(db, redisClient) // TODO: implement health handler
 
 	// Configuration du routeur
@@ -151,7 +185,7 @@ func main() {
 
 	// Configuration des routes
 	// TODO: Implement routes setup
-	routes.SetupRoutes(r, userUsecase, authService, authMiddleware, taskHandler, transactionHandler, accountHandler, budgetHandler, savingGoalHandler, categoryHandler, preferencesHandler, financeDashboardHandler, loggerInstance)
+	routes.SetupRoutes(r, userUsecase, authService, authMiddleware, taskHandler, transactionHandler, recurringTransactionHandler, importHandler, attachmentHandler, tagHandler, accountHandler, budgetHandler, savingGoalHandler, categoryHandler, preferencesHandler, financeDashboardHandler, trashHandler, reconciliationHandler, exchangeRateHandler, loanHandler, tontineHandler, loggerInstance)
 
 	// Configuration du serveur
 	server := &http.Server{
@@ -173,12 +207,22 @@ func main() {
 		}
 	}()
 
+	// Démarrage des planificateurs (transactions récurrentes, échéance des transactions planifiées, purge de la corbeille)
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	go recurringTransactionService.StartScheduler(schedulerCtx, time.Duration(cfg.Scheduler.RecurringInterval)*time.Minute)
+	go transactionService.StartScheduledPosting(schedulerCtx, time.Duration(cfg.Scheduler.ScheduledInterval)*time.Minute)
+	go loanService.StartReminderScheduler(schedulerCtx, time.Duration(cfg.Scheduler.ReminderInterval)*time.Minute)
+	go tontineService.StartReminderScheduler(schedulerCtx, time.Duration(cfg.Scheduler.ReminderInterval)*time.Minute)
+	go trashService.StartPurgeScheduler(schedulerCtx, time.Duration(cfg.Trash.PurgeInterval)*time.Minute)
+
 	// Attendre le signal d'arrêt
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	loggerInstance.Info("Arrêt du serveur en cours...")
+	stopScheduler()
 
 	// Arrêt propre avec timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
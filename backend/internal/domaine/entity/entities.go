@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -35,36 +37,520 @@ type Mood struct {
 }
 
 type Account struct {
+	ID                uuid.UUID  `json:"id" db:"id"`
+	UserID            uuid.UUID  `json:"user_id" db:"user_id"`
+	Name              string     `json:"name" db:"name"`                                      // ex: "Bancaire", "Cash"
+	Type              string     `json:"type" db:"type"`                                      // checking, savings, mobile_money, debt, other
+	Balance           Money      `json:"balance" db:"balance" pg:",use_zero"`                 // solde courant (transactions passées et rapprochées)
+	PendingBalance    Money      `json:"pending_balance" db:"pending_balance" pg:",use_zero"` // effet des transactions en attente
+	AvailableBalance  Money      `json:"available_balance" pg:"-"`                            // solde courant et transactions en attente
+	Currency          string     `json:"currency" db:"currency"`
+	AccountNumber     *string    `json:"account_number,omitempty" db:"account_number"`
+	Icon              string     `json:"icon" db:"icon"`
+	Color             string     `json:"color" db:"color"`
+	ReconciledThrough *time.Time `json:"reconciled_through,omitempty" db:"reconciled_through"` // fin de la période rapprochée et verrouillée
+	ArchivedAt        *time.Time `json:"archived_at,omitempty" db:"archived_at"`               // clôture : masqué des listes, plus de nouvelles transactions
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt         *time.Time `json:"deleted_at,omitempty" pg:"deleted_at,soft_delete"` // mise à la corbeille
+}
+
+// AfterScan rattache la devise du compte à ses soldes et calcule le solde disponible
+func (a *Account) AfterScan(ctx context.Context) error {
+	a.Balance.Currency = a.Currency
+	a.PendingBalance.Currency = a.Currency
+	a.AvailableBalance = a.Balance.Add(a.PendingBalance)
+	return nil
+}
+
+// AccountBalanceSnapshot représente le solde courant d'un compte en fin de journée, enregistré pour chaque jour où
+// des transactions passées ou rapprochées l'ont modifié ; entre deux instantanés, le solde est celui du précédent
+type AccountBalanceSnapshot struct {
+	AccountID uuid.UUID `json:"account_id" db:"account_id" pg:",pk"`
+	Date      time.Time `json:"date" db:"date" pg:",pk"`
+	Change    Money     `json:"change" db:"change" pg:",use_zero"`   // variation du solde sur la journée
+	Balance   Money     `json:"balance" db:"balance" pg:",use_zero"` // solde en fin de journée
+	Currency  string    `json:"currency" db:"currency"`              // devise du compte
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// AfterScan rattache la devise du compte aux montants de l'instantané
+func (s *AccountBalanceSnapshot) AfterScan(ctx context.Context) error {
+	s.Change.Currency = s.Currency
+	s.Balance.Currency = s.Currency
+	return nil
+}
+
+// ExchangeRate représente un taux de change saisi ou importé par l'utilisateur : à partir de sa date, une unité
+// de FromCurrency vaut Rate unités de ToCurrency, jusqu'au taux suivant de la même paire. Le taux inverse s'en déduit.
+type ExchangeRate struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	UserID       uuid.UUID `json:"user_id" db:"user_id"`
+	FromCurrency string    `json:"from_currency" db:"from_currency" example:"EUR"`
+	ToCurrency   string    `json:"to_currency" db:"to_currency" example:"XAF"`
+	Rate         Decimal   `json:"rate" db:"rate" swaggertype:"number" example:"655.957"`
+	Date         time.Time `json:"date" db:"date" example:"2024-01-15T00:00:00Z"`
+	Source       string    `json:"source" db:"source" example:"manual"` // manual ou import
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Loan représente un emprunt (ou une dette) remboursé par mensualités constantes, suivi sur un compte de type debt
+// dont le solde négatif est le capital restant dû
+type Loan struct {
+	ID                   uuid.UUID          `json:"id" db:"id"`
+	UserID               uuid.UUID          `json:"user_id" db:"user_id"`
+	AccountID            uuid.UUID          `json:"account_id" db:"account_id"` // compte de type debt
+	Name                 string             `json:"name" db:"name"`
+	Lender               string             `json:"lender,omitempty" db:"lender"`
+	Principal            Money              `json:"principal" db:"principal" pg:",use_zero"`
+	InterestRate         Decimal            `json:"interest_rate" db:"interest_rate" swaggertype:"number" example:"9.5"` // taux annuel en pourcentage
+	TermMonths           int                `json:"term_months" db:"term_months"`
+	PaymentDay           int                `json:"payment_day" db:"payment_day"`        // jour du mois des échéances
+	StartDate            time.Time          `json:"start_date" db:"start_date"`          // date de déblocage des fonds
+	Payment              Money              `json:"payment" db:"payment" pg:",use_zero"` // mensualité
+	OutstandingPrincipal Money              `json:"outstanding_principal" db:"outstanding_principal" pg:",use_zero"`
+	InterestPaid         Money              `json:"interest_paid" db:"interest_paid" pg:",use_zero"`
+	Currency             string             `json:"currency" db:"currency"` // devise du compte
+	InterestCategoryID   *uuid.UUID         `json:"interest_category_id,omitempty" db:"interest_category_id"`
+	Status               string             `json:"status" db:"status"` // active, paid_off
+	CreatedAt            time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time          `json:"updated_at" db:"updated_at"`
+	Account              *Account           `json:"account,omitempty" pg:"rel:has-one,fk:account_id"`
+	Installments         []*LoanInstallment `json:"installments,omitempty" pg:"rel:has-many"` // échéancier
+}
+
+// AfterScan rattache la devise de l'emprunt à ses montants
+func (l *Loan) AfterScan(ctx context.Context) error {
+	l.Principal.Currency = l.Currency
+	l.Payment.Currency = l.Currency
+	l.OutstandingPrincipal.Currency = l.Currency
+	l.InterestPaid.Currency = l.Currency
+	return nil
+}
+
+// LoanInstallment représente une échéance de l'échéancier d'un emprunt : la mensualité se décompose en capital
+// et en intérêts, calculés sur le capital restant dû après l'échéance précédente
+type LoanInstallment struct {
+	ID                     uuid.UUID  `json:"id" db:"id"`
+	LoanID                 uuid.UUID  `json:"loan_id" db:"loan_id"`
+	UserID                 uuid.UUID  `json:"user_id" db:"user_id"`
+	Number                 int        `json:"number" db:"number"`
+	DueDate                time.Time  `json:"due_date" db:"due_date"`
+	Payment                Money      `json:"payment" db:"payment" pg:",use_zero"`
+	Principal              Money      `json:"principal" db:"principal" pg:",use_zero"`
+	Interest               Money      `json:"interest" db:"interest" pg:",use_zero"`
+	ExtraPrincipal         Money      `json:"extra_principal" db:"extra_principal" pg:",use_zero"`         // remboursement anticipé versé avec l'échéance
+	RemainingPrincipal     Money      `json:"remaining_principal" db:"remaining_principal" pg:",use_zero"` // capital restant dû après l'échéance
+	Currency               string     `json:"currency" db:"currency"`
+	Status                 string     `json:"status" db:"status"` // upcoming, missed, paid
+	PaidAt                 *time.Time `json:"paid_at,omitempty" db:"paid_at"`
+	PrincipalTransactionID *uuid.UUID `json:"principal_transaction_id,omitempty" db:"principal_transaction_id"` // transfert du capital vers le compte de l'emprunt
+	InterestTransactionID  *uuid.UUID `json:"interest_transaction_id,omitempty" db:"interest_transaction_id"`   // dépense d'intérêts
+	RemindedAt             *time.Time `json:"-" db:"reminded_at"`                                               // rappel de l'échéance à venir envoyé
+}
+
+// AfterScan rattache la devise de l'emprunt aux montants de l'échéance
+func (i *LoanInstallment) AfterScan(ctx context.Context) error {
+	i.Payment.Currency = i.Currency
+	i.Principal.Currency = i.Currency
+	i.Interest.Currency = i.Currency
+	i.ExtraPrincipal.Currency = i.Currency
+	i.RemainingPrincipal.Currency = i.Currency
+	return nil
+}
+
+// Tontine représente une tontine (njangi) : un groupe de membres qui cotisent le même montant à chaque tour, la
+// cagnotte du tour revenant à tour de rôle à chaque membre selon l'ordre de passage. Elle est suivie sur un compte de
+// type tontine dont le solde est la différence entre les cotisations versées et les cagnottes reçues par l'utilisateur.
+type Tontine struct {
+	ID                 uuid.UUID        `json:"id" db:"id"`
+	UserID             uuid.UUID        `json:"user_id" db:"user_id"`
+	AccountID          uuid.UUID        `json:"account_id" db:"account_id"` // compte de type tontine
+	Name               string           `json:"name" db:"name"`
+	ContributionAmount Money            `json:"contribution_amount" db:"contribution_amount" pg:",use_zero"` // cotisation de chaque membre par tour
+	Frequency          string           `json:"frequency" db:"frequency"`                                    // weekly, biweekly, monthly
+	StartDate          time.Time        `json:"start_date" db:"start_date"`                                  // date du premier tour
+	Currency           string           `json:"currency" db:"currency"`                                      // devise du compte
+	TotalContributed   Money            `json:"total_contributed" db:"total_contributed" pg:",use_zero"`     // cotisations versées par l'utilisateur
+	TotalReceived      Money            `json:"total_received" db:"total_received" pg:",use_zero"`           // cagnottes reçues par l'utilisateur
+	Status             string           `json:"status" db:"status"`                                          // active, completed
+	CreatedAt          time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time        `json:"updated_at" db:"updated_at"`
+	Account            *Account         `json:"account,omitempty" pg:"rel:has-one,fk:account_id"`
+	Members            []*TontineMember `json:"members,omitempty" pg:"rel:has-many"` // par ordre de passage
+	Cycles             []*TontineCycle  `json:"cycles,omitempty" pg:"rel:has-many"`  // tours
+}
+
+// AfterScan rattache la devise de la tontine à ses montants
+func (t *Tontine) AfterScan(ctx context.Context) error {
+	t.ContributionAmount.Currency = t.Currency
+	t.TotalContributed.Currency = t.Currency
+	t.TotalReceived.Currency = t.Currency
+	return nil
+}
+
+// TontineMember représente un membre d'une tontine ; IsSelf désigne l'utilisateur lui-même, dont les cotisations et
+// la cagnotte sont enregistrées sur ses comptes
+type TontineMember struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	TontineID   uuid.UUID `json:"tontine_id" db:"tontine_id"`
+	Name        string    `json:"name" db:"name"`
+	Phone       string    `json:"phone,omitempty" db:"phone"`
+	PayoutOrder int       `json:"payout_order" db:"payout_order"` // numéro du tour où le membre reçoit la cagnotte
+	IsSelf      bool      `json:"is_self" db:"is_self" pg:",use_zero"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// TontineCycle représente un tour d'une tontine : chaque membre y cotise et la cagnotte revient au bénéficiaire
+type TontineCycle struct {
+	ID                  uuid.UUID              `json:"id" db:"id"`
+	TontineID           uuid.UUID              `json:"tontine_id" db:"tontine_id"`
+	Number              int                    `json:"number" db:"number"`
+	DueDate             time.Time              `json:"due_date" db:"due_date"`
+	BeneficiaryID       uuid.UUID              `json:"beneficiary_id" db:"beneficiary_id"`
+	PayoutAmount        Money                  `json:"payout_amount" db:"payout_amount" pg:",use_zero"`
+	Currency            string                 `json:"currency" db:"currency"`
+	Status              string                 `json:"status" db:"status"` // open, paid_out
+	PaidOutAt           *time.Time             `json:"paid_out_at,omitempty" db:"paid_out_at"`
+	PayoutTransactionID *uuid.UUID             `json:"payout_transaction_id,omitempty" db:"payout_transaction_id"` // cagnotte reçue par l'utilisateur
+	Contributions       []*TontineContribution `json:"contributions,omitempty" pg:"rel:has-many,join_fk:cycle_id"`
+}
+
+// AfterScan rattache la devise de la tontine à la cagnotte du tour
+func (c *TontineCycle) AfterScan(ctx context.Context) error {
+	c.PayoutAmount.Currency = c.Currency
+	return nil
+}
+
+// TontineContribution représente la cotisation d'un membre pour un tour ; celle de l'utilisateur référence le
+// transfert de son compte vers le compte de la tontine
+type TontineContribution struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	TontineID     uuid.UUID  `json:"tontine_id" db:"tontine_id"`
+	CycleID       uuid.UUID  `json:"cycle_id" db:"cycle_id"`
+	MemberID      uuid.UUID  `json:"member_id" db:"member_id"`
+	UserID        uuid.UUID  `json:"user_id" db:"user_id"`
+	DueDate       time.Time  `json:"due_date" db:"due_date"`
+	Amount        Money      `json:"amount" db:"amount" pg:",use_zero"`
+	Currency      string     `json:"currency" db:"currency"`
+	IsSelf        bool       `json:"is_self" db:"is_self" pg:",use_zero"`
+	Status        string     `json:"status" db:"status"` // pending, paid
+	PaidAt        *time.Time `json:"paid_at,omitempty" db:"paid_at"`
+	TransactionID *uuid.UUID `json:"transaction_id,omitempty" db:"transaction_id"`
+	RemindedAt    *time.Time `json:"-" db:"reminded_at"` // rappel de la cotisation à venir envoyé
+}
+
+// AfterScan rattache la devise de la tontine au montant de la cotisation
+func (c *TontineContribution) AfterScan(ctx context.Context) error {
+	c.Amount.Currency = c.Currency
+	return nil
+}
+
+type Transaction struct {
+	ID                   uuid.UUID           `json:"id" db:"id"`
+	UserID               uuid.UUID           `json:"user_id" db:"user_id"`
+	AccountID            *uuid.UUID          `json:"account_id,omitempty" db:"account_id"`
+	CategoryID           *uuid.UUID          `json:"category_id,omitempty" db:"category_id"`
+	Type                 string              `json:"type" db:"type"` // income, expense, transfer, saving, refund
+	ToAccountID          *uuid.UUID          `json:"to_account_id,omitempty" db:"to_account_id"`
+	SavingGoalID         *uuid.UUID          `json:"saving_goal_id,omitempty" db:"saving_goal_id"`
+	TransferGroupID      *uuid.UUID          `json:"transfer_group_id,omitempty" db:"transfer_group_id"`  // relie les deux jambes d'un transfert
+	RecurringID          *uuid.UUID          `json:"recurring_id,omitempty" db:"recurring_id"`            // modèle récurrent ayant généré la transaction
+	ExternalRef          *string             `json:"external_ref,omitempty" db:"external_ref"`            // référence externe unique par compte (relevé, SMS...)
+	ImportBatchID        *uuid.UUID          `json:"import_batch_id,omitempty" db:"import_batch_id"`      // lot d'import ayant créé la transaction
+	RefundOfID           *uuid.UUID          `json:"refund_of_id,omitempty" db:"refund_of_id"`            // dépense d'origine d'un remboursement
+	RefundedAmount       Money               `json:"refunded_amount" db:"refunded_amount" pg:",use_zero"` // total remboursé d'une dépense
+	Amount               Money               `json:"amount" db:"amount" pg:",use_zero"`
+	Currency             string              `json:"currency" db:"currency"`                             // devise du compte
+	OriginalAmount       *Money              `json:"original_amount,omitempty" db:"original_amount"`     // montant payé dans une devise étrangère
+	OriginalCurrency     string              `json:"original_currency,omitempty" db:"original_currency"` // devise du montant d'origine
+	Status               string              `json:"status" db:"status"`                                 // scheduled, pending, cleared, reconciled
+	Description          string              `json:"description" db:"description"`
+	Date                 time.Time           `json:"date" db:"date"`
+	Recurring            bool                `json:"recurring" db:"recurring"`
+	CreatedAt            time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time           `json:"updated_at" db:"updated_at"`
+	DeletedAt            *time.Time          `json:"deleted_at,omitempty" pg:"deleted_at,soft_delete"` // mise à la corbeille
+	DeletedWithAccountID *uuid.UUID          `json:"-" db:"deleted_with_account_id"`                   // compte mis à la corbeille avec la transaction
+	Category             *Category           `json:"category,omitempty" pg:"rel:has-one,fk:category_id"`
+	Account              *Account            `json:"account,omitempty" pg:"rel:has-one,fk:account_id"`
+	SavingGoal           *SavingGoal         `json:"saving_goal,omitempty" pg:"rel:has-one,fk:saving_goal_id"`
+	Splits               []*TransactionSplit `json:"splits,omitempty" pg:"rel:has-many"` // ventilation sur plusieurs catégories
+	Tags                 []*Tag              `json:"tags,omitempty" pg:"many2many:transaction_tags"`
+}
+
+// MarshalJSON ajoute le montant net (montant moins total remboursé) à la représentation JSON
+func (t Transaction) MarshalJSON() ([]byte, error) {
+	type transaction Transaction
+	return json.Marshal(struct {
+		transaction
+		NetAmount Money `json:"net_amount"`
+	}{transaction(t), t.NetAmount()})
+}
+
+// AfterScan rattache la devise de la transaction à ses montants
+func (t *Transaction) AfterScan(ctx context.Context) error {
+	t.Amount.Currency = t.Currency
+	t.RefundedAmount.Currency = t.Currency
+	if t.OriginalAmount != nil {
+		t.OriginalAmount.Currency = t.OriginalCurrency
+	}
+	return nil
+}
+
+// SetAmount fixe le montant de la transaction et sa devise
+func (t *Transaction) SetAmount(amount Money) {
+	t.Amount = amount
+	t.Currency = amount.Currency
+	t.RefundedAmount.Currency = amount.Currency
+}
+
+// SetOriginalAmount fixe le montant d'origine en devise étrangère, ou le retire (nil)
+func (t *Transaction) SetOriginalAmount(amount *Money) {
+	t.OriginalAmount = amount
+	t.OriginalCurrency = ""
+	if amount != nil {
+		t.OriginalCurrency = amount.Currency
+	}
+}
+
+// NetAmount retourne le montant de la transaction déduction faite des remboursements reçus
+func (t *Transaction) NetAmount() Money {
+	return t.Amount.Sub(t.RefundedAmount)
+}
+
+// AmountForCategory retourne la part de la transaction imputée à une catégorie :
+// la somme des lignes de ventilation de cette catégorie, ou le montant total si la transaction n'est pas ventilée
+func (t *Transaction) AmountForCategory(categoryID uuid.UUID) Money {
+	amount := NewMoney(0, t.Currency)
+	if len(t.Splits) == 0 {
+		if t.CategoryID != nil && *t.CategoryID == categoryID {
+			return t.Amount
+		}
+		return amount
+	}
+
+	for _, split := range t.Splits {
+		if split.CategoryID == categoryID {
+			amount = amount.Add(split.Amount)
+		}
+	}
+	return amount
+}
+
+// TransactionSplit représente une ligne de ventilation d'une transaction sur une catégorie.
+// La somme des lignes d'une transaction est égale à son montant.
+type TransactionSplit struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	TransactionID uuid.UUID `json:"transaction_id" db:"transaction_id"`
+	CategoryID    uuid.UUID `json:"category_id" db:"category_id"`
+	Amount        Money     `json:"amount" db:"amount" pg:",use_zero"`
+	Currency      string    `json:"-" db:"currency"` // devise de la transaction
+	Note          string    `json:"note,omitempty" db:"note"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	Category      *Category `json:"category,omitempty" pg:"rel:has-one,fk:category_id"`
+}
+
+// AfterScan rattache la devise de la transaction au montant de la ligne
+func (s *TransactionSplit) AfterScan(ctx context.Context) error {
+	s.Amount.Currency = s.Currency
+	return nil
+}
+
+// Transfer représente un transfert entre deux comptes comme un mouvement unique
+type Transfer struct {
+	ID                uuid.UUID  `json:"id"` // identifiant du groupe de transfert
+	FromAccountID     uuid.UUID  `json:"from_account_id"`
+	ToAccountID       uuid.UUID  `json:"to_account_id"`
+	FromTransactionID uuid.UUID  `json:"from_transaction_id"`
+	ToTransactionID   uuid.UUID  `json:"to_transaction_id"`
+	CategoryID        *uuid.UUID `json:"category_id,omitempty"`
+	Amount            Money      `json:"amount"`
+	Description       string     `json:"description"`
+	Date              time.Time  `json:"date"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// RecurringTransaction représente un modèle de transaction récurrente (loyer, salaire, abonnement...)
+// à partir duquel le planificateur crée les occurrences échues
+type RecurringTransaction struct {
+	ID              uuid.UUID  `json:"id" db:"id"`
+	UserID          uuid.UUID  `json:"user_id" db:"user_id"`
+	AccountID       uuid.UUID  `json:"account_id" db:"account_id"`
+	CategoryID      *uuid.UUID `json:"category_id,omitempty" db:"category_id"`
+	Type            string     `json:"type" db:"type"` // income, expense
+	Amount          Money      `json:"amount" db:"amount" pg:",use_zero"`
+	Currency        string     `json:"currency" db:"currency"` // devise du compte
+	Description     string     `json:"description" db:"description"`
+	Frequency       string     `json:"frequency" db:"frequency"`                              // daily, weekly, monthly, yearly
+	Interval        int        `json:"interval" db:"interval" pg:",use_zero"`                 // toutes les N périodes
+	DayOfMonth      *int       `json:"day_of_month,omitempty" db:"day_of_month"`              // jour du mois pour une fréquence mensuelle
+	StartDate       time.Time  `json:"start_date" db:"start_date"`                            // première occurrence
+	EndDate         *time.Time `json:"end_date,omitempty" db:"end_date"`                      // dernière date possible
+	MaxOccurrences  *int       `json:"max_occurrences,omitempty" db:"max_occurrences"`        // nombre total d'occurrences
+	OccurrenceCount int        `json:"occurrence_count" db:"occurrence_count" pg:",use_zero"` // occurrences déjà créées
+	NextOccurrence  *time.Time `json:"next_occurrence,omitempty" db:"next_occurrence"`        // nil une fois le modèle terminé
+	Status          string     `json:"status" db:"status"`                                    // active, paused, completed
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+	Account         *Account   `json:"account,omitempty" pg:"rel:has-one,fk:account_id"`
+	Category        *Category  `json:"category,omitempty" pg:"rel:has-one,fk:category_id"`
+}
+
+// AfterScan rattache la devise du modèle à son montant
+func (r *RecurringTransaction) AfterScan(ctx context.Context) error {
+	r.Amount.Currency = r.Currency
+	return nil
+}
+
+// Reconciliation représente le rapprochement d'un compte avec un relevé : les transactions passées jusqu'à la date
+// du relevé sont rapprochées et la période est verrouillée à sa clôture
+type Reconciliation struct {
+	ID                      uuid.UUID  `json:"id" db:"id"`
+	UserID                  uuid.UUID  `json:"user_id" db:"user_id"`
+	AccountID               uuid.UUID  `json:"account_id" db:"account_id"`
+	StatementDate           time.Time  `json:"statement_date" db:"statement_date"`
+	StatementBalance        Money      `json:"statement_balance" db:"statement_balance" pg:",use_zero"`
+	ClearedBalance          Money      `json:"cleared_balance" db:"cleared_balance" pg:",use_zero"` // solde calculé à la clôture
+	Currency                string     `json:"currency" db:"currency"`                              // devise du compte
+	Status                  string     `json:"status" db:"status"`                                  // open, completed, cancelled
+	AdjustmentTransactionID *uuid.UUID `json:"adjustment_transaction_id,omitempty" db:"adjustment_transaction_id"`
+	ReconciledCount         int        `json:"reconciled_count" db:"reconciled_count" pg:",use_zero"` // transactions rapprochées à la clôture
+	CreatedAt               time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt               time.Time  `json:"updated_at" db:"updated_at"`
+	CompletedAt             *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+}
+
+// AfterScan rattache la devise du compte aux soldes du rapprochement
+func (r *Reconciliation) AfterScan(ctx context.Context) error {
+	r.StatementBalance.Currency = r.Currency
+	r.ClearedBalance.Currency = r.Currency
+	return nil
+}
+
+// ImportBatch représente un lot de transactions importées depuis un relevé bancaire
+type ImportBatch struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	UserID        uuid.UUID  `json:"user_id" db:"user_id"`
+	AccountID     uuid.UUID  `json:"account_id" db:"account_id"`
+	Format        string     `json:"format" db:"format"` // csv, ofx, qif
+	FileName      string     `json:"file_name" db:"file_name"`
+	Status        string     `json:"status" db:"status"` // completed, undone
+	TotalLines    int        `json:"total_lines" db:"total_lines" pg:",use_zero"`
+	ImportedCount int        `json:"imported_count" db:"imported_count" pg:",use_zero"`
+	SkippedCount  int        `json:"skipped_count" db:"skipped_count" pg:",use_zero"` // lignes déjà importées
+	FailedCount   int        `json:"failed_count" db:"failed_count" pg:",use_zero"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UndoneAt      *time.Time `json:"undone_at,omitempty" db:"undone_at"`
+}
+
+// TransactionAttachment représente une pièce jointe (photo de reçu, PDF) d'une transaction
+type TransactionAttachment struct {
 	ID            uuid.UUID `json:"id" db:"id"`
 	UserID        uuid.UUID `json:"user_id" db:"user_id"`
-	Name          string    `json:"name" db:"name"`       // ex: "Bancaire", "Cash"
-	Type          string    `json:"type" db:"type"`       // checking, savings, mobile_money, debt, other
-	Balance       float64   `json:"balance" db:"balance"` // solde actuel
-	Currency      string    `json:"currency" db:"currency"`
-	AccountNumber *string   `json:"account_number,omitempty" db:"account_number"`
-	Icon          string    `json:"icon" db:"icon"`
-	Color         string    `json:"color" db:"color"`
+	TransactionID uuid.UUID `json:"transaction_id" db:"transaction_id"`
+	FileName      string    `json:"file_name" db:"file_name"`
+	ContentType   string    `json:"content_type" db:"content_type"`
+	Size          int64     `json:"size" db:"size"`     // en octets
+	StorageKey    string    `json:"-" db:"storage_key"` // clé du fichier dans le stockage
 	CreatedAt     time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
 }
 
-type Transaction struct {
-	ID           uuid.UUID   `json:"id" db:"id"`
-	UserID       uuid.UUID   `json:"user_id" db:"user_id"`
-	AccountID    *uuid.UUID  `json:"account_id,omitempty" db:"account_id"`
-	CategoryID   *uuid.UUID  `json:"category_id,omitempty" db:"category_id"`
-	Type         string      `json:"type" db:"type"` // income, expense, transfer, saving, refund
-	ToAccountID  *uuid.UUID  `json:"to_account_id,omitempty" db:"to_account_id"`
-	SavingGoalID *uuid.UUID  `json:"saving_goal_id,omitempty" db:"saving_goal_id"`
-	Amount       float64     `json:"amount" db:"amount"`
-	Description  string      `json:"description" db:"description"`
-	Date         time.Time   `json:"date" db:"date"`
-	Recurring    bool        `json:"recurring" db:"recurring"`
-	CreatedAt    time.Time   `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time   `json:"updated_at" db:"updated_at"`
-	Category     *Category   `json:"category,omitempty" pg:"rel:has-one,fk:category_id"`
-	Account      *Account    `json:"account,omitempty" pg:"rel:has-one,fk:account_id"`
-	SavingGoal   *SavingGoal `json:"saving_goal,omitempty" pg:"rel:has-one,fk:saving_goal_id"`
+// TransactionVersion représente une version de l'historique d'une transaction : l'état obtenu après
+// une création, une modification, une suppression, une restauration ou un retour arrière
+type TransactionVersion struct {
+	ID              uuid.UUID                 `json:"id" db:"id"`
+	TransactionID   uuid.UUID                 `json:"transaction_id" db:"transaction_id"`
+	UserID          uuid.UUID                 `json:"user_id" db:"user_id"`
+	Version         int                       `json:"version" db:"version"`
+	Action          string                    `json:"action" db:"action"`                               // create, update, delete, restore, revert
+	Source          string                    `json:"source" db:"source"`                               // api, import, ai, recurring, sms, scheduler, reconciliation, bulk, system
+	ActorID         *uuid.UUID                `json:"actor_id,omitempty" db:"actor_id"`                 // utilisateur à l'origine du changement (vide pour un traitement automatique)
+	RevertedVersion *int                      `json:"reverted_version,omitempty" db:"reverted_version"` // version rétablie par un retour arrière
+	Changes         []*TransactionFieldChange `json:"changes" db:"changes" pg:",type:jsonb"`            // champs modifiés par rapport à la version précédente
+	Snapshot        *TransactionSnapshot      `json:"snapshot" db:"snapshot" pg:",type:jsonb"`          // état de la transaction après le changement
+	CreatedAt       time.Time                 `json:"created_at" db:"created_at"`
+}
+
+// TransactionFieldChange représente la modification d'un champ entre deux versions d'une transaction
+type TransactionFieldChange struct {
+	Field string          `json:"field" example:"amount"`
+	Old   json.RawMessage `json:"old" swaggertype:"object"`
+	New   json.RawMessage `json:"new" swaggertype:"object"`
+}
+
+// TransactionSnapshot représente l'état versionné d'une transaction (les étiquettes ne sont pas versionnées)
+type TransactionSnapshot struct {
+	AccountID        *uuid.UUID                `json:"account_id"`
+	CategoryID       *uuid.UUID                `json:"category_id"`
+	Type             string                    `json:"type"`
+	ToAccountID      *uuid.UUID                `json:"to_account_id"`
+	SavingGoalID     *uuid.UUID                `json:"saving_goal_id"`
+	Amount           Money                     `json:"amount"`
+	Currency         string                    `json:"currency"`
+	OriginalAmount   *Money                    `json:"original_amount"`
+	OriginalCurrency string                    `json:"original_currency"`
+	Description      string                    `json:"description"`
+	Date             time.Time                 `json:"date"`
+	Recurring        bool                      `json:"recurring"`
+	ExternalRef      *string                   `json:"external_ref"`
+	RefundOfID       *uuid.UUID                `json:"refund_of_id"`
+	RefundedAmount   Money                     `json:"refunded_amount"`
+	Status           string                    `json:"status"`
+	Splits           []TransactionSplitRequest `json:"splits"`
+	TagIDs           []uuid.UUID               `json:"tag_ids"` // nul dans les versions antérieures au suivi des étiquettes
+}
+
+// UnmarshalJSON relit un état versionné : les montants, écrits comme des nombres, sont lus dans la devise
+// de la transaction ; les versions antérieures portent la devise dans chaque montant
+func (s *TransactionSnapshot) UnmarshalJSON(data []byte) error {
+	var currencies struct {
+		Currency         string          `json:"currency"`
+		OriginalCurrency string          `json:"original_currency"`
+		OriginalAmount   json.RawMessage `json:"original_amount"`
+	}
+	if err := json.Unmarshal(data, &currencies); err != nil {
+		return err
+	}
+
+	type plain TransactionSnapshot
+	value := plain{
+		Amount:         Money{Currency: currencies.Currency},
+		RefundedAmount: Money{Currency: currencies.Currency},
+	}
+	if len(currencies.OriginalAmount) > 0 && string(currencies.OriginalAmount) != "null" {
+		value.OriginalAmount = &Money{Currency: currencies.OriginalCurrency}
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	if value.Currency == "" {
+		value.Currency = value.Amount.Currency
+	}
+	if value.OriginalCurrency == "" && value.OriginalAmount != nil {
+		value.OriginalCurrency = value.OriginalAmount.Currency
+	}
+	*s = TransactionSnapshot(value)
+	return nil
+}
+
+// Tag représente une étiquette libre de l'utilisateur (« voyage d'affaires », « remboursable »...),
+// transversale à la hiérarchie des catégories
+type Tag struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Name      string    `json:"name" db:"name"`
+	Color     string    `json:"color" db:"color"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// TransactionTag relie une transaction à une étiquette
+type TransactionTag struct {
+	TransactionID uuid.UUID `json:"transaction_id" db:"transaction_id"`
+	TagID         uuid.UUID `json:"tag_id" db:"tag_id"`
 }
 
 // Reminder représente un rappel ou notification intelligente
@@ -97,32 +583,51 @@ type Category struct {
 
 // SavingGoal représente un objectif d'épargne
 type SavingGoal struct {
+	ID                   uuid.UUID  `json:"id" db:"id"`
+	UserID               uuid.UUID  `json:"user_id" db:"user_id"`
+	AccountID            uuid.UUID  `json:"account_id" db:"account_id"`
+	Title                string     `json:"title" db:"title"`
+	TargetAmount         Money      `json:"target_amount" db:"target_amount" pg:",use_zero"`
+	CurrentAmount        Money      `json:"current_amount" db:"current_amount" pg:",use_zero"`
+	Currency             string     `json:"currency" db:"currency"` // devise du compte
+	Deadline             *time.Time `json:"deadline,omitempty" db:"deadline"`
+	IsAchieved           bool       `json:"is_achieved" db:"is_achieved"`
+	Frequency            string     `json:"frequency" db:"frequency"` // weekly, monthly, yearly, cron
+	CreatedAt            time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt            *time.Time `json:"deleted_at,omitempty" pg:"deleted_at,soft_delete"` // mise à la corbeille
+	DeletedWithAccountID *uuid.UUID `json:"-" db:"deleted_with_account_id"`                   // compte mis à la corbeille avec l'objectif
+	Account              *Account   `json:"account,omitempty" pg:"rel:has-one,fk:account_id"`
+}
+
+// AfterScan rattache la devise de l'objectif à ses montants
+func (g *SavingGoal) AfterScan(ctx context.Context) error {
+	g.TargetAmount.Currency = g.Currency
+	g.CurrentAmount.Currency = g.Currency
+	return nil
+}
+
+// Budget représente un budget mensuel ou annuel
+type Budget struct {
 	ID            uuid.UUID  `json:"id" db:"id"`
 	UserID        uuid.UUID  `json:"user_id" db:"user_id"`
-	AccountID     uuid.UUID  `json:"account_id" db:"account_id"`
-	Title         string     `json:"title" db:"title"`
-	TargetAmount  float64    `json:"target_amount" db:"target_amount"`
-	CurrentAmount float64    `json:"current_amount" db:"current_amount"`
-	Deadline      *time.Time `json:"deadline,omitempty" db:"deadline"`
-	IsAchieved    bool       `json:"is_achieved" db:"is_achieved"`
-	Frequency     string     `json:"frequency" db:"frequency"` // weekly, monthly, yearly, cron
+	CategoryID    uuid.UUID  `json:"category_id" db:"category_id"`
+	Name          string     `json:"name" db:"name"`
+	AmountPlanned Money      `json:"amount_planned" db:"amount_planned" pg:",use_zero"`
+	AmountSpent   Money      `json:"amount_spent" db:"amount_spent" pg:",use_zero"`
+	Currency      string     `json:"currency" db:"currency"`
+	Period        string     `json:"period" db:"period"` // monthly, yearly, weekly, daily
 	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
-	Account       *Account   `json:"account,omitempty" pg:"rel:has-one,fk:account_id"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty" pg:"deleted_at,soft_delete"` // mise à la corbeille
+	Category      *Category  `json:"category,omitempty" pg:"rel:has-one,fk:category_id"`
 }
 
-// Budget représente un budget mensuel ou annuel
-type Budget struct {
-	ID            uuid.UUID `json:"id" db:"id"`
-	UserID        uuid.UUID `json:"user_id" db:"user_id"`
-	CategoryID    uuid.UUID `json:"category_id" db:"category_id"`
-	Name          string    `json:"name" db:"name"`
-	AmountPlanned float64   `json:"amount_planned" db:"amount_planned"`
-	AmountSpent   float64   `json:"amount_spent" db:"amount_spent"`
-	Period        string    `json:"period" db:"period"` // monthly, yearly, weekly, daily
-	CreatedAt     time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
-	Category      *Category `json:"category,omitempty" pg:"rel:has-one,fk:category_id"`
+// AfterScan rattache la devise du budget à ses montants
+func (b *Budget) AfterScan(ctx context.Context) error {
+	b.AmountPlanned.Currency = b.Currency
+	b.AmountSpent.Currency = b.Currency
+	return nil
 }
 
 // Motivation représente une motivation
@@ -178,7 +683,8 @@ type TransactionWithDetails struct {
 	Type         string     `json:"type" db:"type"` // income, expense, transfer, saving, refund
 	ToAccountID  *uuid.UUID `json:"to_account_id,omitempty" db:"to_account_id"`
 	SavingGoalID *uuid.UUID `json:"saving_goal_id,omitempty" db:"saving_goal_id"`
-	Amount       float64    `json:"amount" db:"amount"`
+	Amount       Money      `json:"amount" db:"amount"`
+	Currency     string     `json:"currency" db:"currency"`
 	Description  string     `json:"description" db:"description"`
 	Date         time.Time  `json:"date" db:"date"`
 	Recurring    bool       `json:"recurring" db:"recurring"`
@@ -20,12 +20,23 @@ var (
 
 // Erreurs génériques
 var (
-	ErrInternalServer = errors.New("erreur interne du serveur")
-	ErrValidation     = errors.New("erreur de validation")
-	ErrUnauthorized   = errors.New("non autorisé")
-	ErrForbidden      = errors.New("accès interdit")
-	ErrBadRequest     = errors.New("requête invalide")
-	ErrAccessDenied   = errors.New("accès refusé")
+	ErrInternalServer   = errors.New("erreur interne du serveur")
+	ErrValidation       = errors.New("erreur de validation")
+	ErrUnauthorized     = errors.New("non autorisé")
+	ErrForbidden        = errors.New("accès interdit")
+	ErrBadRequest       = errors.New("requête invalide")
+	ErrAccessDenied     = errors.New("accès refusé")
+	ErrInvalidAmount    = errors.New("montant invalide")
+	ErrCurrencyMismatch = errors.New("devises différentes")
+)
+
+// Erreurs du domaine Account
+var (
+	ErrAccountNotFound        = errors.New("compte non trouvé")
+	ErrAccountArchived        = errors.New("compte clôturé")
+	ErrAccountNotArchived     = errors.New("compte non clôturé")
+	ErrInvalidAccountClosure  = errors.New("clôture de compte invalide")
+	ErrAccountHasTransactions = errors.New("le compte a des transactions")
 )
 
 // Erreurs du domaine Task
@@ -52,6 +63,96 @@ var (
 	ErrInvalidExoticTaskData = errors.New("données de tâche exotique invalides")
 )
 
+// Erreurs du domaine Transaction
+var (
+	ErrInvalidTransactionData     = errors.New("données de transaction invalides")
+	ErrInvalidTransactionQuery    = errors.New("paramètres de recherche de transactions invalides")
+	ErrDuplicateExternalRef       = errors.New("transaction déjà enregistrée avec cette référence")
+	ErrInvalidSMSData             = errors.New("SMS de mobile money invalides")
+	ErrPossibleDuplicate          = errors.New("transaction probablement déjà enregistrée")
+	ErrInvalidDuplicateMerge      = errors.New("fusion de doublons invalide")
+	ErrInvalidRefund              = errors.New("remboursement invalide")
+	ErrRefundExceedsOriginal      = errors.New("le remboursement dépasse le montant restant de la dépense d'origine")
+	ErrTransactionHasRefunds      = errors.New("la transaction a des remboursements liés")
+	ErrInvalidBulkOperation       = errors.New("opération en masse invalide")
+	ErrBulkOperationRolledBack    = errors.New("opération en masse annulée")
+	ErrTransactionVersionNotFound = errors.New("version de transaction non trouvée")
+	ErrInvalidRevert              = errors.New("retour à une version antérieure impossible")
+	ErrInvalidTransactionStatus   = errors.New("statut de transaction invalide")
+	ErrTransactionReconciled      = errors.New("transaction rapprochée, verrouillée contre les modifications")
+)
+
+// Erreurs du domaine Account
+var (
+	ErrInvalidBalanceHistoryQuery = errors.New("paramètres d'historique de solde invalides")
+)
+
+// Erreurs du domaine Currency
+var (
+	ErrExchangeRateNotFound    = errors.New("taux de change non trouvé")
+	ErrInvalidExchangeRateData = errors.New("données de taux de change invalides")
+	ErrInvalidCurrency         = errors.New("devise invalide")
+)
+
+// Erreurs du domaine Reconciliation
+var (
+	ErrReconciliationNotFound     = errors.New("rapprochement non trouvé")
+	ErrInvalidReconciliation      = errors.New("rapprochement invalide")
+	ErrReconciliationInProgress   = errors.New("un rapprochement est déjà en cours sur ce compte")
+	ErrReconciliationClosed       = errors.New("rapprochement déjà clôturé")
+	ErrReconciliationUnbalanced   = errors.New("le solde calculé ne correspond pas au relevé")
+	ErrReconciliationPeriodLocked = errors.New("période rapprochée, verrouillée contre les modifications")
+)
+
+// Erreurs du domaine Loan
+var (
+	ErrLoanNotFound    = errors.New("emprunt non trouvé")
+	ErrInvalidLoanData = errors.New("données d'emprunt invalides")
+	ErrLoanPaidOff     = errors.New("emprunt déjà remboursé")
+)
+
+// Erreurs du domaine Tontine
+var (
+	ErrTontineNotFound             = errors.New("tontine non trouvée")
+	ErrInvalidTontineData          = errors.New("données de tontine invalides")
+	ErrTontineContributionRecorded = errors.New("cotisation déjà enregistrée")
+	ErrTontinePayoutRecorded       = errors.New("cagnotte déjà versée")
+)
+
+// Erreurs du domaine Trash
+var (
+	ErrTrashItemNotFound  = errors.New("élément non trouvé dans la corbeille")
+	ErrInvalidTrashType   = errors.New("type d'élément de corbeille invalide")
+	ErrRestoreNotPossible = errors.New("restauration impossible")
+)
+
+// Erreurs du domaine Attachment
+var (
+	ErrAttachmentNotFound  = errors.New("pièce jointe non trouvée")
+	ErrTransactionNotFound = errors.New("transaction non trouvée")
+	ErrTooManyAttachments  = errors.New("nombre maximum de pièces jointes atteint")
+)
+
+// Erreurs du domaine Tag
+var (
+	ErrTagNotFound      = errors.New("étiquette non trouvée")
+	ErrInvalidTagData   = errors.New("données d'étiquette invalides")
+	ErrTagAlreadyExists = errors.New("étiquette déjà existante")
+)
+
+// Erreurs du domaine Import
+var (
+	ErrImportBatchNotFound = errors.New("lot d'import non trouvé")
+	ErrInvalidImportData   = errors.New("données d'import invalides")
+	ErrImportAlreadyUndone = errors.New("lot d'import déjà annulé")
+)
+
+// Erreurs du domaine RecurringTransaction
+var (
+	ErrRecurringTransactionNotFound    = errors.New("transaction récurrente non trouvée")
+	ErrInvalidRecurringTransactionData = errors.New("données de transaction récurrente invalides")
+)
+
 // Erreurs du domaine Expense
 var (
 	ErrExpenseNotFound    = errors.New("dépense non trouvée")
@@ -69,3 +170,19 @@ var (
 	ErrSavingStrategyNotFound    = errors.New("stratégie d'épargne non trouvée")
 	ErrInvalidSavingStrategyData = errors.New("données de stratégie d'épargne invalides")
 )
+
+// DuplicateTransactionError signale qu'une transaction ressemble à des transactions déjà enregistrées
+// (même compte, même type, même montant, date proche, libellé équivalent)
+type DuplicateTransactionError struct {
+	Matches []*Transaction
+}
+
+// Error implémente l'interface error
+func (e *DuplicateTransactionError) Error() string {
+	return ErrPossibleDuplicate.Error()
+}
+
+// Unwrap permet errors.Is(err, ErrPossibleDuplicate)
+func (e *DuplicateTransactionError) Unwrap() error {
+	return ErrPossibleDuplicate
+}
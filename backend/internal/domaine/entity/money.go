@@ -0,0 +1,371 @@
+package entity
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency est la devise des montants qui ne dépendent pas d'un compte (budgets) : le franc CFA (BEAC)
+const DefaultCurrency = "XAF"
+
+// currencyExponents donne le nombre de décimales (ISO 4217) des devises qui n'en ont pas deux.
+// La fonction SQL currency_exponent (migration 37) reprend la même liste.
+var currencyExponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// CurrencyExponent retourne le nombre de décimales d'une devise (2 par défaut)
+func CurrencyExponent(currency string) int {
+	if exponent, ok := currencyExponents[strings.ToUpper(currency)]; ok {
+		return exponent
+	}
+	return 2
+}
+
+// Money représente un montant exact : un nombre entier d'unités mineures de sa devise
+// (centimes pour l'euro, francs pour le XAF qui n'a pas de décimales).
+// Les opérations arithmétiques supposent des montants de même devise.
+type Money struct {
+	Minor    int64  // montant en unités mineures
+	Currency string // code ISO 4217
+}
+
+// NewMoney crée un montant à partir d'un nombre d'unités mineures
+func NewMoney(minor int64, currency string) Money {
+	return Money{Minor: minor, Currency: currency}
+}
+
+// ParseMoney lit un montant décimal exact (ex. "1500", "-12.50") dans une devise ; un montant ayant plus de
+// décimales que la devise n'en admet est refusé
+func ParseMoney(value, currency string) (Money, error) {
+	negative, integer, fraction, ok := splitDecimal(value)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+
+	exponent := CurrencyExponent(currency)
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > exponent {
+		return Money{}, fmt.Errorf("%w: %s admet au plus %d décimale(s)", ErrInvalidAmount, currency, exponent)
+	}
+	digits := integer + fraction + strings.Repeat("0", exponent-len(fraction))
+	if digits == "" {
+		digits = "0"
+	}
+
+	if negative {
+		digits = "-" + digits
+	}
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q hors limites", ErrInvalidAmount, value)
+	}
+	return Money{Minor: minor, Currency: currency}, nil
+}
+
+// splitDecimal découpe une écriture décimale (signe facultatif, partie entière et décimales séparées par un point)
+func splitDecimal(value string) (negative bool, integer, fraction string, ok bool) {
+	value = strings.TrimSpace(value)
+	switch {
+	case strings.HasPrefix(value, "-"):
+		negative, value = true, value[1:]
+	case strings.HasPrefix(value, "+"):
+		value = value[1:]
+	}
+	integer, fraction, _ = strings.Cut(value, ".")
+	ok = (integer != "" || fraction != "") && isDigits(integer) && isDigits(fraction)
+	return negative, integer, fraction, ok
+}
+
+// MoneyFromFloat convertit un montant flottant (valeurs par défaut codées en dur) en reprenant son écriture décimale
+// la plus courte ; un montant ayant plus de décimales que la devise n'en admet est refusé
+func MoneyFromFloat(value float64, currency string) (Money, error) {
+	return ParseMoney(strconv.FormatFloat(value, 'f', -1, 64), currency)
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Add retourne la somme de deux montants
+func (m Money) Add(other Money) Money {
+	return Money{Minor: m.Minor + other.Minor, Currency: m.currencyWith(other)}
+}
+
+// Sub retourne la différence de deux montants
+func (m Money) Sub(other Money) Money {
+	return Money{Minor: m.Minor - other.Minor, Currency: m.currencyWith(other)}
+}
+
+// Neg retourne l'opposé du montant
+func (m Money) Neg() Money {
+	return Money{Minor: -m.Minor, Currency: m.Currency}
+}
+
+// Mul retourne le montant multiplié par un entier
+func (m Money) Mul(n int64) Money {
+	return Money{Minor: m.Minor * n, Currency: m.Currency}
+}
+
+// Convert convertit le montant dans une autre devise au taux donné (unités de la devise cible pour une unité de
+// la devise du montant), arrondi à l'unité mineure la plus proche, à l'écart de zéro en cas d'égalité
+func (m Money) Convert(rate *big.Rat, currency string) Money {
+	value := new(big.Rat).Mul(new(big.Rat).SetInt64(m.Minor), rate)
+	if shift := CurrencyExponent(currency) - CurrencyExponent(m.Currency); shift != 0 {
+		scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(shift))), nil))
+		if shift > 0 {
+			value.Mul(value, scale)
+		} else {
+			value.Quo(value, scale)
+		}
+	}
+
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	if new(big.Int).Mul(remainder.Abs(remainder), big.NewInt(2)).Cmp(value.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(value.Sign())))
+	}
+	return Money{Minor: quotient.Int64(), Currency: currency}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// currencyWith retient la devise d'un résultat : celle du montant, ou de l'autre opérande pour un zéro sans devise
+func (m Money) currencyWith(other Money) string {
+	if m.Currency == "" {
+		return other.Currency
+	}
+	return m.Currency
+}
+
+// Cmp compare deux montants : -1, 0 ou 1
+func (m Money) Cmp(other Money) int {
+	switch {
+	case m.Minor < other.Minor:
+		return -1
+	case m.Minor > other.Minor:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// IsZero indique si le montant est nul
+func (m Money) IsZero() bool {
+	return m.Minor == 0
+}
+
+// IsPositive indique si le montant est strictement positif
+func (m Money) IsPositive() bool {
+	return m.Minor > 0
+}
+
+// IsNegative indique si le montant est strictement négatif
+func (m Money) IsNegative() bool {
+	return m.Minor < 0
+}
+
+// Float64 retourne le montant en unités de la devise, pour les ratios et pourcentages uniquement
+func (m Money) Float64() float64 {
+	value, _ := strconv.ParseFloat(m.Decimal(), 64)
+	return value
+}
+
+// Decimal retourne l'écriture décimale exacte du montant avec les décimales de sa devise (ex. "12.50", "1500")
+func (m Money) Decimal() string {
+	exponent := CurrencyExponent(m.Currency)
+	sign := ""
+	minor := uint64(m.Minor)
+	if m.Minor < 0 {
+		sign = "-"
+		minor = -minor // magnitude exacte, y compris pour le plus petit int64
+	}
+	digits := strconv.FormatUint(minor, 10)
+	if exponent == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+}
+
+// String retourne le montant suivi de sa devise (ex. "1500 XAF")
+func (m Money) String() string {
+	return strings.TrimSpace(m.Decimal() + " " + m.Currency)
+}
+
+// legacyMoneyJSON est l'ancienne représentation JSON d'un montant (valeur décimale, unités mineures et devise),
+// encore présente dans les versions de transactions enregistrées avec ce format
+type legacyMoneyJSON struct {
+	Value    string `json:"value"`
+	Minor    *int64 `json:"minor"`
+	Currency string `json:"currency"`
+}
+
+// MarshalJSON écrit le montant comme un nombre JSON exact ; la devise est portée par un champ voisin
+// (currency, original_currency...) de l'entité
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.Decimal()), nil
+}
+
+// UnmarshalJSON lit un nombre ou une chaîne décimale dans la devise déjà portée par le montant, ou un montant
+// à l'ancien format objet dont les unités mineures priment sur la valeur décimale
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if bytes.HasPrefix(data, []byte("{")) {
+		var value legacyMoneyJSON
+		if err := json.Unmarshal(data, &value); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidAmount, err)
+		}
+		if value.Minor != nil {
+			*m = Money{Minor: *value.Minor, Currency: value.Currency}
+			return nil
+		}
+		parsed, err := ParseMoney(value.Value, value.Currency)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	}
+
+	var amount Decimal
+	if err := amount.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	parsed, err := amount.Money(m.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value enregistre le montant en base en unités mineures ; la devise est portée par une colonne dédiée
+func (m Money) Value() (driver.Value, error) {
+	return m.Minor, nil
+}
+
+// Scan lit des unités mineures ; la devise est rattachée par le hook AfterScan de l'entité
+func (m *Money) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		m.Minor = 0
+	case int64:
+		m.Minor = value
+	case []byte:
+		return m.scanText(string(value))
+	case string:
+		return m.scanText(value)
+	default:
+		return fmt.Errorf("type de montant non supporté: %T", src)
+	}
+	return nil
+}
+
+func (m *Money) scanText(value string) error {
+	minor, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("montant invalide en base %q: %w", value, err)
+	}
+	m.Minor = minor
+	return nil
+}
+
+// Decimal est un montant saisi en unités de la devise (ex. 12.50), conservé sous sa forme décimale exacte
+// jusqu'à sa conversion en Money dans la devise du compte concerné. En JSON, il s'écrit comme un nombre
+// ou une chaîne.
+type Decimal string
+
+// DecimalOf retourne l'écriture décimale exacte d'un montant
+func DecimalOf(m Money) Decimal {
+	return Decimal(m.Decimal())
+}
+
+// ParseDecimal lit une écriture décimale (ex. "12.50", ".5", "+2") et retourne sa forme canonique : sans signe
+// plus, sans zéros superflus ni point final (ex. "12.5", "0.5", "2")
+func ParseDecimal(value string) (Decimal, error) {
+	negative, integer, fraction, ok := splitDecimal(value)
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+
+	integer = strings.TrimLeft(integer, "0")
+	if integer == "" {
+		integer = "0"
+	}
+	text := integer
+	if fraction = strings.TrimRight(fraction, "0"); fraction != "" {
+		text += "." + fraction
+	}
+	if negative && text != "0" {
+		text = "-" + text
+	}
+	return Decimal(text), nil
+}
+
+// Money convertit le montant saisi dans une devise
+func (d Decimal) Money(currency string) (Money, error) {
+	return ParseMoney(string(d), currency)
+}
+
+// Rat retourne la valeur exacte du nombre décimal saisi
+func (d Decimal) Rat() (*big.Rat, error) {
+	if _, _, _, ok := splitDecimal(string(d)); !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAmount, string(d))
+	}
+	value, ok := new(big.Rat).SetString(strings.TrimSpace(string(d)))
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAmount, string(d))
+	}
+	return value, nil
+}
+
+// MarshalJSON écrit le montant comme un nombre JSON exact, sous sa forme canonique
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if d == "" {
+		return []byte("null"), nil
+	}
+	canonical, err := ParseDecimal(string(d))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(canonical), nil
+}
+
+// UnmarshalJSON accepte un nombre ou une chaîne décimale, conservé sous sa forme canonique
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	text := string(data)
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+	canonical, err := ParseDecimal(text)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidAmount, string(data))
+	}
+	*d = canonical
+	return nil
+}
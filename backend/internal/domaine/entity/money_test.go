@@ -0,0 +1,359 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		currency string
+		want     int64
+		wantErr  bool
+	}{
+		{name: "entier XAF", value: "1500", currency: "XAF", want: 1500},
+		{name: "décimales nulles XAF", value: "1500.00", currency: "XAF", want: 1500},
+		{name: "décimale refusée XAF", value: "1500.5", currency: "XAF", wantErr: true},
+		{name: "euro", value: "12.50", currency: "EUR", want: 1250},
+		{name: "une décimale", value: "12.5", currency: "EUR", want: 1250},
+		{name: "trois décimales refusées EUR", value: "12.505", currency: "EUR", wantErr: true},
+		{name: "trois décimales KWD", value: "1.234", currency: "KWD", want: 1234},
+		{name: "une décimale KWD", value: "1.2", currency: "KWD", want: 1200},
+		{name: "quatre décimales refusées KWD", value: "1.2345", currency: "KWD", wantErr: true},
+		{name: "négatif", value: "-12.50", currency: "EUR", want: -1250},
+		{name: "négatif sous l'unité", value: "-0.05", currency: "EUR", want: -5},
+		{name: "moins zéro", value: "-0", currency: "XAF", want: 0},
+		{name: "signe plus", value: "+2", currency: "EUR", want: 200},
+		{name: "sans partie entière", value: ".5", currency: "EUR", want: 50},
+		{name: "sans décimales", value: "5.", currency: "EUR", want: 500},
+		{name: "espaces", value: " 42 ", currency: "XAF", want: 42},
+		{name: "devise en minuscules", value: "1.234", currency: "kwd", want: 1234},
+		{name: "int64 maximal", value: "9223372036854775807", currency: "XAF", want: math.MaxInt64},
+		{name: "int64 minimal", value: "-9223372036854775808", currency: "XAF", want: math.MinInt64},
+		{name: "dépassement int64", value: "9223372036854775808", currency: "XAF", wantErr: true},
+		{name: "dépassement par les décimales", value: "92233720368547758.08", currency: "EUR", wantErr: true},
+		{name: "vide", value: "", currency: "XAF", wantErr: true},
+		{name: "point seul", value: ".", currency: "XAF", wantErr: true},
+		{name: "virgule", value: "12,50", currency: "EUR", wantErr: true},
+		{name: "exposant", value: "1e3", currency: "XAF", wantErr: true},
+		{name: "double signe", value: "--1", currency: "XAF", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMoney(tt.value, tt.currency)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidAmount) {
+					t.Fatalf("ParseMoney(%q, %s) erreur = %v, attendu ErrInvalidAmount", tt.value, tt.currency, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMoney(%q, %s) erreur inattendue: %v", tt.value, tt.currency, err)
+			}
+			if got.Minor != tt.want || got.Currency != tt.currency {
+				t.Errorf("ParseMoney(%q, %s) = %d %s, attendu %d %s", tt.value, tt.currency, got.Minor, got.Currency, tt.want, tt.currency)
+			}
+		})
+	}
+}
+
+func TestSplitDecimal(t *testing.T) {
+	tests := []struct {
+		value        string
+		wantNegative bool
+		wantInteger  string
+		wantFraction string
+		wantOK       bool
+	}{
+		{value: "12.50", wantInteger: "12", wantFraction: "50", wantOK: true},
+		{value: "-3", wantNegative: true, wantInteger: "3", wantOK: true},
+		{value: "+2", wantInteger: "2", wantOK: true},
+		{value: ".5", wantFraction: "5", wantOK: true},
+		{value: "5.", wantInteger: "5", wantOK: true},
+		{value: "", wantOK: false},
+		{value: "-", wantOK: false},
+		{value: "1.2.3", wantOK: false},
+		{value: "1 000", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		negative, integer, fraction, ok := splitDecimal(tt.value)
+		if ok != tt.wantOK {
+			t.Errorf("splitDecimal(%q) ok = %v, attendu %v", tt.value, ok, tt.wantOK)
+			continue
+		}
+		if ok && (negative != tt.wantNegative || integer != tt.wantInteger || fraction != tt.wantFraction) {
+			t.Errorf("splitDecimal(%q) = %v %q %q, attendu %v %q %q", tt.value, negative, integer, fraction, tt.wantNegative, tt.wantInteger, tt.wantFraction)
+		}
+	}
+}
+
+func TestMoneyDecimal(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{money: NewMoney(1500, "XAF"), want: "1500"},
+		{money: NewMoney(-1500, "XAF"), want: "-1500"},
+		{money: NewMoney(0, "XAF"), want: "0"},
+		{money: NewMoney(1250, "EUR"), want: "12.50"},
+		{money: NewMoney(5, "EUR"), want: "0.05"},
+		{money: NewMoney(-5, "EUR"), want: "-0.05"},
+		{money: NewMoney(0, "EUR"), want: "0.00"},
+		{money: NewMoney(1234, "KWD"), want: "1.234"},
+		{money: NewMoney(-7, "KWD"), want: "-0.007"},
+		{money: NewMoney(math.MaxInt64, "XAF"), want: "9223372036854775807"},
+		{money: NewMoney(math.MinInt64, "XAF"), want: "-9223372036854775808"},
+		{money: NewMoney(math.MinInt64, "EUR"), want: "-92233720368547758.08"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.Decimal(); got != tt.want {
+			t.Errorf("Money{%d, %s}.Decimal() = %q, attendu %q", tt.money.Minor, tt.money.Currency, got, tt.want)
+		}
+		parsed, err := ParseMoney(tt.want, tt.money.Currency)
+		if err != nil || parsed != tt.money {
+			t.Errorf("ParseMoney(%q) = %v, %v, attendu l'aller-retour de %v", tt.want, parsed, err, tt.money)
+		}
+	}
+}
+
+func TestMoneyConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		money    Money
+		rate     *big.Rat
+		currency string
+		want     int64
+	}{
+		{name: "même exposant", money: NewMoney(1000, "EUR"), rate: big.NewRat(11, 10), currency: "USD", want: 1100},
+		{name: "vers devise sans décimales", money: NewMoney(1000, "EUR"), rate: big.NewRat(655957, 1000), currency: "XAF", want: 6560},
+		{name: "depuis devise sans décimales", money: NewMoney(6560, "XAF"), rate: big.NewRat(1000, 655957), currency: "EUR", want: 1000},
+		{name: "vers trois décimales", money: NewMoney(1000, "EUR"), rate: big.NewRat(1, 3), currency: "KWD", want: 3333},
+		{name: "depuis trois décimales", money: NewMoney(1005, "KWD"), rate: big.NewRat(1, 1), currency: "EUR", want: 101},
+		{name: "demi arrondi à l'écart de zéro", money: NewMoney(1, "EUR"), rate: big.NewRat(1, 2), currency: "EUR", want: 1},
+		{name: "moins un demi arrondi à l'écart de zéro", money: NewMoney(-1, "EUR"), rate: big.NewRat(1, 2), currency: "EUR", want: -1},
+		{name: "sous le demi", money: NewMoney(-1, "EUR"), rate: big.NewRat(49, 100), currency: "EUR", want: 0},
+		{name: "au-dessus du demi négatif", money: NewMoney(-3, "EUR"), rate: big.NewRat(1, 2), currency: "EUR", want: -2},
+		{name: "zéro", money: NewMoney(0, "XAF"), rate: big.NewRat(3, 7), currency: "EUR", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.money.Convert(tt.rate, tt.currency)
+			if got.Minor != tt.want || got.Currency != tt.currency {
+				t.Errorf("Convert = %d %s, attendu %d %s", got.Minor, got.Currency, tt.want, tt.currency)
+			}
+		})
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	zero := Money{}
+	price := NewMoney(1250, "EUR")
+
+	if got := zero.Add(price); got != price {
+		t.Errorf("zéro sans devise + %v = %v, attendu %v", price, got, price)
+	}
+	if got := price.Sub(NewMoney(250, "EUR")); got != NewMoney(1000, "EUR") {
+		t.Errorf("Sub = %v, attendu 10.00 EUR", got)
+	}
+	if got := price.Neg(); got != NewMoney(-1250, "EUR") || !got.IsNegative() {
+		t.Errorf("Neg = %v, attendu -12.50 EUR", got)
+	}
+	if got := price.Mul(3); got != NewMoney(3750, "EUR") {
+		t.Errorf("Mul = %v, attendu 37.50 EUR", got)
+	}
+	if price.Cmp(NewMoney(1251, "EUR")) != -1 || price.Cmp(price) != 0 || price.Cmp(zero) != 1 {
+		t.Errorf("Cmp incohérent pour %v", price)
+	}
+	if got := price.String(); got != "12.50 EUR" {
+		t.Errorf("String = %q, attendu \"12.50 EUR\"", got)
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	for money, want := range map[Money]string{
+		NewMoney(-1234, "KWD"): `-1.234`,
+		NewMoney(1500, "XAF"):  `1500`,
+		NewMoney(5, "EUR"):     `0.05`,
+	} {
+		data, err := json.Marshal(money)
+		if err != nil || string(data) != want {
+			t.Errorf("Marshal(%v) = %s, %v, attendu %s", money, data, err, want)
+		}
+	}
+
+	tests := []struct {
+		input    string
+		currency string // devise portée par le montant avant la lecture
+		want     Money
+		wantErr  bool
+	}{
+		{input: `-1.234`, currency: "KWD", want: NewMoney(-1234, "KWD")},
+		{input: `"12.50"`, currency: "EUR", want: NewMoney(1250, "EUR")},
+		{input: `1500`, currency: "XAF", want: NewMoney(1500, "XAF")},
+		{input: `12.505`, currency: "EUR", wantErr: true},
+		{input: `12.5`, currency: "XAF", wantErr: true},
+		{input: `true`, currency: "EUR", wantErr: true},
+		// ancien format objet
+		{input: `{"value":"-1.234","minor":-1234,"currency":"KWD"}`, currency: "EUR", want: NewMoney(-1234, "KWD")},
+		{input: `{"minor":1500,"currency":"XAF"}`, want: NewMoney(1500, "XAF")},
+		{input: `{"value":"12.5","currency":"EUR"}`, want: NewMoney(1250, "EUR")},
+		{input: `{"value":"12.505","currency":"EUR"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got := Money{Currency: tt.currency}
+		err := json.Unmarshal([]byte(tt.input), &got)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Unmarshal(%s) = %v, attendu une erreur", tt.input, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Unmarshal(%s) = %v, %v, attendu %v", tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestTransactionSnapshotJSON(t *testing.T) {
+	original := NewMoney(-1234, "KWD")
+	snapshot := TransactionSnapshot{
+		Type:             "expense",
+		Amount:           NewMoney(-250000, "XAF"),
+		Currency:         "XAF",
+		OriginalAmount:   &original,
+		OriginalCurrency: "KWD",
+		RefundedAmount:   NewMoney(1500, "XAF"),
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var got TransactionSnapshot
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal(%s): %v", data, err)
+	}
+	if got.Amount != snapshot.Amount || got.RefundedAmount != snapshot.RefundedAmount ||
+		got.OriginalAmount == nil || *got.OriginalAmount != original || got.OriginalCurrency != "KWD" {
+		t.Errorf("Unmarshal(%s) = %+v, attendu %+v", data, got, snapshot)
+	}
+
+	// Les versions enregistrées avant l'écriture des montants en nombres portent la devise dans chaque montant
+	legacy := `{"type":"expense","amount":{"value":"-2500","minor":-2500,"currency":"XAF"},` +
+		`"original_amount":{"value":"-1.234","minor":-1234,"currency":"KWD"},` +
+		`"refunded_amount":{"value":"0","minor":0,"currency":"XAF"}}`
+	got = TransactionSnapshot{}
+	if err := json.Unmarshal([]byte(legacy), &got); err != nil {
+		t.Fatalf("Unmarshal(ancien format): %v", err)
+	}
+	if got.Amount != NewMoney(-2500, "XAF") || got.Currency != "XAF" ||
+		got.OriginalAmount == nil || *got.OriginalAmount != original || got.OriginalCurrency != "KWD" {
+		t.Errorf("Unmarshal(ancien format) = %+v", got)
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Decimal
+		wantErr bool
+	}{
+		{value: "12.50", want: "12.5"},
+		{value: "12.00", want: "12"},
+		{value: ".5", want: "0.5"},
+		{value: "5.", want: "5"},
+		{value: "+2", want: "2"},
+		{value: "-0.0", want: "0"},
+		{value: "-.250", want: "-0.25"},
+		{value: "007", want: "7"},
+		{value: " 1500 ", want: "1500"},
+		{value: "0.000001", want: "0.000001"},
+		{value: "", wantErr: true},
+		{value: "1,5", wantErr: true},
+		{value: "abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDecimal(tt.value)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidAmount) {
+				t.Errorf("ParseDecimal(%q) erreur = %v, attendu ErrInvalidAmount", tt.value, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDecimal(%q) = %q, %v, attendu %q", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Decimal
+		wantErr bool
+	}{
+		{input: `12.50`, want: "12.5"},
+		{input: `"12.50"`, want: "12.5"},
+		{input: `".5"`, want: "0.5"},
+		{input: `"5."`, want: "5"},
+		{input: `"+2"`, want: "2"},
+		{input: `-3`, want: "-3"},
+		{input: `"1e3"`, wantErr: true},
+		{input: `true`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		var got Decimal
+		err := json.Unmarshal([]byte(tt.input), &got)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Unmarshal(%s) = %q, attendu une erreur", tt.input, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Unmarshal(%s) = %q, %v, attendu %q", tt.input, got, err, tt.want)
+			continue
+		}
+
+		// La valeur relue doit toujours pouvoir être réécrite en JSON valide
+		data, err := json.Marshal(struct{ Amount Decimal }{got})
+		if err != nil || !json.Valid(data) {
+			t.Errorf("Marshal(%q) = %s, %v", got, data, err)
+		}
+	}
+
+	// Une valeur non canonique construite en Go est normalisée à l'écriture
+	for value, want := range map[Decimal]string{".5": "0.5", "5.": "5", "+2": "2", "1.50": "1.5"} {
+		data, err := json.Marshal(value)
+		if err != nil || string(data) != want {
+			t.Errorf("Marshal(%q) = %s, %v, attendu %s", value, data, err, want)
+		}
+	}
+	if data, err := json.Marshal(Decimal("")); err != nil || string(data) != "null" {
+		t.Errorf("Marshal(\"\") = %s, %v, attendu null", data, err)
+	}
+	if _, err := json.Marshal(Decimal("abc")); err == nil {
+		t.Error("Marshal(\"abc\") attendu une erreur")
+	}
+}
+
+func TestDecimalRat(t *testing.T) {
+	got, err := Decimal("0.0016").Rat()
+	if err != nil || got.Cmp(big.NewRat(16, 10000)) != 0 {
+		t.Errorf("Rat(0.0016) = %v, %v", got, err)
+	}
+	if _, err := Decimal("1/3").Rat(); err == nil {
+		t.Error("Rat(1/3) attendu une erreur")
+	}
+}
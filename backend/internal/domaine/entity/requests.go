@@ -36,28 +36,178 @@ type UpdateTaskRequest struct {
 
 // CreateTransactionRequest représente la requête pour créer une transaction
 type CreateTransactionRequest struct {
-	AccountID    *uuid.UUID `json:"account_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
-	CategoryID   *uuid.UUID `json:"category_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
-	Type         string     `json:"type" validate:"required,oneof=income expense transfer saving refund" example:"expense"`
-	ToAccountID  *uuid.UUID `json:"to_account_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
-	SavingGoalID *uuid.UUID `json:"saving_goal_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
-	Amount       float64    `json:"amount" validate:"required" example:"25.50"`
-	Description  string     `json:"description" validate:"required,min=1,max=255" example:"Achat alimentaire"`
-	Date         time.Time  `json:"date" validate:"required" example:"2024-01-15T00:00:00Z"`
-	Recurring    bool       `json:"recurring" example:"false"`
+	AccountID        *uuid.UUID                `json:"account_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CategoryID       *uuid.UUID                `json:"category_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Type             string                    `json:"type" validate:"required,oneof=income expense transfer saving refund" example:"expense"`
+	ToAccountID      *uuid.UUID                `json:"to_account_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	SavingGoalID     *uuid.UUID                `json:"saving_goal_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Amount           Decimal                   `json:"amount" validate:"required_without=OriginalAmount" swaggertype:"number" example:"25.50"`            // dans la devise du compte ; converti du montant d'origine si absent
+	OriginalAmount   Decimal                   `json:"original_amount,omitempty" swaggertype:"number" example:"40.00"`                                    // montant payé dans une devise étrangère
+	OriginalCurrency string                    `json:"original_currency,omitempty" validate:"required_with=OriginalAmount,omitempty,len=3" example:"EUR"` // devise du montant d'origine
+	Description      string                    `json:"description" validate:"required,min=1,max=255" example:"Achat alimentaire"`
+	Date             time.Time                 `json:"date" validate:"required" example:"2024-01-15T00:00:00Z"`
+	Status           *string                   `json:"status,omitempty" validate:"omitempty,oneof=scheduled pending cleared" example:"cleared"` // cleared par défaut, scheduled pour une date future
+	Recurring        bool                      `json:"recurring" example:"false"`
+	Splits           []TransactionSplitRequest `json:"splits,omitempty"` // ventilation sur plusieurs catégories (somme = montant)
+	ExternalRef      *string                   `json:"external_ref,omitempty" validate:"omitempty,max=128" example:"stmt:20240115-0001"`
+	TagIDs           []uuid.UUID               `json:"tag_ids,omitempty"`                                                                               // étiquettes de l'utilisateur
+	RefundOfID       *uuid.UUID                `json:"refund_of_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"` // dépense remboursée (type refund)
+	ImportBatchID    *uuid.UUID                `json:"-"`                                                                                               // renseigné par l'import de relevés
+	// AllowDuplicate confirme la création malgré une transaction similaire déjà enregistrée (réponse 409)
+	AllowDuplicate bool `json:"allow_duplicate" example:"false"`
+}
+
+// ParseSMSRequest représente la requête d'analyse de SMS de confirmation mobile money
+type ParseSMSRequest struct {
+	Messages  []string   `json:"messages" validate:"required,min=1,max=50" example:"Vous avez recu 10000 FCFA de JEAN (237670000000). Nouveau solde: 25000 FCFA. Transaction Id: 1234567890."`
+	AccountID *uuid.UUID `json:"account_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"` // force le compte au lieu de le déduire de l'opérateur
+	Confirm   bool       `json:"confirm" example:"false"`                                                                       // crée les transactions au lieu de retourner des brouillons
+}
+
+// CreateTagRequest représente la requête pour créer une étiquette
+type CreateTagRequest struct {
+	Name  string `json:"name" validate:"required,min=1,max=50" example:"Voyage d'affaires"`
+	Color string `json:"color,omitempty" validate:"omitempty,max=20" example:"#0984E3"`
+}
+
+// UpdateTagRequest représente la requête pour modifier une étiquette
+type UpdateTagRequest struct {
+	Name  *string `json:"name,omitempty" validate:"omitempty,min=1,max=50" example:"Remboursable"`
+	Color *string `json:"color,omitempty" validate:"omitempty,max=20" example:"#00B894"`
+}
+
+// TagTransactionsRequest représente la requête d'ajout ou de retrait d'étiquettes sur plusieurs transactions
+type TagTransactionsRequest struct {
+	TransactionIDs []uuid.UUID `json:"transaction_ids" validate:"required,min=1,max=500"`
+	TagIDs         []uuid.UUID `json:"tag_ids" validate:"required,min=1,max=20"`
+}
+
+// MergeDuplicatesRequest représente la requête de fusion de transactions en double
+type MergeDuplicatesRequest struct {
+	KeepID       uuid.UUID   `json:"keep_id" validate:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	DuplicateIDs []uuid.UUID `json:"duplicate_ids" validate:"required,min=1"` // transactions supprimées au profit de keep_id
+}
+
+// BulkTransactionRequest représente une opération appliquée à plusieurs transactions,
+// sélectionnées par leurs IDs ou par un filtre
+type BulkTransactionRequest struct {
+	Action         string                 `json:"action" validate:"required,oneof=recategorize retag tag untag move delete" example:"recategorize"`
+	TransactionIDs []uuid.UUID            `json:"transaction_ids,omitempty"`
+	Filter         *BulkTransactionFilter `json:"filter,omitempty"`                                                                               // utilisé si transaction_ids est vide
+	CategoryID     *uuid.UUID             `json:"category_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"` // recategorize
+	TagIDs         []uuid.UUID            `json:"tag_ids,omitempty"`                                                                              // retag (remplace), tag (ajoute), untag (retire)
+	AccountID      *uuid.UUID             `json:"account_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`  // move
+	// AllOrNothing annule toute l'opération au premier échec au lieu de traiter les transactions une à une
+	AllOrNothing bool `json:"all_or_nothing" example:"false"`
+}
+
+// BulkTransactionFilter représente les critères de sélection d'une opération en masse
+type BulkTransactionFilter struct {
+	Type       string      `json:"type,omitempty" example:"expense"`
+	CategoryID *uuid.UUID  `json:"category_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	AccountID  *uuid.UUID  `json:"account_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	TagIDs     []uuid.UUID `json:"tag_ids,omitempty"`
+	TagMatch   string      `json:"tag_match,omitempty" example:"any"`
+	StartDate  string      `json:"start_date,omitempty" example:"2024-01-01"`
+	EndDate    string      `json:"end_date,omitempty" example:"2024-01-31"`
+	MinAmount  *Decimal    `json:"min_amount,omitempty" swaggertype:"number" example:"10"`
+	MaxAmount  *Decimal    `json:"max_amount,omitempty" swaggertype:"number" example:"500"`
+	Search     string      `json:"search,omitempty" example:"carrefour"`
 }
 
 // UpdateTransactionRequest représente la requête pour mettre à jour une transaction
 type UpdateTransactionRequest struct {
-	AccountID    *uuid.UUID `json:"account_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
-	CategoryID   *uuid.UUID `json:"category_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
-	Type         *string    `json:"type,omitempty" validate:"omitempty,oneof=income expense transfer saving refund" example:"income"`
-	ToAccountID  *uuid.UUID `json:"to_account_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
-	SavingGoalID *uuid.UUID `json:"saving_goal_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
-	Amount       *float64   `json:"amount,omitempty" validate:"omitempty" example:"100.00"`
-	Description  *string    `json:"description,omitempty" validate:"omitempty,min=1,max=255" example:"Salaire mensuel"`
-	Date         *time.Time `json:"date,omitempty" validate:"omitempty" example:"2024-01-15T00:00:00Z"`
-	Recurring    *bool      `json:"recurring,omitempty" example:"true"`
+	AccountID        *uuid.UUID                `json:"account_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CategoryID       *uuid.UUID                `json:"category_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ClearCategory    bool                      `json:"clear_category,omitempty" example:"false"` // retire la catégorie ; ignoré si category_id est fourni
+	Type             *string                   `json:"type,omitempty" validate:"omitempty,oneof=income expense transfer saving refund" example:"income"`
+	ToAccountID      *uuid.UUID                `json:"to_account_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	SavingGoalID     *uuid.UUID                `json:"saving_goal_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Amount           *Decimal                  `json:"amount,omitempty" validate:"omitempty" swaggertype:"number" example:"100.00"`
+	OriginalAmount   *Decimal                  `json:"original_amount,omitempty" validate:"omitempty" swaggertype:"number" example:"40.00"` // montant d'origine ; sans montant, celui-ci est reconverti
+	OriginalCurrency *string                   `json:"original_currency,omitempty" validate:"omitempty,max=3" example:"EUR"`                // une devise vide retire le montant d'origine
+	Description      *string                   `json:"description,omitempty" validate:"omitempty,min=1,max=255" example:"Salaire mensuel"`
+	Date             *time.Time                `json:"date,omitempty" validate:"omitempty" example:"2024-01-15T00:00:00Z"`
+	Recurring        *bool                     `json:"recurring,omitempty" example:"true"`
+	Splits           []TransactionSplitRequest `json:"splits,omitempty"`  // remplace la ventilation ; une liste vide la supprime
+	TagIDs           []uuid.UUID               `json:"tag_ids,omitempty"` // remplace les étiquettes ; une liste vide les retire
+}
+
+// SetTransactionStatusRequest représente la requête de changement de statut d'une transaction
+type SetTransactionStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=scheduled pending cleared reconciled" example:"cleared"`
+}
+
+// TransactionSplitRequest représente une ligne de ventilation d'une transaction
+type TransactionSplitRequest struct {
+	CategoryID uuid.UUID `json:"category_id" validate:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Amount     Decimal   `json:"amount" validate:"required" swaggertype:"number" example:"12.50"`
+	Note       string    `json:"note,omitempty" validate:"omitempty,max=255" example:"Produits ménagers"`
+}
+
+// TransactionFilter représente les critères de filtrage, de tri et de pagination par curseur des transactions
+type TransactionFilter struct {
+	Type       string
+	CategoryID *uuid.UUID
+	AccountID  *uuid.UUID
+	StartDate  *time.Time
+	EndDate    *time.Time
+	MinAmount  *Decimal // en unités de la devise de chaque transaction
+	MaxAmount  *Decimal
+	TagIDs     []uuid.UUID        // transactions portant l'une de ces étiquettes (toutes si TagMatch vaut all)
+	TagMatch   string             // any, all
+	Search     string             // recherche dans la description
+	Status     string             // scheduled, pending, cleared, reconciled
+	SortBy     string             // date, amount, created_at
+	SortOrder  string             // asc, desc
+	Cursor     *TransactionCursor // position après laquelle reprendre la lecture
+	Limit      int
+}
+
+// TransactionCursor repère la dernière transaction d'une page pour la pagination par clé (keyset)
+type TransactionCursor struct {
+	SortValue string    `json:"v,omitempty"` // valeur de la colonne de tri (date ou montant)
+	CreatedAt time.Time `json:"c"`
+	ID        uuid.UUID `json:"i"`
+}
+
+// TransactionStatsFilter représente la plage de dates (bornes incluses), le compte éventuel et les statuts
+// sur lesquels calculer les statistiques des transactions
+type TransactionStatsFilter struct {
+	StartDate      time.Time
+	EndDate        time.Time
+	AccountID      *uuid.UUID
+	IncludePending bool // compte les transactions en attente ; les transactions planifiées sont toujours exclues
+}
+
+// ==================== RECURRING TRANSACTION REQUESTS ====================
+
+// CreateRecurringTransactionRequest représente la requête pour créer une transaction récurrente
+type CreateRecurringTransactionRequest struct {
+	AccountID      uuid.UUID  `json:"account_id" validate:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CategoryID     *uuid.UUID `json:"category_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Type           string     `json:"type" validate:"required,oneof=income expense" example:"expense"`
+	Amount         Decimal    `json:"amount" validate:"required" swaggertype:"number" example:"150000"`
+	Description    string     `json:"description" validate:"required,min=1,max=255" example:"Loyer"`
+	Frequency      string     `json:"frequency" validate:"required,oneof=daily weekly monthly yearly" example:"monthly"`
+	Interval       int        `json:"interval,omitempty" validate:"omitempty,gte=1" example:"1"`
+	DayOfMonth     *int       `json:"day_of_month,omitempty" validate:"omitempty,min=1,max=31" example:"5"`
+	StartDate      time.Time  `json:"start_date" validate:"required" example:"2024-01-05T00:00:00Z"`
+	EndDate        *time.Time `json:"end_date,omitempty" example:"2024-12-31T00:00:00Z"`
+	MaxOccurrences *int       `json:"max_occurrences,omitempty" validate:"omitempty,gte=1" example:"12"`
+}
+
+// UpdateRecurringTransactionRequest représente la requête pour modifier les occurrences futures d'une transaction récurrente
+type UpdateRecurringTransactionRequest struct {
+	AccountID      *uuid.UUID `json:"account_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CategoryID     *uuid.UUID `json:"category_id,omitempty" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Amount         *Decimal   `json:"amount,omitempty" validate:"omitempty" swaggertype:"number" example:"160000"`
+	Description    *string    `json:"description,omitempty" validate:"omitempty,min=1,max=255" example:"Loyer + charges"`
+	Frequency      *string    `json:"frequency,omitempty" validate:"omitempty,oneof=daily weekly monthly yearly" example:"monthly"`
+	Interval       *int       `json:"interval,omitempty" validate:"omitempty,gte=1" example:"1"`
+	DayOfMonth     *int       `json:"day_of_month,omitempty" validate:"omitempty,min=1,max=31" example:"10"`
+	EndDate        *time.Time `json:"end_date,omitempty" example:"2025-12-31T00:00:00Z"`
+	MaxOccurrences *int       `json:"max_occurrences,omitempty" validate:"omitempty,gte=1" example:"24"`
 }
 
 // ==================== BUDGET REQUESTS ====================
@@ -66,15 +216,16 @@ type UpdateTransactionRequest struct {
 type CreateBudgetRequest struct {
 	CategoryID    uuid.UUID `json:"category_id" validate:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
 	Name          string    `json:"name" validate:"required,min=1,max=255" example:"Budget Alimentation Janvier"`
-	AmountPlanned float64   `json:"amount_planned" validate:"required,gt=0" example:"500.00"`
+	AmountPlanned Decimal   `json:"amount_planned" validate:"required" swaggertype:"number" example:"50000"`
+	Currency      string    `json:"currency,omitempty" validate:"omitempty,len=3" example:"XAF"` // XAF par défaut
 	Period        string    `json:"period" validate:"required,oneof=monthly yearly weekly daily" example:"monthly"`
 }
 
 // UpdateBudgetRequest représente la requête pour mettre à jour un budget
 type UpdateBudgetRequest struct {
 	Name          *string  `json:"name,omitempty" validate:"omitempty,min=1,max=255" example:"Budget Alimentation Février"`
-	AmountPlanned *float64 `json:"amount_planned,omitempty" validate:"omitempty,gt=0" example:"600.00"`
-	AmountSpent   *float64 `json:"amount_spent,omitempty" validate:"omitempty,gte=0" example:"450.00"`
+	AmountPlanned *Decimal `json:"amount_planned,omitempty" validate:"omitempty" swaggertype:"number" example:"60000"`
+	AmountSpent   *Decimal `json:"amount_spent,omitempty" validate:"omitempty" swaggertype:"number" example:"45000"`
 	Period        *string  `json:"period,omitempty" validate:"omitempty,oneof=monthly yearly weekly daily" example:"monthly"`
 }
 
@@ -83,7 +234,7 @@ type UpdateBudgetRequest struct {
 // CreateSavingGoalRequest représente la requête pour créer un objectif d'épargne
 type CreateSavingGoalRequest struct {
 	Title        string     `json:"title" validate:"required,min=1,max=255" example:"Vacances d'été"`
-	TargetAmount float64    `json:"target_amount" validate:"required,gt=0" example:"2000.00"`
+	TargetAmount Decimal    `json:"target_amount" validate:"required" swaggertype:"number" example:"200000"`
 	Deadline     *time.Time `json:"deadline,omitempty" validate:"omitempty,gt=now" example:"2024-06-30T00:00:00Z"`
 	Frequency    *string    `json:"frequency,omitempty" validate:"omitempty,oneof=weekly monthly yearly" example:"monthly"`
 }
@@ -91,8 +242,8 @@ type CreateSavingGoalRequest struct {
 // UpdateSavingGoalRequest représente la requête pour mettre à jour un objectif d'épargne
 type UpdateSavingGoalRequest struct {
 	Title         *string    `json:"title,omitempty" validate:"omitempty,min=1,max=255" example:"Vacances d'été 2024"`
-	TargetAmount  *float64   `json:"target_amount,omitempty" validate:"omitempty,gt=0" example:"2500.00"`
-	CurrentAmount *float64   `json:"current_amount,omitempty" validate:"omitempty,gte=0" example:"1500.00"`
+	TargetAmount  *Decimal   `json:"target_amount,omitempty" validate:"omitempty" swaggertype:"number" example:"250000"`
+	CurrentAmount *Decimal   `json:"current_amount,omitempty" validate:"omitempty" swaggertype:"number" example:"150000"`
 	Deadline      *time.Time `json:"deadline,omitempty" validate:"omitempty,gt=now" example:"2024-07-31T00:00:00Z"`
 	IsAchieved    *bool      `json:"is_achieved,omitempty" example:"false"`
 	Frequency     *string    `json:"frequency,omitempty" validate:"omitempty,oneof=weekly monthly yearly" example:"monthly"`
@@ -141,7 +292,7 @@ type UpdateCategoryRequest struct {
 type CreateAccountRequest struct {
 	Name          string  `json:"name" validate:"required,min=1,max=255" example:"Compte principal"`
 	Type          string  `json:"type" validate:"required,oneof=checking savings mobile_money" example:"checking"`
-	Balance       float64 `json:"balance" validate:"gte=0" example:"1500.00"`
+	Balance       Decimal `json:"balance" swaggertype:"number" example:"1500.00"`
 	Currency      string  `json:"currency" validate:"required,len=3" example:"EUR"`
 	Icon          string  `json:"icon" validate:"omitempty,max=50" example:"fad:utensils"`
 	Color         string  `json:"color" validate:"omitempty,max=20" example:"#FF6B6B"`
@@ -152,10 +303,104 @@ type CreateAccountRequest struct {
 type UpdateAccountRequest struct {
 	Name     *string  `json:"name,omitempty" validate:"omitempty,min=1,max=255" example:"Compte principal BNP"`
 	Type     *string  `json:"type,omitempty" validate:"omitempty,oneof=checking savings mobile_money" example:"checking"`
-	Balance  *float64 `json:"balance,omitempty" validate:"omitempty,gte=0" example:"2000.00"`
+	Balance  *Decimal `json:"balance,omitempty" validate:"omitempty" swaggertype:"number" example:"2000.00"`
 	Currency *string  `json:"currency,omitempty" validate:"omitempty,len=3" example:"EUR"`
 }
 
+// CloseAccountRequest représente la requête de clôture d'un compte ; un solde non nul est transféré vers (ou, s'il
+// est négatif, depuis) le compte TransferToAccountID
+type CloseAccountRequest struct {
+	TransferToAccountID *uuid.UUID `json:"transfer_to_account_id,omitempty" validate:"omitempty,uuid"` // requis si le solde n'est pas nul
+	Date                *time.Time `json:"date,omitempty" example:"2024-01-31T00:00:00Z"`              // date du transfert, aujourd'hui par défaut ; pas dans le futur
+}
+
+// StartReconciliationRequest représente la requête pour démarrer le rapprochement d'un compte avec un relevé
+type StartReconciliationRequest struct {
+	StatementDate    time.Time `json:"statement_date" validate:"required" example:"2024-01-31T00:00:00Z"`
+	StatementBalance Decimal   `json:"statement_balance" validate:"required" swaggertype:"number" example:"1250.75"` // dans la devise du compte
+}
+
+// ClearReconciliationItemsRequest représente la requête pour pointer des transactions en attente comme passées
+type ClearReconciliationItemsRequest struct {
+	TransactionIDs []uuid.UUID `json:"transaction_ids" validate:"required,min=1,dive,uuid"`
+}
+
+// ReconciliationAdjustmentRequest représente la requête pour enregistrer l'écart restant d'un rapprochement
+type ReconciliationAdjustmentRequest struct {
+	Description string `json:"description,omitempty" validate:"omitempty,max=255" example:"Frais bancaires non saisis"`
+}
+
+// CreateExchangeRateRequest représente la requête pour saisir un taux de change ; un taux existant pour la même
+// paire et la même date est remplacé
+type CreateExchangeRateRequest struct {
+	FromCurrency string    `json:"from_currency" validate:"required,len=3" example:"EUR"`
+	ToCurrency   string    `json:"to_currency" validate:"required,len=3" example:"XAF"`
+	Rate         Decimal   `json:"rate" validate:"required" swaggertype:"number" example:"655.957"` // unités de to_currency pour une unité de from_currency
+	Date         time.Time `json:"date" validate:"required" example:"2024-01-15T00:00:00Z"`
+}
+
+// SetBaseCurrencyRequest représente la requête pour changer la devise de référence de l'utilisateur
+type SetBaseCurrencyRequest struct {
+	Currency string `json:"currency" validate:"required,len=3" example:"XAF"`
+}
+
+// CreateLoanRequest représente la requête pour enregistrer un emprunt. Un compte de type debt est créé pour le suivre ;
+// les fonds sont versés sur le compte de déblocage s'il est fourni, sinon le solde du compte est ouvert au capital
+// restant dû après les échéances déjà réglées
+type CreateLoanRequest struct {
+	Name                  string     `json:"name" validate:"required,min=1,max=100" example:"Prêt immobilier"`
+	Lender                string     `json:"lender,omitempty" validate:"omitempty,max=100" example:"Afriland First Bank"`
+	Principal             Decimal    `json:"principal" validate:"required" swaggertype:"number" example:"5000000"`
+	InterestRate          Decimal    `json:"interest_rate" validate:"required" swaggertype:"number" example:"9.5"` // taux annuel en pourcentage
+	TermMonths            int        `json:"term_months" validate:"required,min=1,max=600" example:"60"`
+	PaymentDay            int        `json:"payment_day" validate:"required,min=1,max=31" example:"5"`
+	StartDate             time.Time  `json:"start_date" validate:"required" example:"2024-01-15T00:00:00Z"`      // date de déblocage
+	Currency              string     `json:"currency,omitempty" validate:"omitempty,len=3" example:"XAF"`        // devise du compte de déblocage ou de référence par défaut
+	DisbursementAccountID *uuid.UUID `json:"disbursement_account_id,omitempty" validate:"omitempty,uuid"`        // compte crédité du capital
+	InterestCategoryID    *uuid.UUID `json:"interest_category_id,omitempty" validate:"omitempty,uuid"`           // catégorie des dépenses d'intérêts
+	InstallmentsPaid      int        `json:"installments_paid,omitempty" validate:"omitempty,min=0" example:"0"` // échéances réglées avant l'enregistrement
+}
+
+// PayLoanInstallmentRequest représente la requête de règlement de la prochaine échéance d'un emprunt
+type PayLoanInstallmentRequest struct {
+	FromAccountID  uuid.UUID  `json:"from_account_id" validate:"required,uuid"`
+	Date           *time.Time `json:"date,omitempty" example:"2024-02-05T00:00:00Z"`                   // aujourd'hui par défaut
+	ExtraPrincipal Decimal    `json:"extra_principal,omitempty" swaggertype:"number" example:"100000"` // remboursement anticipé, qui raccourcit la durée
+}
+
+// CreateTontineRequest représente la requête pour enregistrer une tontine. L'ordre des membres est l'ordre de
+// passage ; un compte de type tontine est créé pour suivre les cotisations et la cagnotte de l'utilisateur.
+type CreateTontineRequest struct {
+	Name               string                 `json:"name" validate:"required,min=1,max=100" example:"Njangi du quartier"`
+	ContributionAmount Decimal                `json:"contribution_amount" validate:"required" swaggertype:"number" example:"25000"`
+	Frequency          string                 `json:"frequency" validate:"required,oneof=weekly biweekly monthly" example:"monthly"`
+	StartDate          time.Time              `json:"start_date" validate:"required" example:"2024-01-31T00:00:00Z"` // date du premier tour
+	Currency           string                 `json:"currency,omitempty" validate:"omitempty,len=3" example:"XAF"`   // devise de référence par défaut
+	Members            []TontineMemberRequest `json:"members" validate:"required,min=2,dive"`
+}
+
+// TontineMemberRequest représente un membre d'une tontine à enregistrer
+type TontineMemberRequest struct {
+	Name   string `json:"name" validate:"required,min=1,max=100" example:"Aïcha"`
+	Phone  string `json:"phone,omitempty" validate:"omitempty,max=20" example:"+237690000000"`
+	IsSelf bool   `json:"is_self,omitempty"` // l'utilisateur lui-même, exactement un membre
+}
+
+// RecordTontineContributionRequest représente la requête d'enregistrement de la cotisation d'un membre pour un tour.
+// La cotisation de l'utilisateur est transférée depuis AccountID vers le compte de la tontine.
+type RecordTontineContributionRequest struct {
+	MemberID  uuid.UUID  `json:"member_id" validate:"required,uuid"`
+	AccountID *uuid.UUID `json:"account_id,omitempty" validate:"omitempty,uuid"` // requis pour la cotisation de l'utilisateur
+	Date      *time.Time `json:"date,omitempty" example:"2024-01-31T00:00:00Z"`  // aujourd'hui par défaut
+}
+
+// RecordTontinePayoutRequest représente la requête d'enregistrement du versement de la cagnotte d'un tour. La
+// cagnotte reçue par l'utilisateur est transférée du compte de la tontine vers AccountID.
+type RecordTontinePayoutRequest struct {
+	AccountID *uuid.UUID `json:"account_id,omitempty" validate:"omitempty,uuid"` // requis si l'utilisateur est le bénéficiaire
+	Date      *time.Time `json:"date,omitempty" example:"2024-01-31T00:00:00Z"`  // aujourd'hui par défaut
+}
+
 // ==================== MOOD REQUESTS ====================
 
 // CreateMoodRequest représente la requête pour créer une humeur
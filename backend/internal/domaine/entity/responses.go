@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"context"
 	"time"
 
 	"github.com/google/uuid"
@@ -30,25 +31,419 @@ type TransactionResponse struct {
 	AccountID   uuid.UUID `json:"account_id"`
 	CategoryID  uuid.UUID `json:"category_id"`
 	Type        string    `json:"type"` // income, expense
-	Amount      float64   `json:"amount"`
+	Amount      Money     `json:"amount"`
 	Description string    `json:"description"`
 	Date        time.Time `json:"date"`
 	Recurring   bool      `json:"recurring"`
 }
 
+// TransactionPageResponse représente une page de transactions paginée par curseur
+type TransactionPageResponse struct {
+	Transactions []*Transaction   `json:"transactions"`
+	Pagination   CursorPagination `json:"pagination"`
+}
+
+// CursorPagination décrit la position d'une page dans une pagination par curseur
+type CursorPagination struct {
+	Limit      int    `json:"limit" example:"20"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more" example:"true"`
+	Total      int64  `json:"total" example:"250"`
+}
+
+// ImportLine représente une ligne de relevé analysée lors d'un import
+type ImportLine struct {
+	Line          int        `json:"line" example:"3"`
+	Date          *time.Time `json:"date,omitempty"`
+	Type          string     `json:"type,omitempty" example:"expense"`
+	Amount        Money      `json:"amount"`
+	Description   string     `json:"description" example:"CARTE SUPERMARCHE"`
+	ExternalRef   string     `json:"external_ref,omitempty"`
+	Status        string     `json:"status" example:"new"` // new, duplicate, invalid, imported, failed
+	Error         string     `json:"error,omitempty"`
+	TransactionID *uuid.UUID `json:"transaction_id,omitempty"`
+}
+
+// ImportResult représente le résultat d'un aperçu (dry-run) ou d'un import de relevé
+type ImportResult struct {
+	DryRun         bool          `json:"dry_run"`
+	Batch          *ImportBatch  `json:"batch,omitempty"`
+	Format         string        `json:"format" example:"csv"`
+	TotalLines     int           `json:"total_lines"`
+	NewCount       int           `json:"new_count"`
+	DuplicateCount int           `json:"duplicate_count"`
+	InvalidCount   int           `json:"invalid_count"`
+	Lines          []*ImportLine `json:"lines"`
+}
+
+// SMSDraft représente une transaction extraite d'un SMS de mobile money
+type SMSDraft struct {
+	Message          int        `json:"message" example:"0"` // index du SMS dans la requête
+	Operator         string     `json:"operator,omitempty" example:"mtn_momo"`
+	Kind             string     `json:"kind,omitempty" example:"sent"` // received, sent, payment, withdrawal, deposit
+	Type             string     `json:"type,omitempty" example:"expense"`
+	Amount           Money      `json:"amount"` // dans la devise du compte retenu (XAF à défaut)
+	Fees             Money      `json:"fees"`
+	Counterparty     string     `json:"counterparty,omitempty" example:"MARIE NGO (237680000000)"`
+	Reference        string     `json:"reference,omitempty" example:"9876543210"`
+	BalanceAfter     *Money     `json:"balance_after,omitempty"`
+	Date             *time.Time `json:"date,omitempty"`
+	Description      string     `json:"description,omitempty" example:"Transfert vers MARIE NGO (237680000000)"`
+	AccountID        *uuid.UUID `json:"account_id,omitempty"`
+	AccountName      string     `json:"account_name,omitempty" example:"MOMO"`
+	Status           string     `json:"status" example:"draft"` // draft, duplicate, unrecognized, invalid, created, failed
+	Error            string     `json:"error,omitempty"`
+	TransactionID    *uuid.UUID `json:"transaction_id,omitempty"`
+	FeeTransactionID *uuid.UUID `json:"fee_transaction_id,omitempty"`
+}
+
+// ParseSMSResponse représente le résultat de l'analyse de SMS de mobile money
+type ParseSMSResponse struct {
+	Drafts            []*SMSDraft `json:"drafts"`
+	CreatedCount      int         `json:"created_count"`
+	DuplicateCount    int         `json:"duplicate_count"`
+	UnrecognizedCount int         `json:"unrecognized_count"`
+}
+
+// DuplicateGroup représente un groupe de transactions probablement en double
+type DuplicateGroup struct {
+	Fingerprint  string         `json:"fingerprint"`
+	Transactions []*Transaction `json:"transactions"` // la plus ancienne en premier
+}
+
+// DuplicateScanResponse représente le résultat d'une recherche de doublons
+type DuplicateScanResponse struct {
+	WindowDays     int               `json:"window_days" example:"2"`
+	Groups         []*DuplicateGroup `json:"groups"`
+	DuplicateCount int               `json:"duplicate_count"` // transactions en trop (hors la première de chaque groupe)
+}
+
+// MergeDuplicatesResponse représente le résultat d'une fusion de doublons
+type MergeDuplicatesResponse struct {
+	Transaction *Transaction `json:"transaction"`
+	MergedCount int          `json:"merged_count"`
+}
+
+// TransactionExportRow représente une transaction lue pour un export, avec son compte et ses étiquettes
+type TransactionExportRow struct {
+	ID               uuid.UUID  `db:"id"`
+	Date             time.Time  `db:"date"`
+	Type             string     `db:"type"`
+	Amount           Money      `db:"amount"`
+	Description      string     `db:"description"`
+	CategoryID       *uuid.UUID `db:"category_id"`
+	ExternalRef      *string    `db:"external_ref"`
+	AccountName      string     `db:"account_name"`
+	Currency         string     `db:"currency"`
+	Status           string     `db:"status"`
+	Tags             string     `db:"tags"`                           // noms des étiquettes séparés par des virgules
+	SplitCategoryIDs []string   `db:"split_category_ids" pg:",array"` // catégories des lignes de ventilation
+	SplitAmounts     []int64    `db:"split_amounts" pg:",array"`      // montants des lignes en unités mineures, dans le même ordre
+}
+
+// AfterScan rattache le montant lu à la devise du compte
+func (r *TransactionExportRow) AfterScan(ctx context.Context) error {
+	r.Amount.Currency = r.Currency
+	return nil
+}
+
+// BulkTransactionResult représente le résultat d'une opération en masse pour une transaction
+type BulkTransactionResult struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	Status        string    `json:"status" example:"succeeded"` // succeeded, failed, skipped, rolled_back
+	Error         string    `json:"error,omitempty"`
+}
+
+// BulkTransactionResponse représente le résultat d'une opération en masse
+type BulkTransactionResponse struct {
+	Action       string                   `json:"action" example:"recategorize"`
+	AllOrNothing bool                     `json:"all_or_nothing"`
+	Total        int                      `json:"total" example:"42"`
+	Succeeded    int                      `json:"succeeded" example:"40"`
+	Failed       int                      `json:"failed" example:"1"`
+	Skipped      int                      `json:"skipped" example:"1"`
+	Results      []*BulkTransactionResult `json:"results"`
+}
+
+// TrashItem représente un élément de la corbeille : transaction, compte, budget ou objectif d'épargne.
+// Les transactions et objectifs mis à la corbeille avec leur compte n'apparaissent pas : ils sont
+// restaurés avec lui et comptés dans RelatedCount.
+type TrashItem struct {
+	Type         string     `json:"type" example:"transaction"` // transaction, account, budget, saving_goal
+	ID           uuid.UUID  `json:"id"`
+	Name         string     `json:"name" example:"Courses"`
+	Amount       Money      `json:"amount"`
+	Currency     string     `json:"-"`
+	Date         *time.Time `json:"date,omitempty"` // date de la transaction
+	RelatedCount int        `json:"related_count,omitempty" example:"12"`
+	DeletedAt    time.Time  `json:"deleted_at"`
+	PurgeAt      time.Time  `json:"purge_at"` // date de suppression définitive
+}
+
+// AfterScan rattache le montant lu à sa devise
+func (i *TrashItem) AfterScan(ctx context.Context) error {
+	i.Amount.Currency = i.Currency
+	return nil
+}
+
+// TrashPurgeResult représente le nombre d'éléments supprimés définitivement de la corbeille
+type TrashPurgeResult struct {
+	Transactions int      `json:"transactions"`
+	Accounts     int      `json:"accounts"`
+	Budgets      int      `json:"budgets"`
+	SavingGoals  int      `json:"saving_goals"`
+	StorageKeys  []string `json:"-"` // fichiers des pièces jointes à supprimer du stockage
+}
+
+// TransactionStatsTotals représente les totaux de revenus et de dépenses d'une période (transferts exclus)
+type TransactionStatsTotals struct {
+	Income  Money `json:"income"`
+	Expense Money `json:"expense"`
+	Net     Money `json:"net"`
+	Count   int   `json:"count" example:"42"`
+}
+
+// TransactionStatsBucket représente les totaux d'un intervalle de la série temporelle
+type TransactionStatsBucket struct {
+	PeriodStart time.Time `json:"period_start"` // premier jour de l'intervalle (jour, lundi de la semaine ou 1er du mois)
+	Income      Money     `json:"income"`
+	Expense     Money     `json:"expense"`
+	Net         Money     `json:"net"`
+	Count       int       `json:"count"`
+}
+
+// CategoryStatsLine représente le total d'une catégorie sur une période, rattachée à sa catégorie racine
+type CategoryStatsLine struct {
+	RootID       *uuid.UUID // nil pour les transactions sans catégorie
+	RootName     string
+	RootIcon     string
+	RootColor    string
+	CategoryID   *uuid.UUID
+	CategoryName string
+	Type         string // income ou expense
+	Amount       Money
+	Count        int
+}
+
+// CategoryStats représente le total d'une catégorie racine, sous-catégories comprises
+type CategoryStats struct {
+	CategoryID    *uuid.UUID       `json:"category_id"` // nil pour les transactions sans catégorie
+	Name          string           `json:"name" example:"Alimentation"`
+	Icon          string           `json:"icon,omitempty"`
+	Color         string           `json:"color,omitempty"`
+	Type          string           `json:"type" example:"expense"`
+	Amount        Money            `json:"amount"`
+	Count         int              `json:"count" example:"12"`
+	Share         float64          `json:"share" example:"26.56"` // part en pourcentage du total du même type
+	Subcategories []*CategoryStats `json:"subcategories,omitempty"`
+}
+
+// AccountStats représente les totaux d'un compte sur une période
+type AccountStats struct {
+	AccountID   *uuid.UUID `json:"account_id"`
+	AccountName string     `json:"account_name" example:"MTN MoMo"`
+	Income      Money      `json:"income"`
+	Expense     Money      `json:"expense"`
+	Net         Money      `json:"net"`
+	Count       int        `json:"count"`
+}
+
+// TagStats représente les totaux des transactions portant une étiquette sur une période ; une transaction
+// portant plusieurs étiquettes compte pour chacune
+type TagStats struct {
+	TagID   uuid.UUID `json:"tag_id"`
+	Name    string    `json:"name" example:"Voyage d'affaires"`
+	Color   string    `json:"color,omitempty"`
+	Income  Money     `json:"income"`
+	Expense Money     `json:"expense"`
+	Net     Money     `json:"net"`
+	Count   int       `json:"count"`
+}
+
+// BaseCurrencyResponse représente la devise de référence de l'utilisateur
+type BaseCurrencyResponse struct {
+	Currency string `json:"currency" example:"XAF"`
+}
+
+// ExchangeRateImportLine représente une ligne rejetée d'un fichier de taux de change
+type ExchangeRateImportLine struct {
+	Line  int    `json:"line" example:"3"`
+	Error string `json:"error" example:"taux invalide"`
+}
+
+// ExchangeRateImportResult représente le résultat de l'import d'un fichier de taux de change
+type ExchangeRateImportResult struct {
+	TotalLines    int                       `json:"total_lines"`
+	ImportedCount int                       `json:"imported_count"` // taux créés ou remplacés
+	InvalidCount  int                       `json:"invalid_count"`
+	Invalid       []*ExchangeRateImportLine `json:"invalid"`
+}
+
+// TagTransactionsResponse représente le résultat d'un ajout ou d'un retrait d'étiquettes en masse
+type TagTransactionsResponse struct {
+	TransactionCount int `json:"transaction_count" example:"12"` // transactions concernées
+	AffectedCount    int `json:"affected_count" example:"20"`    // liens transaction-étiquette ajoutés ou retirés
+}
+
+// TransactionStatsComparison représente les totaux de la période précédente et les écarts avec la période courante
+type TransactionStatsComparison struct {
+	StartDate     time.Time              `json:"start_date"`
+	EndDate       time.Time              `json:"end_date"`
+	Totals        TransactionStatsTotals `json:"totals"`
+	IncomeChange  *float64               `json:"income_change"`  // variation en pourcentage, nil si la période précédente est nulle
+	ExpenseChange *float64               `json:"expense_change"` // variation en pourcentage, nil si la période précédente est nulle
+	NetChange     Money                  `json:"net_change"`     // écart du solde net en valeur absolue
+}
+
+// TransactionStatsResponse représente les statistiques des transactions sur une période
+type TransactionStatsResponse struct {
+	Period         string                      `json:"period" example:"month"`    // week, month, year ou custom
+	Granularity    string                      `json:"granularity" example:"day"` // day, week ou month
+	StartDate      time.Time                   `json:"start_date"`
+	EndDate        time.Time                   `json:"end_date"`
+	AccountID      *uuid.UUID                  `json:"account_id,omitempty"`
+	IncludePending bool                        `json:"include_pending"`         // transactions en attente comptées ; les planifiées ne le sont jamais
+	Currency       string                      `json:"currency" example:"XAF"`  // devise de référence des montants
+	MissingRates   []string                    `json:"missing_rates,omitempty"` // devises sans taux de change vers la devise de référence, exclues des montants
+	Totals         TransactionStatsTotals      `json:"totals"`
+	Series         []*TransactionStatsBucket   `json:"series"`
+	ByCategory     []*CategoryStats            `json:"by_category"`
+	ByAccount      []*AccountStats             `json:"by_account"`
+	ByTag          []*TagStats                 `json:"by_tag"`
+	Previous       *TransactionStatsComparison `json:"previous"`
+}
+
+// AccountBalanceResponse représente les soldes d'un compte : courant (transactions passées et rapprochées),
+// en attente et disponible (courant et en attente)
+type AccountBalanceResponse struct {
+	AccountID uuid.UUID `json:"account_id"`
+	Balance   Money     `json:"balance"`
+	Pending   Money     `json:"pending"`
+	Available Money     `json:"available"`
+}
+
+// AccountBalanceHistoryResponse représente l'évolution du solde courant d'un compte sur une période,
+// un point par intervalle
+type AccountBalanceHistoryResponse struct {
+	AccountID      uuid.UUID              `json:"account_id"`
+	Currency       string                 `json:"currency" example:"XAF"`
+	Interval       string                 `json:"interval" example:"day"` // day, week, month
+	From           time.Time              `json:"from"`
+	To             time.Time              `json:"to"`
+	OpeningBalance Money                  `json:"opening_balance"` // solde la veille du début de la période
+	Points         []*BalanceHistoryPoint `json:"points"`
+}
+
+// BalanceHistoryPoint représente le solde d'un compte à la fin d'un intervalle et sa variation sur l'intervalle
+type BalanceHistoryPoint struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Balance Money     `json:"balance"`
+	Change  Money     `json:"change"`
+}
+
+// AccountBalancesAsOfResponse représente le solde courant de chaque compte à la fin d'une journée
+type AccountBalancesAsOfResponse struct {
+	Date     time.Time           `json:"date"`
+	Accounts []*AccountBalanceAt `json:"accounts"`
+}
+
+// AccountBalanceAt représente le solde courant d'un compte à la fin d'une journée
+type AccountBalanceAt struct {
+	AccountID   uuid.UUID `json:"account_id"`
+	AccountName string    `json:"account_name" example:"MTN MoMo"`
+	Type        string    `json:"type" example:"mobile_money"`
+	Currency    string    `json:"currency" example:"XAF"`
+	Balance     Money     `json:"balance"`
+}
+
+// AfterScan rattache la devise du compte à son solde
+func (b *AccountBalanceAt) AfterScan(ctx context.Context) error {
+	b.Balance.Currency = b.Currency
+	return nil
+}
+
+// ReconciliationSummaryResponse représente l'état d'un rapprochement : le solde calculé à la date du relevé
+// (solde courant moins les transactions passées datées après), l'écart avec le relevé et les transactions
+// encore en attente jusqu'à cette date
+type ReconciliationSummaryResponse struct {
+	Reconciliation  *Reconciliation `json:"reconciliation"`
+	ComputedBalance Money           `json:"computed_balance"`
+	Difference      Money           `json:"difference"` // solde du relevé moins solde calculé
+	UnclearedTotal  Money           `json:"uncleared_total"`
+	Uncleared       []*Transaction  `json:"uncleared"`
+}
+
+// LoanProjectionResponse représente la projection du remboursement d'un emprunt à partir du capital restant dû,
+// au rythme de l'échéancier et avec un versement supplémentaire mensuel éventuel
+type LoanProjectionResponse struct {
+	OutstandingPrincipal  Money     `json:"outstanding_principal"`
+	Payment               Money     `json:"payment"`
+	ExtraPayment          Money     `json:"extra_payment"`
+	RemainingInstallments int       `json:"remaining_installments"`
+	PayoffDate            time.Time `json:"payoff_date"`
+	TotalInterest         Money     `json:"total_interest"` // intérêts restant à payer
+	ScheduledPayoffDate   time.Time `json:"scheduled_payoff_date"`
+	ScheduledInterest     Money     `json:"scheduled_interest"`
+	InterestSaved         Money     `json:"interest_saved"`
+	MonthsSaved           int       `json:"months_saved"`
+}
+
+// LoanSummary représente l'état du remboursement d'un emprunt : prochaine échéance, échéances impayées et date de
+// fin prévue par l'échéancier
+type LoanSummary struct {
+	LoanID               uuid.UUID        `json:"loan_id"`
+	Name                 string           `json:"name" example:"Prêt immobilier"`
+	Payment              Money            `json:"payment"`
+	OutstandingPrincipal Money            `json:"outstanding_principal"`
+	NextInstallment      *LoanInstallment `json:"next_installment,omitempty"`
+	MissedInstallments   int              `json:"missed_installments"`
+	PayoffDate           *time.Time       `json:"payoff_date,omitempty"`
+}
+
+// LoanReminder représente une échéance d'emprunt impayée ou à venir
+type LoanReminder struct {
+	LoanID      uuid.UUID        `json:"loan_id"`
+	LoanName    string           `json:"loan_name" example:"Prêt immobilier"`
+	Installment *LoanInstallment `json:"installment"`
+	DaysUntil   int              `json:"days_until"` // négatif pour une échéance impayée
+}
+
+// TontinePosition représente la position nette de l'utilisateur dans une tontine : cagnottes reçues moins
+// cotisations versées, négative tant que son tour n'est pas passé
+type TontinePosition struct {
+	TontineID        uuid.UUID            `json:"tontine_id"`
+	Name             string               `json:"name" example:"Njangi du quartier"`
+	TotalContributed Money                `json:"total_contributed"`
+	TotalReceived    Money                `json:"total_received"`
+	NetPosition      Money                `json:"net_position"`
+	PayoutCycle      int                  `json:"payout_cycle"` // tour où l'utilisateur reçoit la cagnotte
+	PayoutDate       time.Time            `json:"payout_date"`  // date de ce tour
+	PayoutReceived   bool                 `json:"payout_received"`
+	NextContribution *TontineContribution `json:"next_contribution,omitempty"` // prochaine cotisation due par l'utilisateur
+}
+
+// TontineReminder représente une cotisation de l'utilisateur en retard ou à venir
+type TontineReminder struct {
+	TontineID    uuid.UUID            `json:"tontine_id"`
+	TontineName  string               `json:"tontine_name" example:"Njangi du quartier"`
+	Contribution *TontineContribution `json:"contribution"`
+	DaysUntil    int                  `json:"days_until"` // négatif pour une cotisation en retard
+}
+
 type AccountResponse struct {
 	ID       uuid.UUID `json:"id"`
 	Name     string    `json:"name"`
 	Type     string    `json:"type"`
-	Balance  float64   `json:"balance"`
+	Balance  Money     `json:"balance"`
 	Currency string    `json:"currency"`
 }
 
 type BudgetResponse struct {
 	ID            uuid.UUID `json:"id"`
 	CategoryID    uuid.UUID `json:"category_id"`
-	AmountPlanned float64   `json:"amount_planned"`
-	AmountSpent   float64   `json:"amount_spent"`
+	AmountPlanned Money     `json:"amount_planned"`
+	AmountSpent   Money     `json:"amount_spent"`
 	Month         int       `json:"month"`
 	Year          int       `json:"year"`
 	Progress      float64   `json:"progress"` // AmountSpent / AmountPlanned * 100
@@ -57,8 +452,8 @@ type BudgetResponse struct {
 type SavingGoalResponse struct {
 	ID            uuid.UUID  `json:"id"`
 	Title         string     `json:"title"`
-	TargetAmount  float64    `json:"target_amount"`
-	CurrentAmount float64    `json:"current_amount"`
+	TargetAmount  Money      `json:"target_amount"`
+	CurrentAmount Money      `json:"current_amount"`
 	Deadline      *time.Time `json:"deadline,omitempty"`
 	IsAchieved    bool       `json:"is_achieved"`
 	Progress      float64    `json:"progress"` // CurrentAmount / TargetAmount * 100
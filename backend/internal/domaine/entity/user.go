@@ -14,7 +14,8 @@ type User struct {
 	Name         string     `pg:"name,notnull" json:"name" validate:"required,min=2,max=100" example:"John Doe"`
 	Email        string     `pg:"email,unique,notnull" json:"email" validate:"required,email" example:"john.doe@example.com"`
 	Avatar       string     `pg:"avatar,default:''" json:"avatar,omitempty" example:"https://example.com/avatar.jpg"`
-	PasswordHash string     `pg:"password_hash,notnull" json:"-" validate:"required"` // Le hash du mot de passe n'est jamais exposé en JSON
+	PasswordHash string     `pg:"password_hash,notnull" json:"-" validate:"required"`             // Le hash du mot de passe n'est jamais exposé en JSON
+	BaseCurrency string     `pg:"base_currency,default:'XAF'" json:"base_currency" example:"XAF"` // devise des totaux convertis
 	CreatedAt    time.Time  `pg:"created_at,default:now()" json:"created_at" example:"2023-01-01T12:00:00Z"`
 	UpdatedAt    time.Time  `pg:"updated_at,default:now()" json:"updated_at" example:"2023-01-01T12:00:00Z"`
 	DeletedAt    *time.Time `pg:"deleted_at,soft_delete" json:"-"`
@@ -9,6 +9,14 @@ import (
 	"github.com/google/uuid"
 )
 
+// TRANSACTION MANAGER
+// TxManager exécute plusieurs opérations de repositories dans une même transaction SQL
+type TxManager interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	// AfterCommit diffère une action hors base de données (suppression de fichiers...) après la validation
+	AfterCommit(ctx context.Context, fn func())
+}
+
 // USER
 type UserRepository interface {
 	Create(ctx context.Context, user *entity.User) error
@@ -48,32 +56,177 @@ type HabitRepository interface {
 type AccountRepository interface {
 	Create(ctx context.Context, account *entity.Account) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.Account, error)
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Account, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Account, error)
 	Update(ctx context.Context, account *entity.Account) error
 	Delete(ctx context.Context, id uuid.UUID) error
-	GetBalanceByUserID(ctx context.Context, userID uuid.UUID) (float64, error)
+	GetDeletedByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Account, error)
+	Restore(ctx context.Context, id uuid.UUID) error
+}
+
+// ACCOUNT BALANCE HISTORY
+// AccountBalanceHistoryRepository tient les soldes courants de fin de journée des comptes
+type AccountBalanceHistoryRepository interface {
+	ApplyChange(ctx context.Context, accountID uuid.UUID, date time.Time, delta entity.Money, balanceBefore entity.Money) error
+	GetRange(ctx context.Context, accountID uuid.UUID, from, to time.Time) ([]*entity.AccountBalanceSnapshot, error)
+	GetBalanceAt(ctx context.Context, accountID uuid.UUID, date time.Time) (entity.Money, error)
+	GetBalancesAt(ctx context.Context, userID uuid.UUID, date time.Time) ([]*entity.AccountBalanceAt, error)
+	DeleteByAccountID(ctx context.Context, accountID uuid.UUID) error
+}
+
+// EXCHANGE RATE
+// ExchangeRateRepository tient les taux de change datés des utilisateurs
+type ExchangeRateRepository interface {
+	Upsert(ctx context.Context, rate *entity.ExchangeRate) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.ExchangeRate, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, currency string) ([]*entity.ExchangeRate, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// LOAN
+// LoanRepository tient les emprunts et leurs échéanciers
+type LoanRepository interface {
+	Create(ctx context.Context, loan *entity.Loan) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Loan, error)
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Loan, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Loan, error)
+	Update(ctx context.Context, loan *entity.Loan) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	CreateInstallments(ctx context.Context, installments []*entity.LoanInstallment) error
+	GetInstallments(ctx context.Context, loanID uuid.UUID) ([]*entity.LoanInstallment, error)
+	UpdateInstallment(ctx context.Context, installment *entity.LoanInstallment) error
+	DeleteUnpaidInstallments(ctx context.Context, loanID uuid.UUID) error
+	GetUnpaidInstallments(ctx context.Context, userID uuid.UUID) ([]*entity.LoanInstallment, error)
+	GetInstallmentsToRemind(ctx context.Context, dueBefore time.Time, limit int) ([]*entity.LoanInstallment, error)
+	GetNewlyMissedInstallments(ctx context.Context, today time.Time, limit int) ([]*entity.LoanInstallment, error)
+}
+
+// TONTINE
+// TontineRepository tient les tontines, leurs membres, leurs tours et les cotisations de chaque tour
+type TontineRepository interface {
+	Create(ctx context.Context, tontine *entity.Tontine) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Tontine, error)
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Tontine, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Tontine, error)
+	Update(ctx context.Context, tontine *entity.Tontine) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	CreateMembers(ctx context.Context, members []*entity.TontineMember) error
+	CreateCycles(ctx context.Context, cycles []*entity.TontineCycle) error
+	CreateContributions(ctx context.Context, contributions []*entity.TontineContribution) error
+	GetCycle(ctx context.Context, tontineID uuid.UUID, number int) (*entity.TontineCycle, error)
+	UpdateCycle(ctx context.Context, cycle *entity.TontineCycle) error
+	UpdateContribution(ctx context.Context, contribution *entity.TontineContribution) error
+	GetPendingSelfContributions(ctx context.Context, userID uuid.UUID) ([]*entity.TontineContribution, error)
+	GetContributionsToRemind(ctx context.Context, dueBefore time.Time, limit int) ([]*entity.TontineContribution, error)
+}
+
+// RECURRING TRANSACTION
+type RecurringTransactionRepository interface {
+	Create(ctx context.Context, recurring *entity.RecurringTransaction) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.RecurringTransaction, error)
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.RecurringTransaction, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.RecurringTransaction, error)
+	GetDueIDs(ctx context.Context, date time.Time, limit int) ([]uuid.UUID, error)
+	Update(ctx context.Context, recurring *entity.RecurringTransaction) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// ATTACHMENT
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment *entity.TransactionAttachment) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.TransactionAttachment, error)
+	GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*entity.TransactionAttachment, error)
+	GetByTransactionIDs(ctx context.Context, transactionIDs []uuid.UUID) ([]*entity.TransactionAttachment, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// TAG
+type TagRepository interface {
+	Create(ctx context.Context, tag *entity.Tag) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Tag, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Tag, error)
+	GetByIDs(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) ([]*entity.Tag, error)
+	GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*entity.Tag, error)
+	Update(ctx context.Context, tag *entity.Tag) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	AddToTransactions(ctx context.Context, transactionIDs, tagIDs []uuid.UUID) (int, error)
+	RemoveFromTransactions(ctx context.Context, transactionIDs, tagIDs []uuid.UUID) (int, error)
+	ReplaceTransactionTags(ctx context.Context, transactionID uuid.UUID, tagIDs []uuid.UUID) error
+}
+
+// IMPORT BATCH
+type ImportBatchRepository interface {
+	Create(ctx context.Context, batch *entity.ImportBatch) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.ImportBatch, error)
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.ImportBatch, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, accountID *uuid.UUID) ([]*entity.ImportBatch, error)
+	Update(ctx context.Context, batch *entity.ImportBatch) error
+}
+
+// RECONCILIATION
+type ReconciliationRepository interface {
+	Create(ctx context.Context, reconciliation *entity.Reconciliation) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Reconciliation, error)
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Reconciliation, error)
+	GetOpenByAccountID(ctx context.Context, accountID uuid.UUID) (*entity.Reconciliation, error)
+	GetByAccountID(ctx context.Context, accountID uuid.UUID) ([]*entity.Reconciliation, error)
+	Update(ctx context.Context, reconciliation *entity.Reconciliation) error
 }
 
 // TRANSACTION
 type TransactionRepository interface {
 	Create(ctx context.Context, transaction *entity.Transaction) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.Transaction, error)
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Transaction, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Transaction, error)
+	GetByIDs(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) ([]*entity.Transaction, error)
+	List(ctx context.Context, userID uuid.UUID, filter *entity.TransactionFilter) ([]*entity.Transaction, error)
+	Count(ctx context.Context, userID uuid.UUID, filter *entity.TransactionFilter) (int64, error)
+	GetSplits(ctx context.Context, transactionID uuid.UUID) ([]*entity.TransactionSplit, error)
+	ReplaceSplits(ctx context.Context, transactionID uuid.UUID, splits []*entity.TransactionSplit) error
+	GetExistingExternalRefs(ctx context.Context, accountID uuid.UUID, refs []string) ([]string, error)
+	GetByImportBatchID(ctx context.Context, importBatchID uuid.UUID) ([]*entity.Transaction, error)
+	GetDueScheduledIDs(ctx context.Context, date time.Time, limit int) ([]uuid.UUID, error)
+	GetByAccountAndStatus(ctx context.Context, accountID uuid.UUID, status string, through time.Time) ([]*entity.Transaction, error)
+	GetClearedEffectAfter(ctx context.Context, accountID uuid.UUID, date time.Time) (int64, error)
+	GetSimilar(ctx context.Context, accountID uuid.UUID, txType string, amount entity.Money, from, to time.Time) ([]*entity.Transaction, error)
+	GetForDuplicateScan(ctx context.Context, userID uuid.UUID, since *time.Time) ([]*entity.Transaction, error)
+	GetStatsTotals(ctx context.Context, userID uuid.UUID, filter *entity.TransactionStatsFilter) (*entity.TransactionStatsTotals, error)
+	GetStatsSeries(ctx context.Context, userID uuid.UUID, filter *entity.TransactionStatsFilter, granularity string) ([]*entity.TransactionStatsBucket, error)
+	GetStatsByCategory(ctx context.Context, userID uuid.UUID, filter *entity.TransactionStatsFilter) ([]*entity.CategoryStatsLine, error)
+	GetStatsByAccount(ctx context.Context, userID uuid.UUID, filter *entity.TransactionStatsFilter) ([]*entity.AccountStats, error)
+	GetStatsByTag(ctx context.Context, userID uuid.UUID, filter *entity.TransactionStatsFilter) ([]*entity.TagStats, error)
+	GetStatsMissingRates(ctx context.Context, userID uuid.UUID, filter *entity.TransactionStatsFilter) ([]string, error)
 	Update(ctx context.Context, transaction *entity.Transaction) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	GetDeletedByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Transaction, error)
+	GetDeletedByTransferGroupIDForUpdate(ctx context.Context, transferGroupID uuid.UUID) ([]*entity.Transaction, error)
+	Restore(ctx context.Context, id uuid.UUID) error
+	TrashByAccountID(ctx context.Context, accountID uuid.UUID, deletedAt time.Time) (int, error)
+	RestoreByAccountID(ctx context.Context, accountID uuid.UUID) (int, error)
 	GetByCategoryID(ctx context.Context, userID uuid.UUID, categoryID *uuid.UUID) ([]*entity.Transaction, error)
 	GetByAccountID(ctx context.Context, userID uuid.UUID, accountID *uuid.UUID) ([]*entity.Transaction, error)
 	GetByAccountIDWithCategoryDetails(ctx context.Context, userID uuid.UUID, accountID *uuid.UUID) ([]*entity.Transaction, error)
 	GetBySavingGoalID(ctx context.Context, userID uuid.UUID, savingGoalID uuid.UUID) ([]*entity.Transaction, error)
 	GetTransfers(ctx context.Context, userID uuid.UUID) ([]*entity.Transaction, error)
+	GetByTransferGroupIDForUpdate(ctx context.Context, transferGroupID uuid.UUID) ([]*entity.Transaction, error)
+	GetRefunds(ctx context.Context, originalID uuid.UUID) ([]*entity.Transaction, error)
+	StreamForExport(ctx context.Context, userID uuid.UUID, filter *entity.TransactionFilter, fn func(*entity.TransactionExportRow) error) error
 	GetByDateRange(ctx context.Context, userID uuid.UUID, startDate, endDate string) ([]*entity.Transaction, error)
 	GetByType(ctx context.Context, userID uuid.UUID, txType string) ([]*entity.Transaction, error)
-	GetTotalByUserID(ctx context.Context, userID uuid.UUID) (float64, error)
 	GetAllTransactionsByUserIDAndAccountID(ctx context.Context, userID uuid.UUID, accountID uuid.UUID) ([]*entity.Transaction, error)
 	//get All transaction by saving goal id
 	GetAllTransactionsBySavingGoalID(ctx context.Context, userID uuid.UUID, savingGoalID uuid.UUID) ([]*entity.Transaction, error)
 }
 
+// TRANSACTION VERSION
+type TransactionVersionRepository interface {
+	Create(ctx context.Context, version *entity.TransactionVersion) error
+	GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*entity.TransactionVersion, error)
+	GetByVersion(ctx context.Context, transactionID uuid.UUID, version int) (*entity.TransactionVersion, error)
+	GetLatest(ctx context.Context, transactionID uuid.UUID) (*entity.TransactionVersion, error)
+}
+
 // CATEGORY
 type CategoryRepository interface {
 	Create(ctx context.Context, category *entity.Category) error
@@ -100,6 +253,8 @@ type BudgetRepository interface {
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Budget, error)
 	Update(ctx context.Context, budget *entity.Budget) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	GetDeletedByID(ctx context.Context, id uuid.UUID) (*entity.Budget, error)
+	Restore(ctx context.Context, id uuid.UUID) error
 	GetByCategoryID(ctx context.Context, userID uuid.UUID, categoryID uuid.UUID) ([]*entity.Budget, error)
 	GetByPeriod(ctx context.Context, userID uuid.UUID, period string) ([]*entity.Budget, error)
 	GetByName(ctx context.Context, userID uuid.UUID, name string) ([]*entity.Budget, error)
@@ -109,9 +264,14 @@ type BudgetRepository interface {
 type SavingGoalRepository interface {
 	Create(ctx context.Context, goal *entity.SavingGoal) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.SavingGoal, error)
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.SavingGoal, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.SavingGoal, error)
 	Update(ctx context.Context, goal *entity.SavingGoal) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	GetDeletedByID(ctx context.Context, id uuid.UUID) (*entity.SavingGoal, error)
+	Restore(ctx context.Context, id uuid.UUID) error
+	TrashByAccountID(ctx context.Context, accountID uuid.UUID, deletedAt time.Time) (int, error)
+	RestoreByAccountID(ctx context.Context, accountID uuid.UUID) (int, error)
 	GetAchievedByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.SavingGoal, error)
 	GetActiveByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.SavingGoal, error)
 	GetByAccountID(ctx context.Context, userID uuid.UUID, accountID uuid.UUID) ([]*entity.SavingGoal, error)
@@ -119,6 +279,13 @@ type SavingGoalRepository interface {
 	GetAllSavingGoalsByUserIDAndAccountID(ctx context.Context, userID uuid.UUID, accountID uuid.UUID) ([]*entity.SavingGoal, error)
 }
 
+// TRASH
+// TrashRepository liste et purge la corbeille (transactions, comptes, budgets et objectifs d'épargne supprimés)
+type TrashRepository interface {
+	List(ctx context.Context, userID uuid.UUID) ([]*entity.TrashItem, error)
+	Purge(ctx context.Context, before time.Time) (*entity.TrashPurgeResult, error)
+}
+
 // REMINDER
 type ReminderRepository interface {
 	Create(ctx context.Context, reminder *entity.Reminder) error
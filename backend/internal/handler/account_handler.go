@@ -3,10 +3,12 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"backend/internal/domaine/entity"
+	"backend/internal/service"
 	"backend/pkg/logger"
 	"backend/pkg/response"
 
@@ -18,6 +20,7 @@ import (
 type AccountHandler struct {
 	accountService     AccountService
 	transactionService TransactionService
+	closureService     AccountClosureService
 	logger             logger.Logger
 }
 
@@ -30,21 +33,30 @@ type AccountDetails struct {
 type AccountService interface {
 	CreateAccount(ctx context.Context, userID uuid.UUID, req entity.CreateAccountRequest) (*entity.Account, error)
 	GetAccount(ctx context.Context, userID, accountID uuid.UUID) (*entity.Account, error)
-	GetAccounts(ctx context.Context, userID uuid.UUID, page, limit int) ([]*entity.Account, int64, error)
+	GetAccounts(ctx context.Context, userID uuid.UUID, page, limit int, includeArchived bool) ([]*entity.Account, int64, error)
 	UpdateAccount(ctx context.Context, userID, accountID uuid.UUID, req entity.UpdateAccountRequest) (*entity.Account, error)
-	DeleteAccount(ctx context.Context, userID, accountID uuid.UUID) error
-	GetAccountBalance(ctx context.Context, userID, accountID uuid.UUID) (float64, error)
+	DeleteAccount(ctx context.Context, userID, accountID uuid.UUID, force bool) error
+	GetAccountBalance(ctx context.Context, userID, accountID uuid.UUID) (*entity.AccountBalanceResponse, error)
+	GetBalanceHistory(ctx context.Context, userID, accountID uuid.UUID, query service.BalanceHistoryQuery) (*entity.AccountBalanceHistoryResponse, error)
+	GetBalancesAsOf(ctx context.Context, userID uuid.UUID, date string) (*entity.AccountBalancesAsOfResponse, error)
 }
 
 type TransactionService interface {
 	GetTransactionsByAccountID(ctx context.Context, userID, accountID uuid.UUID) ([]*entity.Transaction, error)
 }
 
+// AccountClosureService interface pour la clôture et la réouverture des comptes
+type AccountClosureService interface {
+	CloseAccount(ctx context.Context, userID, accountID uuid.UUID, req entity.CloseAccountRequest) (*entity.Account, error)
+	ReopenAccount(ctx context.Context, userID, accountID uuid.UUID) (*entity.Account, error)
+}
+
 // NewAccountHandler crée une nouvelle instance de AccountHandler
-func NewAccountHandler(accountService AccountService, transactionService TransactionService, logger logger.Logger) *AccountHandler {
+func NewAccountHandler(accountService AccountService, transactionService TransactionService, closureService AccountClosureService, logger logger.Logger) *AccountHandler {
 	return &AccountHandler{
 		accountService:     accountService,
 		transactionService: transactionService,
+		closureService:     closureService,
 		logger:             logger,
 	}
 }
@@ -137,6 +149,7 @@ func (h *AccountHandler) GetAccount(w http.ResponseWriter, r *http.Request) {
 // @Param type query string false "Type de compte (checking/savings/mobile_money)"
 // @Param page query int false "Numéro de page" default(1)
 // @Param limit query int false "Nombre d'éléments par page" default(10)
+// @Param include_archived query bool false "Inclure les comptes clôturés" default(false)
 // @Success 200 {object} response.Response "Comptes récupérés"
 // @Failure 401 {object} response.ErrorResponse "Non authentifié"
 // @Failure 500 {object} response.ErrorResponse "Erreur serveur"
@@ -166,7 +179,16 @@ func (h *AccountHandler) GetAccounts(w http.ResponseWriter, r *http.Request) {
 		limit = 100
 	}
 
-	accounts, total, err := h.accountService.GetAccounts(r.Context(), userID, page, limit)
+	includeArchived := false
+	if value := query.Get("include_archived"); value != "" {
+		var err error
+		if includeArchived, err = strconv.ParseBool(value); err != nil {
+			response.Error(w, http.StatusBadRequest, "Paramètre include_archived invalide", err)
+			return
+		}
+	}
+
+	accounts, total, err := h.accountService.GetAccounts(r.Context(), userID, page, limit, includeArchived)
 	if err != nil {
 		h.logger.Error("Erreur récupération comptes", logger.Error(err))
 		response.Error(w, http.StatusInternalServerError, "Erreur récupération comptes", err)
@@ -234,16 +256,18 @@ func (h *AccountHandler) UpdateAccount(w http.ResponseWriter, r *http.Request) {
 
 // DeleteAccount supprime un compte
 // @Summary Supprimer un compte
-// @Description Supprime un compte existant
+// @Description Met un compte à la corbeille avec ses transactions et objectifs d'épargne, sans modifier les soldes ; il peut être restauré jusqu'à sa purge. Un compte ayant des transactions n'est supprimé qu'avec force=true : le clôturer conserve son historique.
 // @Tags accounts
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "ID du compte"
+// @Param force query bool false "Supprimer même si le compte a des transactions" default(false)
 // @Success 200 {object} response.Response "Compte supprimé"
 // @Failure 400 {object} response.ErrorResponse "ID invalide"
 // @Failure 401 {object} response.ErrorResponse "Non authentifié"
 // @Failure 404 {object} response.ErrorResponse "Compte non trouvé"
+// @Failure 409 {object} response.ErrorResponse "Le compte a des transactions"
 // @Failure 500 {object} response.ErrorResponse "Erreur serveur"
 // @Router /accounts/{id} [delete]
 func (h *AccountHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
@@ -262,8 +286,20 @@ func (h *AccountHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.accountService.DeleteAccount(r.Context(), userID, accountID)
+	force := false
+	if value := r.URL.Query().Get("force"); value != "" {
+		if force, err = strconv.ParseBool(value); err != nil {
+			response.Error(w, http.StatusBadRequest, "Paramètre force invalide", err)
+			return
+		}
+	}
+
+	err = h.accountService.DeleteAccount(r.Context(), userID, accountID, force)
 	if err != nil {
+		if errors.Is(err, entity.ErrAccountHasTransactions) {
+			response.Error(w, http.StatusConflict, "Le compte a des transactions", err)
+			return
+		}
 		h.logger.Error("Erreur suppression compte", logger.Error(err))
 		response.Error(w, http.StatusInternalServerError, "Erreur suppression compte", err)
 		return
@@ -272,15 +308,116 @@ func (h *AccountHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, http.StatusOK, "Compte supprimé avec succès", nil)
 }
 
+// CloseAccount clôture un compte
+// @Summary Clôturer un compte
+// @Description Solde le compte par un transfert vers (ou depuis) un autre compte, suspend ses transactions récurrentes et l'archive : il est masqué des listes par défaut et ne reçoit plus de transactions, mais reste dans l'historique et les rapports
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID du compte"
+// @Param closure body entity.CloseAccountRequest true "Clôture"
+// @Success 200 {object} response.Response{data=entity.Account} "Compte clôturé"
+// @Failure 400 {object} response.ErrorResponse "Données invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Compte non trouvé"
+// @Failure 409 {object} response.ErrorResponse "Compte déjà clôturé"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /accounts/{id}/close [post]
+func (h *AccountHandler) CloseAccount(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	// Récupérer l'ID du compte depuis l'URL
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID de compte invalide", err)
+		return
+	}
+
+	var req entity.CloseAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Erreur décodage JSON", logger.Error(err))
+		response.Error(w, http.StatusBadRequest, "Données JSON invalides", err)
+		return
+	}
+
+	account, err := h.closureService.CloseAccount(r.Context(), userID, accountID, req)
+	if err != nil {
+		h.writeClosureError(w, err, "Erreur clôture compte")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Compte clôturé avec succès", account)
+}
+
+// ReopenAccount rouvre un compte clôturé
+// @Summary Rouvrir un compte
+// @Description Rouvre un compte clôturé ; ses transactions récurrentes restent suspendues
+// @Tags accounts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID du compte"
+// @Success 200 {object} response.Response{data=entity.Account} "Compte rouvert"
+// @Failure 400 {object} response.ErrorResponse "ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Compte non trouvé"
+// @Failure 409 {object} response.ErrorResponse "Compte non clôturé"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /accounts/{id}/reopen [post]
+func (h *AccountHandler) ReopenAccount(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	// Récupérer l'ID du compte depuis l'URL
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID de compte invalide", err)
+		return
+	}
+
+	account, err := h.closureService.ReopenAccount(r.Context(), userID, accountID)
+	if err != nil {
+		h.writeClosureError(w, err, "Erreur réouverture compte")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Compte rouvert avec succès", account)
+}
+
+// writeClosureError traduit les erreurs de clôture d'un compte en codes HTTP
+func (h *AccountHandler) writeClosureError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, entity.ErrAccountNotFound):
+		response.Error(w, http.StatusNotFound, "Compte non trouvé", err)
+	case errors.Is(err, entity.ErrAccountArchived), errors.Is(err, entity.ErrAccountNotArchived),
+		errors.Is(err, entity.ErrReconciliationPeriodLocked):
+		response.Error(w, http.StatusConflict, message, err)
+	case errors.Is(err, entity.ErrInvalidAccountClosure), errors.Is(err, entity.ErrCurrencyMismatch):
+		response.Error(w, http.StatusBadRequest, "Données invalides", err)
+	default:
+		h.logger.Error(message, logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, message, err)
+	}
+}
+
 // GetAccountBalance récupère le solde d'un compte
 // @Summary Récupérer le solde d'un compte
-// @Description Récupère le solde actuel d'un compte spécifique
+// @Description Récupère les soldes d'un compte : courant (transactions passées et rapprochées), en attente et disponible (courant et en attente)
 // @Tags accounts
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "ID du compte"
-// @Success 200 {object} response.Response "Solde récupéré"
+// @Success 200 {object} response.Response{data=entity.AccountBalanceResponse} "Solde récupéré"
 // @Failure 400 {object} response.ErrorResponse "ID invalide"
 // @Failure 401 {object} response.ErrorResponse "Non authentifié"
 // @Failure 404 {object} response.ErrorResponse "Compte non trouvé"
@@ -309,9 +446,96 @@ func (h *AccountHandler) GetAccountBalance(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	response.Success(w, http.StatusOK, "Solde récupéré avec succès", map[string]interface{}{
-		"balance": balance,
-	})
+	response.Success(w, http.StatusOK, "Solde récupéré avec succès", balance)
+}
+
+// GetBalanceHistory récupère l'historique du solde d'un compte
+// @Summary Récupérer l'historique du solde d'un compte
+// @Description Retrace le solde courant (transactions passées et rapprochées) d'un compte sur une période, un point par jour, par semaine (du lundi) ou par mois civil, avec le solde en fin d'intervalle et sa variation
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID du compte"
+// @Param from query string false "Date de début (YYYY-MM-DD), déduite de l'intervalle par défaut"
+// @Param to query string false "Date de fin incluse (YYYY-MM-DD), aujourd'hui par défaut"
+// @Param interval query string false "Intervalle des points (day/week/month)" default(day)
+// @Success 200 {object} response.Response{data=entity.AccountBalanceHistoryResponse} "Historique récupéré"
+// @Failure 400 {object} response.ErrorResponse "Paramètres invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Compte non trouvé"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /accounts/{id}/history [get]
+func (h *AccountHandler) GetBalanceHistory(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	// Récupérer l'ID du compte depuis l'URL
+	accountIDStr := chi.URLParam(r, "id")
+	accountID, err := uuid.Parse(accountIDStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID de compte invalide", err)
+		return
+	}
+
+	values := r.URL.Query()
+	query := service.BalanceHistoryQuery{
+		From:     values.Get("from"),
+		To:       values.Get("to"),
+		Interval: values.Get("interval"),
+	}
+
+	history, err := h.accountService.GetBalanceHistory(r.Context(), userID, accountID, query)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidBalanceHistoryQuery) {
+			response.Error(w, http.StatusBadRequest, "Paramètres invalides", err)
+			return
+		}
+		h.logger.Error("Erreur récupération historique du solde", logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Erreur récupération historique du solde", err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Historique du solde récupéré avec succès", history)
+}
+
+// GetBalancesAsOf récupère les soldes des comptes à une date
+// @Summary Récupérer les soldes des comptes à une date
+// @Description Récupère le solde courant (transactions passées et rapprochées) de chaque compte de l'utilisateur à la fin d'une journée
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param date query string false "Date (YYYY-MM-DD), aujourd'hui par défaut"
+// @Success 200 {object} response.Response{data=entity.AccountBalancesAsOfResponse} "Soldes récupérés"
+// @Failure 400 {object} response.ErrorResponse "Date invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /accounts/balances [get]
+func (h *AccountHandler) GetBalancesAsOf(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	balances, err := h.accountService.GetBalancesAsOf(r.Context(), userID, r.URL.Query().Get("date"))
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidBalanceHistoryQuery) {
+			response.Error(w, http.StatusBadRequest, "Date invalide", err)
+			return
+		}
+		h.logger.Error("Erreur récupération soldes à date", logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Erreur récupération soldes à date", err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Soldes récupérés avec succès", balances)
 }
 
 func (h *AccountHandler) GetAccountDetails(w http.ResponseWriter, r *http.Request) {
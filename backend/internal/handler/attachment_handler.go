@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"backend/internal/domaine/entity"
+	"backend/internal/service"
+	"backend/pkg/logger"
+	"backend/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// multipartOverhead est la marge accordée aux en-têtes d'un formulaire multipart au-delà du fichier
+const multipartOverhead = 1 << 20
+
+// AttachmentHandler gère les requêtes HTTP pour les pièces jointes des transactions
+type AttachmentHandler struct {
+	attachmentService *service.AttachmentService
+	logger            logger.Logger
+}
+
+// NewAttachmentHandler crée une nouvelle instance de AttachmentHandler
+func NewAttachmentHandler(attachmentService *service.AttachmentService, logger logger.Logger) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+		logger:            logger,
+	}
+}
+
+// UploadAttachment ajoute une pièce jointe à une transaction
+// @Summary Ajouter une pièce jointe
+// @Description Ajoute une photo de reçu (JPEG, PNG, GIF, WebP) ou un PDF à une transaction
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la transaction"
+// @Param file formData file true "Fichier à joindre"
+// @Success 201 {object} response.Response{data=entity.TransactionAttachment} "Pièce jointe ajoutée"
+// @Failure 400 {object} response.ErrorResponse "Fichier invalide ou type non autorisé"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Transaction non trouvée"
+// @Failure 413 {object} response.ErrorResponse "Fichier trop volumineux"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /transactions/{id}/attachments [post]
+func (h *AttachmentHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID de transaction invalide", err)
+		return
+	}
+
+	maxSize := h.attachmentService.MaxFileSize()
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+multipartOverhead)
+	if err := r.ParseMultipartForm(maxSize + multipartOverhead); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.writeError(w, fmt.Errorf("%w: %d Mo maximum", entity.ErrFileTooLarge, maxSize>>20), "Erreur ajout pièce jointe")
+			return
+		}
+		response.Error(w, http.StatusBadRequest, "Formulaire multipart invalide", err)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Fichier manquant", err)
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentService.UploadAttachment(r.Context(), userID, transactionID, header.Filename, file)
+	if err != nil {
+		h.writeError(w, err, "Erreur ajout pièce jointe")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Pièce jointe ajoutée avec succès", attachment)
+}
+
+// GetAttachments liste les pièces jointes d'une transaction
+// @Summary Lister les pièces jointes
+// @Description Récupère les pièces jointes d'une transaction
+// @Tags attachments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la transaction"
+// @Success 200 {object} response.Response{data=[]entity.TransactionAttachment} "Pièces jointes récupérées"
+// @Failure 400 {object} response.ErrorResponse "ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Transaction non trouvée"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /transactions/{id}/attachments [get]
+func (h *AttachmentHandler) GetAttachments(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID de transaction invalide", err)
+		return
+	}
+
+	attachments, err := h.attachmentService.GetAttachments(r.Context(), userID, transactionID)
+	if err != nil {
+		h.writeError(w, err, "Erreur récupération pièces jointes")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Pièces jointes récupérées avec succès", attachments)
+}
+
+// DownloadAttachment télécharge une pièce jointe
+// @Summary Télécharger une pièce jointe
+// @Description Retourne le contenu du fichier joint à la transaction
+// @Tags attachments
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param id path string true "ID de la transaction"
+// @Param attachmentID path string true "ID de la pièce jointe"
+// @Param download query bool false "Forcer le téléchargement plutôt que l'affichage"
+// @Success 200 {file} file "Contenu du fichier"
+// @Failure 400 {object} response.ErrorResponse "ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Pièce jointe non trouvée"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /transactions/{id}/attachments/{attachmentID} [get]
+func (h *AttachmentHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	transactionID, attachmentID, err := attachmentPathIDs(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID invalide", err)
+		return
+	}
+
+	attachment, content, err := h.attachmentService.OpenAttachment(r.Context(), userID, transactionID, attachmentID)
+	if err != nil {
+		h.writeError(w, err, "Erreur téléchargement pièce jointe")
+		return
+	}
+	defer content.Close()
+
+	disposition := "inline"
+	if download, _ := strconv.ParseBool(r.URL.Query().Get("download")); download {
+		disposition = "attachment"
+	}
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("%s; filename*=UTF-8''%s", disposition, url.PathEscape(attachment.FileName)))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, content); err != nil {
+		h.logger.Warn("Erreur envoi pièce jointe", logger.String("attachment_id", attachment.ID.String()), logger.Error(err))
+	}
+}
+
+// DeleteAttachment supprime une pièce jointe
+// @Summary Supprimer une pièce jointe
+// @Description Supprime une pièce jointe et son fichier
+// @Tags attachments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la transaction"
+// @Param attachmentID path string true "ID de la pièce jointe"
+// @Success 200 {object} response.Response "Pièce jointe supprimée"
+// @Failure 400 {object} response.ErrorResponse "ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Pièce jointe non trouvée"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /transactions/{id}/attachments/{attachmentID} [delete]
+func (h *AttachmentHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	transactionID, attachmentID, err := attachmentPathIDs(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID invalide", err)
+		return
+	}
+
+	if err := h.attachmentService.DeleteAttachment(r.Context(), userID, transactionID, attachmentID); err != nil {
+		h.writeError(w, err, "Erreur suppression pièce jointe")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Pièce jointe supprimée avec succès", nil)
+}
+
+// attachmentPathIDs lit les IDs de transaction et de pièce jointe de l'URL
+func attachmentPathIDs(r *http.Request) (uuid.UUID, uuid.UUID, error) {
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	attachmentID, err := uuid.Parse(chi.URLParam(r, "attachmentID"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return transactionID, attachmentID, nil
+}
+
+// writeError traduit les erreurs du domaine en codes HTTP
+func (h *AttachmentHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, entity.ErrFileTooLarge):
+		response.Error(w, http.StatusRequestEntityTooLarge, "Fichier trop volumineux", err)
+	case errors.Is(err, entity.ErrInvalidFileType):
+		response.Error(w, http.StatusBadRequest, "Type de fichier non autorisé", err)
+	case errors.Is(err, entity.ErrTooManyAttachments):
+		response.Error(w, http.StatusBadRequest, "Trop de pièces jointes", err)
+	case errors.Is(err, entity.ErrTransactionNotFound):
+		response.Error(w, http.StatusNotFound, "Transaction non trouvée", err)
+	case errors.Is(err, entity.ErrAttachmentNotFound), errors.Is(err, entity.ErrFileNotFound):
+		response.Error(w, http.StatusNotFound, "Pièce jointe non trouvée", err)
+	default:
+		h.logger.Error(message, logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, message, err)
+	}
+}
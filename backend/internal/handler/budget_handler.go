@@ -239,7 +239,7 @@ func (h *BudgetHandler) UpdateBudget(w http.ResponseWriter, r *http.Request) {
 
 // DeleteBudget supprime un budget
 // @Summary Supprimer un budget
-// @Description Supprime un budget existant
+// @Description Met un budget à la corbeille ; il peut être restauré jusqu'à sa purge
 // @Tags budgets
 // @Accept json
 // @Produce json
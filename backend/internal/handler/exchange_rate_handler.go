@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"backend/internal/domaine/entity"
+	"backend/internal/service"
+	"backend/pkg/logger"
+	"backend/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxExchangeRateFileSize est la taille maximale d'un fichier de taux de change importé (1 Mo)
+const maxExchangeRateFileSize = 1 << 20
+
+// ExchangeRateHandler gère les requêtes HTTP pour les devises : devise de référence et taux de change
+type ExchangeRateHandler struct {
+	exchangeRateService *service.ExchangeRateService
+	logger              logger.Logger
+}
+
+// NewExchangeRateHandler crée une nouvelle instance de ExchangeRateHandler
+func NewExchangeRateHandler(exchangeRateService *service.ExchangeRateService, logger logger.Logger) *ExchangeRateHandler {
+	return &ExchangeRateHandler{
+		exchangeRateService: exchangeRateService,
+		logger:              logger,
+	}
+}
+
+// GetBaseCurrency récupère la devise de référence de l'utilisateur
+// @Summary Récupérer la devise de référence
+// @Description Récupère la devise dans laquelle sont convertis les totaux du tableau de bord, des statistiques et des budgets
+// @Tags currencies
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=entity.BaseCurrencyResponse} "Devise de référence"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /currencies/base [get]
+func (h *ExchangeRateHandler) GetBaseCurrency(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	currency, err := h.exchangeRateService.GetBaseCurrency(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, err, "Erreur récupération devise de référence")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Devise de référence récupérée avec succès", entity.BaseCurrencyResponse{Currency: currency})
+}
+
+// SetBaseCurrency change la devise de référence de l'utilisateur
+// @Summary Changer la devise de référence
+// @Description Change la devise dans laquelle sont convertis les totaux ; les montants enregistrés ne sont pas modifiés
+// @Tags currencies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param currency body entity.SetBaseCurrencyRequest true "Devise de référence"
+// @Success 200 {object} response.Response{data=entity.BaseCurrencyResponse} "Devise de référence modifiée"
+// @Failure 400 {object} response.ErrorResponse "Devise invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /currencies/base [put]
+func (h *ExchangeRateHandler) SetBaseCurrency(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	var req entity.SetBaseCurrencyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Erreur décodage JSON", logger.Error(err))
+		response.Error(w, http.StatusBadRequest, "Données JSON invalides", err)
+		return
+	}
+
+	currency, err := h.exchangeRateService.SetBaseCurrency(r.Context(), userID, req)
+	if err != nil {
+		h.writeError(w, err, "Erreur changement devise de référence")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Devise de référence modifiée avec succès", entity.BaseCurrencyResponse{Currency: currency})
+}
+
+// GetRates récupère les taux de change de l'utilisateur
+// @Summary Récupérer les taux de change
+// @Description Récupère les taux de change saisis ou importés, par paire puis par date
+// @Tags currencies
+// @Produce json
+// @Security BearerAuth
+// @Param currency query string false "Limiter aux paires impliquant cette devise (ex: EUR)"
+// @Success 200 {object} response.Response{data=[]entity.ExchangeRate} "Taux de change récupérés"
+// @Failure 400 {object} response.ErrorResponse "Devise invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /currencies/rates [get]
+func (h *ExchangeRateHandler) GetRates(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	rates, err := h.exchangeRateService.GetRates(r.Context(), userID, r.URL.Query().Get("currency"))
+	if err != nil {
+		h.writeError(w, err, "Erreur récupération taux de change")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Taux de change récupérés avec succès", rates)
+}
+
+// CreateRate enregistre un taux de change
+// @Summary Saisir un taux de change
+// @Description Enregistre le taux d'une paire de devises à une date (unités de to_currency pour une unité de from_currency) ; un taux de la même paire à la même date est remplacé. Le taux inverse s'en déduit.
+// @Tags currencies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param rate body entity.CreateExchangeRateRequest true "Taux de change"
+// @Success 201 {object} response.Response{data=entity.ExchangeRate} "Taux de change enregistré"
+// @Failure 400 {object} response.ErrorResponse "Données invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /currencies/rates [post]
+func (h *ExchangeRateHandler) CreateRate(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	var req entity.CreateExchangeRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Erreur décodage JSON", logger.Error(err))
+		response.Error(w, http.StatusBadRequest, "Données JSON invalides", err)
+		return
+	}
+
+	rate, err := h.exchangeRateService.CreateRate(r.Context(), userID, req)
+	if err != nil {
+		h.writeError(w, err, "Erreur enregistrement taux de change")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Taux de change enregistré avec succès", rate)
+}
+
+// ImportRates importe un fichier de taux de change
+// @Summary Importer des taux de change
+// @Description Importe un fichier CSV aux colonnes date (YYYY-MM-DD ou DD/MM/YYYY), devise source, devise cible et taux, séparées par des virgules ou des points-virgules, avec ou sans ligne d'en-tête. Les lignes invalides sont signalées sans interrompre l'import.
+// @Tags currencies
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "Fichier CSV des taux"
+// @Success 201 {object} response.Response{data=entity.ExchangeRateImportResult} "Taux de change importés"
+// @Failure 400 {object} response.ErrorResponse "Fichier invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 413 {object} response.ErrorResponse "Fichier trop volumineux"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /currencies/rates/import [post]
+func (h *ExchangeRateHandler) ImportRates(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxExchangeRateFileSize+multipartOverhead)
+	if err := r.ParseMultipartForm(maxExchangeRateFileSize + multipartOverhead); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.Error(w, http.StatusRequestEntityTooLarge, "Fichier trop volumineux", entity.ErrFileTooLarge)
+			return
+		}
+		response.Error(w, http.StatusBadRequest, "Formulaire multipart invalide", err)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Fichier manquant", err)
+		return
+	}
+	defer file.Close()
+
+	result, err := h.exchangeRateService.ImportRates(r.Context(), userID, file)
+	if err != nil {
+		h.writeError(w, err, "Erreur import taux de change")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Taux de change importés avec succès", result)
+}
+
+// DeleteRate supprime un taux de change
+// @Summary Supprimer un taux de change
+// @Description Supprime un taux de change ; les conversions utilisent alors le taux précédent de la paire
+// @Tags currencies
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID du taux de change"
+// @Success 200 {object} response.Response "Taux de change supprimé"
+// @Failure 400 {object} response.ErrorResponse "ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Taux de change non trouvé"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /currencies/rates/{id} [delete]
+func (h *ExchangeRateHandler) DeleteRate(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	rateID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID de taux de change invalide", err)
+		return
+	}
+
+	if err := h.exchangeRateService.DeleteRate(r.Context(), userID, rateID); err != nil {
+		h.writeError(w, err, "Erreur suppression taux de change")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Taux de change supprimé avec succès", nil)
+}
+
+// writeError traduit les erreurs du domaine en codes HTTP
+func (h *ExchangeRateHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, entity.ErrExchangeRateNotFound):
+		response.Error(w, http.StatusNotFound, "Taux de change non trouvé", err)
+	case errors.Is(err, entity.ErrInvalidExchangeRateData), errors.Is(err, entity.ErrInvalidCurrency):
+		response.Error(w, http.StatusBadRequest, "Données invalides", err)
+	case errors.Is(err, entity.ErrUserNotFound):
+		response.Error(w, http.StatusNotFound, "Utilisateur non trouvé", err)
+	default:
+		h.logger.Error(message, logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, message, err)
+	}
+}
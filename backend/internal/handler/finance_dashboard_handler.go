@@ -6,6 +6,7 @@ import (
 	"backend/pkg/logger"
 	"backend/pkg/response"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,6 +18,9 @@ type FinanceDashboardHandler struct {
 	transactionService *service.TransactionService
 	budgetService      *service.BudgetService
 	savingGoalService  *service.SavingGoalService
+	exchangeRates      *service.ExchangeRateService
+	loanService        *service.LoanService
+	tontineService     *service.TontineService
 	logger             logger.Logger
 }
 
@@ -26,6 +30,9 @@ func NewFinanceDashboardHandler(
 	transactionService *service.TransactionService,
 	budgetService *service.BudgetService,
 	savingGoalService *service.SavingGoalService,
+	exchangeRates *service.ExchangeRateService,
+	loanService *service.LoanService,
+	tontineService *service.TontineService,
 	logger logger.Logger,
 ) *FinanceDashboardHandler {
 	return &FinanceDashboardHandler{
@@ -33,6 +40,9 @@ func NewFinanceDashboardHandler(
 		transactionService: transactionService,
 		budgetService:      budgetService,
 		savingGoalService:  savingGoalService,
+		exchangeRates:      exchangeRates,
+		loanService:        loanService,
+		tontineService:     tontineService,
 		logger:             logger,
 	}
 }
@@ -40,22 +50,27 @@ func NewFinanceDashboardHandler(
 // BudgetWithStatus représente un budget avec son statut
 type BudgetWithStatus struct {
 	*entity.Budget
-	Status          string  `json:"status"`           // "good", "warning", "danger"
-	PercentageUsed  float64 `json:"percentage_used"`  // Pourcentage utilisé
-	RemainingAmount float64 `json:"remaining_amount"` // Montant restant
-	DaysRemaining   int     `json:"days_remaining"`   // Jours restants dans la période
+	Status          string       `json:"status"`           // "good", "warning", "danger"
+	PercentageUsed  float64      `json:"percentage_used"`  // Pourcentage utilisé
+	RemainingAmount entity.Money `json:"remaining_amount"` // Montant restant
+	DaysRemaining   int          `json:"days_remaining"`   // Jours restants dans la période
 }
 
 // DebtInfo représente les informations de dette
 type DebtInfo struct {
-	AccountID   uuid.UUID `json:"account_id"`
-	AccountName string    `json:"account_name"`
-	DebtAmount  float64   `json:"debt_amount"` // Montant négatif du solde
-	Currency    string    `json:"currency"`
+	AccountID   uuid.UUID    `json:"account_id"`
+	AccountName string       `json:"account_name"`
+	DebtAmount  entity.Money `json:"debt_amount"` // Montant négatif du solde
+	Currency    string       `json:"currency"`
+	// Loan est l'état du remboursement lorsque le compte suit un emprunt
+	Loan *entity.LoanSummary `json:"loan,omitempty"`
 }
 
 // FinanceDashboardResponse représente la réponse du tableau de bord
 type FinanceDashboardResponse struct {
+	// Transactions en attente comptées dans les soldes, les transactions du mois et les budgets
+	IncludePending bool `json:"include_pending"`
+
 	// Comptes
 	Accounts []*entity.Account `json:"accounts"`
 
@@ -71,27 +86,38 @@ type FinanceDashboardResponse struct {
 	// Dettes (comptes avec solde négatif)
 	Debts []*DebtInfo `json:"debts"`
 
+	// Position nette dans les tontines en cours
+	Tontines []*entity.TontinePosition `json:"tontines"`
+
 	// Transactions récurrentes
 	RecurringTransactions []*entity.Transaction `json:"recurring_transactions"`
 
 	// Statistiques résumées
-	Summary struct {
-		TotalBalance        float64 `json:"total_balance"`
-		MonthlyIncome       float64 `json:"monthly_income"`
-		MonthlyExpenses     float64 `json:"monthly_expenses"`
-		MonthlySavings      float64 `json:"monthly_savings"`
-		TotalDebts          float64 `json:"total_debts"`
-		BudgetsOverspent    int     `json:"budgets_overspent"`
-		SavingGoalsAchieved int     `json:"saving_goals_achieved"`
-	} `json:"summary"`
+	Summary DashboardSummary `json:"summary"`
+}
+
+// DashboardSummary représente les statistiques résumées du tableau de bord ; les montants sont convertis dans
+// la devise de référence de l'utilisateur (soldes et dettes au taux du jour, transactions au taux de leur date)
+type DashboardSummary struct {
+	Currency            string       `json:"currency"`
+	MissingRates        []string     `json:"missing_rates,omitempty"` // devises sans taux de change, comptées pour zéro
+	TotalBalance        entity.Money `json:"total_balance"`
+	MonthlyIncome       entity.Money `json:"monthly_income"`
+	MonthlyExpenses     entity.Money `json:"monthly_expenses"`
+	MonthlySavings      entity.Money `json:"monthly_savings"`
+	TotalDebts          entity.Money `json:"total_debts"`
+	BudgetsOverspent    int          `json:"budgets_overspent"`
+	SavingGoalsAchieved int          `json:"saving_goals_achieved"`
 }
 
 // GetFinanceDashboard godoc
 // @Summary Récupérer le tableau de bord financier
-// @Description Récupère toutes les informations financières pour le tableau de bord
+// @Description Récupère toutes les informations financières pour le tableau de bord. Les transactions planifiées ne sont pas comptées ; les transactions en attente le sont avec include_pending (soldes disponibles au lieu des soldes courants)
 // @Tags Finance
 // @Produce json
+// @Param include_pending query bool false "Compter les transactions en attente" default(false)
 // @Success 200 {object} response.Response{data=FinanceDashboardResponse} "Données du tableau de bord"
+// @Failure 400 {object} response.ErrorResponse "Paramètre include_pending invalide"
 // @Failure 401 {object} response.ErrorResponse "Non autorisé"
 // @Failure 500 {object} response.ErrorResponse "Erreur serveur"
 // @Security BearerAuth
@@ -104,16 +130,38 @@ func (h *FinanceDashboardHandler) GetFinanceDashboard(w http.ResponseWriter, r *
 	}
 
 	var dashboardData FinanceDashboardResponse
+	if value := r.URL.Query().Get("include_pending"); value != "" {
+		includePending, err := strconv.ParseBool(value)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "Paramètre include_pending invalide", err)
+			return
+		}
+		dashboardData.IncludePending = includePending
+	}
 
-	// 1. Récupérer tous les comptes
-	accounts, err := h.accountService.GetAccountsByUserID(r.Context(), userID)
+	// 1. Récupérer les comptes ; les comptes clôturés, soldés, ne sont pas affichés
+	allAccounts, err := h.accountService.GetAccountsByUserID(r.Context(), userID)
 	if err != nil {
 		h.logger.Error("Erreur récupération comptes", logger.Error(err))
 		response.Error(w, http.StatusInternalServerError, "Erreur lors de la récupération des comptes", err)
 		return
 	}
+	accounts := make([]*entity.Account, 0, len(allAccounts))
+	for _, account := range allAccounts {
+		if account.ArchivedAt == nil {
+			accounts = append(accounts, account)
+		}
+	}
 	dashboardData.Accounts = accounts
 
+	// Taux de change de l'utilisateur pour les montants dans une autre devise
+	converter, err := h.exchangeRates.Converter(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Erreur récupération taux de change", logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Erreur lors de la récupération des taux de change", err)
+		return
+	}
+
 	// 2. Récupérer les transactions du mois actuel
 	now := time.Now()
 	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
@@ -130,6 +178,7 @@ func (h *FinanceDashboardHandler) GetFinanceDashboard(w http.ResponseWriter, r *
 		response.Error(w, http.StatusInternalServerError, "Erreur lors de la récupération des transactions", err)
 		return
 	}
+	currentMonthTransactions = countedTransactions(currentMonthTransactions, dashboardData.IncludePending)
 	dashboardData.CurrentMonthTransactions = currentMonthTransactions
 
 	// 3. Récupérer les budgets avec leur état
@@ -144,16 +193,23 @@ func (h *FinanceDashboardHandler) GetFinanceDashboard(w http.ResponseWriter, r *
 
 	//calculer amount_spent pour chaque budget
 	for _, budget := range budgets {
-		var budgetTransactions []*entity.Transaction
+		// Une transaction ventilée compte pour chacune de ses lignes dans la catégorie concernée ;
+		// un remboursement est déduit des dépenses de sa catégorie. Les transactions dans une autre devise
+		// sont converties dans celle du budget au taux de leur date.
+		amountSpent := entity.NewMoney(0, budget.Currency)
 		for _, tx := range currentMonthTransactions {
-			if tx.CategoryID != nil && budget.Category != nil && *tx.CategoryID == budget.CategoryID {
-				budgetTransactions = append(budgetTransactions, tx)
+			if budget.Category == nil || (tx.Type != "expense" && tx.Type != "refund") {
+				continue
 			}
-		}
-		h.logger.Info("budgetTransactions", logger.Any("budgetTransactions", budgetTransactions))
-		var amountSpent float64
-		for _, tx := range budgetTransactions {
-			amountSpent += tx.Amount
+			amount := tx.AmountForCategory(budget.CategoryID)
+			if amount.IsZero() {
+				continue
+			}
+			amount = converter.ConvertTo(amount, budget.Currency, tx.Date)
+			if tx.Type == "refund" {
+				amount = amount.Neg()
+			}
+			amountSpent = amountSpent.Add(amount)
 		}
 		budget.AmountSpent = amountSpent
 	}
@@ -173,21 +229,37 @@ func (h *FinanceDashboardHandler) GetFinanceDashboard(w http.ResponseWriter, r *
 	}
 	dashboardData.SavingGoals = savingGoals
 
-	// 5. Identifier les dettes (comptes avec solde négatif)
+	// 5. Identifier les dettes (comptes avec solde négatif), avec l'échéancier des emprunts
+	loans, err := h.loanService.GetLoanSummaries(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Erreur récupération emprunts", logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Erreur lors de la récupération des emprunts", err)
+		return
+	}
+
 	var debts []*DebtInfo
 	for _, account := range accounts {
-		if account.Balance < 0 {
+		if balance := dashboardBalance(account, dashboardData.IncludePending); balance.IsNegative() {
 			debt := &DebtInfo{
 				AccountID:   account.ID,
 				AccountName: account.Name,
-				DebtAmount:  -account.Balance, // Convertir en montant positif
+				DebtAmount:  balance.Neg(), // Convertir en montant positif
 				Currency:    account.Currency,
+				Loan:        loans[account.ID],
 			}
 			debts = append(debts, debt)
 		}
 	}
 	dashboardData.Debts = debts
 
+	tontines, err := h.tontineService.GetPositions(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Erreur récupération tontines", logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Erreur lors de la récupération des tontines", err)
+		return
+	}
+	dashboardData.Tontines = tontines
+
 	// 6. Récupérer les transactions récurrentes
 	allTransactions, err := h.transactionService.GetTransactionsByUserID(r.Context(), userID)
 	if err != nil {
@@ -205,7 +277,7 @@ func (h *FinanceDashboardHandler) GetFinanceDashboard(w http.ResponseWriter, r *
 	dashboardData.RecurringTransactions = recurringTransactions
 
 	// 7. Calculer les statistiques résumées
-	dashboardData.Summary = h.calculateSummary(accounts, currentMonthTransactions, budgetsWithStatus, savingGoals, debts)
+	dashboardData.Summary = h.calculateSummary(converter, accounts, currentMonthTransactions, budgetsWithStatus, savingGoals, debts, dashboardData.IncludePending)
 
 	response.Success(w, http.StatusOK, "Tableau de bord récupéré avec succès", dashboardData)
 }
@@ -213,11 +285,11 @@ func (h *FinanceDashboardHandler) GetFinanceDashboard(w http.ResponseWriter, r *
 // calculateBudgetStatus calcule le statut d'un budget
 func (h *FinanceDashboardHandler) calculateBudgetStatus(budget *entity.Budget) *BudgetWithStatus {
 	var percentageUsed float64
-	if budget.AmountPlanned > 0 {
-		percentageUsed = (budget.AmountSpent / budget.AmountPlanned) * 100
+	if budget.AmountPlanned.IsPositive() {
+		percentageUsed = (float64(budget.AmountSpent.Minor) / float64(budget.AmountPlanned.Minor)) * 100
 	}
 
-	remainingAmount := budget.AmountPlanned - budget.AmountSpent
+	remainingAmount := budget.AmountPlanned.Sub(budget.AmountSpent)
 
 	// Déterminer le statut
 	var status string
@@ -262,54 +334,55 @@ func (h *FinanceDashboardHandler) calculateBudgetStatus(budget *entity.Budget) *
 	}
 }
 
-// calculateSummary calcule les statistiques résumées
+// calculateSummary calcule les statistiques résumées dans la devise de référence
 func (h *FinanceDashboardHandler) calculateSummary(
+	converter *service.CurrencyConverter,
 	accounts []*entity.Account,
 	transactions []*entity.Transaction,
 	budgets []*BudgetWithStatus,
 	savingGoals []*entity.SavingGoal,
 	debts []*DebtInfo,
-) struct {
-	TotalBalance        float64 `json:"total_balance"`
-	MonthlyIncome       float64 `json:"monthly_income"`
-	MonthlyExpenses     float64 `json:"monthly_expenses"`
-	MonthlySavings      float64 `json:"monthly_savings"`
-	TotalDebts          float64 `json:"total_debts"`
-	BudgetsOverspent    int     `json:"budgets_overspent"`
-	SavingGoalsAchieved int     `json:"saving_goals_achieved"`
-} {
-	var summary struct {
-		TotalBalance        float64 `json:"total_balance"`
-		MonthlyIncome       float64 `json:"monthly_income"`
-		MonthlyExpenses     float64 `json:"monthly_expenses"`
-		MonthlySavings      float64 `json:"monthly_savings"`
-		TotalDebts          float64 `json:"total_debts"`
-		BudgetsOverspent    int     `json:"budgets_overspent"`
-		SavingGoalsAchieved int     `json:"saving_goals_achieved"`
+	includePending bool,
+) DashboardSummary {
+	zero := entity.NewMoney(0, converter.BaseCurrency())
+	summary := DashboardSummary{
+		Currency:        converter.BaseCurrency(),
+		TotalBalance:    zero,
+		MonthlyIncome:   zero,
+		MonthlyExpenses: zero,
+		MonthlySavings:  zero,
+		TotalDebts:      zero,
 	}
+	today := time.Now()
 
 	// Calculer le solde total
 	for _, account := range accounts {
-		if account.Balance > 0 { // Ne compter que les soldes positifs pour le total
-			summary.TotalBalance += account.Balance
+		if balance := dashboardBalance(account, includePending); balance.IsPositive() { // Ne compter que les soldes positifs pour le total
+			summary.TotalBalance = summary.TotalBalance.Add(converter.ToBase(balance, today))
 		}
 	}
 
 	// Calculer les revenus et dépenses du mois
 	for _, transaction := range transactions {
+		if transaction.Type == "transfer" {
+			continue
+		}
+		amount := converter.ToBase(transaction.Amount, transaction.Date)
 		switch transaction.Type {
 		case "income":
-			summary.MonthlyIncome += transaction.Amount
+			summary.MonthlyIncome = summary.MonthlyIncome.Add(amount)
 		case "expense":
-			summary.MonthlyExpenses += transaction.Amount
+			summary.MonthlyExpenses = summary.MonthlyExpenses.Add(amount)
+		case "refund":
+			summary.MonthlyExpenses = summary.MonthlyExpenses.Sub(amount)
 		case "saving":
-			summary.MonthlySavings += transaction.Amount
+			summary.MonthlySavings = summary.MonthlySavings.Add(amount)
 		}
 	}
 
 	// Calculer le total des dettes
 	for _, debt := range debts {
-		summary.TotalDebts += debt.DebtAmount
+		summary.TotalDebts = summary.TotalDebts.Add(converter.ToBase(debt.DebtAmount, today))
 	}
 
 	// Compter les budgets dépassés
@@ -326,5 +399,33 @@ func (h *FinanceDashboardHandler) calculateSummary(
 		}
 	}
 
+	summary.MissingRates = converter.MissingRates()
 	return summary
 }
+
+// dashboardBalance retourne le solde d'un compte affiché au tableau de bord : disponible si les transactions
+// en attente sont comptées, courant sinon
+func dashboardBalance(account *entity.Account, includePending bool) entity.Money {
+	if includePending {
+		return account.AvailableBalance
+	}
+	return account.Balance
+}
+
+// countedTransactions retire les transactions non comptées au tableau de bord : planifiées, et en attente
+// si elles ne sont pas incluses
+func countedTransactions(transactions []*entity.Transaction, includePending bool) []*entity.Transaction {
+	counted := make([]*entity.Transaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		switch transaction.Status {
+		case "scheduled":
+			continue
+		case "pending":
+			if !includePending {
+				continue
+			}
+		}
+		counted = append(counted, transaction)
+	}
+	return counted
+}
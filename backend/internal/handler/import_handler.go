@@ -0,0 +1,339 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"backend/internal/domaine/entity"
+	"backend/internal/service"
+	"backend/internal/service/statement"
+	"backend/pkg/logger"
+	"backend/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxStatementSize est la taille maximale d'un relevé importé (5 Mo)
+const maxStatementSize = 5 << 20
+
+// ImportHandler gère les requêtes HTTP pour l'import de relevés bancaires
+type ImportHandler struct {
+	importService *service.ImportService
+	logger        logger.Logger
+}
+
+// NewImportHandler crée une nouvelle instance de ImportHandler
+func NewImportHandler(importService *service.ImportService, logger logger.Logger) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+		logger:        logger,
+	}
+}
+
+// PreviewImport analyse un relevé sans l'importer
+// @Summary Aperçu d'un import de relevé
+// @Description Analyse un relevé CSV, OFX ou QIF sans rien enregistrer et indique pour chaque ligne si elle est nouvelle, déjà importée ou invalide
+// @Tags imports
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "Relevé (.csv, .ofx, .qfx, .qif)"
+// @Param account_id formData string true "ID du compte"
+// @Param format formData string false "Format (csv, ofx, qif), déduit de l'extension si absent"
+// @Param date_format formData string false "Format Go des dates (ex: 02/01/2006)"
+// @Param delimiter formData string false "Séparateur CSV, détecté si absent"
+// @Param has_header formData bool false "Le CSV a une ligne d'en-tête (défaut: true)"
+// @Param date_column formData string false "Colonne de date (nom ou index, défaut: date)"
+// @Param description_column formData string false "Colonne de libellé (défaut: description)"
+// @Param amount_column formData string false "Colonne de montant signé (défaut: amount)"
+// @Param debit_column formData string false "Colonne de débit (à la place du montant signé)"
+// @Param credit_column formData string false "Colonne de crédit (à la place du montant signé)"
+// @Param reference_column formData string false "Colonne de référence bancaire"
+// @Success 200 {object} response.Response{data=entity.ImportResult} "Aperçu de l'import"
+// @Failure 400 {object} response.ErrorResponse "Fichier ou paramètres invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 413 {object} response.ErrorResponse "Fichier trop volumineux"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /imports/preview [post]
+func (h *ImportHandler) PreviewImport(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	req, cleanup, err := h.parseImportRequest(w, r)
+	if err != nil {
+		h.writeError(w, err, "Erreur lecture du relevé")
+		return
+	}
+	defer cleanup()
+
+	result, err := h.importService.PreviewImport(r.Context(), userID, *req)
+	if err != nil {
+		h.writeError(w, err, "Erreur analyse du relevé")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Aperçu de l'import généré avec succès", result)
+}
+
+// ImportStatement importe un relevé dans un compte
+// @Summary Importer un relevé bancaire
+// @Description Importe les nouvelles lignes d'un relevé CSV, OFX ou QIF dans un compte. Les lignes déjà importées sont ignorées, chaque transaction est catégorisée automatiquement et le solde du compte est mis à jour.
+// @Tags imports
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "Relevé (.csv, .ofx, .qfx, .qif)"
+// @Param account_id formData string true "ID du compte"
+// @Param format formData string false "Format (csv, ofx, qif), déduit de l'extension si absent"
+// @Param date_format formData string false "Format Go des dates (ex: 02/01/2006)"
+// @Param delimiter formData string false "Séparateur CSV, détecté si absent"
+// @Param has_header formData bool false "Le CSV a une ligne d'en-tête (défaut: true)"
+// @Param date_column formData string false "Colonne de date (nom ou index, défaut: date)"
+// @Param description_column formData string false "Colonne de libellé (défaut: description)"
+// @Param amount_column formData string false "Colonne de montant signé (défaut: amount)"
+// @Param debit_column formData string false "Colonne de débit (à la place du montant signé)"
+// @Param credit_column formData string false "Colonne de crédit (à la place du montant signé)"
+// @Param reference_column formData string false "Colonne de référence bancaire"
+// @Success 201 {object} response.Response{data=entity.ImportResult} "Relevé importé"
+// @Failure 400 {object} response.ErrorResponse "Fichier ou paramètres invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 413 {object} response.ErrorResponse "Fichier trop volumineux"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /imports [post]
+func (h *ImportHandler) ImportStatement(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	req, cleanup, err := h.parseImportRequest(w, r)
+	if err != nil {
+		h.writeError(w, err, "Erreur lecture du relevé")
+		return
+	}
+	defer cleanup()
+
+	result, err := h.importService.ImportStatement(r.Context(), userID, *req)
+	if err != nil {
+		h.writeError(w, err, "Erreur import du relevé")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Relevé importé avec succès", result)
+}
+
+// GetImportBatches récupère l'historique des imports
+// @Summary Récupérer les imports de relevés
+// @Description Récupère les lots d'import de l'utilisateur, éventuellement filtrés par compte
+// @Tags imports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param account_id query string false "ID du compte"
+// @Success 200 {object} response.Response{data=[]entity.ImportBatch} "Imports récupérés"
+// @Failure 400 {object} response.ErrorResponse "ID de compte invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /imports [get]
+func (h *ImportHandler) GetImportBatches(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	var accountID *uuid.UUID
+	if value := r.URL.Query().Get("account_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "ID de compte invalide", err)
+			return
+		}
+		accountID = &id
+	}
+
+	batches, err := h.importService.GetImportBatches(r.Context(), userID, accountID)
+	if err != nil {
+		h.writeError(w, err, "Erreur récupération des imports")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Imports récupérés avec succès", batches)
+}
+
+// GetImportBatch récupère un lot d'import
+// @Summary Récupérer un import de relevé
+// @Description Récupère un lot d'import par son ID
+// @Tags imports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID du lot d'import"
+// @Success 200 {object} response.Response{data=entity.ImportBatch} "Import récupéré"
+// @Failure 400 {object} response.ErrorResponse "ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Import non trouvé"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /imports/{id} [get]
+func (h *ImportHandler) GetImportBatch(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	batchID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID d'import invalide", err)
+		return
+	}
+
+	batch, err := h.importService.GetImportBatch(r.Context(), userID, batchID)
+	if err != nil {
+		h.writeError(w, err, "Erreur récupération de l'import")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Import récupéré avec succès", batch)
+}
+
+// UndoImport annule un import
+// @Summary Annuler un import de relevé
+// @Description Supprime toutes les transactions créées par un lot d'import et rétablit le solde du compte
+// @Tags imports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID du lot d'import"
+// @Success 200 {object} response.Response{data=entity.ImportBatch} "Import annulé"
+// @Failure 400 {object} response.ErrorResponse "ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Import non trouvé"
+// @Failure 409 {object} response.ErrorResponse "Import déjà annulé"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /imports/{id}/undo [post]
+func (h *ImportHandler) UndoImport(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	batchID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID d'import invalide", err)
+		return
+	}
+
+	batch, err := h.importService.UndoImport(r.Context(), userID, batchID)
+	if err != nil {
+		h.writeError(w, err, "Erreur annulation de l'import")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Import annulé avec succès", batch)
+}
+
+// parseImportRequest lit le fichier et les paramètres d'import d'un formulaire multipart
+func (h *ImportHandler) parseImportRequest(w http.ResponseWriter, r *http.Request) (*service.ImportRequest, func(), error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxStatementSize)
+	if err := r.ParseMultipartForm(maxStatementSize); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, nil, entity.ErrFileTooLarge
+		}
+		return nil, nil, fmt.Errorf("%w: formulaire multipart invalide", entity.ErrInvalidImportData)
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: fichier manquant", entity.ErrInvalidImportData)
+	}
+
+	accountID, err := uuid.Parse(r.FormValue("account_id"))
+	if err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("%w: ID de compte invalide", entity.ErrInvalidImportData)
+	}
+
+	hasHeader := true
+	if value := r.FormValue("has_header"); value != "" {
+		hasHeader, err = strconv.ParseBool(value)
+		if err != nil {
+			file.Close()
+			return nil, nil, fmt.Errorf("%w: has_header invalide", entity.ErrInvalidImportData)
+		}
+	}
+
+	req := &service.ImportRequest{
+		AccountID: accountID,
+		FileName:  header.Filename,
+		Format:    r.FormValue("format"),
+		Content:   file,
+		Options: statement.Options{
+			DateFormat: r.FormValue("date_format"),
+			CSV: statement.CSVMapping{
+				Delimiter:         r.FormValue("delimiter"),
+				HasHeader:         hasHeader,
+				DateColumn:        formValueOr(r, "date_column", "date"),
+				DescriptionColumn: formValueOr(r, "description_column", "description"),
+				DebitColumn:       r.FormValue("debit_column"),
+				CreditColumn:      r.FormValue("credit_column"),
+				ReferenceColumn:   r.FormValue("reference_column"),
+			},
+		},
+	}
+	// Le montant signé n'est recherché que si aucune colonne débit/crédit n'est fournie
+	if req.Options.CSV.DebitColumn == "" && req.Options.CSV.CreditColumn == "" {
+		req.Options.CSV.AmountColumn = formValueOr(r, "amount_column", "amount")
+	} else {
+		req.Options.CSV.AmountColumn = r.FormValue("amount_column")
+	}
+
+	cleanup := func() {
+		file.Close()
+		if r.MultipartForm != nil {
+			r.MultipartForm.RemoveAll()
+		}
+	}
+	return req, cleanup, nil
+}
+
+// formValueOr retourne la valeur d'un champ de formulaire ou une valeur par défaut
+func formValueOr(r *http.Request, key, fallback string) string {
+	if value := r.FormValue(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// writeError traduit les erreurs du domaine en codes HTTP
+func (h *ImportHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, entity.ErrFileTooLarge):
+		response.Error(w, http.StatusRequestEntityTooLarge, "Fichier trop volumineux", err)
+	case errors.Is(err, entity.ErrInvalidFileType):
+		response.Error(w, http.StatusBadRequest, "Type de fichier non supporté", err)
+	case errors.Is(err, entity.ErrInvalidImportData):
+		response.Error(w, http.StatusBadRequest, "Relevé invalide", err)
+	case errors.Is(err, entity.ErrImportBatchNotFound):
+		response.Error(w, http.StatusNotFound, "Import non trouvé", err)
+	case errors.Is(err, entity.ErrImportAlreadyUndone):
+		response.Error(w, http.StatusConflict, "Import déjà annulé", err)
+	default:
+		h.logger.Error(message, logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, message, err)
+	}
+}
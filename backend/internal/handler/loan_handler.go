@@ -0,0 +1,306 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"backend/internal/domaine/entity"
+	"backend/internal/service"
+	"backend/pkg/logger"
+	"backend/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// LoanHandler gère les requêtes HTTP pour les emprunts
+type LoanHandler struct {
+	loanService *service.LoanService
+	logger      logger.Logger
+}
+
+// NewLoanHandler crée une nouvelle instance de LoanHandler
+func NewLoanHandler(loanService *service.LoanService, logger logger.Logger) *LoanHandler {
+	return &LoanHandler{
+		loanService: loanService,
+		logger:      logger,
+	}
+}
+
+// CreateLoan enregistre un emprunt
+// @Summary Enregistrer un emprunt
+// @Description Enregistre un emprunt (capital, taux annuel, durée, jour de paiement), crée le compte de dette qui le suit et génère son échéancier d'amortissement. Avec un compte de déblocage, le capital y est transféré ; sinon les échéances déjà réglées peuvent être indiquées.
+// @Tags loans
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param loan body entity.CreateLoanRequest true "Emprunt"
+// @Success 201 {object} response.Response{data=entity.Loan} "Emprunt enregistré"
+// @Failure 400 {object} response.ErrorResponse "Données invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /loans [post]
+func (h *LoanHandler) CreateLoan(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	var req entity.CreateLoanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Erreur décodage JSON", logger.Error(err))
+		response.Error(w, http.StatusBadRequest, "Données JSON invalides", err)
+		return
+	}
+
+	loan, err := h.loanService.CreateLoan(r.Context(), userID, req)
+	if err != nil {
+		h.writeError(w, err, "Erreur création emprunt")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Emprunt enregistré avec succès", loan)
+}
+
+// GetLoans récupère les emprunts de l'utilisateur
+// @Summary Récupérer les emprunts
+// @Description Récupère les emprunts de l'utilisateur avec leur compte de dette
+// @Tags loans
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]entity.Loan} "Emprunts récupérés"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /loans [get]
+func (h *LoanHandler) GetLoans(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	loans, err := h.loanService.GetLoans(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, err, "Erreur récupération emprunts")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Emprunts récupérés avec succès", loans)
+}
+
+// GetLoan récupère un emprunt avec son échéancier
+// @Summary Récupérer un emprunt
+// @Description Récupère un emprunt avec son échéancier d'amortissement
+// @Tags loans
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de l'emprunt"
+// @Success 200 {object} response.Response{data=entity.Loan} "Emprunt récupéré"
+// @Failure 400 {object} response.ErrorResponse "ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Emprunt non trouvé"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /loans/{id} [get]
+func (h *LoanHandler) GetLoan(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	loanID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID d'emprunt invalide", err)
+		return
+	}
+
+	loan, err := h.loanService.GetLoan(r.Context(), userID, loanID)
+	if err != nil {
+		h.writeError(w, err, "Erreur récupération emprunt")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Emprunt récupéré avec succès", loan)
+}
+
+// DeleteLoan supprime un emprunt
+// @Summary Supprimer un emprunt
+// @Description Supprime un emprunt et son échéancier ; le compte de dette et les transactions des échéances réglées sont conservés
+// @Tags loans
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de l'emprunt"
+// @Success 200 {object} response.Response "Emprunt supprimé"
+// @Failure 400 {object} response.ErrorResponse "ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Emprunt non trouvé"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /loans/{id} [delete]
+func (h *LoanHandler) DeleteLoan(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	loanID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID d'emprunt invalide", err)
+		return
+	}
+
+	if err := h.loanService.DeleteLoan(r.Context(), userID, loanID); err != nil {
+		h.writeError(w, err, "Erreur suppression emprunt")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Emprunt supprimé avec succès", nil)
+}
+
+// PayInstallment règle la prochaine échéance d'un emprunt
+// @Summary Régler une échéance
+// @Description Règle la plus ancienne échéance non réglée depuis un compte : le capital est transféré vers le compte de dette et les intérêts enregistrés en dépense. Un remboursement anticipé optionnel raccourcit l'échéancier restant.
+// @Tags loans
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de l'emprunt"
+// @Param payment body entity.PayLoanInstallmentRequest true "Règlement"
+// @Success 200 {object} response.Response{data=entity.Loan} "Échéance réglée"
+// @Failure 400 {object} response.ErrorResponse "Données invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Emprunt non trouvé"
+// @Failure 409 {object} response.ErrorResponse "Emprunt déjà remboursé"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /loans/{id}/payments [post]
+func (h *LoanHandler) PayInstallment(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	loanID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID d'emprunt invalide", err)
+		return
+	}
+
+	var req entity.PayLoanInstallmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Erreur décodage JSON", logger.Error(err))
+		response.Error(w, http.StatusBadRequest, "Données JSON invalides", err)
+		return
+	}
+
+	loan, err := h.loanService.PayInstallment(r.Context(), userID, loanID, req)
+	if err != nil {
+		h.writeError(w, err, "Erreur règlement échéance")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Échéance réglée avec succès", loan)
+}
+
+// GetProjection projette le remboursement d'un emprunt
+// @Summary Projeter le remboursement
+// @Description Projette la date de fin et le coût des intérêts restants, avec un versement supplémentaire mensuel optionnel comparé à l'échéancier en cours
+// @Tags loans
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de l'emprunt"
+// @Param extra_payment query string false "Versement supplémentaire mensuel (ex: 5000)"
+// @Success 200 {object} response.Response{data=entity.LoanProjectionResponse} "Projection calculée"
+// @Failure 400 {object} response.ErrorResponse "Données invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Emprunt non trouvé"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /loans/{id}/projection [get]
+func (h *LoanHandler) GetProjection(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	loanID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID d'emprunt invalide", err)
+		return
+	}
+
+	extraPayment := entity.Decimal(r.URL.Query().Get("extra_payment"))
+	projection, err := h.loanService.GetProjection(r.Context(), userID, loanID, extraPayment)
+	if err != nil {
+		h.writeError(w, err, "Erreur projection emprunt")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Projection calculée avec succès", projection)
+}
+
+// GetReminders récupère les échéances impayées et à venir
+// @Summary Récupérer les rappels d'échéances
+// @Description Récupère les échéances impayées et celles dues dans les prochains jours, par date d'échéance
+// @Tags loans
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Nombre de jours à venir (défaut: 7)"
+// @Success 200 {object} response.Response{data=[]entity.LoanReminder} "Rappels récupérés"
+// @Failure 400 {object} response.ErrorResponse "Paramètre invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /loans/reminders [get]
+func (h *LoanHandler) GetReminders(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	days := 7
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		parsed, err := strconv.Atoi(daysStr)
+		if err != nil || parsed < 0 || parsed > 366 {
+			response.Error(w, http.StatusBadRequest, "Nombre de jours invalide", err)
+			return
+		}
+		days = parsed
+	}
+
+	reminders, err := h.loanService.GetReminders(r.Context(), userID, days)
+	if err != nil {
+		h.writeError(w, err, "Erreur récupération rappels d'échéances")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Rappels d'échéances récupérés avec succès", reminders)
+}
+
+// writeError traduit les erreurs du domaine en codes HTTP
+func (h *LoanHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, entity.ErrLoanNotFound):
+		response.Error(w, http.StatusNotFound, "Emprunt non trouvé", err)
+	case errors.Is(err, entity.ErrLoanPaidOff):
+		response.Error(w, http.StatusConflict, "Emprunt déjà remboursé", err)
+	case errors.Is(err, entity.ErrReconciliationPeriodLocked):
+		response.Error(w, http.StatusConflict, "Période rapprochée", err)
+	case errors.Is(err, entity.ErrInvalidLoanData), errors.Is(err, entity.ErrInvalidAmount),
+		errors.Is(err, entity.ErrInvalidCurrency), errors.Is(err, entity.ErrCurrencyMismatch):
+		response.Error(w, http.StatusBadRequest, "Données invalides", err)
+	default:
+		h.logger.Error(message, logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, message, err)
+	}
+}
@@ -0,0 +1,351 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"backend/internal/domaine/entity"
+	"backend/internal/service"
+	"backend/pkg/logger"
+	"backend/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// ReconciliationHandler gère les requêtes HTTP pour le rapprochement des comptes avec leurs relevés
+type ReconciliationHandler struct {
+	reconciliationService *service.ReconciliationService
+	logger                logger.Logger
+}
+
+// NewReconciliationHandler crée une nouvelle instance de ReconciliationHandler
+func NewReconciliationHandler(reconciliationService *service.ReconciliationService, logger logger.Logger) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		reconciliationService: reconciliationService,
+		logger:                logger,
+	}
+}
+
+// StartReconciliation ouvre le rapprochement d'un compte avec un relevé
+// @Summary Démarrer un rapprochement
+// @Description Ouvre le rapprochement d'un compte avec le solde et la date d'un relevé, postérieure à la période déjà rapprochée. Retourne le solde calculé à la date du relevé, l'écart avec le relevé et les transactions encore en attente. Un seul rapprochement peut être en cours par compte
+// @Tags reconciliations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID du compte"
+// @Param request body entity.StartReconciliationRequest true "Solde et date du relevé"
+// @Success 201 {object} response.Response{data=entity.ReconciliationSummaryResponse} "Rapprochement ouvert"
+// @Failure 400 {object} response.ErrorResponse "Données invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Compte non trouvé"
+// @Failure 409 {object} response.ErrorResponse "Un rapprochement est déjà en cours"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /accounts/{id}/reconciliations [post]
+func (h *ReconciliationHandler) StartReconciliation(w http.ResponseWriter, r *http.Request) {
+	userID, accountID, ok := h.accountParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req entity.StartReconciliationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Erreur décodage JSON", logger.Error(err))
+		response.Error(w, http.StatusBadRequest, "Données JSON invalides", err)
+		return
+	}
+
+	summary, err := h.reconciliationService.StartReconciliation(r.Context(), userID, accountID, req)
+	if err != nil {
+		h.writeError(w, err, "Erreur ouverture du rapprochement")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Rapprochement ouvert avec succès", summary)
+}
+
+// GetReconciliations récupère les rapprochements d'un compte
+// @Summary Récupérer les rapprochements d'un compte
+// @Description Récupère les rapprochements d'un compte, du plus récent au plus ancien relevé
+// @Tags reconciliations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID du compte"
+// @Success 200 {object} response.Response{data=[]entity.Reconciliation} "Rapprochements récupérés"
+// @Failure 400 {object} response.ErrorResponse "ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Compte non trouvé"
+// @Router /accounts/{id}/reconciliations [get]
+func (h *ReconciliationHandler) GetReconciliations(w http.ResponseWriter, r *http.Request) {
+	userID, accountID, ok := h.accountParams(w, r)
+	if !ok {
+		return
+	}
+
+	reconciliations, err := h.reconciliationService.GetReconciliations(r.Context(), userID, accountID)
+	if err != nil {
+		h.writeError(w, err, "Erreur récupération des rapprochements")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Rapprochements récupérés avec succès", reconciliations)
+}
+
+// GetReconciliation récupère l'état d'un rapprochement
+// @Summary Récupérer un rapprochement
+// @Description Récupère un rapprochement avec le solde calculé à la date du relevé (solde courant moins les transactions passées datées après), l'écart avec le relevé et les transactions encore en attente jusqu'à cette date
+// @Tags reconciliations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID du compte"
+// @Param reconciliationID path string true "ID du rapprochement"
+// @Success 200 {object} response.Response{data=entity.ReconciliationSummaryResponse} "Rapprochement récupéré"
+// @Failure 400 {object} response.ErrorResponse "ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Compte ou rapprochement non trouvé"
+// @Router /accounts/{id}/reconciliations/{reconciliationID} [get]
+func (h *ReconciliationHandler) GetReconciliation(w http.ResponseWriter, r *http.Request) {
+	userID, accountID, reconciliationID, ok := h.reconciliationParams(w, r)
+	if !ok {
+		return
+	}
+
+	summary, err := h.reconciliationService.GetReconciliation(r.Context(), userID, accountID, reconciliationID)
+	if err != nil {
+		h.writeError(w, err, "Erreur récupération du rapprochement")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Rapprochement récupéré avec succès", summary)
+}
+
+// ClearItems pointe des transactions en attente comme passées
+// @Summary Pointer des transactions
+// @Description Passe en cleared des transactions en attente du compte datées jusqu'au relevé (les deux jambes d'un transfert) ; les transactions déjà passées sont ignorées. Retourne l'état mis à jour du rapprochement
+// @Tags reconciliations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID du compte"
+// @Param reconciliationID path string true "ID du rapprochement"
+// @Param request body entity.ClearReconciliationItemsRequest true "Transactions à pointer"
+// @Success 200 {object} response.Response{data=entity.ReconciliationSummaryResponse} "Transactions pointées"
+// @Failure 400 {object} response.ErrorResponse "Transaction invalide (autre compte, postérieure au relevé, planifiée)"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Compte ou rapprochement non trouvé"
+// @Failure 409 {object} response.ErrorResponse "Rapprochement clôturé ou abandonné"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /accounts/{id}/reconciliations/{reconciliationID}/clear [post]
+func (h *ReconciliationHandler) ClearItems(w http.ResponseWriter, r *http.Request) {
+	userID, accountID, reconciliationID, ok := h.reconciliationParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req entity.ClearReconciliationItemsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Erreur décodage JSON", logger.Error(err))
+		response.Error(w, http.StatusBadRequest, "Données JSON invalides", err)
+		return
+	}
+
+	summary, err := h.reconciliationService.ClearItems(r.Context(), userID, accountID, reconciliationID, req)
+	if err != nil {
+		h.writeError(w, err, "Erreur pointage des transactions")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Transactions pointées avec succès", summary)
+}
+
+// PostAdjustment enregistre l'écart restant d'un rapprochement
+// @Summary Ajuster un rapprochement
+// @Description Enregistre l'écart entre le relevé et le solde calculé comme une transaction passée datée du relevé (revenu pour un écart positif, dépense pour un écart négatif), ce qui équilibre le rapprochement
+// @Tags reconciliations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID du compte"
+// @Param reconciliationID path string true "ID du rapprochement"
+// @Param request body entity.ReconciliationAdjustmentRequest false "Libellé de l'ajustement"
+// @Success 201 {object} response.Response{data=entity.ReconciliationSummaryResponse} "Ajustement enregistré"
+// @Failure 400 {object} response.ErrorResponse "Aucun écart à ajuster"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Compte ou rapprochement non trouvé"
+// @Failure 409 {object} response.ErrorResponse "Rapprochement clôturé ou abandonné"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /accounts/{id}/reconciliations/{reconciliationID}/adjustment [post]
+func (h *ReconciliationHandler) PostAdjustment(w http.ResponseWriter, r *http.Request) {
+	userID, accountID, reconciliationID, ok := h.reconciliationParams(w, r)
+	if !ok {
+		return
+	}
+
+	// Le libellé est optionnel : un corps vide est accepté
+	var req entity.ReconciliationAdjustmentRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.logger.Warn("Erreur décodage JSON", logger.Error(err))
+			response.Error(w, http.StatusBadRequest, "Données JSON invalides", err)
+			return
+		}
+	}
+
+	summary, err := h.reconciliationService.PostAdjustment(r.Context(), userID, accountID, reconciliationID, req)
+	if err != nil {
+		h.writeError(w, err, "Erreur ajustement du rapprochement")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Ajustement enregistré avec succès", summary)
+}
+
+// CompleteReconciliation clôture un rapprochement et verrouille la période
+// @Summary Clôturer un rapprochement
+// @Description Clôture un rapprochement équilibré : les transactions passées du compte datées jusqu'au relevé sont rapprochées (verrouillées) et la période est verrouillée, plus aucune transaction ne pouvant y être créée, supprimée ou modifiée dans son montant, son compte ou sa date. Les transactions encore en attente le restent
+// @Tags reconciliations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID du compte"
+// @Param reconciliationID path string true "ID du rapprochement"
+// @Success 200 {object} response.Response{data=entity.ReconciliationSummaryResponse} "Rapprochement clôturé"
+// @Failure 400 {object} response.ErrorResponse "ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Compte ou rapprochement non trouvé"
+// @Failure 409 {object} response.ErrorResponse "Rapprochement déséquilibré, clôturé ou abandonné"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /accounts/{id}/reconciliations/{reconciliationID}/complete [post]
+func (h *ReconciliationHandler) CompleteReconciliation(w http.ResponseWriter, r *http.Request) {
+	userID, accountID, reconciliationID, ok := h.reconciliationParams(w, r)
+	if !ok {
+		return
+	}
+
+	summary, err := h.reconciliationService.CompleteReconciliation(r.Context(), userID, accountID, reconciliationID)
+	if err != nil {
+		h.writeError(w, err, "Erreur clôture du rapprochement")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Rapprochement clôturé avec succès", summary)
+}
+
+// ReopenReconciliation rouvre le dernier rapprochement clôturé d'un compte
+// @Summary Rouvrir un rapprochement
+// @Description Rouvre le dernier rapprochement clôturé du compte : la période verrouillée revient au rapprochement précédent. Les transactions rapprochées le restent et se déverrouillent par leur statut
+// @Tags reconciliations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID du compte"
+// @Param reconciliationID path string true "ID du rapprochement"
+// @Success 200 {object} response.Response{data=entity.ReconciliationSummaryResponse} "Rapprochement rouvert"
+// @Failure 400 {object} response.ErrorResponse "Rapprochement non clôturé ou non le dernier"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Compte ou rapprochement non trouvé"
+// @Failure 409 {object} response.ErrorResponse "Un rapprochement est déjà en cours"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /accounts/{id}/reconciliations/{reconciliationID}/reopen [post]
+func (h *ReconciliationHandler) ReopenReconciliation(w http.ResponseWriter, r *http.Request) {
+	userID, accountID, reconciliationID, ok := h.reconciliationParams(w, r)
+	if !ok {
+		return
+	}
+
+	summary, err := h.reconciliationService.ReopenReconciliation(r.Context(), userID, accountID, reconciliationID)
+	if err != nil {
+		h.writeError(w, err, "Erreur réouverture du rapprochement")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Rapprochement rouvert avec succès", summary)
+}
+
+// CancelReconciliation abandonne un rapprochement en cours
+// @Summary Abandonner un rapprochement
+// @Description Abandonne un rapprochement en cours ; les transactions pointées et l'ajustement éventuel sont conservés
+// @Tags reconciliations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID du compte"
+// @Param reconciliationID path string true "ID du rapprochement"
+// @Success 200 {object} response.Response "Rapprochement abandonné"
+// @Failure 400 {object} response.ErrorResponse "ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Compte ou rapprochement non trouvé"
+// @Failure 409 {object} response.ErrorResponse "Rapprochement clôturé ou abandonné"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /accounts/{id}/reconciliations/{reconciliationID} [delete]
+func (h *ReconciliationHandler) CancelReconciliation(w http.ResponseWriter, r *http.Request) {
+	userID, accountID, reconciliationID, ok := h.reconciliationParams(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.reconciliationService.CancelReconciliation(r.Context(), userID, accountID, reconciliationID); err != nil {
+		h.writeError(w, err, "Erreur abandon du rapprochement")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Rapprochement abandonné avec succès", nil)
+}
+
+// accountParams lit l'utilisateur authentifié et l'ID du compte ; une réponse d'erreur est écrite en cas d'échec
+func (h *ReconciliationHandler) accountParams(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID de compte invalide", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, accountID, true
+}
+
+// reconciliationParams lit l'utilisateur authentifié, l'ID du compte et celui du rapprochement
+func (h *ReconciliationHandler) reconciliationParams(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, uuid.UUID, bool) {
+	userID, accountID, ok := h.accountParams(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+
+	reconciliationID, err := uuid.Parse(chi.URLParam(r, "reconciliationID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID de rapprochement invalide", err)
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+	return userID, accountID, reconciliationID, true
+}
+
+// writeError traduit une erreur du service de rapprochement en réponse HTTP
+func (h *ReconciliationHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, entity.ErrAccountNotFound):
+		response.Error(w, http.StatusNotFound, "Compte non trouvé", err)
+	case errors.Is(err, entity.ErrReconciliationNotFound):
+		response.Error(w, http.StatusNotFound, "Rapprochement non trouvé", err)
+	case errors.Is(err, entity.ErrInvalidReconciliation), errors.Is(err, entity.ErrInvalidAmount):
+		response.Error(w, http.StatusBadRequest, "Rapprochement invalide", err)
+	case errors.Is(err, entity.ErrReconciliationInProgress):
+		response.Error(w, http.StatusConflict, "Un rapprochement est déjà en cours", err)
+	case errors.Is(err, entity.ErrReconciliationClosed):
+		response.Error(w, http.StatusConflict, "Rapprochement clôturé", err)
+	case errors.Is(err, entity.ErrReconciliationUnbalanced):
+		response.Error(w, http.StatusConflict, "Rapprochement déséquilibré", err)
+	case errors.Is(err, entity.ErrReconciliationPeriodLocked):
+		response.Error(w, http.StatusConflict, "Période rapprochée", err)
+	default:
+		h.logger.Error(message, logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, message, err)
+	}
+}
@@ -0,0 +1,386 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"backend/internal/domaine/entity"
+	"backend/internal/service"
+	"backend/pkg/logger"
+	"backend/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// RecurringTransactionHandler gère les requêtes HTTP pour les transactions récurrentes
+type RecurringTransactionHandler struct {
+	recurringService *service.RecurringTransactionService
+	logger           logger.Logger
+}
+
+// NewRecurringTransactionHandler crée une nouvelle instance de RecurringTransactionHandler
+func NewRecurringTransactionHandler(recurringService *service.RecurringTransactionService, logger logger.Logger) *RecurringTransactionHandler {
+	return &RecurringTransactionHandler{
+		recurringService: recurringService,
+		logger:           logger,
+	}
+}
+
+// CreateRecurringTransaction crée une nouvelle transaction récurrente
+// @Summary Créer une transaction récurrente
+// @Description Crée un modèle de transaction récurrente (loyer, salaire, abonnement...). Les occurrences échues sont créées automatiquement.
+// @Tags recurring-transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param recurring body entity.CreateRecurringTransactionRequest true "Données de la transaction récurrente"
+// @Success 201 {object} response.Response{data=entity.RecurringTransaction} "Transaction récurrente créée"
+// @Failure 400 {object} response.ErrorResponse "Données invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /recurring-transactions [post]
+func (h *RecurringTransactionHandler) CreateRecurringTransaction(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	var req entity.CreateRecurringTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Erreur décodage JSON", logger.Error(err))
+		response.Error(w, http.StatusBadRequest, "Données JSON invalides", err)
+		return
+	}
+
+	recurring, err := h.recurringService.CreateRecurringTransaction(r.Context(), userID, req)
+	if err != nil {
+		h.writeError(w, err, "Erreur création transaction récurrente")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Transaction récurrente créée avec succès", recurring)
+}
+
+// GetRecurringTransactions récupère les transactions récurrentes de l'utilisateur
+// @Summary Récupérer les transactions récurrentes
+// @Description Récupère tous les modèles de transactions récurrentes de l'utilisateur authentifié
+// @Tags recurring-transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]entity.RecurringTransaction} "Transactions récurrentes récupérées"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /recurring-transactions [get]
+func (h *RecurringTransactionHandler) GetRecurringTransactions(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	recurrings, err := h.recurringService.GetRecurringTransactions(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, err, "Erreur récupération transactions récurrentes")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Transactions récurrentes récupérées avec succès", recurrings)
+}
+
+// GetRecurringTransaction récupère une transaction récurrente par son ID
+// @Summary Récupérer une transaction récurrente
+// @Description Récupère un modèle de transaction récurrente par son ID
+// @Tags recurring-transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la transaction récurrente"
+// @Success 200 {object} response.Response{data=entity.RecurringTransaction} "Transaction récurrente récupérée"
+// @Failure 400 {object} response.ErrorResponse "ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Transaction récurrente non trouvée"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /recurring-transactions/{id} [get]
+func (h *RecurringTransactionHandler) GetRecurringTransaction(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	recurringID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID de transaction récurrente invalide", err)
+		return
+	}
+
+	recurring, err := h.recurringService.GetRecurringTransaction(r.Context(), userID, recurringID)
+	if err != nil {
+		h.writeError(w, err, "Erreur récupération transaction récurrente")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Transaction récurrente récupérée avec succès", recurring)
+}
+
+// UpdateRecurringTransaction modifie les occurrences futures d'une transaction récurrente
+// @Summary Modifier une transaction récurrente
+// @Description Modifie le montant, le compte, la catégorie ou la planification des occurrences futures. Les transactions déjà créées ne changent pas.
+// @Tags recurring-transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la transaction récurrente"
+// @Param recurring body entity.UpdateRecurringTransactionRequest true "Données de mise à jour"
+// @Success 200 {object} response.Response{data=entity.RecurringTransaction} "Transaction récurrente mise à jour"
+// @Failure 400 {object} response.ErrorResponse "Données invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Transaction récurrente non trouvée"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /recurring-transactions/{id} [put]
+func (h *RecurringTransactionHandler) UpdateRecurringTransaction(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	recurringID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID de transaction récurrente invalide", err)
+		return
+	}
+
+	var req entity.UpdateRecurringTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Erreur décodage JSON", logger.Error(err))
+		response.Error(w, http.StatusBadRequest, "Données JSON invalides", err)
+		return
+	}
+
+	recurring, err := h.recurringService.UpdateRecurringTransaction(r.Context(), userID, recurringID, req)
+	if err != nil {
+		h.writeError(w, err, "Erreur mise à jour transaction récurrente")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Transaction récurrente mise à jour avec succès", recurring)
+}
+
+// PauseRecurringTransaction suspend une transaction récurrente
+// @Summary Suspendre une transaction récurrente
+// @Description Suspend la création des occurrences jusqu'à la reprise
+// @Tags recurring-transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la transaction récurrente"
+// @Success 200 {object} response.Response{data=entity.RecurringTransaction} "Transaction récurrente suspendue"
+// @Failure 400 {object} response.ErrorResponse "Transaction récurrente non active"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Transaction récurrente non trouvée"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /recurring-transactions/{id}/pause [post]
+func (h *RecurringTransactionHandler) PauseRecurringTransaction(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	recurringID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID de transaction récurrente invalide", err)
+		return
+	}
+
+	recurring, err := h.recurringService.PauseRecurringTransaction(r.Context(), userID, recurringID)
+	if err != nil {
+		h.writeError(w, err, "Erreur suspension transaction récurrente")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Transaction récurrente suspendue avec succès", recurring)
+}
+
+// ResumeRecurringTransaction reprend une transaction récurrente suspendue
+// @Summary Reprendre une transaction récurrente
+// @Description Reprend une transaction récurrente suspendue à partir de la prochaine échéance (les échéances manquées ne sont pas créées)
+// @Tags recurring-transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la transaction récurrente"
+// @Success 200 {object} response.Response{data=entity.RecurringTransaction} "Transaction récurrente reprise"
+// @Failure 400 {object} response.ErrorResponse "Transaction récurrente non suspendue"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Transaction récurrente non trouvée"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /recurring-transactions/{id}/resume [post]
+func (h *RecurringTransactionHandler) ResumeRecurringTransaction(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	recurringID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID de transaction récurrente invalide", err)
+		return
+	}
+
+	recurring, err := h.recurringService.ResumeRecurringTransaction(r.Context(), userID, recurringID)
+	if err != nil {
+		h.writeError(w, err, "Erreur reprise transaction récurrente")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Transaction récurrente reprise avec succès", recurring)
+}
+
+// SkipNextOccurrence saute la prochaine occurrence d'une transaction récurrente
+// @Summary Sauter la prochaine occurrence
+// @Description Saute la prochaine occurrence sans créer de transaction
+// @Tags recurring-transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la transaction récurrente"
+// @Success 200 {object} response.Response{data=entity.RecurringTransaction} "Occurrence sautée"
+// @Failure 400 {object} response.ErrorResponse "Aucune occurrence à venir"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Transaction récurrente non trouvée"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /recurring-transactions/{id}/skip [post]
+func (h *RecurringTransactionHandler) SkipNextOccurrence(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	recurringID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID de transaction récurrente invalide", err)
+		return
+	}
+
+	recurring, err := h.recurringService.SkipNextOccurrence(r.Context(), userID, recurringID)
+	if err != nil {
+		h.writeError(w, err, "Erreur saut d'occurrence")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Occurrence sautée avec succès", recurring)
+}
+
+// GetUpcomingOccurrences récupère les prochaines dates d'une transaction récurrente
+// @Summary Prochaines occurrences
+// @Description Récupère les dates des prochaines occurrences prévues
+// @Tags recurring-transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la transaction récurrente"
+// @Param count query int false "Nombre d'occurrences (max 50)" default(5)
+// @Success 200 {object} response.Response "Prochaines occurrences"
+// @Failure 400 {object} response.ErrorResponse "ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Transaction récurrente non trouvée"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /recurring-transactions/{id}/upcoming [get]
+func (h *RecurringTransactionHandler) GetUpcomingOccurrences(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	recurringID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID de transaction récurrente invalide", err)
+		return
+	}
+
+	count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+	if count <= 0 {
+		count = 5
+	}
+	if count > 50 {
+		count = 50
+	}
+
+	dates, err := h.recurringService.GetUpcomingOccurrences(r.Context(), userID, recurringID, count)
+	if err != nil {
+		h.writeError(w, err, "Erreur récupération prochaines occurrences")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Prochaines occurrences récupérées avec succès", map[string]interface{}{
+		"recurring_id": recurringID,
+		"dates":        dates,
+	})
+}
+
+// DeleteRecurringTransaction supprime une transaction récurrente
+// @Summary Supprimer une transaction récurrente
+// @Description Supprime un modèle de transaction récurrente. Les transactions déjà créées sont conservées.
+// @Tags recurring-transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la transaction récurrente"
+// @Success 200 {object} response.Response "Transaction récurrente supprimée"
+// @Failure 400 {object} response.ErrorResponse "ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Transaction récurrente non trouvée"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /recurring-transactions/{id} [delete]
+func (h *RecurringTransactionHandler) DeleteRecurringTransaction(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	recurringID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID de transaction récurrente invalide", err)
+		return
+	}
+
+	if err := h.recurringService.DeleteRecurringTransaction(r.Context(), userID, recurringID); err != nil {
+		h.writeError(w, err, "Erreur suppression transaction récurrente")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Transaction récurrente supprimée avec succès", nil)
+}
+
+// writeError traduit les erreurs du domaine en codes HTTP
+func (h *RecurringTransactionHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, entity.ErrRecurringTransactionNotFound):
+		response.Error(w, http.StatusNotFound, "Transaction récurrente non trouvée", err)
+	case errors.Is(err, entity.ErrInvalidRecurringTransactionData):
+		response.Error(w, http.StatusBadRequest, "Données invalides", err)
+	default:
+		h.logger.Error(message, logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, message, err)
+	}
+}
@@ -205,7 +205,7 @@ func (h *SavingGoalHandler) UpdateSavingGoal(w http.ResponseWriter, r *http.Requ
 
 // DeleteSavingGoal supprime un objectif d'épargne
 // @Summary Supprimer un objectif d'épargne
-// @Description Supprime un objectif d'épargne existant
+// @Description Met un objectif d'épargne à la corbeille ; il peut être restauré jusqu'à sa purge
 // @Tags saving-goals
 // @Accept json
 // @Produce json
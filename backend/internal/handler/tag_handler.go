@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"backend/internal/domaine/entity"
+	"backend/internal/service"
+	"backend/pkg/logger"
+	"backend/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// TagHandler gère les requêtes HTTP pour les étiquettes de transactions
+type TagHandler struct {
+	tagService *service.TagService
+	logger     logger.Logger
+}
+
+// NewTagHandler crée une nouvelle instance de TagHandler
+func NewTagHandler(tagService *service.TagService, logger logger.Logger) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+		logger:     logger,
+	}
+}
+
+// CreateTag crée une nouvelle étiquette
+// @Summary Créer une étiquette
+// @Description Crée une étiquette libre (« voyage d'affaires », « remboursable »...) à poser sur les transactions ; le nom est unique sans tenir compte de la casse
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tag body entity.CreateTagRequest true "Données de l'étiquette"
+// @Success 201 {object} response.Response{data=entity.Tag} "Étiquette créée"
+// @Failure 400 {object} response.ErrorResponse "Données invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 409 {object} response.ErrorResponse "Étiquette déjà existante"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /tags [post]
+func (h *TagHandler) CreateTag(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	var req entity.CreateTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Erreur décodage JSON", logger.Error(err))
+		response.Error(w, http.StatusBadRequest, "Données JSON invalides", err)
+		return
+	}
+
+	tag, err := h.tagService.CreateTag(r.Context(), userID, req)
+	if err != nil {
+		h.writeError(w, err, "Erreur création étiquette")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Étiquette créée avec succès", tag)
+}
+
+// GetTags récupère les étiquettes de l'utilisateur
+// @Summary Récupérer les étiquettes
+// @Description Récupère les étiquettes de l'utilisateur authentifié, par ordre alphabétique
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]entity.Tag} "Étiquettes récupérées"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /tags [get]
+func (h *TagHandler) GetTags(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	tags, err := h.tagService.GetTags(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, err, "Erreur récupération étiquettes")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Étiquettes récupérées avec succès", tags)
+}
+
+// GetTag récupère une étiquette
+// @Summary Récupérer une étiquette
+// @Description Récupère une étiquette de l'utilisateur par son ID
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de l'étiquette"
+// @Success 200 {object} response.Response{data=entity.Tag} "Étiquette récupérée"
+// @Failure 400 {object} response.ErrorResponse "ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Étiquette non trouvée"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /tags/{id} [get]
+func (h *TagHandler) GetTag(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	tagID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID d'étiquette invalide", err)
+		return
+	}
+
+	tag, err := h.tagService.GetTag(r.Context(), userID, tagID)
+	if err != nil {
+		h.writeError(w, err, "Erreur récupération étiquette")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Étiquette récupérée avec succès", tag)
+}
+
+// UpdateTag modifie une étiquette
+// @Summary Modifier une étiquette
+// @Description Renomme ou change la couleur d'une étiquette
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de l'étiquette"
+// @Param tag body entity.UpdateTagRequest true "Champs à modifier"
+// @Success 200 {object} response.Response{data=entity.Tag} "Étiquette modifiée"
+// @Failure 400 {object} response.ErrorResponse "Données invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Étiquette non trouvée"
+// @Failure 409 {object} response.ErrorResponse "Étiquette déjà existante"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /tags/{id} [put]
+func (h *TagHandler) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	tagID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID d'étiquette invalide", err)
+		return
+	}
+
+	var req entity.UpdateTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Erreur décodage JSON", logger.Error(err))
+		response.Error(w, http.StatusBadRequest, "Données JSON invalides", err)
+		return
+	}
+
+	tag, err := h.tagService.UpdateTag(r.Context(), userID, tagID, req)
+	if err != nil {
+		h.writeError(w, err, "Erreur mise à jour étiquette")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Étiquette mise à jour avec succès", tag)
+}
+
+// DeleteTag supprime une étiquette
+// @Summary Supprimer une étiquette
+// @Description Supprime une étiquette et la retire de toutes les transactions (les transactions sont conservées)
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de l'étiquette"
+// @Success 200 {object} response.Response "Étiquette supprimée"
+// @Failure 400 {object} response.ErrorResponse "ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Étiquette non trouvée"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /tags/{id} [delete]
+func (h *TagHandler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	tagID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID d'étiquette invalide", err)
+		return
+	}
+
+	if err := h.tagService.DeleteTag(r.Context(), userID, tagID); err != nil {
+		h.writeError(w, err, "Erreur suppression étiquette")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Étiquette supprimée avec succès", nil)
+}
+
+// TagTransactions pose des étiquettes sur plusieurs transactions
+// @Summary Étiqueter des transactions en masse
+// @Description Pose chaque étiquette sur chaque transaction (500 transactions et 20 étiquettes maximum) ; les étiquettes déjà posées sont conservées
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entity.TagTransactionsRequest true "Transactions et étiquettes"
+// @Success 200 {object} response.Response{data=entity.TagTransactionsResponse} "Transactions étiquetées"
+// @Failure 400 {object} response.ErrorResponse "Données invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Transaction ou étiquette non trouvée"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /tags/apply [post]
+func (h *TagHandler) TagTransactions(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	var req entity.TagTransactionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Erreur décodage JSON", logger.Error(err))
+		response.Error(w, http.StatusBadRequest, "Données JSON invalides", err)
+		return
+	}
+
+	result, err := h.tagService.TagTransactions(r.Context(), userID, req)
+	if err != nil {
+		h.writeError(w, err, "Erreur étiquetage des transactions")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Transactions étiquetées avec succès", result)
+}
+
+// UntagTransactions retire des étiquettes de plusieurs transactions
+// @Summary Retirer des étiquettes en masse
+// @Description Retire chaque étiquette de chaque transaction (500 transactions et 20 étiquettes maximum)
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entity.TagTransactionsRequest true "Transactions et étiquettes"
+// @Success 200 {object} response.Response{data=entity.TagTransactionsResponse} "Étiquettes retirées"
+// @Failure 400 {object} response.ErrorResponse "Données invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Transaction ou étiquette non trouvée"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /tags/remove [post]
+func (h *TagHandler) UntagTransactions(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	var req entity.TagTransactionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Erreur décodage JSON", logger.Error(err))
+		response.Error(w, http.StatusBadRequest, "Données JSON invalides", err)
+		return
+	}
+
+	result, err := h.tagService.UntagTransactions(r.Context(), userID, req)
+	if err != nil {
+		h.writeError(w, err, "Erreur retrait des étiquettes")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Étiquettes retirées avec succès", result)
+}
+
+// writeError traduit les erreurs du domaine en codes HTTP
+func (h *TagHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, entity.ErrTagNotFound):
+		response.Error(w, http.StatusNotFound, "Étiquette non trouvée", err)
+	case errors.Is(err, entity.ErrTransactionNotFound):
+		response.Error(w, http.StatusNotFound, "Transaction non trouvée", err)
+	case errors.Is(err, entity.ErrTagAlreadyExists):
+		response.Error(w, http.StatusConflict, "Étiquette déjà existante", err)
+	case errors.Is(err, entity.ErrInvalidTagData):
+		response.Error(w, http.StatusBadRequest, "Données invalides", err)
+	default:
+		h.logger.Error(message, logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, message, err)
+	}
+}
@@ -0,0 +1,332 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"backend/internal/domaine/entity"
+	"backend/internal/service"
+	"backend/pkg/logger"
+	"backend/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// TontineHandler gère les requêtes HTTP pour les tontines
+type TontineHandler struct {
+	tontineService *service.TontineService
+	logger         logger.Logger
+}
+
+// NewTontineHandler crée une nouvelle instance de TontineHandler
+func NewTontineHandler(tontineService *service.TontineService, logger logger.Logger) *TontineHandler {
+	return &TontineHandler{
+		tontineService: tontineService,
+		logger:         logger,
+	}
+}
+
+// CreateTontine enregistre une tontine
+// @Summary Enregistrer une tontine
+// @Description Enregistre une tontine (njangi) avec ses membres dans l'ordre de passage, le montant et la fréquence des cotisations ; crée le compte de tontine et génère un tour par membre
+// @Tags tontines
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tontine body entity.CreateTontineRequest true "Tontine"
+// @Success 201 {object} response.Response{data=entity.Tontine} "Tontine enregistrée"
+// @Failure 400 {object} response.ErrorResponse "Données invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /tontines [post]
+func (h *TontineHandler) CreateTontine(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	var req entity.CreateTontineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Erreur décodage JSON", logger.Error(err))
+		response.Error(w, http.StatusBadRequest, "Données JSON invalides", err)
+		return
+	}
+
+	tontine, err := h.tontineService.CreateTontine(r.Context(), userID, req)
+	if err != nil {
+		h.writeError(w, err, "Erreur création tontine")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "Tontine enregistrée avec succès", tontine)
+}
+
+// GetTontines récupère les tontines de l'utilisateur
+// @Summary Récupérer les tontines
+// @Description Récupère les tontines de l'utilisateur avec leur compte et leurs membres
+// @Tags tontines
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]entity.Tontine} "Tontines récupérées"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /tontines [get]
+func (h *TontineHandler) GetTontines(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	tontines, err := h.tontineService.GetTontines(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, err, "Erreur récupération tontines")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Tontines récupérées avec succès", tontines)
+}
+
+// GetTontine récupère une tontine avec ses tours
+// @Summary Récupérer une tontine
+// @Description Récupère une tontine avec ses membres, ses tours et les cotisations de chaque tour
+// @Tags tontines
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la tontine"
+// @Success 200 {object} response.Response{data=entity.Tontine} "Tontine récupérée"
+// @Failure 400 {object} response.ErrorResponse "ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Tontine non trouvée"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /tontines/{id} [get]
+func (h *TontineHandler) GetTontine(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	tontineID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID de tontine invalide", err)
+		return
+	}
+
+	tontine, err := h.tontineService.GetTontine(r.Context(), userID, tontineID)
+	if err != nil {
+		h.writeError(w, err, "Erreur récupération tontine")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Tontine récupérée avec succès", tontine)
+}
+
+// DeleteTontine supprime une tontine
+// @Summary Supprimer une tontine
+// @Description Supprime une tontine avec ses tours ; le compte de tontine et les transferts de l'utilisateur sont conservés
+// @Tags tontines
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la tontine"
+// @Success 200 {object} response.Response "Tontine supprimée"
+// @Failure 400 {object} response.ErrorResponse "ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Tontine non trouvée"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /tontines/{id} [delete]
+func (h *TontineHandler) DeleteTontine(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	tontineID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID de tontine invalide", err)
+		return
+	}
+
+	if err := h.tontineService.DeleteTontine(r.Context(), userID, tontineID); err != nil {
+		h.writeError(w, err, "Erreur suppression tontine")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Tontine supprimée avec succès", nil)
+}
+
+// RecordContribution enregistre la cotisation d'un membre pour un tour
+// @Summary Enregistrer une cotisation
+// @Description Enregistre la cotisation d'un membre pour un tour ; celle de l'utilisateur est transférée de son compte vers le compte de la tontine
+// @Tags tontines
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la tontine"
+// @Param number path int true "Numéro du tour"
+// @Param contribution body entity.RecordTontineContributionRequest true "Cotisation"
+// @Success 200 {object} response.Response{data=entity.Tontine} "Cotisation enregistrée"
+// @Failure 400 {object} response.ErrorResponse "Données invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Tontine non trouvée"
+// @Failure 409 {object} response.ErrorResponse "Cotisation déjà enregistrée"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /tontines/{id}/cycles/{number}/contributions [post]
+func (h *TontineHandler) RecordContribution(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	tontineID, number, ok := h.parseCycle(w, r)
+	if !ok {
+		return
+	}
+
+	var req entity.RecordTontineContributionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Erreur décodage JSON", logger.Error(err))
+		response.Error(w, http.StatusBadRequest, "Données JSON invalides", err)
+		return
+	}
+
+	tontine, err := h.tontineService.RecordContribution(r.Context(), userID, tontineID, number, req)
+	if err != nil {
+		h.writeError(w, err, "Erreur enregistrement cotisation")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Cotisation enregistrée avec succès", tontine)
+}
+
+// RecordPayout enregistre le versement de la cagnotte d'un tour
+// @Summary Verser la cagnotte d'un tour
+// @Description Enregistre le versement de la cagnotte d'un tour à son bénéficiaire ; celle de l'utilisateur est transférée du compte de la tontine vers son compte
+// @Tags tontines
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la tontine"
+// @Param number path int true "Numéro du tour"
+// @Param payout body entity.RecordTontinePayoutRequest true "Versement"
+// @Success 200 {object} response.Response{data=entity.Tontine} "Cagnotte versée"
+// @Failure 400 {object} response.ErrorResponse "Données invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Tontine non trouvée"
+// @Failure 409 {object} response.ErrorResponse "Cagnotte déjà versée"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /tontines/{id}/cycles/{number}/payout [post]
+func (h *TontineHandler) RecordPayout(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	tontineID, number, ok := h.parseCycle(w, r)
+	if !ok {
+		return
+	}
+
+	var req entity.RecordTontinePayoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Erreur décodage JSON", logger.Error(err))
+		response.Error(w, http.StatusBadRequest, "Données JSON invalides", err)
+		return
+	}
+
+	tontine, err := h.tontineService.RecordPayout(r.Context(), userID, tontineID, number, req)
+	if err != nil {
+		h.writeError(w, err, "Erreur versement cagnotte")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Cagnotte versée avec succès", tontine)
+}
+
+// GetReminders récupère les cotisations en retard et à venir
+// @Summary Récupérer les rappels de cotisations
+// @Description Récupère les cotisations de l'utilisateur en retard et celles dues dans les prochains jours, par date
+// @Tags tontines
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Nombre de jours à venir (défaut: 7)"
+// @Success 200 {object} response.Response{data=[]entity.TontineReminder} "Rappels récupérés"
+// @Failure 400 {object} response.ErrorResponse "Paramètre invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /tontines/reminders [get]
+func (h *TontineHandler) GetReminders(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	days := 7
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		parsed, err := strconv.Atoi(daysStr)
+		if err != nil || parsed < 0 || parsed > 366 {
+			response.Error(w, http.StatusBadRequest, "Nombre de jours invalide", err)
+			return
+		}
+		days = parsed
+	}
+
+	reminders, err := h.tontineService.GetReminders(r.Context(), userID, days)
+	if err != nil {
+		h.writeError(w, err, "Erreur récupération rappels de cotisations")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Rappels de cotisations récupérés avec succès", reminders)
+}
+
+// parseCycle lit l'ID de la tontine et le numéro du tour dans l'URL
+func (h *TontineHandler) parseCycle(w http.ResponseWriter, r *http.Request) (uuid.UUID, int, bool) {
+	tontineID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID de tontine invalide", err)
+		return uuid.Nil, 0, false
+	}
+
+	number, err := strconv.Atoi(chi.URLParam(r, "number"))
+	if err != nil || number < 1 {
+		response.Error(w, http.StatusBadRequest, "Numéro de tour invalide", err)
+		return uuid.Nil, 0, false
+	}
+	return tontineID, number, true
+}
+
+// writeError traduit les erreurs du domaine en codes HTTP
+func (h *TontineHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, entity.ErrTontineNotFound):
+		response.Error(w, http.StatusNotFound, "Tontine non trouvée", err)
+	case errors.Is(err, entity.ErrTontineContributionRecorded):
+		response.Error(w, http.StatusConflict, "Cotisation déjà enregistrée", err)
+	case errors.Is(err, entity.ErrTontinePayoutRecorded):
+		response.Error(w, http.StatusConflict, "Cagnotte déjà versée", err)
+	case errors.Is(err, entity.ErrReconciliationPeriodLocked):
+		response.Error(w, http.StatusConflict, "Période rapprochée", err)
+	case errors.Is(err, entity.ErrInvalidTontineData), errors.Is(err, entity.ErrInvalidAmount),
+		errors.Is(err, entity.ErrInvalidCurrency), errors.Is(err, entity.ErrCurrencyMismatch):
+		response.Error(w, http.StatusBadRequest, "Données invalides", err)
+	default:
+		h.logger.Error(message, logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, message, err)
+	}
+}
@@ -2,11 +2,17 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"backend/internal/domaine/entity"
 	"backend/internal/service"
+	"backend/internal/service/export"
 	"backend/pkg/logger"
 	"backend/pkg/response"
 
@@ -30,7 +36,7 @@ func NewTransactionHandler(transactionService *service.TransactionService, logge
 
 // CreateTransaction crée une nouvelle transaction
 // @Summary Créer une nouvelle transaction
-// @Description Crée une nouvelle transaction financière pour l'utilisateur authentifié
+// @Description Crée une nouvelle transaction financière pour l'utilisateur authentifié. Un remboursement (type refund) référence la dépense d'origine (refund_of_id), dont il reprend par défaut le compte et la catégorie ; le total remboursé ne peut pas dépasser le montant de la dépense. Le statut est cleared par défaut ; une transaction datée dans le futur est planifiée (scheduled) et une transaction en attente (pending) ne compte que dans le solde disponible. Un paiement en devise étrangère conserve son montant d'origine (original_amount, original_currency) ; sans amount, celui-ci est converti au taux de change de la date
 // @Tags transactions
 // @Accept json
 // @Produce json
@@ -39,6 +45,7 @@ func NewTransactionHandler(transactionService *service.TransactionService, logge
 // @Success 201 {object} response.Response "Transaction créée"
 // @Failure 400 {object} response.ErrorResponse "Données invalides"
 // @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 409 {object} response.ErrorResponse{data=[]entity.Transaction} "Référence externe déjà enregistrée, compte clôturé, date dans une période rapprochée, ou transaction probablement en double (data contient les transactions similaires ; renvoyer avec allow_duplicate=true pour confirmer)"
 // @Failure 500 {object} response.ErrorResponse "Erreur serveur"
 // @Router /transactions [post]
 func (h *TransactionHandler) CreateTransaction(w http.ResponseWriter, r *http.Request) {
@@ -74,6 +81,43 @@ func (h *TransactionHandler) CreateTransaction(w http.ResponseWriter, r *http.Re
 
 	transaction, err := h.transactionService.CreateTransaction(r.Context(), userID, req)
 	if err != nil {
+		if errors.Is(err, entity.ErrDuplicateExternalRef) {
+			response.Error(w, http.StatusConflict, "Transaction déjà enregistrée", err)
+			return
+		}
+		if errors.Is(err, entity.ErrInvalidTransactionData) {
+			response.Error(w, http.StatusBadRequest, "Transaction invalide", err)
+			return
+		}
+		if errors.Is(err, entity.ErrTagNotFound) {
+			response.Error(w, http.StatusBadRequest, "Étiquette invalide", err)
+			return
+		}
+		if errors.Is(err, entity.ErrInvalidRefund) || errors.Is(err, entity.ErrRefundExceedsOriginal) {
+			response.Error(w, http.StatusBadRequest, "Remboursement invalide", err)
+			return
+		}
+		if errors.Is(err, entity.ErrInvalidTransactionStatus) {
+			response.Error(w, http.StatusBadRequest, "Statut invalide", err)
+			return
+		}
+		if errors.Is(err, entity.ErrInvalidCurrency) || errors.Is(err, entity.ErrExchangeRateNotFound) {
+			response.Error(w, http.StatusBadRequest, "Montant d'origine invalide", err)
+			return
+		}
+		if errors.Is(err, entity.ErrReconciliationPeriodLocked) {
+			response.Error(w, http.StatusConflict, "Période rapprochée", err)
+			return
+		}
+		if errors.Is(err, entity.ErrAccountArchived) {
+			response.Error(w, http.StatusConflict, "Compte clôturé", err)
+			return
+		}
+		var duplicateErr *entity.DuplicateTransactionError
+		if errors.As(err, &duplicateErr) {
+			response.ErrorWithData(w, http.StatusConflict, "Transaction probablement en double", "POSSIBLE_DUPLICATE", err, duplicateErr.Matches)
+			return
+		}
 		h.logger.Error("Erreur création transaction", logger.Error(err))
 		response.Error(w, http.StatusInternalServerError, "Erreur création transaction", err)
 		return
@@ -82,6 +126,194 @@ func (h *TransactionHandler) CreateTransaction(w http.ResponseWriter, r *http.Re
 	response.Success(w, http.StatusCreated, "Transaction créée avec succès", transaction)
 }
 
+// ParseSMS analyse des SMS de confirmation mobile money
+// @Summary Analyser des SMS mobile money
+// @Description Transforme des SMS de confirmation MTN MoMo ou Orange Money en brouillons de transactions (montant, frais, correspondant, référence, solde après opération) rattachés au compte mobile money correspondant. Avec confirm=true, les transactions sont créées ; un SMS déjà enregistré (même référence) est signalé comme doublon.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entity.ParseSMSRequest true "SMS à analyser"
+// @Success 200 {object} response.Response{data=entity.ParseSMSResponse} "SMS analysés"
+// @Failure 400 {object} response.ErrorResponse "Données invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /transactions/parse-sms [post]
+func (h *TransactionHandler) ParseSMS(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	var req entity.ParseSMSRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Erreur décodage JSON", logger.Error(err))
+		response.Error(w, http.StatusBadRequest, "Données JSON invalides", err)
+		return
+	}
+
+	result, err := h.transactionService.ParseMobileMoneySMS(r.Context(), userID, req)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidSMSData) {
+			response.Error(w, http.StatusBadRequest, "Données invalides", err)
+			return
+		}
+		h.logger.Error("Erreur analyse SMS", logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Erreur analyse SMS", err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, "SMS analysés avec succès", result)
+}
+
+// ScanDuplicates recherche les transactions en double
+// @Summary Rechercher les doublons
+// @Description Regroupe les transactions existantes probablement en double (même compte, type et montant, libellé équivalent, dates proches)
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param window_days query int false "Écart maximal entre les dates, en jours (défaut: 2, max: 31)"
+// @Success 200 {object} response.Response{data=entity.DuplicateScanResponse} "Doublons trouvés"
+// @Failure 400 {object} response.ErrorResponse "Paramètres invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /transactions/duplicates [get]
+func (h *TransactionHandler) ScanDuplicates(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	windowDays := 0
+	if value := r.URL.Query().Get("window_days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "window_days invalide", err)
+			return
+		}
+		windowDays = parsed
+	}
+
+	result, err := h.transactionService.ScanDuplicates(r.Context(), userID, windowDays)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidTransactionQuery) {
+			response.Error(w, http.StatusBadRequest, "Paramètres invalides", err)
+			return
+		}
+		h.logger.Error("Erreur recherche des doublons", logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Erreur recherche des doublons", err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Doublons récupérés avec succès", result)
+}
+
+// MergeDuplicates fusionne des transactions en double
+// @Summary Fusionner des doublons
+// @Description Conserve une transaction et supprime ses doublons en rétablissant les soldes ; la catégorie et la référence externe manquantes sont reprises des doublons
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entity.MergeDuplicatesRequest true "Transaction conservée et doublons à supprimer"
+// @Success 200 {object} response.Response{data=entity.MergeDuplicatesResponse} "Doublons fusionnés"
+// @Failure 400 {object} response.ErrorResponse "Données invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 409 {object} response.ErrorResponse "Un doublon a des remboursements liés ou est rapproché"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /transactions/duplicates/merge [post]
+func (h *TransactionHandler) MergeDuplicates(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	var req entity.MergeDuplicatesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Erreur décodage JSON", logger.Error(err))
+		response.Error(w, http.StatusBadRequest, "Données JSON invalides", err)
+		return
+	}
+
+	result, err := h.transactionService.MergeDuplicates(r.Context(), userID, req)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidDuplicateMerge) {
+			response.Error(w, http.StatusBadRequest, "Fusion invalide", err)
+			return
+		}
+		if errors.Is(err, entity.ErrTransactionHasRefunds) {
+			response.Error(w, http.StatusConflict, "Un doublon a des remboursements", err)
+			return
+		}
+		if errors.Is(err, entity.ErrTransactionReconciled) || errors.Is(err, entity.ErrReconciliationPeriodLocked) {
+			response.Error(w, http.StatusConflict, "Un doublon est rapproché", err)
+			return
+		}
+		h.logger.Error("Erreur fusion des doublons", logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Erreur fusion des doublons", err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Doublons fusionnés avec succès", result)
+}
+
+// BulkTransactions applique une opération à plusieurs transactions
+// @Summary Opération en masse sur des transactions
+// @Description Recatégorise (recategorize), remplace (retag), ajoute (tag) ou retire (untag) des étiquettes, change de compte (move) ou supprime (delete) des transactions sélectionnées par IDs ou par filtre, en ajustant les soldes. Chaque transaction a son propre résultat ; avec all_or_nothing, tout est annulé au premier échec (réponse 409 avec les résultats)
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entity.BulkTransactionRequest true "Action, sélection et paramètres de l'opération"
+// @Success 200 {object} response.Response{data=entity.BulkTransactionResponse} "Opération effectuée"
+// @Failure 400 {object} response.ErrorResponse "Données invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 409 {object} response.ErrorResponse{data=entity.BulkTransactionResponse} "Opération annulée en mode tout ou rien, ou compte de destination clôturé"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /transactions/bulk [post]
+func (h *TransactionHandler) BulkTransactions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	var req entity.BulkTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Erreur décodage JSON", logger.Error(err))
+		response.Error(w, http.StatusBadRequest, "Données JSON invalides", err)
+		return
+	}
+
+	result, err := h.transactionService.BulkTransactions(r.Context(), userID, req)
+	if err != nil {
+		if errors.Is(err, entity.ErrBulkOperationRolledBack) {
+			response.ErrorWithData(w, http.StatusConflict, "Opération en masse annulée", "BULK_ROLLED_BACK", err, result)
+			return
+		}
+		if errors.Is(err, entity.ErrInvalidBulkOperation) || errors.Is(err, entity.ErrTagNotFound) {
+			response.Error(w, http.StatusBadRequest, "Opération en masse invalide", err)
+			return
+		}
+		if errors.Is(err, entity.ErrAccountArchived) {
+			response.Error(w, http.StatusConflict, "Compte clôturé", err)
+			return
+		}
+		h.logger.Error("Erreur opération en masse", logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Erreur opération en masse", err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Opération en masse effectuée", result)
+}
+
 // GetTransaction récupère une transaction par son ID
 // @Summary Récupérer une transaction
 // @Description Récupère une transaction spécifique par son ID
@@ -134,9 +366,18 @@ func (h *TransactionHandler) GetTransaction(w http.ResponseWriter, r *http.Reque
 // @Param account_id query string false "ID du compte"
 // @Param start_date query string false "Date de début (YYYY-MM-DD)"
 // @Param end_date query string false "Date de fin (YYYY-MM-DD)"
-// @Param page query int false "Numéro de page" default(1)
+// @Param min_amount query number false "Montant minimum"
+// @Param max_amount query number false "Montant maximum"
+// @Param search query string false "Recherche dans la description"
+// @Param tag_ids query string false "IDs d'étiquettes séparés par des virgules"
+// @Param tag_match query string false "Transactions portant l'une (any) ou toutes (all) les étiquettes" default(any)
+// @Param status query string false "Statut (scheduled/pending/cleared/reconciled)"
+// @Param sort_by query string false "Tri (date/amount/created_at)" default(date)
+// @Param sort_order query string false "Ordre de tri (asc/desc)" default(desc)
+// @Param cursor query string false "Curseur de la page suivante (next_cursor)"
 // @Param limit query int false "Nombre d'éléments par page" default(10)
-// @Success 200 {object} response.Response "Transactions récupérées"
+// @Success 200 {object} response.Response{data=entity.TransactionPageResponse} "Transactions récupérées"
+// @Failure 400 {object} response.ErrorResponse "Paramètres invalides"
 // @Failure 401 {object} response.ErrorResponse "Non authentifié"
 // @Failure 500 {object} response.ErrorResponse "Erreur serveur"
 // @Router /transactions [get]
@@ -152,11 +393,6 @@ func (h *TransactionHandler) GetTransactions(w http.ResponseWriter, r *http.Requ
 	query := r.URL.Query()
 
 	// Paramètres de pagination
-	page, _ := strconv.Atoi(query.Get("page"))
-	if page <= 0 {
-		page = 1
-	}
-
 	limit, _ := strconv.Atoi(query.Get("limit"))
 	if limit <= 0 {
 		limit = 10
@@ -166,54 +402,87 @@ func (h *TransactionHandler) GetTransactions(w http.ResponseWriter, r *http.Requ
 	}
 
 	// Paramètres de filtrage
-	txType := query.Get("type")
-	categoryIDStr := query.Get("category_id")
-	accountIDStr := query.Get("account_id")
-	startDate := query.Get("start_date")
-	endDate := query.Get("end_date")
+	transactionQuery, message, err := parseTransactionQuery(query)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, message, err)
+		return
+	}
+	transactionQuery.SortBy = query.Get("sort_by")
+	transactionQuery.SortOrder = query.Get("sort_order")
+	transactionQuery.Cursor = query.Get("cursor")
+	transactionQuery.Limit = limit
+
+	page, err := h.transactionService.GetTransactions(r.Context(), userID, transactionQuery)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidTransactionQuery) {
+			response.Error(w, http.StatusBadRequest, "Paramètres invalides", err)
+			return
+		}
+		h.logger.Error("Erreur récupération transactions", logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Erreur récupération transactions", err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Transactions récupérées avec succès", page)
+}
+
+// parseTransactionQuery lit les paramètres de filtrage des transactions ; en cas d'erreur,
+// retourne aussi le message à renvoyer au client
+func parseTransactionQuery(query url.Values) (service.TransactionQuery, string, error) {
+	result := service.TransactionQuery{
+		Type:      query.Get("type"),
+		TagMatch:  query.Get("tag_match"),
+		StartDate: query.Get("start_date"),
+		EndDate:   query.Get("end_date"),
+		Search:    query.Get("search"),
+		Status:    query.Get("status"),
+	}
 
 	// Parser les UUIDs optionnels
-	var categoryID, accountID *uuid.UUID
-	if categoryIDStr != "" {
+	if categoryIDStr := query.Get("category_id"); categoryIDStr != "" {
 		if parsed, err := uuid.Parse(categoryIDStr); err == nil {
-			categoryID = &parsed
+			result.CategoryID = &parsed
 		}
 	}
-	if accountIDStr != "" {
+	if accountIDStr := query.Get("account_id"); accountIDStr != "" {
 		if parsed, err := uuid.Parse(accountIDStr); err == nil {
-			accountID = &parsed
+			result.AccountID = &parsed
 		}
 	}
 
-	transactions, total, err := h.transactionService.GetTransactions(r.Context(), userID, service.TransactionQuery{
-		Type:       txType,
-		CategoryID: categoryID,
-		AccountID:  accountID,
-		StartDate:  startDate,
-		EndDate:    endDate,
-		Page:       page,
-		Limit:      limit,
-	})
-	if err != nil {
-		h.logger.Error("Erreur récupération transactions", logger.Error(err))
-		response.Error(w, http.StatusInternalServerError, "Erreur récupération transactions", err)
-		return
+	// Étiquettes séparées par des virgules
+	if tagIDsStr := query.Get("tag_ids"); tagIDsStr != "" {
+		for _, value := range strings.Split(tagIDsStr, ",") {
+			parsed, err := uuid.Parse(strings.TrimSpace(value))
+			if err != nil {
+				return result, "ID d'étiquette invalide", err
+			}
+			result.TagIDs = append(result.TagIDs, parsed)
+		}
 	}
 
-	response.Success(w, http.StatusOK, "Transactions récupérées avec succès", map[string]interface{}{
-		"transactions": transactions,
-		"pagination": map[string]interface{}{
-			"page":        page,
-			"limit":       limit,
-			"total":       total,
-			"total_pages": (total + int64(limit) - 1) / int64(limit),
-		},
-	})
+	// Parser les bornes de montant optionnelles
+	if minAmountStr := query.Get("min_amount"); minAmountStr != "" {
+		parsed, err := entity.ParseDecimal(minAmountStr)
+		if err != nil {
+			return result, "Montant minimum invalide", err
+		}
+		result.MinAmount = &parsed
+	}
+	if maxAmountStr := query.Get("max_amount"); maxAmountStr != "" {
+		parsed, err := entity.ParseDecimal(maxAmountStr)
+		if err != nil {
+			return result, "Montant maximum invalide", err
+		}
+		result.MaxAmount = &parsed
+	}
+
+	return result, "", nil
 }
 
 // UpdateTransaction met à jour une transaction
 // @Summary Mettre à jour une transaction
-// @Description Met à jour une transaction existante
+// @Description Met à jour une transaction existante. Une transaction rapprochée est verrouillée ; changer la date d'une transaction la planifie (date future) ou fait passer en attente une transaction planifiée
 // @Tags transactions
 // @Accept json
 // @Produce json
@@ -224,6 +493,7 @@ func (h *TransactionHandler) GetTransactions(w http.ResponseWriter, r *http.Requ
 // @Failure 400 {object} response.ErrorResponse "Données invalides"
 // @Failure 401 {object} response.ErrorResponse "Non authentifié"
 // @Failure 404 {object} response.ErrorResponse "Transaction non trouvée"
+// @Failure 409 {object} response.ErrorResponse "Transaction rapprochée, changement de solde dans une période rapprochée, ou compte de destination clôturé"
 // @Failure 500 {object} response.ErrorResponse "Erreur serveur"
 // @Router /transactions/{id} [put]
 func (h *TransactionHandler) UpdateTransaction(w http.ResponseWriter, r *http.Request) {
@@ -251,6 +521,34 @@ func (h *TransactionHandler) UpdateTransaction(w http.ResponseWriter, r *http.Re
 
 	transaction, err := h.transactionService.UpdateTransaction(r.Context(), userID, transactionID, req)
 	if err != nil {
+		if errors.Is(err, entity.ErrInvalidTransactionData) {
+			response.Error(w, http.StatusBadRequest, "Transaction invalide", err)
+			return
+		}
+		if errors.Is(err, entity.ErrTagNotFound) {
+			response.Error(w, http.StatusBadRequest, "Étiquette invalide", err)
+			return
+		}
+		if errors.Is(err, entity.ErrInvalidRefund) || errors.Is(err, entity.ErrRefundExceedsOriginal) {
+			response.Error(w, http.StatusBadRequest, "Remboursement invalide", err)
+			return
+		}
+		if errors.Is(err, entity.ErrInvalidCurrency) || errors.Is(err, entity.ErrExchangeRateNotFound) {
+			response.Error(w, http.StatusBadRequest, "Montant d'origine invalide", err)
+			return
+		}
+		if errors.Is(err, entity.ErrTransactionReconciled) {
+			response.Error(w, http.StatusConflict, "Transaction rapprochée", err)
+			return
+		}
+		if errors.Is(err, entity.ErrReconciliationPeriodLocked) {
+			response.Error(w, http.StatusConflict, "Période rapprochée", err)
+			return
+		}
+		if errors.Is(err, entity.ErrAccountArchived) {
+			response.Error(w, http.StatusConflict, "Compte clôturé", err)
+			return
+		}
 		h.logger.Error("Erreur mise à jour transaction", logger.Error(err))
 		response.Error(w, http.StatusInternalServerError, "Erreur mise à jour transaction", err)
 		return
@@ -261,7 +559,7 @@ func (h *TransactionHandler) UpdateTransaction(w http.ResponseWriter, r *http.Re
 
 // DeleteTransaction supprime une transaction
 // @Summary Supprimer une transaction
-// @Description Supprime une transaction existante
+// @Description Met une transaction à la corbeille (les deux jambes d'un transfert) et annule son effet sur le solde ; elle peut être restaurée jusqu'à sa purge
 // @Tags transactions
 // @Accept json
 // @Produce json
@@ -271,6 +569,7 @@ func (h *TransactionHandler) UpdateTransaction(w http.ResponseWriter, r *http.Re
 // @Failure 400 {object} response.ErrorResponse "ID invalide"
 // @Failure 401 {object} response.ErrorResponse "Non authentifié"
 // @Failure 404 {object} response.ErrorResponse "Transaction non trouvée"
+// @Failure 409 {object} response.ErrorResponse "Dépense ayant des remboursements liés, transaction rapprochée ou datée dans une période rapprochée"
 // @Failure 500 {object} response.ErrorResponse "Erreur serveur"
 // @Router /transactions/{id} [delete]
 func (h *TransactionHandler) DeleteTransaction(w http.ResponseWriter, r *http.Request) {
@@ -291,6 +590,18 @@ func (h *TransactionHandler) DeleteTransaction(w http.ResponseWriter, r *http.Re
 
 	err = h.transactionService.DeleteTransaction(r.Context(), userID, transactionID)
 	if err != nil {
+		if errors.Is(err, entity.ErrTransactionHasRefunds) {
+			response.Error(w, http.StatusConflict, "La transaction a des remboursements", err)
+			return
+		}
+		if errors.Is(err, entity.ErrTransactionReconciled) {
+			response.Error(w, http.StatusConflict, "Transaction rapprochée", err)
+			return
+		}
+		if errors.Is(err, entity.ErrReconciliationPeriodLocked) {
+			response.Error(w, http.StatusConflict, "Période rapprochée", err)
+			return
+		}
 		h.logger.Error("Erreur suppression transaction", logger.Error(err))
 		response.Error(w, http.StatusInternalServerError, "Erreur suppression transaction", err)
 		return
@@ -299,15 +610,287 @@ func (h *TransactionHandler) DeleteTransaction(w http.ResponseWriter, r *http.Re
 	response.Success(w, http.StatusOK, "Transaction supprimée avec succès", nil)
 }
 
+// ExportTransactions exporte les transactions filtrées
+// @Summary Exporter les transactions
+// @Description Exporte au fil de l'eau, par ordre chronologique, les transactions filtrées au format CSV, XLSX ou NDJSON. Chaque ligne comprend le chemin de la catégorie, la ventilation, le nom et la devise du compte, et les étiquettes
+// @Tags transactions
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce application/x-ndjson
+// @Security BearerAuth
+// @Param format query string false "Format (csv/xlsx/ndjson)" default(csv)
+// @Param type query string false "Type de transaction"
+// @Param category_id query string false "ID de la catégorie"
+// @Param account_id query string false "ID du compte"
+// @Param start_date query string false "Date de début (YYYY-MM-DD)"
+// @Param end_date query string false "Date de fin (YYYY-MM-DD)"
+// @Param min_amount query number false "Montant minimum"
+// @Param max_amount query number false "Montant maximum"
+// @Param search query string false "Recherche dans la description"
+// @Param tag_ids query string false "IDs d'étiquettes séparés par des virgules"
+// @Param tag_match query string false "Transactions portant l'une (any) ou toutes (all) les étiquettes" default(any)
+// @Param status query string false "Statut (scheduled/pending/cleared/reconciled)"
+// @Success 200 {file} file "Fichier d'export"
+// @Failure 400 {object} response.ErrorResponse "Paramètres invalides"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /transactions/export [get]
+func (h *TransactionHandler) ExportTransactions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	transactionQuery, message, err := parseTransactionQuery(query)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, message, err)
+		return
+	}
+	format := strings.ToLower(query.Get("format"))
+	if format == "" {
+		format = "csv"
+	}
+
+	// Un long historique peut dépasser le délai d'écriture du serveur
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	out := &exportResponseWriter{ResponseWriter: w, contentType: export.ContentType(format),
+		fileName: fmt.Sprintf("transactions-%s.%s", time.Now().Format("20060102"), format)}
+	err = h.transactionService.ExportTransactions(r.Context(), userID, format, transactionQuery, out)
+	if err != nil {
+		if out.started {
+			// Les en-têtes sont partis : le client reçoit un fichier tronqué
+			h.logger.Error("Export des transactions interrompu", logger.Error(err))
+			return
+		}
+		if errors.Is(err, entity.ErrInvalidTransactionQuery) {
+			response.Error(w, http.StatusBadRequest, "Paramètres invalides", err)
+			return
+		}
+		h.logger.Error("Erreur export des transactions", logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Erreur export des transactions", err)
+	}
+}
+
+// exportResponseWriter envoie les en-têtes du fichier d'export à la première écriture,
+// ce qui permet encore de répondre par une erreur JSON tant que rien n'a été écrit
+type exportResponseWriter struct {
+	http.ResponseWriter
+	contentType string
+	fileName    string
+	started     bool
+}
+
+func (e *exportResponseWriter) Write(p []byte) (int, error) {
+	if !e.started {
+		e.started = true
+		e.Header().Set("Content-Type", e.contentType)
+		e.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, e.fileName))
+		e.WriteHeader(http.StatusOK)
+	}
+	return e.ResponseWriter.Write(p)
+}
+
+// GetRefunds récupère les remboursements d'une dépense
+// @Summary Récupérer les remboursements d'une dépense
+// @Description Récupère les remboursements liés à une dépense ; le montant net de la dépense (net_amount) en tient compte
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la dépense"
+// @Success 200 {object} response.Response{data=[]entity.Transaction} "Remboursements récupérés"
+// @Failure 400 {object} response.ErrorResponse "ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Transaction non trouvée"
+// @Router /transactions/{id}/refunds [get]
+func (h *TransactionHandler) GetRefunds(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID de transaction invalide", err)
+		return
+	}
+
+	refunds, err := h.transactionService.GetRefunds(r.Context(), userID, transactionID)
+	if err != nil {
+		h.logger.Error("Erreur récupération remboursements", logger.Error(err))
+		response.Error(w, http.StatusNotFound, "Transaction non trouvée", err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Remboursements récupérés avec succès", refunds)
+}
+
+// GetTransactionHistory récupère l'historique des versions d'une transaction
+// @Summary Récupérer l'historique d'une transaction
+// @Description Récupère les versions d'une transaction, de la plus ancienne à la plus récente : action (create, update, delete, restore, revert), origine (api, import, ai, recurring, sms, scheduler, reconciliation, bulk), auteur, champs modifiés et état de la transaction après le changement
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la transaction"
+// @Success 200 {object} response.Response{data=[]entity.TransactionVersion} "Historique récupéré"
+// @Failure 400 {object} response.ErrorResponse "ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Transaction non trouvée"
+// @Router /transactions/{id}/history [get]
+func (h *TransactionHandler) GetTransactionHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID de transaction invalide", err)
+		return
+	}
+
+	versions, err := h.transactionService.GetTransactionHistory(r.Context(), userID, transactionID)
+	if err != nil {
+		h.logger.Error("Erreur récupération historique de transaction", logger.Error(err))
+		response.Error(w, http.StatusNotFound, "Transaction non trouvée", err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Historique récupéré avec succès", versions)
+}
+
+// RevertTransaction rétablit une version antérieure d'une transaction
+// @Summary Rétablir une version d'une transaction
+// @Description Rétablit l'état d'une version de l'historique (les deux jambes d'un transfert) en réajustant les soldes ; le retour est enregistré comme une nouvelle version. La catégorie et les étiquettes de la version sont rétablies, y compris leur absence
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la transaction"
+// @Param version path int true "Numéro de la version à rétablir"
+// @Success 200 {object} response.Response{data=entity.Transaction} "Transaction rétablie"
+// @Failure 400 {object} response.ErrorResponse "ID ou version invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Version non trouvée"
+// @Failure 409 {object} response.ErrorResponse "Retour impossible (transaction supprimée, état incohérent...), transaction rapprochée, période rapprochée ou compte clôturé"
+// @Router /transactions/{id}/history/{version}/revert [post]
+func (h *TransactionHandler) RevertTransaction(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID de transaction invalide", err)
+		return
+	}
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil || version <= 0 {
+		response.Error(w, http.StatusBadRequest, "Numéro de version invalide", err)
+		return
+	}
+
+	transaction, err := h.transactionService.RevertTransaction(r.Context(), userID, transactionID, version)
+	if err != nil {
+		if errors.Is(err, entity.ErrTransactionVersionNotFound) {
+			response.Error(w, http.StatusNotFound, "Version non trouvée", err)
+			return
+		}
+		if errors.Is(err, entity.ErrInvalidRevert) {
+			response.Error(w, http.StatusConflict, "Retour à la version impossible", err)
+			return
+		}
+		if errors.Is(err, entity.ErrTransactionReconciled) {
+			response.Error(w, http.StatusConflict, "Transaction rapprochée", err)
+			return
+		}
+		if errors.Is(err, entity.ErrReconciliationPeriodLocked) {
+			response.Error(w, http.StatusConflict, "Période rapprochée", err)
+			return
+		}
+		if errors.Is(err, entity.ErrAccountArchived) {
+			response.Error(w, http.StatusConflict, "Compte clôturé", err)
+			return
+		}
+		h.logger.Error("Erreur retour à une version de transaction", logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Erreur retour à une version de transaction", err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Transaction rétablie avec succès", transaction)
+}
+
+// SetTransactionStatus change le statut d'une transaction
+// @Summary Changer le statut d'une transaction
+// @Description Passe une transaction (les deux jambes d'un transfert) au statut scheduled, pending, cleared ou reconciled en déplaçant son effet entre le solde courant et le solde en attente du compte. Seule une transaction datée dans le futur est planifiée. Une transaction rapprochée est verrouillée : la repasser en cleared permet de la modifier
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la transaction"
+// @Param request body entity.SetTransactionStatusRequest true "Nouveau statut"
+// @Success 200 {object} response.Response{data=entity.Transaction} "Statut modifié"
+// @Failure 400 {object} response.ErrorResponse "Statut invalide ou incompatible avec la date"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /transactions/{id}/status [put]
+func (h *TransactionHandler) SetTransactionStatus(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID de transaction invalide", err)
+		return
+	}
+
+	var req entity.SetTransactionStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Erreur décodage JSON", logger.Error(err))
+		response.Error(w, http.StatusBadRequest, "Données JSON invalides", err)
+		return
+	}
+
+	transaction, err := h.transactionService.SetTransactionStatus(r.Context(), userID, transactionID, req.Status)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidTransactionStatus) {
+			response.Error(w, http.StatusBadRequest, "Statut invalide", err)
+			return
+		}
+		h.logger.Error("Erreur changement de statut de transaction", logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Erreur changement de statut de transaction", err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Statut de la transaction modifié avec succès", transaction)
+}
+
 // GetTransactionStats récupère les statistiques des transactions
 // @Summary Récupérer les statistiques des transactions
-// @Description Récupère les statistiques des transactions de l'utilisateur
+// @Description Calcule sur une période les totaux de revenus et de dépenses (transferts exclus), une série temporelle, la répartition par catégorie racine (sous-catégories détaillées), par compte et par étiquette, ainsi que la comparaison avec la période précédente. Seules les transactions passées et rapprochées sont comptées, sauf include_pending ; les transactions planifiées ne le sont jamais
 // @Tags transactions
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param period query string false "Période (week/month/year)" default(month)
-// @Success 200 {object} response.Response "Statistiques récupérées"
+// @Param period query string false "Période civile en cours (week/month/year), ignorée si start_date est fourni" default(month)
+// @Param start_date query string false "Date de début (YYYY-MM-DD)"
+// @Param end_date query string false "Date de fin incluse (YYYY-MM-DD), aujourd'hui par défaut"
+// @Param granularity query string false "Intervalle de la série (day/week/month), déduit de la période par défaut"
+// @Param account_id query string false "Limiter les statistiques à un compte"
+// @Param include_pending query bool false "Compter les transactions en attente" default(false)
+// @Success 200 {object} response.Response{data=entity.TransactionStatsResponse} "Statistiques récupérées"
+// @Failure 400 {object} response.ErrorResponse "Paramètres invalides"
 // @Failure 401 {object} response.ErrorResponse "Non authentifié"
 // @Failure 500 {object} response.ErrorResponse "Erreur serveur"
 // @Router /transactions/stats [get]
@@ -319,14 +902,37 @@ func (h *TransactionHandler) GetTransactionStats(w http.ResponseWriter, r *http.
 		return
 	}
 
-	// Récupérer la période depuis les paramètres de requête
-	period := r.URL.Query().Get("period")
-	if period == "" {
-		period = "month"
+	// Récupérer les paramètres de requête
+	values := r.URL.Query()
+	query := service.TransactionStatsQuery{
+		Period:      values.Get("period"),
+		StartDate:   values.Get("start_date"),
+		EndDate:     values.Get("end_date"),
+		Granularity: values.Get("granularity"),
+	}
+	if accountIDStr := values.Get("account_id"); accountIDStr != "" {
+		accountID, err := uuid.Parse(accountIDStr)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "ID de compte invalide", err)
+			return
+		}
+		query.AccountID = &accountID
+	}
+	if includePending := values.Get("include_pending"); includePending != "" {
+		parsed, err := strconv.ParseBool(includePending)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "Paramètre include_pending invalide", err)
+			return
+		}
+		query.IncludePending = parsed
 	}
 
-	stats, err := h.transactionService.GetTransactionStats(r.Context(), userID, period)
+	stats, err := h.transactionService.GetTransactionStats(r.Context(), userID, query)
 	if err != nil {
+		if errors.Is(err, entity.ErrInvalidTransactionQuery) {
+			response.Error(w, http.StatusBadRequest, "Paramètres invalides", err)
+			return
+		}
 		h.logger.Error("Erreur récupération statistiques", logger.Error(err))
 		response.Error(w, http.StatusInternalServerError, "Erreur récupération statistiques", err)
 		return
@@ -334,3 +940,32 @@ func (h *TransactionHandler) GetTransactionStats(w http.ResponseWriter, r *http.
 
 	response.Success(w, http.StatusOK, "Statistiques récupérées avec succès", stats)
 }
+
+// GetTransfers récupère les transferts entre comptes
+// @Summary Récupérer les transferts
+// @Description Récupère les transferts entre comptes de l'utilisateur, chacun présenté comme un mouvement unique
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]entity.Transfer} "Transferts récupérés"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /transactions/transfers [get]
+func (h *TransactionHandler) GetTransfers(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	transfers, err := h.transactionService.GetTransfers(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Erreur récupération transferts", logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Erreur récupération transferts", err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Transferts récupérés avec succès", transfers)
+}
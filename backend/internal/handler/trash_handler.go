@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"backend/internal/domaine/entity"
+	"backend/internal/service"
+	"backend/pkg/logger"
+	"backend/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// TrashHandler gère les requêtes HTTP pour la corbeille
+type TrashHandler struct {
+	trashService *service.TrashService
+	logger       logger.Logger
+}
+
+// NewTrashHandler crée une nouvelle instance de TrashHandler
+func NewTrashHandler(trashService *service.TrashService, logger logger.Logger) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+		logger:       logger,
+	}
+}
+
+// GetTrash récupère le contenu de la corbeille
+// @Summary Récupérer la corbeille
+// @Description Récupère les transactions, comptes, budgets et objectifs d'épargne supprimés, les plus récents en premier, avec leur date de suppression définitive. Les transactions et objectifs supprimés avec un compte sont comptés dans related_count du compte.
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]entity.TrashItem} "Corbeille récupérée"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /trash [get]
+func (h *TrashHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	items, err := h.trashService.ListTrash(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, err, "Erreur récupération corbeille")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Corbeille récupérée avec succès", items)
+}
+
+// RestoreItem restaure un élément de la corbeille
+// @Summary Restaurer un élément de la corbeille
+// @Description Restaure une transaction (les deux jambes d'un transfert) en réappliquant son effet sur les soldes, un compte avec les transactions et objectifs supprimés avec lui, un budget ou un objectif d'épargne
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Type d'élément" Enums(transaction, account, budget, saving_goal)
+// @Param id path string true "ID de l'élément"
+// @Success 200 {object} response.Response "Élément restauré"
+// @Failure 400 {object} response.ErrorResponse "Type ou ID invalide"
+// @Failure 401 {object} response.ErrorResponse "Non authentifié"
+// @Failure 404 {object} response.ErrorResponse "Élément absent de la corbeille"
+// @Failure 409 {object} response.ErrorResponse "Restauration impossible (compte supprimé, référence externe reprise...)"
+// @Failure 500 {object} response.ErrorResponse "Erreur serveur"
+// @Router /trash/{type}/{id}/restore [post]
+func (h *TrashHandler) RestoreItem(w http.ResponseWriter, r *http.Request) {
+	// Récupérer l'ID utilisateur du contexte
+	userID, ok := r.Context().Value("user_id").(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Utilisateur non authentifié", nil)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "ID invalide", err)
+		return
+	}
+
+	item, err := h.trashService.Restore(r.Context(), userID, chi.URLParam(r, "type"), id)
+	if err != nil {
+		h.writeError(w, err, "Erreur restauration")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "Élément restauré avec succès", item)
+}
+
+// writeError traduit une erreur du service de corbeille en réponse HTTP
+func (h *TrashHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, entity.ErrInvalidTrashType):
+		response.Error(w, http.StatusBadRequest, "Type d'élément invalide", err)
+	case errors.Is(err, entity.ErrTrashItemNotFound):
+		response.Error(w, http.StatusNotFound, "Élément non trouvé dans la corbeille", err)
+	case errors.Is(err, entity.ErrRestoreNotPossible), errors.Is(err, entity.ErrDuplicateExternalRef):
+		response.Error(w, http.StatusConflict, "Restauration impossible", err)
+	default:
+		h.logger.Error(message, logger.Error(err))
+		response.Error(w, http.StatusInternalServerError, message, err)
+	}
+}
@@ -142,6 +142,106 @@ func RunMigrations(db *pg.DB, loggerInstance logger.Logger) error {
 	// 	return fmt.Errorf("erreur mise à jour table budgets: %w", err)
 	// }
 
+	// Migration 26: Relier les deux jambes des transferts
+	if err := addTransferGroupToTransactions(db, loggerInstance); err != nil {
+		return fmt.Errorf("erreur ajout transfer_group_id à transactions: %w", err)
+	}
+
+	// Migration 27: Index pour la pagination par curseur des transactions
+	if err := addTransactionPaginationIndexes(db, loggerInstance); err != nil {
+		return fmt.Errorf("erreur création index de pagination des transactions: %w", err)
+	}
+
+	// Migration 28: Table recurring_transactions
+	if err := createRecurringTransactionsTable(db, loggerInstance); err != nil {
+		return fmt.Errorf("erreur création table recurring_transactions: %w", err)
+	}
+
+	// Migration 29: Table transaction_splits et vue transaction_lines
+	if err := createTransactionSplitsTable(db, loggerInstance); err != nil {
+		return fmt.Errorf("erreur création table transaction_splits: %w", err)
+	}
+
+	// Migration 30: Table import_batches et références externes des transactions
+	if err := createImportBatchesTable(db, loggerInstance); err != nil {
+		return fmt.Errorf("erreur création table import_batches: %w", err)
+	}
+
+	// Migration 31: Index de détection des doublons de transactions
+	if err := addTransactionDuplicateIndex(db, loggerInstance); err != nil {
+		return fmt.Errorf("erreur création index de détection des doublons: %w", err)
+	}
+
+	// Migration 32: Table transaction_attachments
+	if err := createTransactionAttachmentsTable(db, loggerInstance); err != nil {
+		return fmt.Errorf("erreur création table transaction_attachments: %w", err)
+	}
+
+	// Migration 33: Tables tags et transaction_tags
+	if err := createTagsTables(db, loggerInstance); err != nil {
+		return fmt.Errorf("erreur création tables tags: %w", err)
+	}
+
+	// Migration 34: Lien des remboursements vers leur dépense d'origine
+	if err := addTransactionRefundColumns(db, loggerInstance); err != nil {
+		return fmt.Errorf("erreur ajout colonnes de remboursement: %w", err)
+	}
+
+	// Migration 35: Corbeille (suppression logique) des transactions, comptes, budgets et objectifs d'épargne
+	if err := addSoftDeleteColumns(db, loggerInstance); err != nil {
+		return fmt.Errorf("erreur ajout colonnes de suppression logique: %w", err)
+	}
+
+	// Migration 36: Table transaction_versions (historique des modifications)
+	if err := createTransactionVersionsTable(db, loggerInstance); err != nil {
+		return fmt.Errorf("erreur création table transaction_versions: %w", err)
+	}
+
+	// Migration 37: Montants en unités mineures (BIGINT) avec leur devise
+	if err := convertAmountsToMinorUnits(db, loggerInstance); err != nil {
+		return fmt.Errorf("erreur conversion des montants en unités mineures: %w", err)
+	}
+
+	// Migration 38: Statut des transactions (planifiée, en attente, passée, rapprochée) et solde en attente des comptes
+	if err := addTransactionStatus(db, loggerInstance); err != nil {
+		return fmt.Errorf("erreur ajout du statut des transactions: %w", err)
+	}
+
+	// Migration 39: Table reconciliations (rapprochement des comptes avec leurs relevés) et période verrouillée des comptes
+	if err := createReconciliationsTable(db, loggerInstance); err != nil {
+		return fmt.Errorf("erreur création table reconciliations: %w", err)
+	}
+
+	// Migration 40: Table account_balance_snapshots (historique des soldes de fin de journée des comptes)
+	if err := createAccountBalanceSnapshotsTable(db, loggerInstance); err != nil {
+		return fmt.Errorf("erreur création table account_balance_snapshots: %w", err)
+	}
+
+	// Migration 41: Taux de change, devise de référence des utilisateurs et montant d'origine des transactions
+	if err := createExchangeRatesTable(db, loggerInstance); err != nil {
+		return fmt.Errorf("erreur création table exchange_rates: %w", err)
+	}
+
+	// Migration 42: Tables loans et loan_installments (emprunts et échéanciers d'amortissement)
+	if err := createLoansTables(db, loggerInstance); err != nil {
+		return fmt.Errorf("erreur création tables loans: %w", err)
+	}
+
+	// Migration 43: Tables des tontines (membres, tours et cotisations)
+	if err := createTontinesTables(db, loggerInstance); err != nil {
+		return fmt.Errorf("erreur création tables tontines: %w", err)
+	}
+
+	// Migration 44: Clôture des comptes (archived_at)
+	if err := addAccountArchivedAt(db, loggerInstance); err != nil {
+		return fmt.Errorf("erreur ajout colonne archived_at: %w", err)
+	}
+
+	// Migration 45: Fonction to_base_minor (montants des statistiques en unités mineures de la devise de référence)
+	if err := createToBaseMinorFunction(db, loggerInstance); err != nil {
+		return fmt.Errorf("erreur création fonction to_base_minor: %w", err)
+	}
+
 	loggerInstance.Info("Toutes les migrations ont été exécutées avec succès")
 	return nil
 }
@@ -1056,7 +1156,7 @@ func createAccountsTable(db *pg.DB, loggerInstance logger.Logger) error {
 		END IF;
 		
 		-- Ajouter la nouvelle contrainte avec tous les types
-		ALTER TABLE accounts ADD CONSTRAINT accounts_type_check CHECK (type IN ('checking', 'savings', 'mobile_money', 'cash', 'bank'));
+		ALTER TABLE accounts ADD CONSTRAINT accounts_type_check CHECK (type IN ('checking', 'savings', 'mobile_money', 'cash', 'bank', 'debt', 'tontine'));
 	END $$;
 	`
 
@@ -1411,3 +1511,887 @@ func updateBudgetsTable(db *pg.DB, loggerInstance logger.Logger) error {
 	loggerInstance.Info("Table budgets mise à jour")
 	return nil
 }
+
+// addTransferGroupToTransactions ajoute la colonne transfer_group_id qui relie les deux jambes d'un transfert
+func addTransferGroupToTransactions(db *pg.DB, loggerInstance logger.Logger) error {
+	query := `
+	DO $$ 
+	BEGIN 
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'transactions' AND column_name = 'transfer_group_id') THEN
+			ALTER TABLE transactions ADD COLUMN transfer_group_id UUID;
+		END IF;
+	END $$;
+	
+	CREATE INDEX IF NOT EXISTS idx_transactions_transfer_group_id ON transactions(transfer_group_id);
+	`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		loggerInstance.Error("Erreur ajout colonne transfer_group_id", logger.Error(err))
+		return err
+	}
+
+	loggerInstance.Info("Colonne transfer_group_id ajoutée à transactions (si nécessaire)")
+	return nil
+}
+
+// addTransactionPaginationIndexes crée les index composites utilisés par le tri et la pagination par curseur
+func addTransactionPaginationIndexes(db *pg.DB, loggerInstance logger.Logger) error {
+	query := `
+	CREATE INDEX IF NOT EXISTS idx_transactions_user_date_cursor ON transactions(user_id, date DESC, created_at DESC, id DESC);
+	CREATE INDEX IF NOT EXISTS idx_transactions_user_amount_cursor ON transactions(user_id, amount DESC, created_at DESC, id DESC);
+	CREATE INDEX IF NOT EXISTS idx_transactions_user_created_cursor ON transactions(user_id, created_at DESC, id DESC);
+	`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		loggerInstance.Error("Erreur création index de pagination des transactions", logger.Error(err))
+		return err
+	}
+
+	loggerInstance.Info("Index de pagination des transactions créés (si nécessaire)")
+	return nil
+}
+
+// createRecurringTransactionsTable crée la table recurring_transactions et relie les occurrences générées
+// à leur modèle. L'index unique (recurring_id, date) garantit qu'une occurrence n'est créée qu'une seule fois.
+func createRecurringTransactionsTable(db *pg.DB, loggerInstance logger.Logger) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS recurring_transactions (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+		category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+		type VARCHAR(20) NOT NULL CHECK (type IN ('income', 'expense')),
+		amount DECIMAL(10,2) NOT NULL CHECK (amount > 0),
+		description VARCHAR(255) NOT NULL,
+		frequency VARCHAR(20) NOT NULL CHECK (frequency IN ('daily', 'weekly', 'monthly', 'yearly')),
+		"interval" INTEGER NOT NULL DEFAULT 1 CHECK ("interval" > 0),
+		day_of_month INTEGER CHECK (day_of_month BETWEEN 1 AND 31),
+		start_date DATE NOT NULL,
+		end_date DATE,
+		max_occurrences INTEGER CHECK (max_occurrences > 0),
+		occurrence_count INTEGER NOT NULL DEFAULT 0,
+		next_occurrence DATE,
+		status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'paused', 'completed')),
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_recurring_transactions_user_id ON recurring_transactions(user_id);
+	CREATE INDEX IF NOT EXISTS idx_recurring_transactions_due ON recurring_transactions(next_occurrence) WHERE status = 'active';
+
+	DO $$ 
+	BEGIN 
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'transactions' AND column_name = 'recurring_id') THEN
+			ALTER TABLE transactions ADD COLUMN recurring_id UUID REFERENCES recurring_transactions(id) ON DELETE SET NULL;
+		END IF;
+	END $$;
+
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_recurring_occurrence ON transactions(recurring_id, date) WHERE recurring_id IS NOT NULL;
+	`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		loggerInstance.Error("Erreur création table recurring_transactions", logger.Error(err))
+		return err
+	}
+
+	loggerInstance.Info("Table recurring_transactions créée avec succès")
+	return nil
+}
+
+// createTransactionSplitsTable crée la table des lignes de ventilation et la vue transaction_lines,
+// qui expose une ligne par catégorie imputée (les lignes de ventilation, ou la transaction elle-même
+// si elle n'est pas ventilée). Les agrégats par catégorie doivent s'appuyer sur cette vue.
+func createTransactionSplitsTable(db *pg.DB, loggerInstance logger.Logger) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS transaction_splits (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+		category_id UUID NOT NULL REFERENCES categories(id) ON DELETE RESTRICT,
+		amount DECIMAL(10,2) NOT NULL CHECK (amount > 0),
+		note VARCHAR(255),
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_transaction_splits_transaction_id ON transaction_splits(transaction_id);
+	CREATE INDEX IF NOT EXISTS idx_transaction_splits_category_id ON transaction_splits(category_id);
+
+	DROP VIEW IF EXISTS transaction_lines;
+	CREATE VIEW transaction_lines AS
+	SELECT t.id AS transaction_id, t.user_id, t.account_id, t.type, t.date, s.category_id, s.amount
+	FROM transactions t
+	JOIN transaction_splits s ON s.transaction_id = t.id
+	UNION ALL
+	SELECT t.id AS transaction_id, t.user_id, t.account_id, t.type, t.date, t.category_id, t.amount
+	FROM transactions t
+	WHERE NOT EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id);
+	`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		loggerInstance.Error("Erreur création table transaction_splits", logger.Error(err))
+		return err
+	}
+
+	loggerInstance.Info("Table transaction_splits et vue transaction_lines créées avec succès")
+	return nil
+}
+
+// createImportBatchesTable crée la table des lots d'import et ajoute aux transactions la référence externe
+// (unique par compte, pour ignorer les lignes déjà importées) et le lot d'import d'origine
+func createImportBatchesTable(db *pg.DB, loggerInstance logger.Logger) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS import_batches (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+		format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'ofx', 'qif')),
+		file_name VARCHAR(255),
+		status VARCHAR(20) NOT NULL DEFAULT 'completed' CHECK (status IN ('completed', 'undone')),
+		total_lines INTEGER NOT NULL DEFAULT 0,
+		imported_count INTEGER NOT NULL DEFAULT 0,
+		skipped_count INTEGER NOT NULL DEFAULT 0,
+		failed_count INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		undone_at TIMESTAMP WITH TIME ZONE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_import_batches_user_account ON import_batches(user_id, account_id);
+
+	DO $$ 
+	BEGIN 
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'transactions' AND column_name = 'external_ref') THEN
+			ALTER TABLE transactions ADD COLUMN external_ref VARCHAR(128);
+		END IF;
+
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'transactions' AND column_name = 'import_batch_id') THEN
+			ALTER TABLE transactions ADD COLUMN import_batch_id UUID REFERENCES import_batches(id) ON DELETE SET NULL;
+		END IF;
+	END $$;
+
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_account_external_ref ON transactions(account_id, external_ref) WHERE external_ref IS NOT NULL;
+	CREATE INDEX IF NOT EXISTS idx_transactions_import_batch_id ON transactions(import_batch_id);
+	`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		loggerInstance.Error("Erreur création table import_batches", logger.Error(err))
+		return err
+	}
+
+	loggerInstance.Info("Table import_batches créée avec succès")
+	return nil
+}
+
+// addTransactionDuplicateIndex ajoute l'index utilisé pour rechercher les transactions similaires d'un compte
+// (même montant, date proche) lors de la détection des doublons
+func addTransactionDuplicateIndex(db *pg.DB, loggerInstance logger.Logger) error {
+	query := `
+	CREATE INDEX IF NOT EXISTS idx_transactions_account_amount_date ON transactions(account_id, amount, date);
+	`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		loggerInstance.Error("Erreur création index de détection des doublons", logger.Error(err))
+		return err
+	}
+
+	loggerInstance.Info("Index de détection des doublons créé avec succès")
+	return nil
+}
+
+// createTransactionAttachmentsTable crée la table des pièces jointes des transactions (reçus, factures).
+// Les fichiers eux-mêmes sont dans le stockage configuré ; storage_key les y retrouve.
+func createTransactionAttachmentsTable(db *pg.DB, loggerInstance logger.Logger) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS transaction_attachments (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+		file_name VARCHAR(255) NOT NULL,
+		content_type VARCHAR(100) NOT NULL,
+		size BIGINT NOT NULL CHECK (size > 0),
+		storage_key VARCHAR(512) NOT NULL UNIQUE,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_transaction_attachments_transaction_id ON transaction_attachments(transaction_id);
+	`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		loggerInstance.Error("Erreur création table transaction_attachments", logger.Error(err))
+		return err
+	}
+
+	loggerInstance.Info("Table transaction_attachments créée avec succès")
+	return nil
+}
+
+// createTagsTables crée la table des étiquettes (nom unique par utilisateur, sans tenir compte de la casse)
+// et la table de liaison transaction_tags
+func createTagsTables(db *pg.DB, loggerInstance logger.Logger) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS tags (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		name VARCHAR(50) NOT NULL,
+		color VARCHAR(20),
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+	);
+
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_user_name ON tags(user_id, LOWER(name));
+
+	CREATE TABLE IF NOT EXISTS transaction_tags (
+		transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+		tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+		PRIMARY KEY (transaction_id, tag_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_transaction_tags_tag_id ON transaction_tags(tag_id);
+	`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		loggerInstance.Error("Erreur création tables tags", logger.Error(err))
+		return err
+	}
+
+	loggerInstance.Info("Tables tags et transaction_tags créées avec succès")
+	return nil
+}
+
+// addTransactionRefundColumns relie un remboursement à sa dépense d'origine et conserve le total remboursé
+func addTransactionRefundColumns(db *pg.DB, loggerInstance logger.Logger) error {
+	query := `
+	DO $$ 
+	BEGIN 
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'transactions' AND column_name = 'refund_of_id') THEN
+			ALTER TABLE transactions ADD COLUMN refund_of_id UUID REFERENCES transactions(id) ON DELETE RESTRICT;
+		END IF;
+
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'transactions' AND column_name = 'refunded_amount') THEN
+			ALTER TABLE transactions ADD COLUMN refunded_amount DECIMAL(10,2) NOT NULL DEFAULT 0;
+		END IF;
+	END $$;
+
+	CREATE INDEX IF NOT EXISTS idx_transactions_refund_of_id ON transactions(refund_of_id) WHERE refund_of_id IS NOT NULL;
+	`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		loggerInstance.Error("Erreur ajout colonnes de remboursement", logger.Error(err))
+		return err
+	}
+
+	loggerInstance.Info("Colonnes de remboursement des transactions ajoutées avec succès")
+	return nil
+}
+
+// addSoftDeleteColumns ajoute la date de mise à la corbeille des transactions, comptes, budgets et objectifs
+// d'épargne. Les transactions et objectifs supprimés avec leur compte retiennent ce compte pour être restaurés
+// avec lui. La vue transaction_lines et l'unicité des références externes ignorent les lignes à la corbeille.
+func addSoftDeleteColumns(db *pg.DB, loggerInstance logger.Logger) error {
+	query := `
+	DO $$ 
+	BEGIN 
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'transactions' AND column_name = 'deleted_at') THEN
+			ALTER TABLE transactions ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+		END IF;
+
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'transactions' AND column_name = 'deleted_with_account_id') THEN
+			ALTER TABLE transactions ADD COLUMN deleted_with_account_id UUID;
+		END IF;
+
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'accounts' AND column_name = 'deleted_at') THEN
+			ALTER TABLE accounts ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+		END IF;
+
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'budgets' AND column_name = 'deleted_at') THEN
+			ALTER TABLE budgets ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+		END IF;
+
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'saving_goals' AND column_name = 'deleted_at') THEN
+			ALTER TABLE saving_goals ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+		END IF;
+
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'saving_goals' AND column_name = 'deleted_with_account_id') THEN
+			ALTER TABLE saving_goals ADD COLUMN deleted_with_account_id UUID;
+		END IF;
+
+		IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_transactions_account_external_ref' AND indexdef LIKE '%deleted_at%') THEN
+			DROP INDEX IF EXISTS idx_transactions_account_external_ref;
+			CREATE UNIQUE INDEX idx_transactions_account_external_ref ON transactions(account_id, external_ref) WHERE external_ref IS NOT NULL AND deleted_at IS NULL;
+		END IF;
+	END $$;
+
+	CREATE INDEX IF NOT EXISTS idx_transactions_deleted_at ON transactions(deleted_at) WHERE deleted_at IS NOT NULL;
+	CREATE INDEX IF NOT EXISTS idx_accounts_deleted_at ON accounts(deleted_at) WHERE deleted_at IS NOT NULL;
+	CREATE INDEX IF NOT EXISTS idx_budgets_deleted_at ON budgets(deleted_at) WHERE deleted_at IS NOT NULL;
+	CREATE INDEX IF NOT EXISTS idx_saving_goals_deleted_at ON saving_goals(deleted_at) WHERE deleted_at IS NOT NULL;
+
+	DROP VIEW IF EXISTS transaction_lines;
+	CREATE VIEW transaction_lines AS
+	SELECT t.id AS transaction_id, t.user_id, t.account_id, t.type, t.date, s.category_id, s.amount
+	FROM transactions t
+	JOIN transaction_splits s ON s.transaction_id = t.id
+	WHERE t.deleted_at IS NULL
+	UNION ALL
+	SELECT t.id AS transaction_id, t.user_id, t.account_id, t.type, t.date, t.category_id, t.amount
+	FROM transactions t
+	WHERE t.deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id);
+	`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		loggerInstance.Error("Erreur ajout colonnes de suppression logique", logger.Error(err))
+		return err
+	}
+
+	loggerInstance.Info("Colonnes de suppression logique ajoutées avec succès")
+	return nil
+}
+
+// createTransactionVersionsTable crée l'historique des versions des transactions : chaque création,
+// modification, suppression, restauration ou retour arrière enregistre l'état obtenu et les champs modifiés
+func createTransactionVersionsTable(db *pg.DB, loggerInstance logger.Logger) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS transaction_versions (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		version INTEGER NOT NULL,
+		action VARCHAR(20) NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore', 'revert')),
+		source VARCHAR(20) NOT NULL,
+		actor_id UUID,
+		reverted_version INTEGER,
+		changes JSONB NOT NULL DEFAULT '[]',
+		snapshot JSONB NOT NULL,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		UNIQUE (transaction_id, version)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_transaction_versions_user_id ON transaction_versions(user_id);
+	`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		loggerInstance.Error("Erreur création table transaction_versions", logger.Error(err))
+		return err
+	}
+
+	loggerInstance.Info("Table transaction_versions créée avec succès")
+	return nil
+}
+
+// convertAmountsToMinorUnits stocke les montants en nombre entier d'unités mineures de leur devise (BIGINT) au lieu
+// de DECIMAL(10,2) : centimes pour l'euro, francs pour le XAF qui n'a pas de décimales. Chaque table de montants
+// reçoit sa devise (celle du compte, XAF pour les budgets) ; les fonctions currency_exponent et minor_to_major
+// servent aux agrégats exprimés en unités de la devise. Les snapshots de l'historique passent au format Money.
+func convertAmountsToMinorUnits(db *pg.DB, loggerInstance logger.Logger) error {
+	query := `
+	CREATE OR REPLACE FUNCTION currency_exponent(code TEXT) RETURNS INTEGER AS $$
+		SELECT CASE UPPER(code)
+			WHEN 'BIF' THEN 0 WHEN 'CLP' THEN 0 WHEN 'DJF' THEN 0 WHEN 'GNF' THEN 0
+			WHEN 'ISK' THEN 0 WHEN 'JPY' THEN 0 WHEN 'KMF' THEN 0 WHEN 'KRW' THEN 0
+			WHEN 'PYG' THEN 0 WHEN 'RWF' THEN 0 WHEN 'UGX' THEN 0 WHEN 'VND' THEN 0
+			WHEN 'VUV' THEN 0 WHEN 'XAF' THEN 0 WHEN 'XOF' THEN 0 WHEN 'XPF' THEN 0
+			WHEN 'BHD' THEN 3 WHEN 'IQD' THEN 3 WHEN 'JOD' THEN 3 WHEN 'KWD' THEN 3
+			WHEN 'LYD' THEN 3 WHEN 'OMR' THEN 3 WHEN 'TND' THEN 3
+			ELSE 2
+		END
+	$$ LANGUAGE SQL IMMUTABLE;
+
+	CREATE OR REPLACE FUNCTION minor_to_major(amount BIGINT, code TEXT) RETURNS NUMERIC AS $$
+		SELECT ROUND(amount::NUMERIC / POWER(10::NUMERIC, currency_exponent(code)), currency_exponent(code))
+	$$ LANGUAGE SQL IMMUTABLE;
+
+	DO $$ 
+	BEGIN 
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'transactions' AND column_name = 'currency') THEN
+			ALTER TABLE transactions ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'XAF';
+			UPDATE transactions t SET currency = a.currency FROM accounts a WHERE a.id = t.account_id;
+		END IF;
+
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'transaction_splits' AND column_name = 'currency') THEN
+			ALTER TABLE transaction_splits ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'XAF';
+			UPDATE transaction_splits s SET currency = t.currency FROM transactions t WHERE t.id = s.transaction_id;
+		END IF;
+
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'recurring_transactions' AND column_name = 'currency') THEN
+			ALTER TABLE recurring_transactions ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'XAF';
+			UPDATE recurring_transactions r SET currency = a.currency FROM accounts a WHERE a.id = r.account_id;
+		END IF;
+
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'saving_goals' AND column_name = 'currency') THEN
+			ALTER TABLE saving_goals ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'XAF';
+			UPDATE saving_goals g SET currency = a.currency FROM accounts a WHERE a.id = g.account_id;
+		END IF;
+
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'budgets' AND column_name = 'currency') THEN
+			ALTER TABLE budgets ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'XAF';
+		END IF;
+	END $$;
+
+	-- La vue dépend des colonnes de montant : elle est recréée après leur conversion
+	DROP VIEW IF EXISTS transaction_lines;
+
+	DO $$ 
+	BEGIN 
+		IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'transactions' AND column_name = 'amount' AND data_type = 'numeric') THEN
+			ALTER TABLE transactions
+				ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * POWER(10::NUMERIC, currency_exponent(currency)))::BIGINT,
+				ALTER COLUMN refunded_amount TYPE BIGINT USING ROUND(refunded_amount * POWER(10::NUMERIC, currency_exponent(currency)))::BIGINT;
+		END IF;
+
+		IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'transaction_splits' AND column_name = 'amount' AND data_type = 'numeric') THEN
+			ALTER TABLE transaction_splits
+				ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * POWER(10::NUMERIC, currency_exponent(currency)))::BIGINT;
+		END IF;
+
+		IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'recurring_transactions' AND column_name = 'amount' AND data_type = 'numeric') THEN
+			ALTER TABLE recurring_transactions
+				ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * POWER(10::NUMERIC, currency_exponent(currency)))::BIGINT;
+		END IF;
+
+		IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'accounts' AND column_name = 'balance' AND data_type = 'numeric') THEN
+			ALTER TABLE accounts
+				ALTER COLUMN balance TYPE BIGINT USING ROUND(balance * POWER(10::NUMERIC, currency_exponent(currency)))::BIGINT;
+		END IF;
+
+		IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'budgets' AND column_name = 'amount_planned' AND data_type = 'numeric') THEN
+			ALTER TABLE budgets
+				ALTER COLUMN amount_planned TYPE BIGINT USING ROUND(amount_planned * POWER(10::NUMERIC, currency_exponent(currency)))::BIGINT,
+				ALTER COLUMN amount_spent TYPE BIGINT USING ROUND(amount_spent * POWER(10::NUMERIC, currency_exponent(currency)))::BIGINT;
+		END IF;
+
+		IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'saving_goals' AND column_name = 'target_amount' AND data_type = 'numeric') THEN
+			ALTER TABLE saving_goals
+				ALTER COLUMN target_amount TYPE BIGINT USING ROUND(target_amount * POWER(10::NUMERIC, currency_exponent(currency)))::BIGINT,
+				ALTER COLUMN current_amount TYPE BIGINT USING ROUND(current_amount * POWER(10::NUMERIC, currency_exponent(currency)))::BIGINT;
+		END IF;
+	END $$;
+
+	CREATE VIEW transaction_lines AS
+	SELECT t.id AS transaction_id, t.user_id, t.account_id, t.type, t.date, s.category_id, s.amount, t.currency
+	FROM transactions t
+	JOIN transaction_splits s ON s.transaction_id = t.id
+	WHERE t.deleted_at IS NULL
+	UNION ALL
+	SELECT t.id AS transaction_id, t.user_id, t.account_id, t.type, t.date, t.category_id, t.amount, t.currency
+	FROM transactions t
+	WHERE t.deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id);
+
+	UPDATE transaction_versions v
+	SET snapshot = jsonb_set(v.snapshot, '{amount}', jsonb_build_object(
+		'value', minor_to_major(m.minor, t.currency)::TEXT, 'minor', m.minor, 'currency', t.currency))
+	FROM transactions t,
+		LATERAL (SELECT ROUND((v.snapshot->>'amount')::NUMERIC * POWER(10::NUMERIC, currency_exponent(t.currency)))::BIGINT AS minor) m
+	WHERE t.id = v.transaction_id AND jsonb_typeof(v.snapshot->'amount') = 'number';
+
+	UPDATE transaction_versions v
+	SET snapshot = jsonb_set(v.snapshot, '{refunded_amount}', jsonb_build_object(
+		'value', minor_to_major(m.minor, t.currency)::TEXT, 'minor', m.minor, 'currency', t.currency))
+	FROM transactions t,
+		LATERAL (SELECT ROUND((v.snapshot->>'refunded_amount')::NUMERIC * POWER(10::NUMERIC, currency_exponent(t.currency)))::BIGINT AS minor) m
+	WHERE t.id = v.transaction_id AND jsonb_typeof(v.snapshot->'refunded_amount') = 'number';
+	`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		loggerInstance.Error("Erreur conversion des montants en unités mineures", logger.Error(err))
+		return err
+	}
+
+	loggerInstance.Info("Montants convertis en unités mineures avec succès")
+	return nil
+}
+
+// addTransactionStatus ajoute le cycle de vie des transactions : planifiée (datée dans le futur, sans effet sur
+// les soldes), en attente (comptée dans le solde disponible via accounts.pending_balance), passée ou rapprochée
+// (comptées dans le solde courant). Les transactions existantes sont passées.
+func addTransactionStatus(db *pg.DB, loggerInstance logger.Logger) error {
+	query := `
+	DO $$ 
+	BEGIN 
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'transactions' AND column_name = 'status') THEN
+			ALTER TABLE transactions ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'cleared'
+				CHECK (status IN ('scheduled', 'pending', 'cleared', 'reconciled'));
+		END IF;
+
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'accounts' AND column_name = 'pending_balance') THEN
+			ALTER TABLE accounts ADD COLUMN pending_balance BIGINT NOT NULL DEFAULT 0;
+		END IF;
+	END $$;
+
+	CREATE INDEX IF NOT EXISTS idx_transactions_scheduled ON transactions(date) WHERE status = 'scheduled' AND deleted_at IS NULL;
+
+	-- Les statistiques filtrent les lignes sur leur statut
+	DROP VIEW IF EXISTS transaction_lines;
+
+	CREATE VIEW transaction_lines AS
+	SELECT t.id AS transaction_id, t.user_id, t.account_id, t.type, t.date, s.category_id, s.amount, t.currency, t.status
+	FROM transactions t
+	JOIN transaction_splits s ON s.transaction_id = t.id
+	WHERE t.deleted_at IS NULL
+	UNION ALL
+	SELECT t.id AS transaction_id, t.user_id, t.account_id, t.type, t.date, t.category_id, t.amount, t.currency, t.status
+	FROM transactions t
+	WHERE t.deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id);
+
+	UPDATE transaction_versions SET snapshot = jsonb_set(snapshot, '{status}', '"cleared"') WHERE snapshot->'status' IS NULL;
+	`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		loggerInstance.Error("Erreur ajout du statut des transactions", logger.Error(err))
+		return err
+	}
+
+	loggerInstance.Info("Statut des transactions ajouté avec succès")
+	return nil
+}
+
+// createReconciliationsTable crée la table des rapprochements de comptes (un seul rapprochement ouvert par compte)
+// et ajoute accounts.reconciled_through, fin de la période rapprochée dans laquelle plus aucune écriture n'est admise
+func createReconciliationsTable(db *pg.DB, loggerInstance logger.Logger) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS reconciliations (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+		statement_date DATE NOT NULL,
+		statement_balance BIGINT NOT NULL,
+		cleared_balance BIGINT NOT NULL DEFAULT 0,
+		currency VARCHAR(3) NOT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'completed', 'cancelled')),
+		adjustment_transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+		reconciled_count INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		completed_at TIMESTAMP WITH TIME ZONE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_reconciliations_account ON reconciliations(account_id, statement_date);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_reconciliations_open ON reconciliations(account_id) WHERE status = 'open';
+
+	DO $$ 
+	BEGIN 
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'accounts' AND column_name = 'reconciled_through') THEN
+			ALTER TABLE accounts ADD COLUMN reconciled_through DATE;
+		END IF;
+	END $$;
+	`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		loggerInstance.Error("Erreur création table reconciliations", logger.Error(err))
+		return err
+	}
+
+	loggerInstance.Info("Table reconciliations créée avec succès")
+	return nil
+}
+
+// createAccountBalanceSnapshotsTable crée l'historique des soldes courants de fin de journée des comptes et le
+// reconstitue à partir des transactions passées ou rapprochées (y compris celles mises à la corbeille avec leur
+// compte, qui n'ont pas modifié son solde) : le solde d'un jour est le solde actuel moins l'effet des jours suivants
+func createAccountBalanceSnapshotsTable(db *pg.DB, loggerInstance logger.Logger) error {
+	query := `
+	DO $$ 
+	BEGIN 
+		IF NOT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_name = 'account_balance_snapshots') THEN
+			CREATE TABLE account_balance_snapshots (
+				account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+				date DATE NOT NULL,
+				change BIGINT NOT NULL DEFAULT 0,
+				balance BIGINT NOT NULL,
+				currency VARCHAR(3) NOT NULL,
+				updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+				PRIMARY KEY (account_id, date)
+			);
+
+			INSERT INTO account_balance_snapshots (account_id, date, change, balance, currency)
+			SELECT d.account_id, d.day, d.change,
+				a.balance - COALESCE(SUM(d.change) OVER (
+					PARTITION BY d.account_id ORDER BY d.day DESC ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0),
+				a.currency
+			FROM (
+				SELECT account_id, date AS day, SUM(CASE
+					WHEN type IN ('income', 'refund') THEN amount
+					WHEN type = 'transfer' AND account_id = to_account_id THEN amount
+					ELSE -amount
+				END) AS change
+				FROM transactions
+				WHERE account_id IS NOT NULL
+					AND status IN ('cleared', 'reconciled')
+					AND (deleted_at IS NULL OR deleted_with_account_id IS NOT NULL)
+				GROUP BY account_id, date
+			) d
+			JOIN accounts a ON a.id = d.account_id;
+		END IF;
+	END $$;
+	`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		loggerInstance.Error("Erreur création table account_balance_snapshots", logger.Error(err))
+		return err
+	}
+
+	loggerInstance.Info("Table account_balance_snapshots créée avec succès")
+	return nil
+}
+
+// createExchangeRatesTable crée la table des taux de change datés des utilisateurs, ajoute leur devise de référence
+// (users.base_currency) et le montant d'origine en devise étrangère des transactions. La fonction exchange_rate
+// retourne le taux en vigueur à une date (dernier taux de la paire, directe ou inverse, à cette date ou avant,
+// à défaut le plus ancien connu ; NULL sans taux) et to_base_currency convertit un montant en unités mineures
+// dans la devise de référence de l'utilisateur, en unités de cette devise.
+func createExchangeRatesTable(db *pg.DB, loggerInstance logger.Logger) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS exchange_rates (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		from_currency VARCHAR(3) NOT NULL,
+		to_currency VARCHAR(3) NOT NULL,
+		rate NUMERIC NOT NULL CHECK (rate > 0),
+		date DATE NOT NULL,
+		source VARCHAR(20) NOT NULL DEFAULT 'manual' CHECK (source IN ('manual', 'import')),
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		CHECK (from_currency <> to_currency),
+		UNIQUE (user_id, from_currency, to_currency, date)
+	);
+
+	DO $$ 
+	BEGIN 
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'users' AND column_name = 'base_currency') THEN
+			ALTER TABLE users ADD COLUMN base_currency VARCHAR(3) NOT NULL DEFAULT 'XAF';
+		END IF;
+
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'transactions' AND column_name = 'original_amount') THEN
+			ALTER TABLE transactions
+				ADD COLUMN original_amount BIGINT,
+				ADD COLUMN original_currency VARCHAR(3);
+		END IF;
+	END $$;
+
+	CREATE OR REPLACE FUNCTION exchange_rate(p_user UUID, p_from TEXT, p_to TEXT, p_date DATE) RETURNS NUMERIC AS $$
+		SELECT CASE WHEN p_from = p_to THEN 1::NUMERIC ELSE (
+			SELECT r.rate
+			FROM (
+				SELECT date, rate, 0 AS inverse FROM exchange_rates
+				WHERE user_id = p_user AND from_currency = p_from AND to_currency = p_to
+				UNION ALL
+				SELECT date, 1 / rate, 1 AS inverse FROM exchange_rates
+				WHERE user_id = p_user AND from_currency = p_to AND to_currency = p_from
+			) r
+			ORDER BY r.date <= p_date DESC, ABS(r.date - p_date), r.inverse
+			LIMIT 1
+		) END
+	$$ LANGUAGE SQL STABLE;
+
+	CREATE OR REPLACE FUNCTION to_base_currency(p_user UUID, amount BIGINT, code TEXT, p_date DATE) RETURNS NUMERIC AS $$
+		SELECT ROUND(minor_to_major(amount, code) * exchange_rate(p_user, code, u.base_currency, p_date), currency_exponent(u.base_currency))
+		FROM users u
+		WHERE u.id = p_user
+	$$ LANGUAGE SQL STABLE;
+	`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		loggerInstance.Error("Erreur création table exchange_rates", logger.Error(err))
+		return err
+	}
+
+	loggerInstance.Info("Table exchange_rates créée avec succès")
+	return nil
+}
+
+// createLoansTables crée les tables des emprunts (suivis sur un compte de type debt) et de leurs échéances. Une échéance
+// réglée référence le transfert de son capital et la dépense de ses intérêts ; reminded_at évite de rappeler deux fois
+// une échéance à venir.
+func createLoansTables(db *pg.DB, loggerInstance logger.Logger) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS loans (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		account_id UUID NOT NULL UNIQUE REFERENCES accounts(id) ON DELETE CASCADE,
+		name VARCHAR(100) NOT NULL,
+		lender VARCHAR(100),
+		principal BIGINT NOT NULL CHECK (principal > 0),
+		interest_rate NUMERIC NOT NULL CHECK (interest_rate >= 0),
+		term_months INTEGER NOT NULL CHECK (term_months > 0),
+		payment_day INTEGER NOT NULL CHECK (payment_day BETWEEN 1 AND 31),
+		start_date DATE NOT NULL,
+		payment BIGINT NOT NULL,
+		outstanding_principal BIGINT NOT NULL,
+		interest_paid BIGINT NOT NULL DEFAULT 0,
+		currency VARCHAR(3) NOT NULL,
+		interest_category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'paid_off')),
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_loans_user ON loans(user_id);
+
+	CREATE TABLE IF NOT EXISTS loan_installments (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		loan_id UUID NOT NULL REFERENCES loans(id) ON DELETE CASCADE,
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		number INTEGER NOT NULL,
+		due_date DATE NOT NULL,
+		payment BIGINT NOT NULL,
+		principal BIGINT NOT NULL,
+		interest BIGINT NOT NULL,
+		extra_principal BIGINT NOT NULL DEFAULT 0,
+		remaining_principal BIGINT NOT NULL,
+		currency VARCHAR(3) NOT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'upcoming' CHECK (status IN ('upcoming', 'missed', 'paid')),
+		paid_at DATE,
+		principal_transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+		interest_transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+		reminded_at TIMESTAMP WITH TIME ZONE,
+		UNIQUE (loan_id, number)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_loan_installments_unpaid ON loan_installments(user_id, due_date) WHERE status <> 'paid';
+	`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		loggerInstance.Error("Erreur création tables loans", logger.Error(err))
+		return err
+	}
+
+	loggerInstance.Info("Tables loans et loan_installments créées avec succès")
+	return nil
+}
+
+// createTontinesTables crée les tables des tontines (suivies sur un compte de type tontine), de leurs membres, de leurs
+// tours et des cotisations de chaque tour. Les cotisations de l'utilisateur référencent leur transfert ; reminded_at
+// évite de rappeler deux fois une cotisation à venir.
+func createTontinesTables(db *pg.DB, loggerInstance logger.Logger) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS tontines (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		account_id UUID NOT NULL UNIQUE REFERENCES accounts(id) ON DELETE CASCADE,
+		name VARCHAR(100) NOT NULL,
+		contribution_amount BIGINT NOT NULL CHECK (contribution_amount > 0),
+		frequency VARCHAR(20) NOT NULL CHECK (frequency IN ('weekly', 'biweekly', 'monthly')),
+		start_date DATE NOT NULL,
+		currency VARCHAR(3) NOT NULL,
+		total_contributed BIGINT NOT NULL DEFAULT 0,
+		total_received BIGINT NOT NULL DEFAULT 0,
+		status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'completed')),
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_tontines_user ON tontines(user_id);
+
+	CREATE TABLE IF NOT EXISTS tontine_members (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tontine_id UUID NOT NULL REFERENCES tontines(id) ON DELETE CASCADE,
+		name VARCHAR(100) NOT NULL,
+		phone VARCHAR(20),
+		payout_order INTEGER NOT NULL,
+		is_self BOOLEAN NOT NULL DEFAULT FALSE,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		UNIQUE (tontine_id, payout_order)
+	);
+
+	CREATE TABLE IF NOT EXISTS tontine_cycles (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tontine_id UUID NOT NULL REFERENCES tontines(id) ON DELETE CASCADE,
+		number INTEGER NOT NULL,
+		due_date DATE NOT NULL,
+		beneficiary_id UUID NOT NULL REFERENCES tontine_members(id) ON DELETE CASCADE,
+		payout_amount BIGINT NOT NULL,
+		currency VARCHAR(3) NOT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'paid_out')),
+		paid_out_at DATE,
+		payout_transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+		UNIQUE (tontine_id, number)
+	);
+
+	CREATE TABLE IF NOT EXISTS tontine_contributions (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tontine_id UUID NOT NULL REFERENCES tontines(id) ON DELETE CASCADE,
+		cycle_id UUID NOT NULL REFERENCES tontine_cycles(id) ON DELETE CASCADE,
+		member_id UUID NOT NULL REFERENCES tontine_members(id) ON DELETE CASCADE,
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		due_date DATE NOT NULL,
+		amount BIGINT NOT NULL,
+		currency VARCHAR(3) NOT NULL,
+		is_self BOOLEAN NOT NULL DEFAULT FALSE,
+		status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'paid')),
+		paid_at DATE,
+		transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+		reminded_at TIMESTAMP WITH TIME ZONE,
+		UNIQUE (cycle_id, member_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_tontine_contributions_self_pending ON tontine_contributions(user_id, due_date) WHERE is_self AND status = 'pending';
+	`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		loggerInstance.Error("Erreur création tables tontines", logger.Error(err))
+		return err
+	}
+
+	loggerInstance.Info("Tables tontines, tontine_members, tontine_cycles et tontine_contributions créées avec succès")
+	return nil
+}
+
+// addAccountArchivedAt ajoute la date de clôture des comptes : un compte clôturé est masqué des listes par défaut
+// mais garde son historique dans les rapports
+func addAccountArchivedAt(db *pg.DB, loggerInstance logger.Logger) error {
+	query := `
+	DO $$ 
+	BEGIN 
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'accounts' AND column_name = 'archived_at') THEN
+			ALTER TABLE accounts ADD COLUMN archived_at TIMESTAMP WITH TIME ZONE;
+		END IF;
+	END $$;
+	`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		loggerInstance.Error("Erreur ajout colonne archived_at", logger.Error(err))
+		return err
+	}
+
+	loggerInstance.Info("Colonne archived_at ajoutée à la table accounts avec succès")
+	return nil
+}
+
+// createToBaseMinorFunction crée la fonction to_base_minor, qui convertit comme to_base_currency un montant en unités
+// mineures dans la devise de référence de l'utilisateur, mais en unités mineures de cette devise : les sommes des
+// statistiques restent des entiers exacts (NULL sans taux)
+func createToBaseMinorFunction(db *pg.DB, loggerInstance logger.Logger) error {
+	query := `
+	CREATE OR REPLACE FUNCTION to_base_minor(p_user UUID, amount BIGINT, code TEXT, p_date DATE) RETURNS BIGINT AS $$
+		SELECT ROUND(amount::NUMERIC * POWER(10::NUMERIC, currency_exponent(u.base_currency) - currency_exponent(code))
+			* exchange_rate(p_user, code, u.base_currency, p_date))::BIGINT
+		FROM users u
+		WHERE u.id = p_user
+	$$ LANGUAGE SQL STABLE;
+	`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		loggerInstance.Error("Erreur création fonction to_base_minor", logger.Error(err))
+		return err
+	}
+
+	loggerInstance.Info("Fonction to_base_minor créée avec succès")
+	return nil
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStorage stocke les fichiers sur le disque local
+type LocalStorage struct {
+	basePath string
+}
+
+// NewLocalStorage crée un stockage sur disque sous basePath (créé s'il n'existe pas)
+func NewLocalStorage(basePath string) (*LocalStorage, error) {
+	if basePath == "" {
+		return nil, fmt.Errorf("chemin de stockage local requis")
+	}
+	if err := os.MkdirAll(basePath, 0o755); err != nil {
+		return nil, fmt.Errorf("erreur création du dossier de stockage: %w", err)
+	}
+	return &LocalStorage{basePath: basePath}, nil
+}
+
+// Put écrit le fichier de façon atomique (fichier temporaire puis renommage)
+func (s *LocalStorage) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("erreur création du dossier: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("erreur création du fichier: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("erreur écriture du fichier: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("erreur écriture du fichier: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("erreur enregistrement du fichier: %w", err)
+	}
+	return nil
+}
+
+// Get ouvre le fichier stocké sous la clé
+func (s *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("erreur ouverture du fichier: %w", err)
+	}
+	return file, nil
+}
+
+// Delete supprime le fichier ; un fichier déjà absent n'est pas une erreur
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("erreur suppression du fichier: %w", err)
+	}
+	return nil
+}
+
+// path résout la clé sous le dossier de stockage en refusant toute sortie de ce dossier
+func (s *LocalStorage) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + key)
+	if key == "" || strings.Contains(key, "..") || cleaned == "/" {
+		return "", fmt.Errorf("clé de stockage invalide: %s", key)
+	}
+	return filepath.Join(s.basePath, filepath.FromSlash(cleaned)), nil
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStoragePath(t *testing.T) {
+	base := t.TempDir()
+	storage := &LocalStorage{basePath: base}
+
+	valid := map[string]string{
+		"attachments/u/t/fichier.pdf":  "attachments/u/t/fichier.pdf",
+		"/attachments/fichier.pdf":     "attachments/fichier.pdf",
+		"attachments//t/./fichier.pdf": "attachments/t/fichier.pdf",
+	}
+	for key, want := range valid {
+		path, err := storage.path(key)
+		if err != nil || path != filepath.Join(base, filepath.FromSlash(want)) {
+			t.Errorf("path(%q) = %q, %v, attendu %q", key, path, err, filepath.Join(base, want))
+		}
+	}
+
+	for _, key := range []string{"", "/", ".", "..", "../secret", "attachments/../../secret", "attachments/..", `..\secret`, "a/b/../../../etc/passwd"} {
+		if path, err := storage.path(key); err == nil {
+			t.Errorf("path(%q) = %q, attendu une erreur", key, path)
+		}
+	}
+}
+
+func TestLocalStorageRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	storage, err := NewLocalStorage(filepath.Join(t.TempDir(), "fichiers"))
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+
+	key := "attachments/u/t/recu.pdf"
+	if err := storage.Put(ctx, key, strings.NewReader("contenu"), 7, "application/pdf"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	file, err := storage.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	content, err := io.ReadAll(file)
+	file.Close()
+	if err != nil || string(content) != "contenu" {
+		t.Errorf("Get = %q, %v, attendu \"contenu\"", content, err)
+	}
+
+	if err := storage.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := storage.Get(ctx, key); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("Get après suppression = %v, attendu %v", err, ErrObjectNotFound)
+	}
+	if err := storage.Delete(ctx, key); err != nil {
+		t.Errorf("Delete d'un fichier absent = %v, attendu aucune erreur", err)
+	}
+
+	if err := storage.Put(ctx, "../hors-du-dossier", strings.NewReader("x"), 1, "text/plain"); err == nil {
+		t.Error("Put hors du dossier de stockage devrait échouer")
+	}
+}
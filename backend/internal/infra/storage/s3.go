@@ -0,0 +1,232 @@
+package storage
+
+import (
+	"backend/pkg/config"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// S3Storage stocke les fichiers dans un bucket compatible S3 (AWS S3, MinIO...).
+// Les requêtes sont signées en AWS Signature Version 4.
+type S3Storage struct {
+	bucket       string
+	region       string
+	accessKey    string
+	secretKey    string
+	endpoint     *url.URL
+	usePathStyle bool
+	client       *http.Client
+}
+
+// NewS3Storage crée un stockage S3. Sans endpoint, le point d'accès AWS de la région est utilisé ;
+// pour MinIO, renseigner l'endpoint (ex: http://localhost:9000) et use_path_style.
+func NewS3Storage(cfg config.S3Config) (*S3Storage, error) {
+	if cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, fmt.Errorf("configuration S3 incomplète (bucket, access_key et secret_key requis)")
+	}
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	rawEndpoint := cfg.Endpoint
+	if rawEndpoint == "" {
+		rawEndpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	endpoint, err := url.Parse(strings.TrimRight(rawEndpoint, "/"))
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("endpoint S3 invalide: %s", cfg.Endpoint)
+	}
+
+	return &S3Storage{
+		bucket:       cfg.Bucket,
+		region:       region,
+		accessKey:    cfg.AccessKey,
+		secretKey:    cfg.SecretKey,
+		endpoint:     endpoint,
+		usePathStyle: cfg.UsePathStyle,
+		client:       &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// Put envoie le fichier dans le bucket
+func (s *S3Storage) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	body, err := io.ReadAll(content)
+	if err != nil {
+		return fmt.Errorf("erreur lecture du fichier: %w", err)
+	}
+
+	req, err := s.newRequest(ctx, http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.do(req, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return s.responseError("envoi", resp)
+	}
+	return nil
+}
+
+// Get télécharge le fichier stocké sous la clé
+func (s *S3Storage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrObjectNotFound
+	default:
+		defer resp.Body.Close()
+		return nil, s.responseError("téléchargement", resp)
+	}
+}
+
+// Delete supprime le fichier ; un fichier déjà absent n'est pas une erreur
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return s.responseError("suppression", resp)
+	}
+	return nil
+}
+
+// newRequest construit la requête vers l'objet, en style chemin (endpoint/bucket/clé) ou hôte virtuel
+// (bucket.endpoint/clé)
+func (s *S3Storage) newRequest(ctx context.Context, method, key string, body []byte) (*http.Request, error) {
+	if key == "" {
+		return nil, fmt.Errorf("clé de stockage invalide")
+	}
+
+	target := *s.endpoint
+	path := "/" + key
+	if s.usePathStyle {
+		path = "/" + s.bucket + path
+	} else {
+		target.Host = s.bucket + "." + target.Host
+	}
+	target.Path = path
+	target.RawPath = s3EscapePath(path)
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), reader)
+	if err != nil {
+		return nil, fmt.Errorf("erreur création requête S3: %w", err)
+	}
+	return req, nil
+}
+
+// do signe et exécute la requête
+func (s *S3Storage) do(req *http.Request, body []byte) (*http.Response, error) {
+	s.sign(req, body, time.Now().UTC())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erreur requête S3: %w", err)
+	}
+	return resp, nil
+}
+
+// sign ajoute à la requête les en-têtes d'authentification AWS Signature Version 4
+func (s *S3Storage) sign(req *http.Request, body []byte, now time.Time) {
+	payloadHash := sha256Hex(body)
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.secretKey), day)
+	signingKey = hmacSHA256(signingKey, s.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+// responseError construit une erreur à partir d'une réponse S3 en échec
+func (s *S3Storage) responseError(operation string, resp *http.Response) error {
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("erreur S3 (%s): %s %s", operation, resp.Status, strings.TrimSpace(string(message)))
+}
+
+// s3EscapePath encode chaque segment du chemin comme l'exige la signature S3
+// (tout sauf les caractères non réservés A-Z a-z 0-9 - _ . ~)
+func s3EscapePath(path string) string {
+	var builder strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if c == '/' || 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			builder.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&builder, "%%%02X", c)
+	}
+	return builder.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
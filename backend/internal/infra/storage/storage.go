@@ -1,11 +1,31 @@
 package storage
 
-// Storage is a placeholder for your storage logic.
-type Storage struct {
-	// Add dependencies here (e.g., config, logger)
+import (
+	"backend/pkg/config"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrObjectNotFound est retournée lorsqu'aucun fichier n'est stocké sous la clé demandée
+var ErrObjectNotFound = errors.New("fichier introuvable dans le stockage")
+
+// Storage stocke des fichiers sous une clé (ex: "attachments/<user>/<transaction>/<id>.jpg")
+type Storage interface {
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
 }
 
-// NewStorage creates a new Storage instance.
-func NewStorage() *Storage {
-	return &Storage{}
+// NewStorage crée le stockage décrit par la configuration (local ou s3)
+func NewStorage(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Type {
+	case "", "local":
+		return NewLocalStorage(cfg.LocalPath)
+	case "s3":
+		return NewS3Storage(cfg.S3Config)
+	default:
+		return nil, fmt.Errorf("type de stockage non supporté: %s", cfg.Type)
+	}
 }
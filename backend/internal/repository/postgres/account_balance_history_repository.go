@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/internal/domaine/repository"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+)
+
+// balanceAtExpr calcule le solde courant d'un compte a en fin de journée : dernier instantané jusqu'à cette date,
+// sinon solde de la veille du premier instantané suivant, sinon solde actuel (aucune variation enregistrée)
+const balanceAtExpr = `COALESCE(
+	(SELECT s.balance FROM account_balance_snapshots s WHERE s.account_id = a.id AND s.date <= ?0 ORDER BY s.date DESC LIMIT 1),
+	(SELECT s.balance - s.change FROM account_balance_snapshots s WHERE s.account_id = a.id AND s.date > ?0 ORDER BY s.date LIMIT 1),
+	a.balance)`
+
+// AccountBalanceHistoryRepository implémente repository.AccountBalanceHistoryRepository
+type AccountBalanceHistoryRepository struct {
+	db *pg.DB
+}
+
+// NewAccountBalanceHistoryRepository crée une nouvelle instance de AccountBalanceHistoryRepository
+func NewAccountBalanceHistoryRepository(db *pg.DB) repository.AccountBalanceHistoryRepository {
+	return &AccountBalanceHistoryRepository{db: db}
+}
+
+// ApplyChange ajoute delta au solde d'un compte à partir du jour donné : l'instantané du jour est créé s'il n'existe
+// pas (avec le solde de la veille, ou balanceBefore si le compte n'a encore aucun instantané), sa variation est
+// ajustée et les soldes de ce jour et des jours suivants sont décalés de delta
+func (r *AccountBalanceHistoryRepository) ApplyChange(ctx context.Context, accountID uuid.UUID, date time.Time, delta entity.Money, balanceBefore entity.Money) error {
+	db := dbFromContext(ctx, r.db)
+	_, err := db.Exec(`
+		INSERT INTO account_balance_snapshots (account_id, date, change, balance, currency, updated_at)
+		VALUES (?0, ?1, 0, COALESCE(
+			(SELECT balance FROM account_balance_snapshots WHERE account_id = ?0 AND date < ?1 ORDER BY date DESC LIMIT 1),
+			(SELECT balance - change FROM account_balance_snapshots WHERE account_id = ?0 AND date > ?1 ORDER BY date LIMIT 1),
+			?2), ?3, NOW())
+		ON CONFLICT (account_id, date) DO NOTHING`,
+		accountID, date, balanceBefore.Minor, delta.Currency)
+	if err != nil {
+		return fmt.Errorf("erreur création instantané de solde: %w", err)
+	}
+
+	_, err = db.Exec(`
+		UPDATE account_balance_snapshots
+		SET balance = balance + ?2,
+			change = change + CASE WHEN date = ?1 THEN ?2 ELSE 0 END,
+			updated_at = NOW()
+		WHERE account_id = ?0 AND date >= ?1`,
+		accountID, date, delta.Minor)
+	if err != nil {
+		return fmt.Errorf("erreur mise à jour historique de solde: %w", err)
+	}
+	return nil
+}
+
+// GetRange récupère les instantanés d'un compte entre deux dates incluses, par date croissante
+func (r *AccountBalanceHistoryRepository) GetRange(ctx context.Context, accountID uuid.UUID, from, to time.Time) ([]*entity.AccountBalanceSnapshot, error) {
+	var snapshots []*entity.AccountBalanceSnapshot
+	err := dbFromContext(ctx, r.db).Model(&snapshots).
+		Where("account_id = ?", accountID).
+		Where("date >= ?", from).
+		Where("date <= ?", to).
+		Order("date").
+		Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération historique de solde: %w", err)
+	}
+	return snapshots, nil
+}
+
+// GetBalanceAt retourne le solde courant d'un compte à la fin du jour donné
+func (r *AccountBalanceHistoryRepository) GetBalanceAt(ctx context.Context, accountID uuid.UUID, date time.Time) (entity.Money, error) {
+	var balance struct {
+		Balance  int64
+		Currency string
+	}
+	_, err := dbFromContext(ctx, r.db).QueryOne(&balance,
+		`SELECT `+balanceAtExpr+` AS balance, a.currency FROM accounts a WHERE a.id = ?1`, date, accountID)
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return entity.Money{}, fmt.Errorf("compte non trouvé")
+		}
+		return entity.Money{}, fmt.Errorf("erreur calcul du solde à date: %w", err)
+	}
+	return entity.NewMoney(balance.Balance, balance.Currency), nil
+}
+
+// GetBalancesAt retourne le solde courant de chaque compte de l'utilisateur (hors corbeille) à la fin du jour donné
+func (r *AccountBalanceHistoryRepository) GetBalancesAt(ctx context.Context, userID uuid.UUID, date time.Time) ([]*entity.AccountBalanceAt, error) {
+	var balances []*entity.AccountBalanceAt
+	_, err := dbFromContext(ctx, r.db).Query(&balances, `
+		SELECT a.id AS account_id, a.name AS account_name, a.type, a.currency, `+balanceAtExpr+` AS balance
+		FROM accounts a
+		WHERE a.user_id = ?1 AND a.deleted_at IS NULL
+		ORDER BY a.created_at DESC`, date, userID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur calcul des soldes à date: %w", err)
+	}
+	return balances, nil
+}
+
+// DeleteByAccountID efface l'historique des soldes d'un compte
+func (r *AccountBalanceHistoryRepository) DeleteByAccountID(ctx context.Context, accountID uuid.UUID) error {
+	_, err := dbFromContext(ctx, r.db).Model((*entity.AccountBalanceSnapshot)(nil)).Where("account_id = ?", accountID).Delete()
+	if err != nil {
+		return fmt.Errorf("erreur suppression historique de solde: %w", err)
+	}
+	return nil
+}
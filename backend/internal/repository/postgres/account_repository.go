@@ -22,7 +22,7 @@ func NewAccountRepository(db *pg.DB) repository.AccountRepository {
 
 // Create crée un nouveau compte
 func (r *AccountRepository) Create(ctx context.Context, account *entity.Account) error {
-	_, err := r.db.WithContext(ctx).Model(account).Insert()
+	_, err := dbFromContext(ctx, r.db).Model(account).Insert()
 	if err != nil {
 		return fmt.Errorf("erreur création compte: %w", err)
 	}
@@ -32,20 +32,34 @@ func (r *AccountRepository) Create(ctx context.Context, account *entity.Account)
 // GetByID récupère un compte par son ID
 func (r *AccountRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Account, error) {
 	account := &entity.Account{}
-	err := r.db.WithContext(ctx).Model(account).Where("id = ?", id).Select()
+	err := dbFromContext(ctx, r.db).Model(account).Where("id = ?", id).Select()
 	if err != nil {
 		if err == pg.ErrNoRows {
-			return nil, fmt.Errorf("compte non trouvé")
+			return nil, entity.ErrAccountNotFound
 		}
 		return nil, fmt.Errorf("erreur récupération compte: %w", err)
 	}
 	return account, nil
 }
 
+// GetByIDForUpdate récupère un compte par son ID en verrouillant sa ligne (SELECT ... FOR UPDATE)
+// jusqu'à la fin de la transaction en cours
+func (r *AccountRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Account, error) {
+	account := &entity.Account{}
+	err := dbFromContext(ctx, r.db).Model(account).Where("id = ?", id).For("UPDATE").Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, entity.ErrAccountNotFound
+		}
+		return nil, fmt.Errorf("erreur verrouillage compte: %w", err)
+	}
+	return account, nil
+}
+
 // GetByUserID récupère tous les comptes d'un utilisateur
 func (r *AccountRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Account, error) {
 	var accounts []*entity.Account
-	err := r.db.WithContext(ctx).Model(&accounts).Where("user_id = ?", userID).Order("created_at DESC").Select()
+	err := dbFromContext(ctx, r.db).Model(&accounts).Where("user_id = ?", userID).Order("created_at DESC").Select()
 	if err != nil {
 		return nil, fmt.Errorf("erreur récupération comptes utilisateur: %w", err)
 	}
@@ -54,7 +68,7 @@ func (r *AccountRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (
 
 // Update met à jour un compte
 func (r *AccountRepository) Update(ctx context.Context, account *entity.Account) error {
-	_, err := r.db.WithContext(ctx).Model(account).Where("id = ?", account.ID).Update()
+	_, err := dbFromContext(ctx, r.db).Model(account).Where("id = ?", account.ID).Update()
 	if err != nil {
 		return fmt.Errorf("erreur mise à jour compte: %w", err)
 	}
@@ -63,19 +77,31 @@ func (r *AccountRepository) Update(ctx context.Context, account *entity.Account)
 
 // Delete supprime un compte
 func (r *AccountRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	_, err := r.db.WithContext(ctx).Model(&entity.Account{}).Where("id = ?", id).Delete()
+	_, err := dbFromContext(ctx, r.db).Model(&entity.Account{}).Where("id = ?", id).Delete()
 	if err != nil {
 		return fmt.Errorf("erreur suppression compte: %w", err)
 	}
 	return nil
 }
 
-// GetBalanceByUserID récupère le solde total d'un utilisateur
-func (r *AccountRepository) GetBalanceByUserID(ctx context.Context, userID uuid.UUID) (float64, error) {
-	var total float64
-	err := r.db.WithContext(ctx).Model(&entity.Account{}).Column("balance").Where("user_id = ?", userID).Select(&total)
+// GetDeletedByIDForUpdate récupère un compte de la corbeille en verrouillant sa ligne
+func (r *AccountRepository) GetDeletedByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Account, error) {
+	account := &entity.Account{}
+	err := dbFromContext(ctx, r.db).Model(account).Deleted().Where("id = ?", id).For("UPDATE").Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, fmt.Errorf("%w: compte %s", entity.ErrTrashItemNotFound, id)
+		}
+		return nil, fmt.Errorf("erreur verrouillage compte supprimé: %w", err)
+	}
+	return account, nil
+}
+
+// Restore sort un compte de la corbeille
+func (r *AccountRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	_, err := dbFromContext(ctx, r.db).Model((*entity.Account)(nil)).Set("deleted_at = NULL").Where("id = ?", id).Deleted().Update()
 	if err != nil {
-		return 0, fmt.Errorf("erreur récupération solde total: %w", err)
+		return fmt.Errorf("erreur restauration compte: %w", err)
 	}
-	return total, nil
-} 
\ No newline at end of file
+	return nil
+}
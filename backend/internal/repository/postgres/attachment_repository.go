@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/internal/domaine/repository"
+	"context"
+	"fmt"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+)
+
+// AttachmentRepository implémente repository.AttachmentRepository
+type AttachmentRepository struct {
+	db *pg.DB
+}
+
+// NewAttachmentRepository crée une nouvelle instance de AttachmentRepository
+func NewAttachmentRepository(db *pg.DB) repository.AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+// Create enregistre une pièce jointe
+func (r *AttachmentRepository) Create(ctx context.Context, attachment *entity.TransactionAttachment) error {
+	_, err := dbFromContext(ctx, r.db).Model(attachment).Insert()
+	if err != nil {
+		return fmt.Errorf("erreur création pièce jointe: %w", err)
+	}
+	return nil
+}
+
+// GetByID récupère une pièce jointe par son ID
+func (r *AttachmentRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.TransactionAttachment, error) {
+	attachment := &entity.TransactionAttachment{}
+	err := dbFromContext(ctx, r.db).Model(attachment).Where("id = ?", id).Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, entity.ErrAttachmentNotFound
+		}
+		return nil, fmt.Errorf("erreur récupération pièce jointe: %w", err)
+	}
+	return attachment, nil
+}
+
+// GetByTransactionID récupère les pièces jointes d'une transaction
+func (r *AttachmentRepository) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*entity.TransactionAttachment, error) {
+	var attachments []*entity.TransactionAttachment
+	err := dbFromContext(ctx, r.db).Model(&attachments).Where("transaction_id = ?", transactionID).Order("created_at").Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération pièces jointes: %w", err)
+	}
+	return attachments, nil
+}
+
+// GetByTransactionIDs récupère les pièces jointes de plusieurs transactions
+func (r *AttachmentRepository) GetByTransactionIDs(ctx context.Context, transactionIDs []uuid.UUID) ([]*entity.TransactionAttachment, error) {
+	var attachments []*entity.TransactionAttachment
+	if len(transactionIDs) == 0 {
+		return attachments, nil
+	}
+	err := dbFromContext(ctx, r.db).Model(&attachments).Where("transaction_id IN (?)", pg.In(transactionIDs)).Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération pièces jointes: %w", err)
+	}
+	return attachments, nil
+}
+
+// Delete supprime une pièce jointe
+func (r *AttachmentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := dbFromContext(ctx, r.db).Model((*entity.TransactionAttachment)(nil)).Where("id = ?", id).Delete()
+	if err != nil {
+		return fmt.Errorf("erreur suppression pièce jointe: %w", err)
+	}
+	return nil
+}
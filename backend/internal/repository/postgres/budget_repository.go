@@ -49,11 +49,12 @@ func (r *BudgetRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([
 	err := r.db.WithContext(ctx).Model(&budgets).
 		Column("budget.id", "budget.user_id", "budget.category_id",
 			"budget.name", "budget.amount_planned", "budget.period",
-			"budget.created_at", "budget.updated_at", "budget.amount_spent").
+			"budget.currency", "budget.created_at", "budget.updated_at", "budget.amount_spent").
 		Join("JOIN categories AS category ON category.id = budget.category_id").
 		ColumnExpr("category.id AS category__id, category.name AS category__name, category.type AS category__type, category.parent_id AS category__parent_id, category.icon AS category__icon, category.color AS category__color").
-		ColumnExpr("(SELECT COALESCE(SUM(t.amount), 0) FROM transactions t WHERE t.category_id = budget.category_id) AS amount_spent").
-		ColumnExpr("(SELECT COALESCE(SUM(t.amount), 0) FROM transactions t WHERE t.category_id = budget.category_id) AS amount_spent").
+		// Les remboursements sont déduits des dépenses de la catégorie, exprimées en unités mineures de la devise du budget ;
+		// les transactions planifiées ne sont pas encore dépensées
+		ColumnExpr("(SELECT ROUND(COALESCE(SUM(CASE WHEN l.type = 'refund' THEN -minor_to_major(l.amount, l.currency) ELSE minor_to_major(l.amount, l.currency) END), 0) * POWER(10::NUMERIC, currency_exponent(budget.currency)))::BIGINT FROM transaction_lines l WHERE l.category_id = budget.category_id AND l.status <> 'scheduled') AS amount_spent").
 		Where("budget.user_id = ?", userID).
 		Order("budget.created_at DESC").
 		Select()
@@ -83,6 +84,28 @@ func (r *BudgetRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// GetDeletedByID récupère un budget de la corbeille
+func (r *BudgetRepository) GetDeletedByID(ctx context.Context, id uuid.UUID) (*entity.Budget, error) {
+	budget := &entity.Budget{}
+	err := r.db.WithContext(ctx).Model(budget).Deleted().Where("id = ?", id).Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, fmt.Errorf("%w: budget %s", entity.ErrTrashItemNotFound, id)
+		}
+		return nil, fmt.Errorf("erreur récupération budget supprimé: %w", err)
+	}
+	return budget, nil
+}
+
+// Restore sort un budget de la corbeille
+func (r *BudgetRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.WithContext(ctx).Model((*entity.Budget)(nil)).Set("deleted_at = NULL").Where("id = ?", id).Deleted().Update()
+	if err != nil {
+		return fmt.Errorf("erreur restauration budget: %w", err)
+	}
+	return nil
+}
+
 // GetByCategoryID récupère les budgets d'une catégorie
 func (r *BudgetRepository) GetByCategoryID(ctx context.Context, userID uuid.UUID, categoryID uuid.UUID) ([]*entity.Budget, error) {
 	var budgets []*entity.Budget
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/internal/domaine/repository"
+	"context"
+	"fmt"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+)
+
+// ExchangeRateRepository implémente repository.ExchangeRateRepository
+type ExchangeRateRepository struct {
+	db *pg.DB
+}
+
+// NewExchangeRateRepository crée une nouvelle instance de ExchangeRateRepository
+func NewExchangeRateRepository(db *pg.DB) repository.ExchangeRateRepository {
+	return &ExchangeRateRepository{db: db}
+}
+
+// Upsert enregistre un taux de change ; un taux de la même paire à la même date est remplacé
+// et le taux enregistré (ID d'origine compris) est relu dans rate
+func (r *ExchangeRateRepository) Upsert(ctx context.Context, rate *entity.ExchangeRate) error {
+	_, err := dbFromContext(ctx, r.db).Model(rate).
+		OnConflict("(user_id, from_currency, to_currency, date) DO UPDATE").
+		Set("rate = EXCLUDED.rate, source = EXCLUDED.source, updated_at = EXCLUDED.updated_at").
+		Returning("*").
+		Insert()
+	if err != nil {
+		return fmt.Errorf("erreur enregistrement taux de change: %w", err)
+	}
+	return nil
+}
+
+// GetByID récupère un taux de change par son ID
+func (r *ExchangeRateRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.ExchangeRate, error) {
+	rate := &entity.ExchangeRate{}
+	err := dbFromContext(ctx, r.db).Model(rate).Where("id = ?", id).Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, entity.ErrExchangeRateNotFound
+		}
+		return nil, fmt.Errorf("erreur récupération taux de change: %w", err)
+	}
+	return rate, nil
+}
+
+// GetByUserID récupère les taux de change d'un utilisateur par paire puis par date, limités aux paires
+// impliquant la devise donnée si elle n'est pas vide
+func (r *ExchangeRateRepository) GetByUserID(ctx context.Context, userID uuid.UUID, currency string) ([]*entity.ExchangeRate, error) {
+	var rates []*entity.ExchangeRate
+	query := dbFromContext(ctx, r.db).Model(&rates).Where("user_id = ?", userID)
+	if currency != "" {
+		query = query.Where("(from_currency = ? OR to_currency = ?)", currency, currency)
+	}
+	err := query.Order("from_currency ASC", "to_currency ASC", "date ASC").Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération taux de change: %w", err)
+	}
+	return rates, nil
+}
+
+// Delete supprime un taux de change
+func (r *ExchangeRateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := dbFromContext(ctx, r.db).Model((*entity.ExchangeRate)(nil)).Where("id = ?", id).Delete()
+	if err != nil {
+		return fmt.Errorf("erreur suppression taux de change: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/internal/domaine/repository"
+	"context"
+	"fmt"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+)
+
+// ImportBatchRepository implémente repository.ImportBatchRepository
+type ImportBatchRepository struct {
+	db *pg.DB
+}
+
+// NewImportBatchRepository crée une nouvelle instance de ImportBatchRepository
+func NewImportBatchRepository(db *pg.DB) repository.ImportBatchRepository {
+	return &ImportBatchRepository{db: db}
+}
+
+// Create crée un nouveau lot d'import
+func (r *ImportBatchRepository) Create(ctx context.Context, batch *entity.ImportBatch) error {
+	_, err := dbFromContext(ctx, r.db).Model(batch).Insert()
+	if err != nil {
+		return fmt.Errorf("erreur création lot d'import: %w", err)
+	}
+	return nil
+}
+
+// GetByID récupère un lot d'import par son ID
+func (r *ImportBatchRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.ImportBatch, error) {
+	batch := &entity.ImportBatch{}
+	err := dbFromContext(ctx, r.db).Model(batch).Where("id = ?", id).Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, entity.ErrImportBatchNotFound
+		}
+		return nil, fmt.Errorf("erreur récupération lot d'import: %w", err)
+	}
+	return batch, nil
+}
+
+// GetByIDForUpdate récupère un lot d'import en verrouillant sa ligne jusqu'à la fin de la transaction SQL
+func (r *ImportBatchRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.ImportBatch, error) {
+	batch := &entity.ImportBatch{}
+	err := dbFromContext(ctx, r.db).Model(batch).Where("id = ?", id).For("UPDATE").Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, entity.ErrImportBatchNotFound
+		}
+		return nil, fmt.Errorf("erreur verrouillage lot d'import: %w", err)
+	}
+	return batch, nil
+}
+
+// GetByUserID récupère les lots d'import d'un utilisateur, éventuellement limités à un compte
+func (r *ImportBatchRepository) GetByUserID(ctx context.Context, userID uuid.UUID, accountID *uuid.UUID) ([]*entity.ImportBatch, error) {
+	var batches []*entity.ImportBatch
+	query := dbFromContext(ctx, r.db).Model(&batches).Where("user_id = ?", userID)
+	if accountID != nil {
+		query = query.Where("account_id = ?", *accountID)
+	}
+	err := query.Order("created_at DESC").Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération lots d'import: %w", err)
+	}
+	return batches, nil
+}
+
+// Update met à jour un lot d'import
+func (r *ImportBatchRepository) Update(ctx context.Context, batch *entity.ImportBatch) error {
+	_, err := dbFromContext(ctx, r.db).Model(batch).Where("id = ?", batch.ID).Update()
+	if err != nil {
+		return fmt.Errorf("erreur mise à jour lot d'import: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package postgres
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/internal/domaine/repository"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+	"github.com/google/uuid"
+)
+
+// LoanRepository implémente repository.LoanRepository
+type LoanRepository struct {
+	db *pg.DB
+}
+
+// NewLoanRepository crée une nouvelle instance de LoanRepository
+func NewLoanRepository(db *pg.DB) repository.LoanRepository {
+	return &LoanRepository{db: db}
+}
+
+// Create crée un nouvel emprunt
+func (r *LoanRepository) Create(ctx context.Context, loan *entity.Loan) error {
+	_, err := dbFromContext(ctx, r.db).Model(loan).Insert()
+	if err != nil {
+		return fmt.Errorf("erreur création emprunt: %w", err)
+	}
+	return nil
+}
+
+// GetByID récupère un emprunt par son ID avec son compte et son échéancier
+func (r *LoanRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Loan, error) {
+	loan := &entity.Loan{}
+	err := dbFromContext(ctx, r.db).Model(loan).
+		Relation("Account").
+		Relation("Installments", func(q *orm.Query) (*orm.Query, error) {
+			return q.Order("number ASC"), nil
+		}).
+		Where("loan.id = ?", id).
+		Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, entity.ErrLoanNotFound
+		}
+		return nil, fmt.Errorf("erreur récupération emprunt: %w", err)
+	}
+	return loan, nil
+}
+
+// GetByIDForUpdate récupère un emprunt en verrouillant sa ligne jusqu'à la fin de la transaction SQL
+func (r *LoanRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Loan, error) {
+	loan := &entity.Loan{}
+	err := dbFromContext(ctx, r.db).Model(loan).Where("id = ?", id).For("UPDATE").Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, entity.ErrLoanNotFound
+		}
+		return nil, fmt.Errorf("erreur verrouillage emprunt: %w", err)
+	}
+	return loan, nil
+}
+
+// GetByUserID récupère les emprunts d'un utilisateur avec leur compte, du plus récent au plus ancien
+func (r *LoanRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Loan, error) {
+	var loans []*entity.Loan
+	err := dbFromContext(ctx, r.db).Model(&loans).
+		Relation("Account").
+		Where("loan.user_id = ?", userID).
+		Order("loan.start_date DESC", "loan.created_at DESC").
+		Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération emprunts: %w", err)
+	}
+	return loans, nil
+}
+
+// Update met à jour un emprunt
+func (r *LoanRepository) Update(ctx context.Context, loan *entity.Loan) error {
+	_, err := dbFromContext(ctx, r.db).Model(loan).Where("id = ?", loan.ID).Update()
+	if err != nil {
+		return fmt.Errorf("erreur mise à jour emprunt: %w", err)
+	}
+	return nil
+}
+
+// Delete supprime un emprunt et son échéancier
+func (r *LoanRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := dbFromContext(ctx, r.db).Model((*entity.Loan)(nil)).Where("id = ?", id).Delete()
+	if err != nil {
+		return fmt.Errorf("erreur suppression emprunt: %w", err)
+	}
+	return nil
+}
+
+// CreateInstallments enregistre des échéances
+func (r *LoanRepository) CreateInstallments(ctx context.Context, installments []*entity.LoanInstallment) error {
+	if len(installments) == 0 {
+		return nil
+	}
+	if _, err := dbFromContext(ctx, r.db).Model(&installments).Insert(); err != nil {
+		return fmt.Errorf("erreur création échéancier: %w", err)
+	}
+	return nil
+}
+
+// GetInstallments récupère l'échéancier d'un emprunt
+func (r *LoanRepository) GetInstallments(ctx context.Context, loanID uuid.UUID) ([]*entity.LoanInstallment, error) {
+	var installments []*entity.LoanInstallment
+	err := dbFromContext(ctx, r.db).Model(&installments).
+		Where("loan_id = ?", loanID).
+		Order("number ASC").
+		Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération échéancier: %w", err)
+	}
+	return installments, nil
+}
+
+// UpdateInstallment met à jour une échéance
+func (r *LoanRepository) UpdateInstallment(ctx context.Context, installment *entity.LoanInstallment) error {
+	_, err := dbFromContext(ctx, r.db).Model(installment).Where("id = ?", installment.ID).Update()
+	if err != nil {
+		return fmt.Errorf("erreur mise à jour échéance: %w", err)
+	}
+	return nil
+}
+
+// DeleteUnpaidInstallments supprime les échéances non réglées d'un emprunt, avant le recalcul de son échéancier
+func (r *LoanRepository) DeleteUnpaidInstallments(ctx context.Context, loanID uuid.UUID) error {
+	_, err := dbFromContext(ctx, r.db).Model((*entity.LoanInstallment)(nil)).
+		Where("loan_id = ? AND status <> 'paid'", loanID).
+		Delete()
+	if err != nil {
+		return fmt.Errorf("erreur suppression échéances non réglées: %w", err)
+	}
+	return nil
+}
+
+// GetUnpaidInstallments récupère les échéances non réglées des emprunts d'un utilisateur, par date d'échéance
+func (r *LoanRepository) GetUnpaidInstallments(ctx context.Context, userID uuid.UUID) ([]*entity.LoanInstallment, error) {
+	var installments []*entity.LoanInstallment
+	err := dbFromContext(ctx, r.db).Model(&installments).
+		Where("user_id = ? AND status <> 'paid'", userID).
+		Order("due_date ASC", "number ASC").
+		Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération échéances non réglées: %w", err)
+	}
+	return installments, nil
+}
+
+// GetInstallmentsToRemind récupère les échéances à venir jusqu'à la date donnée dont le rappel n'a pas été envoyé
+func (r *LoanRepository) GetInstallmentsToRemind(ctx context.Context, dueBefore time.Time, limit int) ([]*entity.LoanInstallment, error) {
+	var installments []*entity.LoanInstallment
+	err := dbFromContext(ctx, r.db).Model(&installments).
+		Where("status = 'upcoming' AND reminded_at IS NULL AND due_date <= ?", dueBefore).
+		Order("due_date ASC").
+		Limit(limit).
+		Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération échéances à rappeler: %w", err)
+	}
+	return installments, nil
+}
+
+// GetNewlyMissedInstallments récupère les échéances dépassées à la date donnée et pas encore signalées impayées
+func (r *LoanRepository) GetNewlyMissedInstallments(ctx context.Context, today time.Time, limit int) ([]*entity.LoanInstallment, error) {
+	var installments []*entity.LoanInstallment
+	err := dbFromContext(ctx, r.db).Model(&installments).
+		Where("status = 'upcoming' AND due_date < ?", today).
+		Order("due_date ASC").
+		Limit(limit).
+		Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération échéances impayées: %w", err)
+	}
+	return installments, nil
+}
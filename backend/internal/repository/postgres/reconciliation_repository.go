@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/internal/domaine/repository"
+	"context"
+	"fmt"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+)
+
+// ReconciliationRepository implémente repository.ReconciliationRepository
+type ReconciliationRepository struct {
+	db *pg.DB
+}
+
+// NewReconciliationRepository crée une nouvelle instance de ReconciliationRepository
+func NewReconciliationRepository(db *pg.DB) repository.ReconciliationRepository {
+	return &ReconciliationRepository{db: db}
+}
+
+// Create crée un nouveau rapprochement
+func (r *ReconciliationRepository) Create(ctx context.Context, reconciliation *entity.Reconciliation) error {
+	_, err := dbFromContext(ctx, r.db).Model(reconciliation).Insert()
+	if err != nil {
+		return fmt.Errorf("erreur création rapprochement: %w", err)
+	}
+	return nil
+}
+
+// GetByID récupère un rapprochement par son ID
+func (r *ReconciliationRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Reconciliation, error) {
+	reconciliation := &entity.Reconciliation{}
+	err := dbFromContext(ctx, r.db).Model(reconciliation).Where("id = ?", id).Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, entity.ErrReconciliationNotFound
+		}
+		return nil, fmt.Errorf("erreur récupération rapprochement: %w", err)
+	}
+	return reconciliation, nil
+}
+
+// GetByIDForUpdate récupère un rapprochement en verrouillant sa ligne jusqu'à la fin de la transaction SQL
+func (r *ReconciliationRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Reconciliation, error) {
+	reconciliation := &entity.Reconciliation{}
+	err := dbFromContext(ctx, r.db).Model(reconciliation).Where("id = ?", id).For("UPDATE").Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, entity.ErrReconciliationNotFound
+		}
+		return nil, fmt.Errorf("erreur verrouillage rapprochement: %w", err)
+	}
+	return reconciliation, nil
+}
+
+// GetOpenByAccountID récupère le rapprochement en cours d'un compte, nil s'il n'y en a pas
+func (r *ReconciliationRepository) GetOpenByAccountID(ctx context.Context, accountID uuid.UUID) (*entity.Reconciliation, error) {
+	reconciliation := &entity.Reconciliation{}
+	err := dbFromContext(ctx, r.db).Model(reconciliation).Where("account_id = ? AND status = 'open'", accountID).Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erreur récupération rapprochement en cours: %w", err)
+	}
+	return reconciliation, nil
+}
+
+// GetByAccountID récupère les rapprochements d'un compte, du plus récent au plus ancien relevé
+func (r *ReconciliationRepository) GetByAccountID(ctx context.Context, accountID uuid.UUID) ([]*entity.Reconciliation, error) {
+	var reconciliations []*entity.Reconciliation
+	err := dbFromContext(ctx, r.db).Model(&reconciliations).
+		Where("account_id = ?", accountID).
+		Order("statement_date DESC", "created_at DESC").
+		Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération rapprochements: %w", err)
+	}
+	return reconciliations, nil
+}
+
+// Update met à jour un rapprochement
+func (r *ReconciliationRepository) Update(ctx context.Context, reconciliation *entity.Reconciliation) error {
+	_, err := dbFromContext(ctx, r.db).Model(reconciliation).Where("id = ?", reconciliation.ID).Update()
+	if err != nil {
+		return fmt.Errorf("erreur mise à jour rapprochement: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/internal/domaine/repository"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+)
+
+// RecurringTransactionRepository implémente repository.RecurringTransactionRepository
+type RecurringTransactionRepository struct {
+	db *pg.DB
+}
+
+// NewRecurringTransactionRepository crée une nouvelle instance de RecurringTransactionRepository
+func NewRecurringTransactionRepository(db *pg.DB) repository.RecurringTransactionRepository {
+	return &RecurringTransactionRepository{db: db}
+}
+
+// Create crée une nouvelle transaction récurrente
+func (r *RecurringTransactionRepository) Create(ctx context.Context, recurring *entity.RecurringTransaction) error {
+	_, err := dbFromContext(ctx, r.db).Model(recurring).Insert()
+	if err != nil {
+		return fmt.Errorf("erreur création transaction récurrente: %w", err)
+	}
+	return nil
+}
+
+// GetByID récupère une transaction récurrente par son ID
+func (r *RecurringTransactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.RecurringTransaction, error) {
+	recurring := &entity.RecurringTransaction{}
+	err := dbFromContext(ctx, r.db).Model(recurring).
+		Relation("Category").
+		Where("recurring_transaction.id = ?", id).
+		Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, entity.ErrRecurringTransactionNotFound
+		}
+		return nil, fmt.Errorf("erreur récupération transaction récurrente: %w", err)
+	}
+	return recurring, nil
+}
+
+// GetByIDForUpdate récupère une transaction récurrente en verrouillant sa ligne (SELECT ... FOR UPDATE).
+// Doit être appelé dans une transaction SQL (TxManager.WithinTransaction).
+func (r *RecurringTransactionRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.RecurringTransaction, error) {
+	recurring := &entity.RecurringTransaction{}
+	err := dbFromContext(ctx, r.db).Model(recurring).Where("id = ?", id).For("UPDATE").Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, entity.ErrRecurringTransactionNotFound
+		}
+		return nil, fmt.Errorf("erreur verrouillage transaction récurrente: %w", err)
+	}
+	return recurring, nil
+}
+
+// GetByUserID récupère toutes les transactions récurrentes d'un utilisateur
+func (r *RecurringTransactionRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.RecurringTransaction, error) {
+	var recurrings []*entity.RecurringTransaction
+	err := dbFromContext(ctx, r.db).Model(&recurrings).
+		Relation("Category").
+		Where("recurring_transaction.user_id = ?", userID).
+		Order("recurring_transaction.next_occurrence ASC NULLS LAST", "recurring_transaction.created_at DESC").
+		Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération transactions récurrentes: %w", err)
+	}
+	return recurrings, nil
+}
+
+// GetDueIDs récupère les IDs des modèles actifs dont la prochaine occurrence est échue à la date donnée
+// (modèles des comptes à la corbeille exclus)
+func (r *RecurringTransactionRepository) GetDueIDs(ctx context.Context, date time.Time, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := dbFromContext(ctx, r.db).Model((*entity.RecurringTransaction)(nil)).
+		Column("id").
+		Where("status = 'active'").
+		Where("next_occurrence <= ?", date).
+		Where("NOT EXISTS (SELECT 1 FROM accounts a WHERE a.id = recurring_transaction.account_id AND a.deleted_at IS NOT NULL)").
+		Order("next_occurrence ASC").
+		Limit(limit).
+		Select(&ids)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération transactions récurrentes échues: %w", err)
+	}
+	return ids, nil
+}
+
+// Update met à jour une transaction récurrente
+func (r *RecurringTransactionRepository) Update(ctx context.Context, recurring *entity.RecurringTransaction) error {
+	_, err := dbFromContext(ctx, r.db).Model(recurring).Where("id = ?", recurring.ID).Update()
+	if err != nil {
+		return fmt.Errorf("erreur mise à jour transaction récurrente: %w", err)
+	}
+	return nil
+}
+
+// Delete supprime une transaction récurrente (les occurrences déjà créées sont conservées)
+func (r *RecurringTransactionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := dbFromContext(ctx, r.db).Model(&entity.RecurringTransaction{}).Where("id = ?", id).Delete()
+	if err != nil {
+		return fmt.Errorf("erreur suppression transaction récurrente: %w", err)
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"backend/internal/domaine/repository"
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-pg/pg/v10"
 	"github.com/google/uuid"
@@ -22,7 +23,7 @@ func NewSavingGoalRepository(db *pg.DB) repository.SavingGoalRepository {
 
 // Create crée un nouvel objectif d'épargne
 func (r *SavingGoalRepository) Create(ctx context.Context, goal *entity.SavingGoal) error {
-	_, err := r.db.WithContext(ctx).Model(goal).Insert()
+	_, err := dbFromContext(ctx, r.db).Model(goal).Insert()
 	if err != nil {
 		return fmt.Errorf("erreur création objectif d'épargne: %w", err)
 	}
@@ -32,7 +33,7 @@ func (r *SavingGoalRepository) Create(ctx context.Context, goal *entity.SavingGo
 // GetByID récupère un objectif d'épargne par son ID
 func (r *SavingGoalRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.SavingGoal, error) {
 	goal := &entity.SavingGoal{}
-	err := r.db.WithContext(ctx).Model(goal).Where("id = ?", id).Select()
+	err := dbFromContext(ctx, r.db).Model(goal).Where("id = ?", id).Select()
 	if err != nil {
 		if err == pg.ErrNoRows {
 			return nil, fmt.Errorf("objectif d'épargne non trouvé")
@@ -42,10 +43,24 @@ func (r *SavingGoalRepository) GetByID(ctx context.Context, id uuid.UUID) (*enti
 	return goal, nil
 }
 
+// GetByIDForUpdate récupère un objectif d'épargne par son ID en verrouillant sa ligne
+// jusqu'à la fin de la transaction en cours
+func (r *SavingGoalRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.SavingGoal, error) {
+	goal := &entity.SavingGoal{}
+	err := dbFromContext(ctx, r.db).Model(goal).Where("id = ?", id).For("UPDATE").Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, fmt.Errorf("objectif d'épargne non trouvé")
+		}
+		return nil, fmt.Errorf("erreur verrouillage objectif d'épargne: %w", err)
+	}
+	return goal, nil
+}
+
 // GetByUserID récupère tous les objectifs d'épargne d'un utilisateur
 func (r *SavingGoalRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.SavingGoal, error) {
 	var goals []*entity.SavingGoal
-	err := r.db.WithContext(ctx).Model(&goals).Where("user_id = ?", userID).Order("created_at DESC").Select()
+	err := dbFromContext(ctx, r.db).Model(&goals).Where("user_id = ?", userID).Order("created_at DESC").Select()
 	if err != nil {
 		return nil, fmt.Errorf("erreur récupération objectifs d'épargne utilisateur: %w", err)
 	}
@@ -54,7 +69,7 @@ func (r *SavingGoalRepository) GetByUserID(ctx context.Context, userID uuid.UUID
 
 // Update met à jour un objectif d'épargne
 func (r *SavingGoalRepository) Update(ctx context.Context, goal *entity.SavingGoal) error {
-	_, err := r.db.WithContext(ctx).Model(goal).Where("id = ?", goal.ID).Update()
+	_, err := dbFromContext(ctx, r.db).Model(goal).Where("id = ?", goal.ID).Update()
 	if err != nil {
 		return fmt.Errorf("erreur mise à jour objectif d'épargne: %w", err)
 	}
@@ -63,17 +78,72 @@ func (r *SavingGoalRepository) Update(ctx context.Context, goal *entity.SavingGo
 
 // Delete supprime un objectif d'épargne
 func (r *SavingGoalRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	_, err := r.db.WithContext(ctx).Model(&entity.SavingGoal{}).Where("id = ?", id).Delete()
+	_, err := dbFromContext(ctx, r.db).Model(&entity.SavingGoal{}).Where("id = ?", id).Delete()
 	if err != nil {
 		return fmt.Errorf("erreur suppression objectif d'épargne: %w", err)
 	}
 	return nil
 }
 
+// GetDeletedByID récupère un objectif d'épargne de la corbeille
+func (r *SavingGoalRepository) GetDeletedByID(ctx context.Context, id uuid.UUID) (*entity.SavingGoal, error) {
+	goal := &entity.SavingGoal{}
+	err := dbFromContext(ctx, r.db).Model(goal).Deleted().Where("id = ?", id).Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, fmt.Errorf("%w: objectif d'épargne %s", entity.ErrTrashItemNotFound, id)
+		}
+		return nil, fmt.Errorf("erreur récupération objectif d'épargne supprimé: %w", err)
+	}
+	return goal, nil
+}
+
+// Restore sort un objectif d'épargne de la corbeille
+func (r *SavingGoalRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	_, err := dbFromContext(ctx, r.db).Model((*entity.SavingGoal)(nil)).
+		Set("deleted_at = NULL").
+		Set("deleted_with_account_id = NULL").
+		Where("id = ?", id).
+		Deleted().
+		Update()
+	if err != nil {
+		return fmt.Errorf("erreur restauration objectif d'épargne: %w", err)
+	}
+	return nil
+}
+
+// TrashByAccountID met à la corbeille les objectifs d'épargne actifs d'un compte, en retenant ce compte
+// pour les restaurer avec lui
+func (r *SavingGoalRepository) TrashByAccountID(ctx context.Context, accountID uuid.UUID, deletedAt time.Time) (int, error) {
+	result, err := dbFromContext(ctx, r.db).Model((*entity.SavingGoal)(nil)).
+		Set("deleted_at = ?", deletedAt).
+		Set("deleted_with_account_id = ?", accountID).
+		Where("account_id = ?", accountID).
+		Update()
+	if err != nil {
+		return 0, fmt.Errorf("erreur suppression objectifs d'épargne du compte: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// RestoreByAccountID sort de la corbeille les objectifs d'épargne supprimés avec un compte
+func (r *SavingGoalRepository) RestoreByAccountID(ctx context.Context, accountID uuid.UUID) (int, error) {
+	result, err := dbFromContext(ctx, r.db).Model((*entity.SavingGoal)(nil)).
+		Set("deleted_at = NULL").
+		Set("deleted_with_account_id = NULL").
+		Where("deleted_with_account_id = ?", accountID).
+		Deleted().
+		Update()
+	if err != nil {
+		return 0, fmt.Errorf("erreur restauration objectifs d'épargne du compte: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
 // GetAchievedByUserID récupère les objectifs d'épargne atteints d'un utilisateur
 func (r *SavingGoalRepository) GetAchievedByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.SavingGoal, error) {
 	var goals []*entity.SavingGoal
-	err := r.db.WithContext(ctx).Model(&goals).Where("user_id = ? AND is_achieved = ?", userID, true).Order("updated_at DESC").Select()
+	err := dbFromContext(ctx, r.db).Model(&goals).Where("user_id = ? AND is_achieved = ?", userID, true).Order("updated_at DESC").Select()
 	if err != nil {
 		return nil, fmt.Errorf("erreur récupération objectifs d'épargne atteints: %w", err)
 	}
@@ -83,7 +153,7 @@ func (r *SavingGoalRepository) GetAchievedByUserID(ctx context.Context, userID u
 // GetActiveByUserID récupère les objectifs d'épargne actifs d'un utilisateur
 func (r *SavingGoalRepository) GetActiveByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.SavingGoal, error) {
 	var goals []*entity.SavingGoal
-	err := r.db.WithContext(ctx).Model(&goals).Where("user_id = ? AND is_achieved = ?", userID, false).Order("deadline ASC NULLS LAST, created_at DESC").Select()
+	err := dbFromContext(ctx, r.db).Model(&goals).Where("user_id = ? AND is_achieved = ?", userID, false).Order("deadline ASC NULLS LAST, created_at DESC").Select()
 	if err != nil {
 		return nil, fmt.Errorf("erreur récupération objectifs d'épargne actifs: %w", err)
 	}
@@ -93,7 +163,7 @@ func (r *SavingGoalRepository) GetActiveByUserID(ctx context.Context, userID uui
 // GetByAccountID récupère les objectifs d'épargne d'un compte
 func (r *SavingGoalRepository) GetByAccountID(ctx context.Context, userID uuid.UUID, accountID uuid.UUID) ([]*entity.SavingGoal, error) {
 	var goals []*entity.SavingGoal
-	err := r.db.WithContext(ctx).Model(&goals).Where("user_id = ? AND account_id = ?", userID, accountID).Order("created_at DESC").Select()
+	err := dbFromContext(ctx, r.db).Model(&goals).Where("user_id = ? AND account_id = ?", userID, accountID).Order("created_at DESC").Select()
 	if err != nil {
 		return nil, fmt.Errorf("erreur récupération objectifs d'épargne par compte: %w", err)
 	}
@@ -103,7 +173,7 @@ func (r *SavingGoalRepository) GetByAccountID(ctx context.Context, userID uuid.U
 // GetByFrequency récupère les objectifs d'épargne par fréquence
 func (r *SavingGoalRepository) GetByFrequency(ctx context.Context, userID uuid.UUID, frequency string) ([]*entity.SavingGoal, error) {
 	var goals []*entity.SavingGoal
-	err := r.db.WithContext(ctx).Model(&goals).Where("user_id = ? AND frequency = ?", userID, frequency).Order("created_at DESC").Select()
+	err := dbFromContext(ctx, r.db).Model(&goals).Where("user_id = ? AND frequency = ?", userID, frequency).Order("created_at DESC").Select()
 	if err != nil {
 		return nil, fmt.Errorf("erreur récupération objectifs d'épargne par fréquence: %w", err)
 	}
@@ -113,7 +183,7 @@ func (r *SavingGoalRepository) GetByFrequency(ctx context.Context, userID uuid.U
 // GetAllSavingGoalsByUserIDAndAccountID récupère tous les objectifs d'épargne d'un utilisateur et d'un compte
 func (r *SavingGoalRepository) GetAllSavingGoalsByUserIDAndAccountID(ctx context.Context, userID uuid.UUID, accountID uuid.UUID) ([]*entity.SavingGoal, error) {
 	var goals []*entity.SavingGoal
-	err := r.db.WithContext(ctx).Model(&goals).Where("user_id = ? AND account_id = ?", userID, accountID).Order("created_at DESC").Select()
+	err := dbFromContext(ctx, r.db).Model(&goals).Where("user_id = ? AND account_id = ?", userID, accountID).Order("created_at DESC").Select()
 	if err != nil {
 		return nil, fmt.Errorf("erreur récupération objectifs d'épargne par utilisateur et compte: %w", err)
 	}
@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/internal/domaine/repository"
+	"context"
+	"fmt"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+	"github.com/google/uuid"
+)
+
+func init() {
+	// Table de liaison de la relation many2many Transaction.Tags
+	orm.RegisterTable((*entity.TransactionTag)(nil))
+}
+
+// TagRepository implémente repository.TagRepository
+type TagRepository struct {
+	db *pg.DB
+}
+
+// NewTagRepository crée une nouvelle instance de TagRepository
+func NewTagRepository(db *pg.DB) repository.TagRepository {
+	return &TagRepository{db: db}
+}
+
+// Create crée une nouvelle étiquette
+func (r *TagRepository) Create(ctx context.Context, tag *entity.Tag) error {
+	_, err := dbFromContext(ctx, r.db).Model(tag).Insert()
+	if err != nil {
+		return fmt.Errorf("erreur création étiquette: %w", err)
+	}
+	return nil
+}
+
+// GetByID récupère une étiquette par son ID
+func (r *TagRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Tag, error) {
+	tag := &entity.Tag{}
+	err := dbFromContext(ctx, r.db).Model(tag).Where("id = ?", id).Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, entity.ErrTagNotFound
+		}
+		return nil, fmt.Errorf("erreur récupération étiquette: %w", err)
+	}
+	return tag, nil
+}
+
+// GetByUserID récupère les étiquettes d'un utilisateur, par ordre alphabétique
+func (r *TagRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Tag, error) {
+	var tags []*entity.Tag
+	err := dbFromContext(ctx, r.db).Model(&tags).Where("user_id = ?", userID).Order("name ASC").Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération étiquettes: %w", err)
+	}
+	return tags, nil
+}
+
+// GetByIDs récupère, parmi les IDs donnés, les étiquettes appartenant à l'utilisateur
+func (r *TagRepository) GetByIDs(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) ([]*entity.Tag, error) {
+	var tags []*entity.Tag
+	if len(ids) == 0 {
+		return tags, nil
+	}
+	err := dbFromContext(ctx, r.db).Model(&tags).
+		Where("user_id = ?", userID).
+		Where("id IN (?)", pg.In(ids)).
+		Order("name ASC").
+		Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération étiquettes: %w", err)
+	}
+	return tags, nil
+}
+
+// GetByTransactionID récupère les étiquettes d'une transaction
+func (r *TagRepository) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*entity.Tag, error) {
+	var tags []*entity.Tag
+	err := dbFromContext(ctx, r.db).Model(&tags).
+		Join("JOIN transaction_tags AS tt ON tt.tag_id = tag.id").
+		Where("tt.transaction_id = ?", transactionID).
+		Order("tag.name ASC").
+		Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération étiquettes de la transaction: %w", err)
+	}
+	return tags, nil
+}
+
+// Update met à jour une étiquette
+func (r *TagRepository) Update(ctx context.Context, tag *entity.Tag) error {
+	_, err := dbFromContext(ctx, r.db).Model(tag).Where("id = ?", tag.ID).Update()
+	if err != nil {
+		return fmt.Errorf("erreur mise à jour étiquette: %w", err)
+	}
+	return nil
+}
+
+// Delete supprime une étiquette ; ses liens avec les transactions sont supprimés en cascade
+func (r *TagRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := dbFromContext(ctx, r.db).Model((*entity.Tag)(nil)).Where("id = ?", id).Delete()
+	if err != nil {
+		return fmt.Errorf("erreur suppression étiquette: %w", err)
+	}
+	return nil
+}
+
+// AddToTransactions pose chaque étiquette sur chaque transaction ; les liens déjà présents sont ignorés.
+// Retourne le nombre de liens créés.
+func (r *TagRepository) AddToTransactions(ctx context.Context, transactionIDs, tagIDs []uuid.UUID) (int, error) {
+	links := make([]*entity.TransactionTag, 0, len(transactionIDs)*len(tagIDs))
+	for _, transactionID := range transactionIDs {
+		for _, tagID := range tagIDs {
+			links = append(links, &entity.TransactionTag{TransactionID: transactionID, TagID: tagID})
+		}
+	}
+	if len(links) == 0 {
+		return 0, nil
+	}
+
+	result, err := dbFromContext(ctx, r.db).Model(&links).OnConflict("DO NOTHING").Insert()
+	if err != nil {
+		return 0, fmt.Errorf("erreur ajout étiquettes: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// RemoveFromTransactions retire les étiquettes des transactions. Retourne le nombre de liens supprimés.
+func (r *TagRepository) RemoveFromTransactions(ctx context.Context, transactionIDs, tagIDs []uuid.UUID) (int, error) {
+	if len(transactionIDs) == 0 || len(tagIDs) == 0 {
+		return 0, nil
+	}
+	result, err := dbFromContext(ctx, r.db).Model((*entity.TransactionTag)(nil)).
+		Where("transaction_id IN (?)", pg.In(transactionIDs)).
+		Where("tag_id IN (?)", pg.In(tagIDs)).
+		Delete()
+	if err != nil {
+		return 0, fmt.Errorf("erreur retrait étiquettes: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// ReplaceTransactionTags remplace les étiquettes d'une transaction
+func (r *TagRepository) ReplaceTransactionTags(ctx context.Context, transactionID uuid.UUID, tagIDs []uuid.UUID) error {
+	if _, err := dbFromContext(ctx, r.db).Model((*entity.TransactionTag)(nil)).Where("transaction_id = ?", transactionID).Delete(); err != nil {
+		return fmt.Errorf("erreur suppression étiquettes de la transaction: %w", err)
+	}
+	_, err := r.AddToTransactions(ctx, []uuid.UUID{transactionID}, tagIDs)
+	return err
+}
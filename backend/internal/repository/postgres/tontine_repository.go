@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/internal/domaine/repository"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+	"github.com/google/uuid"
+)
+
+// TontineRepository implémente repository.TontineRepository
+type TontineRepository struct {
+	db *pg.DB
+}
+
+// NewTontineRepository crée une nouvelle instance de TontineRepository
+func NewTontineRepository(db *pg.DB) repository.TontineRepository {
+	return &TontineRepository{db: db}
+}
+
+// Create crée une nouvelle tontine
+func (r *TontineRepository) Create(ctx context.Context, tontine *entity.Tontine) error {
+	_, err := dbFromContext(ctx, r.db).Model(tontine).Insert()
+	if err != nil {
+		return fmt.Errorf("erreur création tontine: %w", err)
+	}
+	return nil
+}
+
+// GetByID récupère une tontine par son ID avec son compte, ses membres et ses tours avec leurs cotisations
+func (r *TontineRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Tontine, error) {
+	tontine := &entity.Tontine{}
+	err := dbFromContext(ctx, r.db).Model(tontine).
+		Relation("Account").
+		Relation("Members", func(q *orm.Query) (*orm.Query, error) {
+			return q.Order("payout_order ASC"), nil
+		}).
+		Relation("Cycles", func(q *orm.Query) (*orm.Query, error) {
+			return q.Order("number ASC"), nil
+		}).
+		Relation("Cycles.Contributions").
+		Where("tontine.id = ?", id).
+		Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, entity.ErrTontineNotFound
+		}
+		return nil, fmt.Errorf("erreur récupération tontine: %w", err)
+	}
+	return tontine, nil
+}
+
+// GetByIDForUpdate récupère une tontine en verrouillant sa ligne jusqu'à la fin de la transaction SQL
+func (r *TontineRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Tontine, error) {
+	tontine := &entity.Tontine{}
+	err := dbFromContext(ctx, r.db).Model(tontine).Where("id = ?", id).For("UPDATE").Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, entity.ErrTontineNotFound
+		}
+		return nil, fmt.Errorf("erreur verrouillage tontine: %w", err)
+	}
+	return tontine, nil
+}
+
+// GetByUserID récupère les tontines d'un utilisateur avec leur compte et leurs membres, de la plus récente à la plus
+// ancienne
+func (r *TontineRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Tontine, error) {
+	var tontines []*entity.Tontine
+	err := dbFromContext(ctx, r.db).Model(&tontines).
+		Relation("Account").
+		Relation("Members", func(q *orm.Query) (*orm.Query, error) {
+			return q.Order("payout_order ASC"), nil
+		}).
+		Where("tontine.user_id = ?", userID).
+		Order("tontine.start_date DESC", "tontine.created_at DESC").
+		Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération tontines: %w", err)
+	}
+	return tontines, nil
+}
+
+// Update met à jour une tontine
+func (r *TontineRepository) Update(ctx context.Context, tontine *entity.Tontine) error {
+	_, err := dbFromContext(ctx, r.db).Model(tontine).Where("id = ?", tontine.ID).Update()
+	if err != nil {
+		return fmt.Errorf("erreur mise à jour tontine: %w", err)
+	}
+	return nil
+}
+
+// Delete supprime une tontine avec ses membres, ses tours et ses cotisations
+func (r *TontineRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := dbFromContext(ctx, r.db).Model((*entity.Tontine)(nil)).Where("id = ?", id).Delete()
+	if err != nil {
+		return fmt.Errorf("erreur suppression tontine: %w", err)
+	}
+	return nil
+}
+
+// CreateMembers enregistre les membres d'une tontine
+func (r *TontineRepository) CreateMembers(ctx context.Context, members []*entity.TontineMember) error {
+	if len(members) == 0 {
+		return nil
+	}
+	if _, err := dbFromContext(ctx, r.db).Model(&members).Insert(); err != nil {
+		return fmt.Errorf("erreur création membres de tontine: %w", err)
+	}
+	return nil
+}
+
+// CreateCycles enregistre les tours d'une tontine
+func (r *TontineRepository) CreateCycles(ctx context.Context, cycles []*entity.TontineCycle) error {
+	if len(cycles) == 0 {
+		return nil
+	}
+	if _, err := dbFromContext(ctx, r.db).Model(&cycles).Insert(); err != nil {
+		return fmt.Errorf("erreur création tours de tontine: %w", err)
+	}
+	return nil
+}
+
+// CreateContributions enregistre les cotisations attendues des tours d'une tontine
+func (r *TontineRepository) CreateContributions(ctx context.Context, contributions []*entity.TontineContribution) error {
+	if len(contributions) == 0 {
+		return nil
+	}
+	if _, err := dbFromContext(ctx, r.db).Model(&contributions).Insert(); err != nil {
+		return fmt.Errorf("erreur création cotisations de tontine: %w", err)
+	}
+	return nil
+}
+
+// GetCycle récupère un tour d'une tontine par son numéro avec ses cotisations
+func (r *TontineRepository) GetCycle(ctx context.Context, tontineID uuid.UUID, number int) (*entity.TontineCycle, error) {
+	cycle := &entity.TontineCycle{}
+	err := dbFromContext(ctx, r.db).Model(cycle).
+		Relation("Contributions").
+		Where("tontine_cycle.tontine_id = ? AND tontine_cycle.number = ?", tontineID, number).
+		Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, fmt.Errorf("%w: tour %d inexistant", entity.ErrInvalidTontineData, number)
+		}
+		return nil, fmt.Errorf("erreur récupération tour de tontine: %w", err)
+	}
+	return cycle, nil
+}
+
+// UpdateCycle met à jour un tour de tontine
+func (r *TontineRepository) UpdateCycle(ctx context.Context, cycle *entity.TontineCycle) error {
+	_, err := dbFromContext(ctx, r.db).Model(cycle).Where("id = ?", cycle.ID).Update()
+	if err != nil {
+		return fmt.Errorf("erreur mise à jour tour de tontine: %w", err)
+	}
+	return nil
+}
+
+// UpdateContribution met à jour une cotisation
+func (r *TontineRepository) UpdateContribution(ctx context.Context, contribution *entity.TontineContribution) error {
+	_, err := dbFromContext(ctx, r.db).Model(contribution).Where("id = ?", contribution.ID).Update()
+	if err != nil {
+		return fmt.Errorf("erreur mise à jour cotisation: %w", err)
+	}
+	return nil
+}
+
+// GetPendingSelfContributions récupère les cotisations non versées de l'utilisateur dans ses tontines, par date
+func (r *TontineRepository) GetPendingSelfContributions(ctx context.Context, userID uuid.UUID) ([]*entity.TontineContribution, error) {
+	var contributions []*entity.TontineContribution
+	err := dbFromContext(ctx, r.db).Model(&contributions).
+		Where("user_id = ? AND is_self AND status = 'pending'", userID).
+		Order("due_date ASC").
+		Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération cotisations à verser: %w", err)
+	}
+	return contributions, nil
+}
+
+// GetContributionsToRemind récupère les cotisations de l'utilisateur dues jusqu'à la date donnée dont le rappel n'a
+// pas été envoyé
+func (r *TontineRepository) GetContributionsToRemind(ctx context.Context, dueBefore time.Time, limit int) ([]*entity.TontineContribution, error) {
+	var contributions []*entity.TontineContribution
+	err := dbFromContext(ctx, r.db).Model(&contributions).
+		Where("is_self AND status = 'pending' AND reminded_at IS NULL AND due_date <= ?", dueBefore).
+		Order("due_date ASC").
+		Limit(limit).
+		Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération cotisations à rappeler: %w", err)
+	}
+	return contributions, nil
+}
@@ -5,8 +5,11 @@ import (
 	"backend/internal/domaine/repository"
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
 	"github.com/google/uuid"
 )
 
@@ -22,7 +25,7 @@ func NewTransactionRepository(db *pg.DB) repository.TransactionRepository {
 
 // Create crée une nouvelle transaction
 func (r *TransactionRepository) Create(ctx context.Context, transaction *entity.Transaction) error {
-	_, err := r.db.WithContext(ctx).Model(transaction).Insert()
+	_, err := dbFromContext(ctx, r.db).Model(transaction).Insert()
 	if err != nil {
 		return fmt.Errorf("erreur création transaction: %w", err)
 	}
@@ -32,7 +35,7 @@ func (r *TransactionRepository) Create(ctx context.Context, transaction *entity.
 // GetByID récupère une transaction par son ID
 func (r *TransactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Transaction, error) {
 	transaction := &entity.Transaction{}
-	err := r.db.WithContext(ctx).Model(transaction).Where("id = ?", id).Select()
+	err := dbFromContext(ctx, r.db).Model(transaction).Relation("Splits").Relation("Tags").Where("id = ?", id).Select()
 	if err != nil {
 		if err == pg.ErrNoRows {
 			return nil, fmt.Errorf("transaction non trouvée")
@@ -42,38 +45,544 @@ func (r *TransactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*ent
 	return transaction, nil
 }
 
+// GetByIDForUpdate récupère une transaction par son ID en verrouillant sa ligne
+// jusqu'à la fin de la transaction SQL en cours
+func (r *TransactionRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Transaction, error) {
+	transaction := &entity.Transaction{}
+	err := dbFromContext(ctx, r.db).Model(transaction).Where("id = ?", id).For("UPDATE").Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, fmt.Errorf("transaction non trouvée")
+		}
+		return nil, fmt.Errorf("erreur verrouillage transaction: %w", err)
+	}
+	return transaction, nil
+}
+
 // GetByUserID récupère toutes les transactions d'un utilisateur
 func (r *TransactionRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Transaction, error) {
 	var transactions []*entity.Transaction
-	err := r.db.WithContext(ctx).Model(&transactions).Where("user_id = ?", userID).Order("date DESC").Order("created_at DESC").Select()
+	err := dbFromContext(ctx, r.db).Model(&transactions).Relation("Splits").Where("user_id = ?", userID).Order("date DESC").Order("created_at DESC").Select()
 	if err != nil {
 		return nil, fmt.Errorf("erreur récupération transactions utilisateur: %w", err)
 	}
 	return transactions, nil
 }
 
+// GetByIDs récupère, parmi les IDs donnés, les transactions appartenant à l'utilisateur
+func (r *TransactionRepository) GetByIDs(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) ([]*entity.Transaction, error) {
+	var transactions []*entity.Transaction
+	if len(ids) == 0 {
+		return transactions, nil
+	}
+	err := dbFromContext(ctx, r.db).Model(&transactions).Relation("Splits").
+		Where("transaction.user_id = ?", userID).
+		Where("transaction.id IN (?)", pg.In(ids)).
+		Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération transactions: %w", err)
+	}
+	return transactions, nil
+}
+
+// transactionSortColumns associe les clés de tri autorisées à leur colonne SQL et au type de la valeur du curseur
+var transactionSortColumns = map[string]struct{ column, cast string }{
+	"date":       {"transaction.date", "date"},
+	"amount":     {"minor_to_major(transaction.amount, transaction.currency)", "numeric"},
+	"created_at": {"transaction.created_at", ""},
+}
+
+// List récupère une page de transactions filtrées, triées et paginées par curseur (keyset).
+// Le tri se fait sur (colonne de tri, created_at, id) afin que l'ordre soit total et stable
+// même lorsque de nouvelles transactions sont insérées entre deux pages.
+func (r *TransactionRepository) List(ctx context.Context, userID uuid.UUID, filter *entity.TransactionFilter) ([]*entity.Transaction, error) {
+	var transactions []*entity.Transaction
+	query := applyTransactionFilter(dbFromContext(ctx, r.db).Model(&transactions).Relation("Category").Relation("Splits").Relation("Tags"), userID, filter)
+
+	sort, ok := transactionSortColumns[filter.SortBy]
+	if !ok {
+		sort = transactionSortColumns["date"]
+	}
+	direction, operator := "DESC", "<"
+	if filter.SortOrder == "asc" {
+		direction, operator = "ASC", ">"
+	}
+
+	if filter.Cursor != nil {
+		if sort.cast == "" {
+			query = query.Where("(transaction.created_at, transaction.id) "+operator+" (?, ?)", filter.Cursor.CreatedAt, filter.Cursor.ID)
+		} else {
+			query = query.Where("("+sort.column+", transaction.created_at, transaction.id) "+operator+" (?::"+sort.cast+", ?, ?)",
+				filter.Cursor.SortValue, filter.Cursor.CreatedAt, filter.Cursor.ID)
+		}
+	}
+
+	if sort.cast != "" {
+		query = query.Order(sort.column + " " + direction)
+	}
+	err := query.Order("transaction.created_at " + direction).Order("transaction.id " + direction).Limit(filter.Limit).Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération transactions filtrées: %w", err)
+	}
+	return transactions, nil
+}
+
+// Count compte les transactions correspondant aux filtres (le curseur est ignoré)
+func (r *TransactionRepository) Count(ctx context.Context, userID uuid.UUID, filter *entity.TransactionFilter) (int64, error) {
+	count, err := applyTransactionFilter(dbFromContext(ctx, r.db).Model((*entity.Transaction)(nil)), userID, filter).Count()
+	if err != nil {
+		return 0, fmt.Errorf("erreur comptage transactions: %w", err)
+	}
+	return int64(count), nil
+}
+
+// applyTransactionFilter ajoute à la requête les critères de filtrage communs à List et Count
+func applyTransactionFilter(query *orm.Query, userID uuid.UUID, filter *entity.TransactionFilter) *orm.Query {
+	query = query.Where("transaction.user_id = ?", userID)
+	if filter.Type != "" {
+		query = query.Where("transaction.type = ?", filter.Type)
+	}
+	if filter.CategoryID != nil {
+		// une transaction ventilée correspond si l'une de ses lignes porte la catégorie
+		query = query.Where("(transaction.category_id = ? OR EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = transaction.id AND s.category_id = ?))",
+			*filter.CategoryID, *filter.CategoryID)
+	}
+	if filter.AccountID != nil {
+		query = query.Where("transaction.account_id = ?", *filter.AccountID)
+	}
+	if len(filter.TagIDs) > 0 {
+		if filter.TagMatch == "all" {
+			query = query.Where("(SELECT COUNT(DISTINCT tt.tag_id) FROM transaction_tags tt WHERE tt.transaction_id = transaction.id AND tt.tag_id IN (?)) = ?",
+				pg.In(filter.TagIDs), len(filter.TagIDs))
+		} else {
+			query = query.Where("EXISTS (SELECT 1 FROM transaction_tags tt WHERE tt.transaction_id = transaction.id AND tt.tag_id IN (?))", pg.In(filter.TagIDs))
+		}
+	}
+	if filter.StartDate != nil {
+		query = query.Where("transaction.date >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("transaction.date <= ?", *filter.EndDate)
+	}
+	if filter.MinAmount != nil {
+		query = query.Where("minor_to_major(transaction.amount, transaction.currency) >= ?::numeric", string(*filter.MinAmount))
+	}
+	if filter.MaxAmount != nil {
+		query = query.Where("minor_to_major(transaction.amount, transaction.currency) <= ?::numeric", string(*filter.MaxAmount))
+	}
+	if filter.Status != "" {
+		query = query.Where("transaction.status = ?", filter.Status)
+	}
+	if filter.Search != "" {
+		query = query.Where("transaction.description ILIKE ?", "%"+escapeLike(filter.Search)+"%")
+	}
+	return query
+}
+
+// escapeLike échappe les caractères spéciaux d'un motif LIKE
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// GetSplits récupère les lignes de ventilation d'une transaction
+func (r *TransactionRepository) GetSplits(ctx context.Context, transactionID uuid.UUID) ([]*entity.TransactionSplit, error) {
+	var splits []*entity.TransactionSplit
+	err := dbFromContext(ctx, r.db).Model(&splits).Where("transaction_id = ?", transactionID).Order("created_at").Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération ventilation: %w", err)
+	}
+	return splits, nil
+}
+
+// ReplaceSplits remplace les lignes de ventilation d'une transaction
+func (r *TransactionRepository) ReplaceSplits(ctx context.Context, transactionID uuid.UUID, splits []*entity.TransactionSplit) error {
+	db := dbFromContext(ctx, r.db)
+	if _, err := db.Model((*entity.TransactionSplit)(nil)).Where("transaction_id = ?", transactionID).Delete(); err != nil {
+		return fmt.Errorf("erreur suppression ventilation: %w", err)
+	}
+	if len(splits) == 0 {
+		return nil
+	}
+	if _, err := db.Model(&splits).Insert(); err != nil {
+		return fmt.Errorf("erreur création ventilation: %w", err)
+	}
+	return nil
+}
+
+// GetExistingExternalRefs retourne, parmi les références données, celles déjà enregistrées sur le compte
+func (r *TransactionRepository) GetExistingExternalRefs(ctx context.Context, accountID uuid.UUID, refs []string) ([]string, error) {
+	var existing []string
+	if len(refs) == 0 {
+		return existing, nil
+	}
+	err := dbFromContext(ctx, r.db).Model((*entity.Transaction)(nil)).
+		Column("external_ref").
+		Where("account_id = ?", accountID).
+		Where("external_ref IN (?)", pg.In(refs)).
+		Select(&existing)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération références externes: %w", err)
+	}
+	return existing, nil
+}
+
+// GetSimilar récupère les transactions d'un compte de même type et de même montant dont la date est comprise
+// entre from et to (transferts exclus), candidates à la détection de doublons
+func (r *TransactionRepository) GetSimilar(ctx context.Context, accountID uuid.UUID, txType string, amount entity.Money, from, to time.Time) ([]*entity.Transaction, error) {
+	var transactions []*entity.Transaction
+	err := dbFromContext(ctx, r.db).Model(&transactions).
+		Relation("Category").
+		Where("transaction.account_id = ?", accountID).
+		Where("transaction.type = ?", txType).
+		Where("transaction.amount = ?", amount.Minor).
+		Where("transaction.date BETWEEN ?::date AND ?::date", from, to).
+		Where("transaction.transfer_group_id IS NULL").
+		Order("transaction.date", "transaction.created_at").
+		Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération transactions similaires: %w", err)
+	}
+	return transactions, nil
+}
+
+// GetForDuplicateScan récupère les transactions d'un utilisateur (transferts exclus), triées pour
+// regrouper les doublons potentiels
+func (r *TransactionRepository) GetForDuplicateScan(ctx context.Context, userID uuid.UUID, since *time.Time) ([]*entity.Transaction, error) {
+	var transactions []*entity.Transaction
+	query := dbFromContext(ctx, r.db).Model(&transactions).
+		Relation("Category").
+		Where("transaction.user_id = ?", userID).
+		Where("transaction.transfer_group_id IS NULL")
+	if since != nil {
+		query = query.Where("transaction.date >= ?::date", *since)
+	}
+	err := query.Order("transaction.account_id", "transaction.type", "transaction.amount", "transaction.date", "transaction.created_at").Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération transactions pour la recherche de doublons: %w", err)
+	}
+	return transactions, nil
+}
+
+// statsBaseAmount exprime le montant d'une transaction d'alias t en unités mineures de la devise de référence de
+// l'utilisateur, au taux de change en vigueur à sa date ; sans taux connu il est NULL et la transaction est exclue des sommes
+const statsBaseAmount = `to_base_minor(t.user_id, t.amount, t.currency, t.date)`
+
+// statsAmountColumns calcule revenus, dépenses et solde net des transactions d'alias t, dans la devise de référence :
+// un remboursement diminue les dépenses au lieu d'augmenter les revenus
+const statsAmountColumns = `COALESCE(SUM(` + statsBaseAmount + `) FILTER (WHERE t.type = 'income'), 0) AS income,
+		COALESCE(SUM(CASE t.type WHEN 'expense' THEN ` + statsBaseAmount + ` WHEN 'refund' THEN -` + statsBaseAmount + ` END), 0) AS expense,
+		COALESCE(SUM(CASE WHEN t.type = 'expense' THEN -` + statsBaseAmount + ` ELSE ` + statsBaseAmount + ` END), 0) AS net`
+
+// statsConditions construit les conditions communes aux agrégats de statistiques sur la table ou la vue
+// d'alias donné : revenus, dépenses et remboursements de l'utilisateur dans la plage de dates, sur le compte éventuel.
+// Les transactions planifiées sont exclues, les transactions en attente le sont sauf si le filtre les inclut.
+func statsConditions(alias string, userID uuid.UUID, filter *entity.TransactionStatsFilter) (string, []interface{}) {
+	conditions := fmt.Sprintf("%[1]s.user_id = ? AND %[1]s.type IN ('income', 'expense', 'refund') AND %[1]s.date BETWEEN ?::date AND ?::date", alias)
+	if filter.IncludePending {
+		conditions += fmt.Sprintf(" AND %s.status <> 'scheduled'", alias)
+	} else {
+		conditions += fmt.Sprintf(" AND %s.status IN ('cleared', 'reconciled')", alias)
+	}
+	params := []interface{}{userID, filter.StartDate, filter.EndDate}
+	if filter.AccountID != nil {
+		conditions += fmt.Sprintf(" AND %s.account_id = ?", alias)
+		params = append(params, *filter.AccountID)
+	}
+	return conditions, params
+}
+
+// GetStatsTotals calcule les totaux de revenus et de dépenses d'une période
+func (r *TransactionRepository) GetStatsTotals(ctx context.Context, userID uuid.UUID, filter *entity.TransactionStatsFilter) (*entity.TransactionStatsTotals, error) {
+	conditions, params := statsConditions("t", userID, filter)
+	query := `
+	SELECT
+		` + statsAmountColumns + `,
+		COUNT(*) AS count
+	FROM transactions t
+	WHERE t.deleted_at IS NULL AND ` + conditions
+
+	var totals entity.TransactionStatsTotals
+	if _, err := dbFromContext(ctx, r.db).QueryOne(&totals, query, params...); err != nil {
+		return nil, fmt.Errorf("erreur calcul totaux des transactions: %w", err)
+	}
+	return &totals, nil
+}
+
+// GetStatsSeries calcule les totaux par intervalle (day, week ou month) d'une période ; les intervalles
+// sans transaction sont présents avec des totaux nuls
+func (r *TransactionRepository) GetStatsSeries(ctx context.Context, userID uuid.UUID, filter *entity.TransactionStatsFilter, granularity string) ([]*entity.TransactionStatsBucket, error) {
+	conditions, conditionParams := statsConditions("t", userID, filter)
+	query := `
+	SELECT
+		b.period_start::date AS period_start,
+		` + statsAmountColumns + `,
+		COUNT(t.id) AS count
+	FROM generate_series(date_trunc(?, ?::date::timestamp), ?::date::timestamp, ?::interval) AS b(period_start)
+	LEFT JOIN transactions t ON date_trunc(?, t.date::timestamp) = b.period_start AND t.deleted_at IS NULL AND ` + conditions + `
+	GROUP BY b.period_start
+	ORDER BY b.period_start`
+
+	params := []interface{}{granularity, filter.StartDate, filter.EndDate, "1 " + granularity, granularity}
+	params = append(params, conditionParams...)
+
+	var buckets []*entity.TransactionStatsBucket
+	if _, err := dbFromContext(ctx, r.db).Query(&buckets, query, params...); err != nil {
+		return nil, fmt.Errorf("erreur calcul série des transactions: %w", err)
+	}
+	return buckets, nil
+}
+
+// GetStatsByCategory calcule les totaux par catégorie d'une période dans la devise de référence, ventilations comprises ; chaque ligne
+// porte la catégorie racine obtenue en remontant les catégories parentes (la catégorie elle-même à défaut).
+// Les remboursements sont déduits des dépenses de leur catégorie.
+func (r *TransactionRepository) GetStatsByCategory(ctx context.Context, userID uuid.UUID, filter *entity.TransactionStatsFilter) ([]*entity.CategoryStatsLine, error) {
+	conditions, conditionParams := statsConditions("l", userID, filter)
+	query := `
+	WITH RECURSIVE category_roots AS (
+		SELECT id, id AS root_id FROM categories WHERE user_id = ? AND parent_id IS NULL
+		UNION ALL
+		SELECT c.id, cr.root_id FROM categories c JOIN category_roots cr ON c.parent_id = cr.id
+	)
+	SELECT
+		COALESCE(cr.root_id, l.category_id) AS root_id,
+		COALESCE(root.name, '') AS root_name,
+		COALESCE(root.icon, '') AS root_icon,
+		COALESCE(root.color, '') AS root_color,
+		l.category_id,
+		COALESCE(c.name, '') AS category_name,
+		CASE WHEN l.type = 'refund' THEN 'expense' ELSE l.type END AS type,
+		COALESCE(SUM(CASE WHEN l.type = 'refund' THEN -1 ELSE 1 END * to_base_minor(l.user_id, l.amount, l.currency, l.date)), 0) AS amount,
+		COUNT(DISTINCT l.transaction_id) AS count
+	FROM transaction_lines l
+	LEFT JOIN category_roots cr ON cr.id = l.category_id
+	LEFT JOIN categories root ON root.id = COALESCE(cr.root_id, l.category_id)
+	LEFT JOIN categories c ON c.id = l.category_id
+	WHERE ` + conditions + `
+	GROUP BY COALESCE(cr.root_id, l.category_id), root.name, root.icon, root.color, l.category_id, c.name,
+		CASE WHEN l.type = 'refund' THEN 'expense' ELSE l.type END
+	ORDER BY type, amount DESC`
+
+	params := append([]interface{}{userID}, conditionParams...)
+
+	var lines []*entity.CategoryStatsLine
+	if _, err := dbFromContext(ctx, r.db).Query(&lines, query, params...); err != nil {
+		return nil, fmt.Errorf("erreur calcul statistiques par catégorie: %w", err)
+	}
+	return lines, nil
+}
+
+// GetStatsByAccount calcule les totaux par compte d'une période
+func (r *TransactionRepository) GetStatsByAccount(ctx context.Context, userID uuid.UUID, filter *entity.TransactionStatsFilter) ([]*entity.AccountStats, error) {
+	conditions, params := statsConditions("t", userID, filter)
+	query := `
+	SELECT
+		t.account_id,
+		COALESCE(a.name, '') AS account_name,
+		` + statsAmountColumns + `,
+		COUNT(*) AS count
+	FROM transactions t
+	LEFT JOIN accounts a ON a.id = t.account_id
+	WHERE t.deleted_at IS NULL AND ` + conditions + `
+	GROUP BY t.account_id, a.name
+	ORDER BY expense DESC, income DESC`
+
+	var accounts []*entity.AccountStats
+	if _, err := dbFromContext(ctx, r.db).Query(&accounts, query, params...); err != nil {
+		return nil, fmt.Errorf("erreur calcul statistiques par compte: %w", err)
+	}
+	return accounts, nil
+}
+
+// GetStatsByTag calcule les totaux par étiquette d'une période
+func (r *TransactionRepository) GetStatsByTag(ctx context.Context, userID uuid.UUID, filter *entity.TransactionStatsFilter) ([]*entity.TagStats, error) {
+	conditions, params := statsConditions("t", userID, filter)
+	query := `
+	SELECT
+		g.id AS tag_id,
+		g.name,
+		COALESCE(g.color, '') AS color,
+		` + statsAmountColumns + `,
+		COUNT(*) AS count
+	FROM transactions t
+	JOIN transaction_tags tt ON tt.transaction_id = t.id
+	JOIN tags g ON g.id = tt.tag_id
+	WHERE t.deleted_at IS NULL AND ` + conditions + `
+	GROUP BY g.id, g.name, g.color
+	ORDER BY expense DESC, income DESC, g.name`
+
+	var tags []*entity.TagStats
+	if _, err := dbFromContext(ctx, r.db).Query(&tags, query, params...); err != nil {
+		return nil, fmt.Errorf("erreur calcul statistiques par étiquette: %w", err)
+	}
+	return tags, nil
+}
+
+// GetStatsMissingRates liste les devises des transactions d'une période qui n'ont pas de taux de change vers
+// la devise de référence de l'utilisateur, et sont donc exclues des montants des statistiques
+func (r *TransactionRepository) GetStatsMissingRates(ctx context.Context, userID uuid.UUID, filter *entity.TransactionStatsFilter) ([]string, error) {
+	conditions, params := statsConditions("t", userID, filter)
+	query := `
+	SELECT DISTINCT t.currency
+	FROM transactions t
+	WHERE t.deleted_at IS NULL AND ` + conditions + ` AND ` + statsBaseAmount + ` IS NULL
+	ORDER BY t.currency`
+
+	var currencies []string
+	if _, err := dbFromContext(ctx, r.db).Query(&currencies, query, params...); err != nil {
+		return nil, fmt.Errorf("erreur recherche des taux de change manquants: %w", err)
+	}
+	return currencies, nil
+}
+
+// GetDueScheduledIDs récupère les IDs des transactions planifiées dont la date est échue à la date donnée
+func (r *TransactionRepository) GetDueScheduledIDs(ctx context.Context, date time.Time, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := dbFromContext(ctx, r.db).Model((*entity.Transaction)(nil)).
+		Column("id").
+		Where("status = 'scheduled'").
+		Where("date <= ?", date).
+		Order("date ASC").
+		Limit(limit).
+		Select(&ids)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération transactions planifiées échues: %w", err)
+	}
+	return ids, nil
+}
+
+// GetByAccountAndStatus récupère les transactions d'un compte ayant le statut donné et datées jusqu'au jour donné inclus
+func (r *TransactionRepository) GetByAccountAndStatus(ctx context.Context, accountID uuid.UUID, status string, through time.Time) ([]*entity.Transaction, error) {
+	var transactions []*entity.Transaction
+	err := dbFromContext(ctx, r.db).Model(&transactions).
+		Relation("Category").
+		Where("transaction.account_id = ?", accountID).
+		Where("transaction.status = ?", status).
+		Where("transaction.date <= ?", through).
+		Order("transaction.date ASC", "transaction.created_at ASC").
+		Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération transactions du compte par statut: %w", err)
+	}
+	return transactions, nil
+}
+
+// GetClearedEffectAfter retourne l'effet cumulé sur le solde courant d'un compte, en unités mineures,
+// des transactions passées ou rapprochées datées après le jour donné
+func (r *TransactionRepository) GetClearedEffectAfter(ctx context.Context, accountID uuid.UUID, date time.Time) (int64, error) {
+	var effect int64
+	err := dbFromContext(ctx, r.db).Model((*entity.Transaction)(nil)).
+		ColumnExpr(`COALESCE(SUM(CASE
+			WHEN type IN ('income', 'refund') THEN amount
+			WHEN type = 'transfer' AND account_id = to_account_id THEN amount
+			ELSE -amount
+		END), 0)`).
+		Where("account_id = ?", accountID).
+		Where("status IN ('cleared', 'reconciled')").
+		Where("date > ?", date).
+		Select(&effect)
+	if err != nil {
+		return 0, fmt.Errorf("erreur calcul de l'effet des transactions passées: %w", err)
+	}
+	return effect, nil
+}
+
+// GetByImportBatchID récupère les transactions créées par un lot d'import
+func (r *TransactionRepository) GetByImportBatchID(ctx context.Context, importBatchID uuid.UUID) ([]*entity.Transaction, error) {
+	var transactions []*entity.Transaction
+	err := dbFromContext(ctx, r.db).Model(&transactions).Where("import_batch_id = ?", importBatchID).Order("date").Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération transactions du lot d'import: %w", err)
+	}
+	return transactions, nil
+}
+
 // Update met à jour une transaction
 func (r *TransactionRepository) Update(ctx context.Context, transaction *entity.Transaction) error {
-	_, err := r.db.WithContext(ctx).Model(transaction).Where("id = ?", transaction.ID).Update()
+	_, err := dbFromContext(ctx, r.db).Model(transaction).Where("id = ?", transaction.ID).Update()
 	if err != nil {
 		return fmt.Errorf("erreur mise à jour transaction: %w", err)
 	}
 	return nil
 }
 
-// Delete supprime une transaction
+// Delete met une transaction à la corbeille (suppression logique)
 func (r *TransactionRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	_, err := r.db.WithContext(ctx).Model(&entity.Transaction{}).Where("id = ?", id).Delete()
+	_, err := dbFromContext(ctx, r.db).Model(&entity.Transaction{}).Where("id = ?", id).Delete()
 	if err != nil {
 		return fmt.Errorf("erreur suppression transaction: %w", err)
 	}
 	return nil
 }
 
+// GetDeletedByIDForUpdate récupère une transaction de la corbeille en verrouillant sa ligne
+func (r *TransactionRepository) GetDeletedByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Transaction, error) {
+	transaction := &entity.Transaction{}
+	err := dbFromContext(ctx, r.db).Model(transaction).Deleted().Where("id = ?", id).For("UPDATE").Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, fmt.Errorf("%w: transaction %s", entity.ErrTrashItemNotFound, id)
+		}
+		return nil, fmt.Errorf("erreur verrouillage transaction supprimée: %w", err)
+	}
+	return transaction, nil
+}
+
+// GetDeletedByTransferGroupIDForUpdate récupère et verrouille les jambes d'un transfert présentes dans la corbeille
+func (r *TransactionRepository) GetDeletedByTransferGroupIDForUpdate(ctx context.Context, transferGroupID uuid.UUID) ([]*entity.Transaction, error) {
+	var transactions []*entity.Transaction
+	err := dbFromContext(ctx, r.db).Model(&transactions).Deleted().Where("transfer_group_id = ?", transferGroupID).Order("id").For("UPDATE").Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur verrouillage jambes supprimées du transfert: %w", err)
+	}
+	return transactions, nil
+}
+
+// Restore sort une transaction de la corbeille
+func (r *TransactionRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	_, err := dbFromContext(ctx, r.db).Model((*entity.Transaction)(nil)).
+		Set("deleted_at = NULL").
+		Set("deleted_with_account_id = NULL").
+		Where("id = ?", id).
+		Deleted().
+		Update()
+	if err != nil {
+		return fmt.Errorf("erreur restauration transaction: %w", err)
+	}
+	return nil
+}
+
+// TrashByAccountID met à la corbeille les transactions actives d'un compte, en retenant ce compte
+// pour les restaurer avec lui. Les soldes ne sont pas modifiés.
+func (r *TransactionRepository) TrashByAccountID(ctx context.Context, accountID uuid.UUID, deletedAt time.Time) (int, error) {
+	result, err := dbFromContext(ctx, r.db).Model((*entity.Transaction)(nil)).
+		Set("deleted_at = ?", deletedAt).
+		Set("deleted_with_account_id = ?", accountID).
+		Where("account_id = ?", accountID).
+		Update()
+	if err != nil {
+		return 0, fmt.Errorf("erreur suppression transactions du compte: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// RestoreByAccountID sort de la corbeille les transactions supprimées avec un compte
+func (r *TransactionRepository) RestoreByAccountID(ctx context.Context, accountID uuid.UUID) (int, error) {
+	result, err := dbFromContext(ctx, r.db).Model((*entity.Transaction)(nil)).
+		Set("deleted_at = NULL").
+		Set("deleted_with_account_id = NULL").
+		Where("deleted_with_account_id = ?", accountID).
+		Deleted().
+		Update()
+	if err != nil {
+		return 0, fmt.Errorf("erreur restauration transactions du compte: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
 // GetByCategoryID récupère les transactions d'une catégorie
 func (r *TransactionRepository) GetByCategoryID(ctx context.Context, userID uuid.UUID, categoryID *uuid.UUID) ([]*entity.Transaction, error) {
 	var transactions []*entity.Transaction
-	query := r.db.WithContext(ctx).Model(&transactions).Where("user_id = ?", userID)
+	query := dbFromContext(ctx, r.db).Model(&transactions).Where("user_id = ?", userID)
 	if categoryID != nil {
 		query = query.Where("category_id = ?", *categoryID)
 	} else {
@@ -89,7 +598,7 @@ func (r *TransactionRepository) GetByCategoryID(ctx context.Context, userID uuid
 // GetByAccountID récupère les transactions d'un compte
 func (r *TransactionRepository) GetByAccountID(ctx context.Context, userID uuid.UUID, accountID *uuid.UUID) ([]*entity.Transaction, error) {
 	var transactions []*entity.Transaction
-	query := r.db.WithContext(ctx).Model(&transactions).Where("user_id = ?", userID)
+	query := dbFromContext(ctx, r.db).Model(&transactions).Where("user_id = ?", userID)
 	if accountID != nil {
 		query = query.Where("account_id = ?", *accountID)
 	} else {
@@ -105,27 +614,68 @@ func (r *TransactionRepository) GetByAccountID(ctx context.Context, userID uuid.
 // GetBySavingGoalID récupère les transactions d'un objectif d'épargne
 func (r *TransactionRepository) GetBySavingGoalID(ctx context.Context, userID uuid.UUID, savingGoalID uuid.UUID) ([]*entity.Transaction, error) {
 	var transactions []*entity.Transaction
-	err := r.db.WithContext(ctx).Model(&transactions).Where("user_id = ? AND saving_goal_id = ?", userID, savingGoalID).Order("date DESC").Select()
+	err := dbFromContext(ctx, r.db).Model(&transactions).Where("user_id = ? AND saving_goal_id = ?", userID, savingGoalID).Order("date DESC").Select()
 	if err != nil {
 		return nil, fmt.Errorf("erreur récupération transactions par objectif d'épargne: %w", err)
 	}
 	return transactions, nil
 }
 
-// GetTransfers récupère les transferts entre comptes
+// GetTransfers récupère les jambes des transferts entre comptes
 func (r *TransactionRepository) GetTransfers(ctx context.Context, userID uuid.UUID) ([]*entity.Transaction, error) {
 	var transactions []*entity.Transaction
-	err := r.db.WithContext(ctx).Model(&transactions).Where("user_id = ? AND type = ? AND to_account_id IS NOT NULL", userID, "transfer").Order("date DESC").Select()
+	err := dbFromContext(ctx, r.db).Model(&transactions).Where("user_id = ? AND type = ? AND transfer_group_id IS NOT NULL", userID, "transfer").Order("date DESC").Order("created_at DESC").Select()
 	if err != nil {
 		return nil, fmt.Errorf("erreur récupération transferts: %w", err)
 	}
 	return transactions, nil
 }
 
+// GetByTransferGroupIDForUpdate récupère et verrouille les deux jambes d'un transfert
+func (r *TransactionRepository) GetByTransferGroupIDForUpdate(ctx context.Context, transferGroupID uuid.UUID) ([]*entity.Transaction, error) {
+	var transactions []*entity.Transaction
+	err := dbFromContext(ctx, r.db).Model(&transactions).Where("transfer_group_id = ?", transferGroupID).Order("id").For("UPDATE").Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur verrouillage jambes du transfert: %w", err)
+	}
+	return transactions, nil
+}
+
+// StreamForExport parcourt les transactions filtrées par ordre chronologique sans les charger en mémoire :
+// fn est appelée pour chaque ligne lue, une erreur de fn interrompt la lecture
+func (r *TransactionRepository) StreamForExport(ctx context.Context, userID uuid.UUID, filter *entity.TransactionFilter, fn func(*entity.TransactionExportRow) error) error {
+	query := applyTransactionFilter(dbFromContext(ctx, r.db).Model((*entity.Transaction)(nil)), userID, filter).
+		ColumnExpr("transaction.id, transaction.date, transaction.type, transaction.amount, transaction.description, transaction.category_id, transaction.external_ref").
+		ColumnExpr("COALESCE(a.name, '') AS account_name, transaction.currency, transaction.status").
+		ColumnExpr("COALESCE((SELECT string_agg(g.name, ', ' ORDER BY g.name) FROM transaction_tags tt JOIN tags g ON g.id = tt.tag_id WHERE tt.transaction_id = transaction.id), '') AS tags").
+		ColumnExpr("(SELECT array_agg(s.category_id::text ORDER BY s.amount DESC) FROM transaction_splits s WHERE s.transaction_id = transaction.id) AS split_category_ids").
+		ColumnExpr("(SELECT array_agg(s.amount ORDER BY s.amount DESC) FROM transaction_splits s WHERE s.transaction_id = transaction.id) AS split_amounts").
+		Join("LEFT JOIN accounts AS a ON a.id = transaction.account_id").
+		Order("transaction.date ASC", "transaction.created_at ASC", "transaction.id ASC")
+
+	if err := query.ForEach(fn); err != nil {
+		return fmt.Errorf("erreur lecture des transactions à exporter: %w", err)
+	}
+	return nil
+}
+
+// GetRefunds récupère les remboursements liés à une dépense, du plus ancien au plus récent
+func (r *TransactionRepository) GetRefunds(ctx context.Context, originalID uuid.UUID) ([]*entity.Transaction, error) {
+	var transactions []*entity.Transaction
+	err := dbFromContext(ctx, r.db).Model(&transactions).Relation("Category").Relation("Account").Relation("Tags").
+		Where("transaction.refund_of_id = ?", originalID).
+		Order("transaction.date ASC").Order("transaction.created_at ASC").
+		Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération remboursements: %w", err)
+	}
+	return transactions, nil
+}
+
 // GetByDateRange récupère les transactions dans une plage de dates
 func (r *TransactionRepository) GetByDateRange(ctx context.Context, userID uuid.UUID, startDate, endDate string) ([]*entity.Transaction, error) {
 	var transactions []*entity.Transaction
-	err := r.db.WithContext(ctx).Model(&transactions).Relation("Category").Relation("Account").Where("transaction.user_id = ? AND transaction.date >= ? AND transaction.date <= ?", userID, startDate, endDate).Order("transaction.date DESC").Select()
+	err := dbFromContext(ctx, r.db).Model(&transactions).Relation("Category").Relation("Account").Relation("Splits").Where("transaction.user_id = ? AND transaction.date >= ? AND transaction.date <= ?", userID, startDate, endDate).Order("transaction.date DESC").Select()
 	if err != nil {
 		return nil, fmt.Errorf("erreur récupération transactions par plage de dates: %w", err)
 	}
@@ -135,27 +685,17 @@ func (r *TransactionRepository) GetByDateRange(ctx context.Context, userID uuid.
 // GetByType récupère les transactions par type
 func (r *TransactionRepository) GetByType(ctx context.Context, userID uuid.UUID, txType string) ([]*entity.Transaction, error) {
 	var transactions []*entity.Transaction
-	err := r.db.WithContext(ctx).Model(&transactions).Where("user_id = ? AND type = ?", userID, txType).Order("date DESC").Select()
+	err := dbFromContext(ctx, r.db).Model(&transactions).Where("user_id = ? AND type = ?", userID, txType).Order("date DESC").Select()
 	if err != nil {
 		return nil, fmt.Errorf("erreur récupération transactions par type: %w", err)
 	}
 	return transactions, nil
 }
 
-// GetTotalByUserID récupère le total des transactions d'un utilisateur
-func (r *TransactionRepository) GetTotalByUserID(ctx context.Context, userID uuid.UUID) (float64, error) {
-	var total float64
-	err := r.db.WithContext(ctx).Model(&entity.Transaction{}).Column("amount").Where("user_id = ?", userID).Select(&total)
-	if err != nil {
-		return 0, fmt.Errorf("erreur récupération total transactions: %w", err)
-	}
-	return total, nil
-}
-
 // get all transactions by user id and account id order ber  created_at desc
 func (r *TransactionRepository) GetAllTransactionsByUserIDAndAccountID(ctx context.Context, userID uuid.UUID, accountID uuid.UUID) ([]*entity.Transaction, error) {
 	var transactions []*entity.Transaction
-	err := r.db.WithContext(ctx).Model(&transactions).Where("user_id = ? AND account_id = ?", userID, accountID).Order("created_at DESC").Select()
+	err := dbFromContext(ctx, r.db).Model(&transactions).Where("user_id = ? AND account_id = ?", userID, accountID).Order("created_at DESC").Select()
 	if err != nil {
 		return nil, fmt.Errorf("erreur récupération transactions par utilisateur et compte: %w", err)
 	}
@@ -165,7 +705,7 @@ func (r *TransactionRepository) GetAllTransactionsByUserIDAndAccountID(ctx conte
 // get all transactions by user id and saving goal id order by created_at desc
 func (r *TransactionRepository) GetAllTransactionsBySavingGoalID(ctx context.Context, userID uuid.UUID, savingGoalID uuid.UUID) ([]*entity.Transaction, error) {
 	var transactions []*entity.Transaction
-	err := r.db.WithContext(ctx).Model(&transactions).Where("user_id = ? AND saving_goal_id = ?", userID, savingGoalID).Order("created_at DESC").Select()
+	err := dbFromContext(ctx, r.db).Model(&transactions).Where("user_id = ? AND saving_goal_id = ?", userID, savingGoalID).Order("created_at DESC").Select()
 	if err != nil {
 		return nil, fmt.Errorf("erreur récupération transactions par utilisateur et objectif d'épargne: %w", err)
 	}
@@ -176,7 +716,7 @@ func (r *TransactionRepository) GetAllTransactionsBySavingGoalID(ctx context.Con
 func (r *TransactionRepository) GetByAccountIDWithCategoryDetails(ctx context.Context, userID uuid.UUID, accountID *uuid.UUID) ([]*entity.Transaction, error) {
 	// D'abord, récupérer les transactions
 	var transactions []*entity.Transaction
-	query := r.db.WithContext(ctx).Model(&transactions).Relation("Category").Relation("SavingGoal").Where("transaction.user_id = ?", userID)
+	query := dbFromContext(ctx, r.db).Model(&transactions).Relation("Category").Relation("SavingGoal").Relation("Splits").Where("transaction.user_id = ?", userID)
 
 	if accountID != nil {
 		query = query.Where("transaction.account_id = ?", *accountID)
@@ -188,13 +728,6 @@ func (r *TransactionRepository) GetByAccountIDWithCategoryDetails(ctx context.Co
 	if err != nil {
 		return nil, fmt.Errorf("erreur récupération transactions: %w", err)
 	}
-	for _, tx := range transactions {
-		if tx.Category != nil {
-			fmt.Printf("Transaction ID: %s, Amount: %.2f, Category: %s\n", tx.ID, tx.Amount, tx.Category.Name)
-		} else {
-			fmt.Printf("Transaction ID: %s, Amount: %.2f, Category: <nil>\n", tx.ID, tx.Amount)
-		}
-	}
 
 	return transactions, nil
 }
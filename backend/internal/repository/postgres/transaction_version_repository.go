@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/internal/domaine/repository"
+	"context"
+	"fmt"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+)
+
+// TransactionVersionRepository implémente repository.TransactionVersionRepository
+type TransactionVersionRepository struct {
+	db *pg.DB
+}
+
+// NewTransactionVersionRepository crée une nouvelle instance de TransactionVersionRepository
+func NewTransactionVersionRepository(db *pg.DB) repository.TransactionVersionRepository {
+	return &TransactionVersionRepository{db: db}
+}
+
+// Create enregistre une version ; son numéro suit la dernière version de la transaction, dont la ligne
+// doit être verrouillée (ou tout juste créée) dans la transaction SQL en cours
+func (r *TransactionVersionRepository) Create(ctx context.Context, version *entity.TransactionVersion) error {
+	_, err := dbFromContext(ctx, r.db).Model(version).
+		Value("version", "(SELECT COALESCE(MAX(version), 0) + 1 FROM transaction_versions WHERE transaction_id = ?)", version.TransactionID).
+		Returning("version").
+		Insert()
+	if err != nil {
+		return fmt.Errorf("erreur création version de transaction: %w", err)
+	}
+	return nil
+}
+
+// GetByTransactionID récupère l'historique d'une transaction, de la plus ancienne à la plus récente version
+func (r *TransactionVersionRepository) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*entity.TransactionVersion, error) {
+	var versions []*entity.TransactionVersion
+	err := dbFromContext(ctx, r.db).Model(&versions).
+		Where("transaction_id = ?", transactionID).
+		Order("version ASC").
+		Select()
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération historique de transaction: %w", err)
+	}
+	return versions, nil
+}
+
+// GetByVersion récupère une version donnée d'une transaction
+func (r *TransactionVersionRepository) GetByVersion(ctx context.Context, transactionID uuid.UUID, version int) (*entity.TransactionVersion, error) {
+	transactionVersion := &entity.TransactionVersion{}
+	err := dbFromContext(ctx, r.db).Model(transactionVersion).
+		Where("transaction_id = ?", transactionID).
+		Where("version = ?", version).
+		Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, fmt.Errorf("%w: version %d", entity.ErrTransactionVersionNotFound, version)
+		}
+		return nil, fmt.Errorf("erreur récupération version de transaction: %w", err)
+	}
+	return transactionVersion, nil
+}
+
+// GetLatest récupère la dernière version d'une transaction, nil si elle n'a pas d'historique
+func (r *TransactionVersionRepository) GetLatest(ctx context.Context, transactionID uuid.UUID) (*entity.TransactionVersion, error) {
+	transactionVersion := &entity.TransactionVersion{}
+	err := dbFromContext(ctx, r.db).Model(transactionVersion).
+		Where("transaction_id = ?", transactionID).
+		Order("version DESC").
+		Limit(1).
+		Select()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erreur récupération dernière version de transaction: %w", err)
+	}
+	return transactionVersion, nil
+}
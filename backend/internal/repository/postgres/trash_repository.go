@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/internal/domaine/repository"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+)
+
+// TrashRepository implémente repository.TrashRepository
+type TrashRepository struct {
+	db *pg.DB
+}
+
+// NewTrashRepository crée une nouvelle instance de TrashRepository
+func NewTrashRepository(db *pg.DB) repository.TrashRepository {
+	return &TrashRepository{db: db}
+}
+
+// List récupère les éléments de la corbeille d'un utilisateur, les plus récemment supprimés en premier.
+// Les transactions et objectifs supprimés avec leur compte sont comptés avec lui au lieu d'être listés.
+func (r *TrashRepository) List(ctx context.Context, userID uuid.UUID) ([]*entity.TrashItem, error) {
+	query := `
+	SELECT 'transaction' AS type, t.id, t.description AS name, t.amount, t.currency, t.date, 0 AS related_count, t.deleted_at
+	FROM transactions t
+	WHERE t.user_id = ? AND t.deleted_at IS NOT NULL AND t.deleted_with_account_id IS NULL
+	UNION ALL
+	SELECT 'account' AS type, a.id, a.name, a.balance AS amount, a.currency, NULL AS date,
+		(SELECT COUNT(*) FROM transactions t WHERE t.deleted_with_account_id = a.id) AS related_count, a.deleted_at
+	FROM accounts a
+	WHERE a.user_id = ? AND a.deleted_at IS NOT NULL
+	UNION ALL
+	SELECT 'budget' AS type, b.id, b.name, b.amount_planned AS amount, b.currency, NULL AS date, 0 AS related_count, b.deleted_at
+	FROM budgets b
+	WHERE b.user_id = ? AND b.deleted_at IS NOT NULL
+	UNION ALL
+	SELECT 'saving_goal' AS type, g.id, g.title AS name, g.target_amount AS amount, g.currency, NULL AS date, 0 AS related_count, g.deleted_at
+	FROM saving_goals g
+	WHERE g.user_id = ? AND g.deleted_at IS NOT NULL AND g.deleted_with_account_id IS NULL
+	ORDER BY deleted_at DESC`
+
+	var items []*entity.TrashItem
+	if _, err := dbFromContext(ctx, r.db).Query(&items, query, userID, userID, userID, userID); err != nil {
+		return nil, fmt.Errorf("erreur récupération corbeille: %w", err)
+	}
+	return items, nil
+}
+
+// Purge supprime définitivement les éléments mis à la corbeille avant la date donnée, tous utilisateurs
+// confondus. Les clés de stockage des pièces jointes supprimées sont retournées pour effacer leurs fichiers
+// après validation. À exécuter dans une transaction SQL.
+func (r *TrashRepository) Purge(ctx context.Context, before time.Time) (*entity.TrashPurgeResult, error) {
+	db := dbFromContext(ctx, r.db)
+	result := &entity.TrashPurgeResult{}
+
+	// Transactions supprimées définitivement : celles de la corbeille et celles des comptes purgés (cascade)
+	const purgedTransactions = `SELECT id FROM transactions
+		WHERE deleted_at < ?0 OR account_id IN (SELECT id FROM accounts WHERE deleted_at < ?0)`
+
+	if _, err := db.Query(&result.StorageKeys, `
+	SELECT a.storage_key FROM transaction_attachments a
+	WHERE a.transaction_id IN (`+purgedTransactions+`)`, before); err != nil {
+		return nil, fmt.Errorf("erreur récupération pièces jointes à purger: %w", err)
+	}
+
+	// Un remboursement encore présent perd le lien vers une dépense purgée (clé étrangère ON DELETE RESTRICT)
+	if _, err := db.Exec(`
+	UPDATE transactions SET refund_of_id = NULL
+	WHERE refund_of_id IN (`+purgedTransactions+`)`, before); err != nil {
+		return nil, fmt.Errorf("erreur détachement des remboursements à purger: %w", err)
+	}
+
+	res, err := db.Model((*entity.Transaction)(nil)).Where("deleted_at < ?", before).ForceDelete()
+	if err != nil {
+		return nil, fmt.Errorf("erreur purge transactions: %w", err)
+	}
+	result.Transactions = res.RowsAffected()
+
+	res, err = db.Model((*entity.SavingGoal)(nil)).Where("deleted_at < ?", before).ForceDelete()
+	if err != nil {
+		return nil, fmt.Errorf("erreur purge objectifs d'épargne: %w", err)
+	}
+	result.SavingGoals = res.RowsAffected()
+
+	res, err = db.Model((*entity.Budget)(nil)).Where("deleted_at < ?", before).ForceDelete()
+	if err != nil {
+		return nil, fmt.Errorf("erreur purge budgets: %w", err)
+	}
+	result.Budgets = res.RowsAffected()
+
+	res, err = db.Model((*entity.Account)(nil)).Where("deleted_at < ?", before).ForceDelete()
+	if err != nil {
+		return nil, fmt.Errorf("erreur purge comptes: %w", err)
+	}
+	result.Accounts = res.RowsAffected()
+
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"backend/internal/domaine/repository"
+	"context"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+)
+
+// txKey est la clé de contexte portant la transaction SQL en cours
+type txKey struct{}
+
+// txState regroupe la transaction SQL en cours et les actions à exécuter après sa validation
+type txState struct {
+	tx          *pg.Tx
+	afterCommit []func()
+}
+
+// TxManager implémente repository.TxManager avec les transactions go-pg
+type TxManager struct {
+	db *pg.DB
+}
+
+// NewTxManager crée une nouvelle instance de TxManager
+func NewTxManager(db *pg.DB) repository.TxManager {
+	return &TxManager{db: db}
+}
+
+// WithinTransaction exécute fn dans une transaction SQL unique.
+// Les repositories appelés avec le contexte fourni à fn utilisent cette transaction,
+// qui est annulée si fn retourne une erreur et validée sinon.
+// Un appel imbriqué réutilise la transaction déjà ouverte.
+func (m *TxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*txState); ok {
+		return fn(ctx)
+	}
+
+	state := &txState{}
+	err := m.db.RunInTransaction(ctx, func(tx *pg.Tx) error {
+		state.tx, state.afterCommit = tx, nil
+		return fn(context.WithValue(ctx, txKey{}, state))
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, action := range state.afterCommit {
+		action()
+	}
+	return nil
+}
+
+// AfterCommit exécute fn une fois la transaction SQL la plus externe validée (jamais si elle est annulée).
+// Hors transaction, fn est exécutée immédiatement.
+func (m *TxManager) AfterCommit(ctx context.Context, fn func()) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, fn)
+		return
+	}
+	fn()
+}
+
+// dbFromContext retourne la transaction portée par le contexte, sinon la connexion principale
+func dbFromContext(ctx context.Context, db *pg.DB) orm.DB {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok && state.tx != nil {
+		return state.tx
+	}
+	return db.WithContext(ctx)
+}
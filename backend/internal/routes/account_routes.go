@@ -17,10 +17,16 @@ func SetupAccountRoutes(r chi.Router, accountHandler *handler.AccountHandler, au
 		// Routes pour la gestion des comptes
 		r.Post("/", accountHandler.CreateAccount)                // POST /api/v1/accounts
 		r.Get("/", accountHandler.GetAccounts)                   // GET /api/v1/accounts
+		r.Get("/balances", accountHandler.GetBalancesAsOf)       // GET /api/v1/accounts/balances?date=
 		r.Get("/{id}", accountHandler.GetAccount)                // GET /api/v1/accounts/{id}
 		r.Put("/{id}", accountHandler.UpdateAccount)             // PUT /api/v1/accounts/{id}
 		r.Delete("/{id}", accountHandler.DeleteAccount)          // DELETE /api/v1/accounts/{id}
 		r.Get("/{id}/balance", accountHandler.GetAccountBalance) // GET /api/v1/accounts/{id}/balance
 		r.Get("/{id}/details", accountHandler.GetAccountDetails) // GET /api/v1/accounts/{id}/details
+		r.Get("/{id}/history", accountHandler.GetBalanceHistory) // GET /api/v1/accounts/{id}/history
+
+		// Clôture et réouverture
+		r.Post("/{id}/close", accountHandler.CloseAccount)   // POST /api/v1/accounts/{id}/close
+		r.Post("/{id}/reopen", accountHandler.ReopenAccount) // POST /api/v1/accounts/{id}/reopen
 	})
 }
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"backend/internal/handler"
+	"backend/pkg/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SetupExchangeRateRoutes configure les routes pour les devises (devise de référence et taux de change)
+func SetupExchangeRateRoutes(r chi.Router, exchangeRateHandler *handler.ExchangeRateHandler, authMiddleware *middleware.AuthMiddleware) {
+	// Groupe de routes pour les devises (protégées par authentification)
+	r.Route("/currencies", func(r chi.Router) {
+		// Appliquer l'authentification à toutes les routes
+		r.Use(authMiddleware.Authenticate)
+
+		// Routes pour la devise de référence
+		r.Get("/base", exchangeRateHandler.GetBaseCurrency) // GET /api/v1/currencies/base
+		r.Put("/base", exchangeRateHandler.SetBaseCurrency) // PUT /api/v1/currencies/base
+
+		// Routes pour les taux de change
+		r.Get("/rates", exchangeRateHandler.GetRates)            // GET /api/v1/currencies/rates
+		r.Post("/rates", exchangeRateHandler.CreateRate)         // POST /api/v1/currencies/rates
+		r.Post("/rates/import", exchangeRateHandler.ImportRates) // POST /api/v1/currencies/rates/import
+		r.Delete("/rates/{id}", exchangeRateHandler.DeleteRate)  // DELETE /api/v1/currencies/rates/{id}
+	})
+}
@@ -1,19 +1,23 @@
 package routes
 
 import (
-	"backend/pkg/logger"
+	"backend/internal/handler"
+	"backend/pkg/middleware"
 
 	"github.com/go-chi/chi/v5"
 )
 
-// SetupFileRoutes configure les routes pour les fichiers
-// TODO: Implémenter quand FileUsecase et FileHandler seront créés
-func SetupFileRoutes(r chi.Router, fileUsecase interface{}, logger logger.Logger) {
-	// fileHandler := handler.NewFileHandler(fileUsecase, logger)
+// SetupFileRoutes configure les routes pour les fichiers (pièces jointes des transactions)
+func SetupFileRoutes(r chi.Router, attachmentHandler *handler.AttachmentHandler, authMiddleware *middleware.AuthMiddleware) {
+	// Groupe de routes pour les pièces jointes (protégées par authentification)
+	r.Route("/transactions/{id}/attachments", func(r chi.Router) {
+		// Appliquer l'authentification à toutes les routes
+		r.Use(authMiddleware.Authenticate)
 
-	// r.Route("/files", func(r chi.Router) {
-	// 	r.Post("/upload", fileHandler.UploadFile)
-	// 	r.Get("/{id}", fileHandler.GetFile)
-	// 	r.Delete("/{id}", fileHandler.DeleteFile)
-	// })
+		// Routes pour la gestion des pièces jointes
+		r.Post("/", attachmentHandler.UploadAttachment)                 // POST /api/v1/transactions/{id}/attachments
+		r.Get("/", attachmentHandler.GetAttachments)                    // GET /api/v1/transactions/{id}/attachments
+		r.Get("/{attachmentID}", attachmentHandler.DownloadAttachment)  // GET /api/v1/transactions/{id}/attachments/{attachmentID}
+		r.Delete("/{attachmentID}", attachmentHandler.DeleteAttachment) // DELETE /api/v1/transactions/{id}/attachments/{attachmentID}
+	})
 }
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"backend/internal/handler"
+	"backend/pkg/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SetupImportRoutes configure les routes pour l'import de relevés bancaires
+func SetupImportRoutes(r chi.Router, importHandler *handler.ImportHandler, authMiddleware *middleware.AuthMiddleware) {
+	// Groupe de routes pour les imports (protégées par authentification)
+	r.Route("/imports", func(r chi.Router) {
+		// Appliquer l'authentification à toutes les routes
+		r.Use(authMiddleware.Authenticate)
+
+		// Routes pour l'import de relevés
+		r.Post("/preview", importHandler.PreviewImport) // POST /api/v1/imports/preview
+		r.Post("/", importHandler.ImportStatement)      // POST /api/v1/imports
+		r.Get("/", importHandler.GetImportBatches)      // GET /api/v1/imports
+		r.Get("/{id}", importHandler.GetImportBatch)    // GET /api/v1/imports/{id}
+		r.Post("/{id}/undo", importHandler.UndoImport)  // POST /api/v1/imports/{id}/undo
+	})
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"backend/internal/handler"
+	"backend/pkg/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SetupLoanRoutes configure les routes pour les emprunts
+func SetupLoanRoutes(r chi.Router, loanHandler *handler.LoanHandler, authMiddleware *middleware.AuthMiddleware) {
+	// Groupe de routes pour les emprunts (protégées par authentification)
+	r.Route("/loans", func(r chi.Router) {
+		// Appliquer l'authentification à toutes les routes
+		r.Use(authMiddleware.Authenticate)
+
+		// Routes de base CRUD
+		r.Post("/", loanHandler.CreateLoan)       // POST /api/v1/loans
+		r.Get("/", loanHandler.GetLoans)          // GET /api/v1/loans
+		r.Get("/{id}", loanHandler.GetLoan)       // GET /api/v1/loans/{id}
+		r.Delete("/{id}", loanHandler.DeleteLoan) // DELETE /api/v1/loans/{id}
+
+		// Rappels des échéances impayées et à venir
+		r.Get("/reminders", loanHandler.GetReminders) // GET /api/v1/loans/reminders
+
+		// Règlement des échéances et projection du remboursement
+		r.Post("/{id}/payments", loanHandler.PayInstallment) // POST /api/v1/loans/{id}/payments
+		r.Get("/{id}/projection", loanHandler.GetProjection) // GET /api/v1/loans/{id}/projection
+	})
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"backend/internal/handler"
+	"backend/pkg/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SetupReconciliationRoutes configure les routes pour le rapprochement des comptes avec leurs relevés
+func SetupReconciliationRoutes(r chi.Router, reconciliationHandler *handler.ReconciliationHandler, authMiddleware *middleware.AuthMiddleware) {
+	// Groupe de routes pour les rapprochements (protégées par authentification)
+	r.Route("/accounts/{id}/reconciliations", func(r chi.Router) {
+		// Appliquer l'authentification à toutes les routes
+		r.Use(authMiddleware.Authenticate)
+
+		// Routes pour la gestion des rapprochements
+		r.Post("/", reconciliationHandler.StartReconciliation)                               // POST /api/v1/accounts/{id}/reconciliations
+		r.Get("/", reconciliationHandler.GetReconciliations)                                 // GET /api/v1/accounts/{id}/reconciliations
+		r.Get("/{reconciliationID}", reconciliationHandler.GetReconciliation)                // GET /api/v1/accounts/{id}/reconciliations/{reconciliationID}
+		r.Delete("/{reconciliationID}", reconciliationHandler.CancelReconciliation)          // DELETE /api/v1/accounts/{id}/reconciliations/{reconciliationID}
+		r.Post("/{reconciliationID}/clear", reconciliationHandler.ClearItems)                // POST /api/v1/accounts/{id}/reconciliations/{reconciliationID}/clear
+		r.Post("/{reconciliationID}/adjustment", reconciliationHandler.PostAdjustment)       // POST /api/v1/accounts/{id}/reconciliations/{reconciliationID}/adjustment
+		r.Post("/{reconciliationID}/complete", reconciliationHandler.CompleteReconciliation) // POST /api/v1/accounts/{id}/reconciliations/{reconciliationID}/complete
+		r.Post("/{reconciliationID}/reopen", reconciliationHandler.ReopenReconciliation)     // POST /api/v1/accounts/{id}/reconciliations/{reconciliationID}/reopen
+	})
+}
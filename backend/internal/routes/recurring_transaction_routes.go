@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"backend/internal/handler"
+	"backend/pkg/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SetupRecurringTransactionRoutes configure les routes pour les transactions récurrentes
+func SetupRecurringTransactionRoutes(r chi.Router, recurringHandler *handler.RecurringTransactionHandler, authMiddleware *middleware.AuthMiddleware) {
+	// Groupe de routes pour les transactions récurrentes (protégées par authentification)
+	r.Route("/recurring-transactions", func(r chi.Router) {
+		// Appliquer l'authentification à toutes les routes
+		r.Use(authMiddleware.Authenticate)
+
+		// Routes pour la gestion des modèles récurrents
+		r.Post("/", recurringHandler.CreateRecurringTransaction)            // POST /api/v1/recurring-transactions
+		r.Get("/", recurringHandler.GetRecurringTransactions)               // GET /api/v1/recurring-transactions
+		r.Get("/{id}", recurringHandler.GetRecurringTransaction)            // GET /api/v1/recurring-transactions/{id}
+		r.Put("/{id}", recurringHandler.UpdateRecurringTransaction)         // PUT /api/v1/recurring-transactions/{id}
+		r.Delete("/{id}", recurringHandler.DeleteRecurringTransaction)      // DELETE /api/v1/recurring-transactions/{id}
+		r.Post("/{id}/pause", recurringHandler.PauseRecurringTransaction)   // POST /api/v1/recurring-transactions/{id}/pause
+		r.Post("/{id}/resume", recurringHandler.ResumeRecurringTransaction) // POST /api/v1/recurring-transactions/{id}/resume
+		r.Post("/{id}/skip", recurringHandler.SkipNextOccurrence)           // POST /api/v1/recurring-transactions/{id}/skip
+		r.Get("/{id}/upcoming", recurringHandler.GetUpcomingOccurrences)    // GET /api/v1/recurring-transactions/{id}/upcoming
+	})
+}
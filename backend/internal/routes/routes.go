@@ -18,12 +18,21 @@ func SetupRoutes(
 	authMiddleware *middleware.AuthMiddleware,
 	taskHandler *handler.TaskHandler,
 	transactionHandler *handler.TransactionHandler,
+	recurringTransactionHandler *handler.RecurringTransactionHandler,
+	importHandler *handler.ImportHandler,
+	attachmentHandler *handler.AttachmentHandler,
+	tagHandler *handler.TagHandler,
 	accountHandler *handler.AccountHandler,
 	budgetHandler *handler.BudgetHandler,
 	savingGoalHandler *handler.SavingGoalHandler,
 	categoryHandler *handler.CategoryHandler,
 	preferencesHandler *handler.PreferencesHandler,
 	financeDashboardHandler *handler.FinanceDashboardHandler,
+	trashHandler *handler.TrashHandler,
+	reconciliationHandler *handler.ReconciliationHandler,
+	exchangeRateHandler *handler.ExchangeRateHandler,
+	loanHandler *handler.LoanHandler,
+	tontineHandler *handler.TontineHandler,
 	logger logger.Logger,
 ) {
 	// Routes pour la documentation Swagger (publiques) - à la racine
@@ -40,6 +49,18 @@ func SetupRoutes(
 		// Routes pour les transactions (protégées)
 		SetupTransactionRoutes(r, transactionHandler, authMiddleware)
 
+		// Routes pour les transactions récurrentes (protégées)
+		SetupRecurringTransactionRoutes(r, recurringTransactionHandler, authMiddleware)
+
+		// Routes pour l'import de relevés bancaires (protégées)
+		SetupImportRoutes(r, importHandler, authMiddleware)
+
+		// Routes pour les pièces jointes des transactions (protégées)
+		SetupFileRoutes(r, attachmentHandler, authMiddleware)
+
+		// Routes pour les étiquettes de transactions (protégées)
+		SetupTagRoutes(r, tagHandler, authMiddleware)
+
 		// Routes pour les comptes (protégées)
 		SetupAccountRoutes(r, accountHandler, authMiddleware)
 
@@ -58,9 +79,23 @@ func SetupRoutes(
 		// Routes pour le tableau de bord financier (protégées)
 		SetupFinanceRoutes(r, financeDashboardHandler, authMiddleware)
 
+		// Routes pour la corbeille (protégées)
+		SetupTrashRoutes(r, trashHandler, authMiddleware)
+
+		// Routes pour le rapprochement des comptes (protégées)
+		SetupReconciliationRoutes(r, reconciliationHandler, authMiddleware)
+
+		// Routes pour les devises et taux de change (protégées)
+		SetupExchangeRateRoutes(r, exchangeRateHandler, authMiddleware)
+
+		// Routes pour les emprunts (protégées)
+		SetupLoanRoutes(r, loanHandler, authMiddleware)
+
+		// Routes pour les tontines (protégées)
+		SetupTontineRoutes(r, tontineHandler, authMiddleware)
+
 		// TODO: Ajouter d'autres routes selon les besoins
 		// SetupUserRoutes(r, userHandler, authMiddleware)
-		// SetupFileRoutes(r, fileHandler, authMiddleware)
 		// SetupHealthRoutes(r, healthHandler)
 	})
 }
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"backend/internal/handler"
+	"backend/pkg/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SetupTagRoutes configure les routes pour les étiquettes de transactions
+func SetupTagRoutes(r chi.Router, tagHandler *handler.TagHandler, authMiddleware *middleware.AuthMiddleware) {
+	// Groupe de routes pour les étiquettes (protégées par authentification)
+	r.Route("/tags", func(r chi.Router) {
+		// Appliquer l'authentification à toutes les routes
+		r.Use(authMiddleware.Authenticate)
+
+		// Routes pour la gestion des étiquettes
+		r.Post("/", tagHandler.CreateTag)       // POST /api/v1/tags
+		r.Get("/", tagHandler.GetTags)          // GET /api/v1/tags
+		r.Get("/{id}", tagHandler.GetTag)       // GET /api/v1/tags/{id}
+		r.Put("/{id}", tagHandler.UpdateTag)    // PUT /api/v1/tags/{id}
+		r.Delete("/{id}", tagHandler.DeleteTag) // DELETE /api/v1/tags/{id}
+
+		// Routes pour l'étiquetage en masse
+		r.Post("/apply", tagHandler.TagTransactions)    // POST /api/v1/tags/apply
+		r.Post("/remove", tagHandler.UntagTransactions) // POST /api/v1/tags/remove
+	})
+}
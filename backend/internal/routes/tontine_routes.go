@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"backend/internal/handler"
+	"backend/pkg/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SetupTontineRoutes configure les routes pour les tontines
+func SetupTontineRoutes(r chi.Router, tontineHandler *handler.TontineHandler, authMiddleware *middleware.AuthMiddleware) {
+	// Groupe de routes pour les tontines (protégées par authentification)
+	r.Route("/tontines", func(r chi.Router) {
+		// Appliquer l'authentification à toutes les routes
+		r.Use(authMiddleware.Authenticate)
+
+		// Routes de base CRUD
+		r.Post("/", tontineHandler.CreateTontine)       // POST /api/v1/tontines
+		r.Get("/", tontineHandler.GetTontines)          // GET /api/v1/tontines
+		r.Get("/{id}", tontineHandler.GetTontine)       // GET /api/v1/tontines/{id}
+		r.Delete("/{id}", tontineHandler.DeleteTontine) // DELETE /api/v1/tontines/{id}
+
+		// Rappels des cotisations en retard et à venir
+		r.Get("/reminders", tontineHandler.GetReminders) // GET /api/v1/tontines/reminders
+
+		// Cotisations et cagnotte de chaque tour
+		r.Post("/{id}/cycles/{number}/contributions", tontineHandler.RecordContribution) // POST /api/v1/tontines/{id}/cycles/{number}/contributions
+		r.Post("/{id}/cycles/{number}/payout", tontineHandler.RecordPayout)              // POST /api/v1/tontines/{id}/cycles/{number}/payout
+	})
+}
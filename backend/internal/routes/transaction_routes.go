@@ -15,11 +15,21 @@ func SetupTransactionRoutes(r chi.Router, transactionHandler *handler.Transactio
 		r.Use(authMiddleware.Authenticate)
 
 		// Routes pour la gestion des transactions
-		r.Post("/", transactionHandler.CreateTransaction)       // POST /api/v1/transactions
-		r.Get("/", transactionHandler.GetTransactions)          // GET /api/v1/transactions
-		r.Get("/stats", transactionHandler.GetTransactionStats) // GET /api/v1/transactions/stats
-		r.Get("/{id}", transactionHandler.GetTransaction)       // GET /api/v1/transactions/{id}
-		r.Put("/{id}", transactionHandler.UpdateTransaction)    // PUT /api/v1/transactions/{id}
-		r.Delete("/{id}", transactionHandler.DeleteTransaction) // DELETE /api/v1/transactions/{id}
+		r.Post("/", transactionHandler.CreateTransaction)                              // POST /api/v1/transactions
+		r.Get("/", transactionHandler.GetTransactions)                                 // GET /api/v1/transactions
+		r.Get("/stats", transactionHandler.GetTransactionStats)                        // GET /api/v1/transactions/stats
+		r.Get("/export", transactionHandler.ExportTransactions)                        // GET /api/v1/transactions/export
+		r.Get("/transfers", transactionHandler.GetTransfers)                           // GET /api/v1/transactions/transfers
+		r.Post("/parse-sms", transactionHandler.ParseSMS)                              // POST /api/v1/transactions/parse-sms
+		r.Get("/duplicates", transactionHandler.ScanDuplicates)                        // GET /api/v1/transactions/duplicates
+		r.Post("/duplicates/merge", transactionHandler.MergeDuplicates)                // POST /api/v1/transactions/duplicates/merge
+		r.Post("/bulk", transactionHandler.BulkTransactions)                           // POST /api/v1/transactions/bulk
+		r.Get("/{id}", transactionHandler.GetTransaction)                              // GET /api/v1/transactions/{id}
+		r.Put("/{id}", transactionHandler.UpdateTransaction)                           // PUT /api/v1/transactions/{id}
+		r.Delete("/{id}", transactionHandler.DeleteTransaction)                        // DELETE /api/v1/transactions/{id}
+		r.Put("/{id}/status", transactionHandler.SetTransactionStatus)                 // PUT /api/v1/transactions/{id}/status
+		r.Get("/{id}/refunds", transactionHandler.GetRefunds)                          // GET /api/v1/transactions/{id}/refunds
+		r.Get("/{id}/history", transactionHandler.GetTransactionHistory)               // GET /api/v1/transactions/{id}/history
+		r.Post("/{id}/history/{version}/revert", transactionHandler.RevertTransaction) // POST /api/v1/transactions/{id}/history/{version}/revert
 	})
 }
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"backend/internal/handler"
+	"backend/pkg/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SetupTrashRoutes configure les routes pour la corbeille
+func SetupTrashRoutes(r chi.Router, trashHandler *handler.TrashHandler, authMiddleware *middleware.AuthMiddleware) {
+	// Groupe de routes pour la corbeille (protégées par authentification)
+	r.Route("/trash", func(r chi.Router) {
+		// Appliquer l'authentification à toutes les routes
+		r.Use(authMiddleware.Authenticate)
+
+		r.Get("/", trashHandler.GetTrash)                        // GET /api/v1/trash
+		r.Post("/{type}/{id}/restore", trashHandler.RestoreItem) // POST /api/v1/trash/{type}/{id}/restore
+	})
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/internal/domaine/repository"
+	"backend/pkg/logger"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountClosureService gère la clôture des comptes : le solde restant est transféré vers un autre compte, puis le
+// compte est archivé. Un compte clôturé garde son historique dans les rapports mais disparaît des listes par défaut
+// et ne reçoit plus de nouvelles transactions ; il peut être rouvert.
+type AccountClosureService struct {
+	accountRepo        repository.AccountRepository
+	transactionRepo    repository.TransactionRepository
+	recurringRepo      repository.RecurringTransactionRepository
+	transactionService *TransactionService
+	txManager          repository.TxManager
+	logger             logger.Logger
+}
+
+// NewAccountClosureService crée une nouvelle instance de AccountClosureService
+func NewAccountClosureService(
+	accountRepo repository.AccountRepository,
+	transactionRepo repository.TransactionRepository,
+	recurringRepo repository.RecurringTransactionRepository,
+	transactionService *TransactionService,
+	txManager repository.TxManager,
+	logger logger.Logger,
+) *AccountClosureService {
+	return &AccountClosureService{
+		accountRepo:        accountRepo,
+		transactionRepo:    transactionRepo,
+		recurringRepo:      recurringRepo,
+		transactionService: transactionService,
+		txManager:          txManager,
+		logger:             logger,
+	}
+}
+
+// CloseAccount clôture un compte. Un solde non nul est soldé par un transfert vers le compte de destination (ou
+// depuis lui si le solde est négatif) ; les transactions récurrentes actives du compte sont suspendues. Un compte
+// avec des transactions en attente ou planifiées ne peut pas être clôturé, ni à une date future.
+func (s *AccountClosureService) CloseAccount(ctx context.Context, userID, accountID uuid.UUID, req entity.CloseAccountRequest) (*entity.Account, error) {
+	date := truncateToDay(time.Now())
+	if req.Date != nil {
+		date = truncateToDay(*req.Date)
+	}
+	// Un transfert daté dans le futur serait planifié et ne solderait pas le compte
+	if isFutureDate(date, time.Now()) {
+		return nil, fmt.Errorf("%w: la date de clôture ne peut pas être dans le futur", entity.ErrInvalidAccountClosure)
+	}
+
+	var paused int
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		account, err := s.lockAccount(ctx, userID, accountID)
+		if err != nil {
+			return err
+		}
+		if account.ArchivedAt != nil {
+			return entity.ErrAccountArchived
+		}
+		if !account.PendingBalance.IsZero() {
+			return fmt.Errorf("%w: le compte a des transactions en attente", entity.ErrInvalidAccountClosure)
+		}
+		scheduled, err := s.transactionRepo.Count(ctx, userID, &entity.TransactionFilter{AccountID: &accountID, Status: transactionStatusScheduled})
+		if err != nil {
+			return err
+		}
+		if scheduled > 0 {
+			return fmt.Errorf("%w: le compte a %d transaction(s) planifiée(s)", entity.ErrInvalidAccountClosure, scheduled)
+		}
+
+		if !account.Balance.IsZero() {
+			if err := s.transferBalance(ctx, userID, account, req.TransferToAccountID, date); err != nil {
+				return err
+			}
+			// Relire le compte soldé par le transfert
+			if account, err = s.accountRepo.GetByIDForUpdate(ctx, accountID); err != nil {
+				return err
+			}
+		}
+
+		if paused, err = s.pauseRecurring(ctx, userID, accountID); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		account.ArchivedAt = &now
+		account.UpdatedAt = now
+		return s.accountRepo.Update(ctx, account)
+	})
+	if err != nil {
+		s.logger.Error("Erreur clôture compte", logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Compte clôturé avec succès",
+		logger.String("account_id", accountID.String()),
+		logger.String("user_id", userID.String()),
+		logger.Int("paused_recurring", paused),
+	)
+
+	return s.accountRepo.GetByID(ctx, accountID)
+}
+
+// ReopenAccount rouvre un compte clôturé. Les transactions récurrentes suspendues à la clôture restent suspendues.
+func (s *AccountClosureService) ReopenAccount(ctx context.Context, userID, accountID uuid.UUID) (*entity.Account, error) {
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		account, err := s.lockAccount(ctx, userID, accountID)
+		if err != nil {
+			return err
+		}
+		if account.ArchivedAt == nil {
+			return entity.ErrAccountNotArchived
+		}
+
+		account.ArchivedAt = nil
+		account.UpdatedAt = time.Now()
+		return s.accountRepo.Update(ctx, account)
+	})
+	if err != nil {
+		s.logger.Error("Erreur réouverture compte", logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Compte rouvert avec succès",
+		logger.String("account_id", accountID.String()),
+		logger.String("user_id", userID.String()),
+	)
+
+	return s.accountRepo.GetByID(ctx, accountID)
+}
+
+// transferBalance solde un compte par un transfert avec le compte de destination de la clôture
+func (s *AccountClosureService) transferBalance(ctx context.Context, userID uuid.UUID, account *entity.Account, targetID *uuid.UUID, date time.Time) error {
+	if targetID == nil {
+		return fmt.Errorf("%w: un compte de destination est requis pour transférer le solde de %s", entity.ErrInvalidAccountClosure, account.Balance)
+	}
+	if *targetID == account.ID {
+		return fmt.Errorf("%w: le compte de destination doit être différent du compte clôturé", entity.ErrInvalidAccountClosure)
+	}
+	target, err := s.accountRepo.GetByID(ctx, *targetID)
+	if err != nil || target.UserID != userID {
+		return fmt.Errorf("%w: compte de destination non trouvé", entity.ErrInvalidAccountClosure)
+	}
+	if target.Currency != account.Currency {
+		return fmt.Errorf("%w: compte de destination en %s, compte clôturé en %s", entity.ErrCurrencyMismatch, target.Currency, account.Currency)
+	}
+
+	from, to, amount := account.ID, target.ID, account.Balance
+	if amount.IsNegative() {
+		from, to, amount = target.ID, account.ID, amount.Neg()
+	}
+	_, err = s.transactionService.CreateTransaction(ctx, userID, entity.CreateTransactionRequest{
+		AccountID:      &from,
+		ToAccountID:    &to,
+		Type:           "transfer",
+		Amount:         entity.DecimalOf(amount),
+		Description:    fmt.Sprintf("Clôture %s", account.Name),
+		Date:           date,
+		AllowDuplicate: true,
+	})
+	return err
+}
+
+// pauseRecurring suspend les transactions récurrentes actives d'un compte et retourne leur nombre
+func (s *AccountClosureService) pauseRecurring(ctx context.Context, userID, accountID uuid.UUID) (int, error) {
+	recurrings, err := s.recurringRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	paused := 0
+	for _, recurring := range recurrings {
+		if recurring.AccountID != accountID || recurring.Status != "active" {
+			continue
+		}
+		recurring.Status = "paused"
+		recurring.UpdatedAt = time.Now()
+		if err := s.recurringRepo.Update(ctx, recurring); err != nil {
+			return paused, err
+		}
+		paused++
+	}
+	return paused, nil
+}
+
+// lockAccount verrouille un compte et vérifie qu'il appartient à l'utilisateur
+func (s *AccountClosureService) lockAccount(ctx context.Context, userID, accountID uuid.UUID) (*entity.Account, error) {
+	account, err := s.accountRepo.GetByIDForUpdate(ctx, accountID)
+	if err != nil || account.UserID != userID {
+		return nil, entity.ErrAccountNotFound
+	}
+	return account, nil
+}
@@ -17,19 +17,35 @@ type AccountService struct {
 	logger          logger.Logger
 	transactionRepo repository.TransactionRepository
 	savingGoalRepo  repository.SavingGoalRepository
+	historyRepo     repository.AccountBalanceHistoryRepository
+	txManager       repository.TxManager
 }
 
+// BalanceHistoryQuery représente les paramètres de requête de l'historique de solde d'un compte
+type BalanceHistoryQuery struct {
+	From     string // date de début (YYYY-MM-DD), déduite de l'intervalle si vide
+	To       string // date de fin incluse (YYYY-MM-DD), aujourd'hui par défaut
+	Interval string // day (défaut), week, month
+}
+
+// maxBalanceHistoryPoints borne le nombre de points d'un historique de solde
+const maxBalanceHistoryPoints = 400
+
 // NewAccountService crée une nouvelle instance de AccountService
 func NewAccountService(
 	accountRepo repository.AccountRepository,
 	transactionRepo repository.TransactionRepository,
 	savingGoalRepo repository.SavingGoalRepository,
+	historyRepo repository.AccountBalanceHistoryRepository,
+	txManager repository.TxManager,
 	logger logger.Logger,
 ) *AccountService {
 	return &AccountService{
 		accountRepo:     accountRepo,
 		transactionRepo: transactionRepo,
 		savingGoalRepo:  savingGoalRepo,
+		historyRepo:     historyRepo,
+		txManager:       txManager,
 		logger:          logger,
 	}
 }
@@ -49,18 +65,28 @@ func (s *AccountService) CreateAccount(ctx context.Context, userID uuid.UUID, re
 		return nil, fmt.Errorf("la devise doit avoir 3 caractères")
 	}
 
+	balance := entity.NewMoney(0, req.Currency)
+	if req.Balance != "" {
+		var err error
+		if balance, err = req.Balance.Money(req.Currency); err != nil {
+			return nil, err
+		}
+	}
+
 	// Création du compte
 	account := &entity.Account{
-		UserID:        userID,
-		Name:          req.Name,
-		Type:          req.Type,
-		Icon:          req.Icon,
-		AccountNumber: req.AccountNumber,
-		Color:         req.Color,
-		Balance:       req.Balance,
-		Currency:      req.Currency,
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
+		UserID:           userID,
+		Name:             req.Name,
+		Type:             req.Type,
+		Icon:             req.Icon,
+		AccountNumber:    req.AccountNumber,
+		Color:            req.Color,
+		Balance:          balance,
+		PendingBalance:   entity.NewMoney(0, req.Currency),
+		AvailableBalance: balance,
+		Currency:         req.Currency,
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
 	}
 
 	if err := s.accountRepo.Create(ctx, account); err != nil {
@@ -98,8 +124,8 @@ func (s *AccountService) GetAccount(ctx context.Context, userID uuid.UUID, accou
 	return account, nil
 }
 
-// GetAccounts récupère tous les comptes d'un utilisateur
-func (s *AccountService) GetAccounts(ctx context.Context, userID uuid.UUID, page, limit int) ([]*entity.Account, int64, error) {
+// GetAccounts récupère les comptes d'un utilisateur ; les comptes clôturés ne sont inclus qu'à la demande
+func (s *AccountService) GetAccounts(ctx context.Context, userID uuid.UUID, page, limit int, includeArchived bool) ([]*entity.Account, int64, error) {
 	// Pour l'instant, récupérer tous les comptes de l'utilisateur
 	// TODO: Implémenter la pagination quand le repository sera créé
 	accounts, err := s.accountRepo.GetByUserID(ctx, userID)
@@ -107,6 +133,9 @@ func (s *AccountService) GetAccounts(ctx context.Context, userID uuid.UUID, page
 		s.logger.Error("Erreur récupération comptes", logger.Error(err))
 		return nil, 0, fmt.Errorf("erreur récupération comptes: %w", err)
 	}
+	if !includeArchived {
+		accounts = activeAccounts(accounts)
+	}
 
 	// Calculer le total
 	total := int64(len(accounts))
@@ -124,56 +153,93 @@ func (s *AccountService) GetAccounts(ctx context.Context, userID uuid.UUID, page
 	return accounts[start:end], total, nil
 }
 
-// UpdateAccount met à jour un compte
+// UpdateAccount met à jour un compte. Le compte est relu et verrouillé dans la transaction d'écriture, pour ne pas
+// écraser les soldes modifiés entre-temps par une transaction concurrente.
 func (s *AccountService) UpdateAccount(ctx context.Context, userID uuid.UUID, accountID uuid.UUID, req entity.UpdateAccountRequest) (*entity.Account, error) {
-	// Récupérer le compte existant
-	account, err := s.accountRepo.GetByID(ctx, accountID)
-	if err != nil {
-		s.logger.Error("Erreur récupération compte pour mise à jour", logger.Error(err))
-		return nil, fmt.Errorf("compte non trouvé")
+	if req.Type != nil && *req.Type != "checking" && *req.Type != "savings" && *req.Type != "mobile_money" {
+		return nil, fmt.Errorf("le type doit être 'checking', 'savings' ou 'mobile_money'")
 	}
 
-	// Vérifier que le compte appartient à l'utilisateur
-	if account.UserID != userID {
-		s.logger.Warn("Tentative de mise à jour non autorisée d'un compte",
-			logger.String("user_id", userID.String()),
-			logger.String("account_id", accountID.String()),
-		)
-		return nil, fmt.Errorf("accès non autorisé")
-	}
+	var account *entity.Account
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Récupérer et verrouiller le compte existant
+		var err error
+		account, err = s.accountRepo.GetByIDForUpdate(ctx, accountID)
+		if err != nil {
+			s.logger.Error("Erreur récupération compte pour mise à jour", logger.Error(err))
+			return fmt.Errorf("compte non trouvé")
+		}
 
-	// Mettre à jour les champs
-	if req.Name != nil {
-		account.Name = *req.Name
-	}
+		// Vérifier que le compte appartient à l'utilisateur
+		if account.UserID != userID {
+			s.logger.Warn("Tentative de mise à jour non autorisée d'un compte",
+				logger.String("user_id", userID.String()),
+				logger.String("account_id", accountID.String()),
+			)
+			return fmt.Errorf("accès non autorisé")
+		}
 
-	if req.Type != nil {
-		if *req.Type != "checking" && *req.Type != "savings" && *req.Type != "mobile_money" {
-			return nil, fmt.Errorf("le type doit être 'checking', 'savings' ou 'mobile_money'")
+		// Mettre à jour les champs
+		if req.Name != nil {
+			account.Name = *req.Name
+		}
+		if req.Type != nil {
+			account.Type = *req.Type
 		}
-		account.Type = *req.Type
-	}
 
-	if req.Balance != nil {
-		if *req.Balance < 0 {
-			return nil, fmt.Errorf("le solde ne peut pas être négatif")
+		previousBalance := account.Balance
+		currencyChanged := false
+		if req.Currency != nil && *req.Currency != account.Currency {
+			if len(*req.Currency) != 3 {
+				return fmt.Errorf("la devise doit avoir 3 caractères")
+			}
+			// Les montants des transactions sont exprimés dans la devise du compte
+			transactions, err := s.transactionRepo.GetAllTransactionsByUserIDAndAccountID(ctx, userID, accountID)
+			if err != nil {
+				s.logger.Error("Erreur récupération transactions du compte", logger.Error(err))
+				return fmt.Errorf("erreur récupération transactions du compte: %w", err)
+			}
+			if len(transactions) > 0 {
+				return fmt.Errorf("la devise d'un compte ayant des transactions ne peut pas être modifiée")
+			}
+			account.Currency = *req.Currency
+			if account.Balance, err = entity.DecimalOf(account.Balance).Money(account.Currency); err != nil {
+				return err
+			}
+			account.PendingBalance = entity.NewMoney(0, account.Currency)
+			currencyChanged = true
 		}
-		account.Balance = *req.Balance
-	}
 
-	if req.Currency != nil {
-		if len(*req.Currency) != 3 {
-			return nil, fmt.Errorf("la devise doit avoir 3 caractères")
+		if req.Balance != nil {
+			balance, err := req.Balance.Money(account.Currency)
+			if err != nil {
+				return err
+			}
+			if balance.IsNegative() {
+				return fmt.Errorf("le solde ne peut pas être négatif")
+			}
+			account.Balance = balance
 		}
-		account.Currency = *req.Currency
-	}
 
-	account.UpdatedAt = time.Now()
+		account.AvailableBalance = account.Balance.Add(account.PendingBalance)
+		account.UpdatedAt = time.Now()
 
-	// Sauvegarder les modifications
-	if err := s.accountRepo.Update(ctx, account); err != nil {
+		// Sauvegarder les modifications. Une correction du solde est datée du jour dans l'historique ; un changement
+		// de devise, permis seulement sans transaction, efface l'historique exprimé dans l'ancienne devise.
+		if err := s.accountRepo.Update(ctx, account); err != nil {
+			return fmt.Errorf("erreur mise à jour compte: %w", err)
+		}
+		if currencyChanged {
+			return s.historyRepo.DeleteByAccountID(ctx, account.ID)
+		}
+		if delta := account.Balance.Sub(previousBalance); !delta.IsZero() {
+			return s.recordBalanceChange(ctx, account, time.Now(), delta)
+		}
+		return nil
+	})
+	if err != nil {
 		s.logger.Error("Erreur mise à jour compte", logger.Error(err))
-		return nil, fmt.Errorf("erreur mise à jour compte: %w", err)
+		return nil, err
 	}
 
 	s.logger.Info("Compte mis à jour avec succès",
@@ -184,45 +250,111 @@ func (s *AccountService) UpdateAccount(ctx context.Context, userID uuid.UUID, ac
 	return account, nil
 }
 
-// DeleteAccount supprime un compte
-func (s *AccountService) DeleteAccount(ctx context.Context, userID uuid.UUID, accountID uuid.UUID) error {
-	// Récupérer le compte existant
-	account, err := s.accountRepo.GetByID(ctx, accountID)
-	if err != nil {
-		s.logger.Error("Erreur récupération compte pour suppression", logger.Error(err))
-		return fmt.Errorf("compte non trouvé")
-	}
+// DeleteAccount met un compte à la corbeille avec ses transactions et objectifs d'épargne actifs.
+// Les soldes ne sont pas modifiés : restaurer le compte rétablit l'état d'avant la suppression. Un compte ayant des
+// transactions, qui disparaîtraient avec lui à la purge de la corbeille, n'est supprimé que si force est vrai ;
+// sinon il doit être clôturé.
+func (s *AccountService) DeleteAccount(ctx context.Context, userID uuid.UUID, accountID uuid.UUID, force bool) error {
+	var trashedTransactions int
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Récupérer et verrouiller le compte existant
+		account, err := s.accountRepo.GetByIDForUpdate(ctx, accountID)
+		if err != nil {
+			s.logger.Error("Erreur récupération compte pour suppression", logger.Error(err))
+			return fmt.Errorf("compte non trouvé")
+		}
 
-	// Vérifier que le compte appartient à l'utilisateur
-	if account.UserID != userID {
-		s.logger.Warn("Tentative de suppression non autorisée d'un compte",
-			logger.String("user_id", userID.String()),
-			logger.String("account_id", accountID.String()),
-		)
-		return fmt.Errorf("accès non autorisé")
-	}
+		// Vérifier que le compte appartient à l'utilisateur
+		if account.UserID != userID {
+			s.logger.Warn("Tentative de suppression non autorisée d'un compte",
+				logger.String("user_id", userID.String()),
+				logger.String("account_id", accountID.String()),
+			)
+			return fmt.Errorf("accès non autorisé")
+		}
+
+		if !force {
+			count, err := s.transactionRepo.Count(ctx, userID, &entity.TransactionFilter{AccountID: &accountID})
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return fmt.Errorf("%w: %d transaction(s), clôturer le compte pour conserver son historique", entity.ErrAccountHasTransactions, count)
+			}
+		}
+
+		now := time.Now()
+		trashedTransactions, err = s.transactionRepo.TrashByAccountID(ctx, accountID, now)
+		if err != nil {
+			return err
+		}
+		if _, err := s.savingGoalRepo.TrashByAccountID(ctx, accountID, now); err != nil {
+			return err
+		}
 
-	// Supprimer le compte
-	if err := s.accountRepo.Delete(ctx, accountID); err != nil {
+		// Mettre le compte à la corbeille
+		if err := s.accountRepo.Delete(ctx, accountID); err != nil {
+			return fmt.Errorf("erreur suppression compte: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
 		s.logger.Error("Erreur suppression compte", logger.Error(err))
-		return fmt.Errorf("erreur suppression compte: %w", err)
+		return err
 	}
 
 	s.logger.Info("Compte supprimé avec succès",
 		logger.String("account_id", accountID.String()),
 		logger.String("user_id", userID.String()),
+		logger.Int("transactions", trashedTransactions),
 	)
 
 	return nil
 }
 
-// GetAccountBalance récupère le solde d'un compte
-func (s *AccountService) GetAccountBalance(ctx context.Context, userID uuid.UUID, accountID uuid.UUID) (float64, error) {
+// RestoreAccount sort un compte de la corbeille avec les transactions et objectifs d'épargne supprimés avec lui
+func (s *AccountService) RestoreAccount(ctx context.Context, userID uuid.UUID, accountID uuid.UUID) (*entity.Account, error) {
+	var restoredTransactions int
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		account, err := s.accountRepo.GetDeletedByIDForUpdate(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		if account.UserID != userID {
+			return fmt.Errorf("%w: compte %s", entity.ErrTrashItemNotFound, accountID)
+		}
+
+		if err := s.accountRepo.Restore(ctx, accountID); err != nil {
+			return err
+		}
+		restoredTransactions, err = s.transactionRepo.RestoreByAccountID(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		_, err = s.savingGoalRepo.RestoreByAccountID(ctx, accountID)
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Erreur restauration compte", logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Compte restauré avec succès",
+		logger.String("account_id", accountID.String()),
+		logger.String("user_id", userID.String()),
+		logger.Int("transactions", restoredTransactions),
+	)
+
+	return s.accountRepo.GetByID(ctx, accountID)
+}
+
+// GetAccountBalance récupère les soldes d'un compte : courant, en attente et disponible
+func (s *AccountService) GetAccountBalance(ctx context.Context, userID uuid.UUID, accountID uuid.UUID) (*entity.AccountBalanceResponse, error) {
 	// Récupérer le compte
 	account, err := s.accountRepo.GetByID(ctx, accountID)
 	if err != nil {
 		s.logger.Error("Erreur récupération compte pour solde", logger.Error(err))
-		return 0, fmt.Errorf("compte non trouvé")
+		return nil, fmt.Errorf("compte non trouvé")
 	}
 
 	// Vérifier que le compte appartient à l'utilisateur
@@ -231,74 +363,179 @@ func (s *AccountService) GetAccountBalance(ctx context.Context, userID uuid.UUID
 			logger.String("user_id", userID.String()),
 			logger.String("account_id", accountID.String()),
 		)
-		return 0, fmt.Errorf("accès non autorisé")
+		return nil, fmt.Errorf("accès non autorisé")
 	}
 
-	return account.Balance, nil
+	return &entity.AccountBalanceResponse{
+		AccountID: account.ID,
+		Balance:   account.Balance,
+		Pending:   account.PendingBalance,
+		Available: account.AvailableBalance,
+	}, nil
 }
 
-// GetAccountsByUserID récupère tous les comptes d'un utilisateur
-func (s *AccountService) GetAccountsByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Account, error) {
-	accounts, err := s.accountRepo.GetByUserID(ctx, userID)
-	if err != nil {
-		s.logger.Error("Erreur récupération comptes utilisateur", logger.Error(err))
-		return nil, fmt.Errorf("erreur récupération comptes: %w", err)
-	}
-
-	return accounts, nil
+// recordBalanceChange reporte dans l'historique de solde d'un compte une variation de son solde courant datée du
+// jour donné. Le compte porte déjà la variation. Doit être appelé à l'intérieur de TxManager.WithinTransaction.
+func (s *AccountService) recordBalanceChange(ctx context.Context, account *entity.Account, date time.Time, delta entity.Money) error {
+	return s.historyRepo.ApplyChange(ctx, account.ID, truncateToDay(date), delta, account.Balance.Sub(delta))
 }
 
-// check and update account balance
-func (s *AccountService) CheckAndUpdateAccountBalance(ctx context.Context, userID uuid.UUID, accountID uuid.UUID) (*entity.Account, error) {
+// GetBalanceHistory retrace le solde courant d'un compte sur une période : un point par jour, par semaine (du
+// lundi) ou par mois civil, portant le solde en fin d'intervalle et sa variation sur l'intervalle
+func (s *AccountService) GetBalanceHistory(ctx context.Context, userID, accountID uuid.UUID, query BalanceHistoryQuery) (*entity.AccountBalanceHistoryResponse, error) {
+	account, err := s.GetAccount(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
 
-	account, err := s.accountRepo.GetByID(ctx, accountID)
+	interval, from, to, err := balanceHistoryRange(query, time.Now())
 	if err != nil {
-		s.logger.Error("Erreur récupération compte", logger.Error(err))
-		return nil, fmt.Errorf("erreur récupération compte: %w", err)
+		return nil, err
 	}
 
-	transaction, err := s.transactionRepo.GetAllTransactionsByUserIDAndAccountID(ctx, userID, accountID)
+	opening, err := s.historyRepo.GetBalanceAt(ctx, accountID, from.AddDate(0, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := s.historyRepo.GetRange(ctx, accountID, from, to)
 	if err != nil {
-		s.logger.Error("Erreur récupération transactions", logger.Error(err))
-		return nil, fmt.Errorf("erreur récupération transactions: %w", err)
+		return nil, err
 	}
-	if len(transaction) == 0 {
-		s.logger.Info("Aucune transaction trouvée", logger.String("account_id", accountID.String()))
-		return account, nil
+
+	history := &entity.AccountBalanceHistoryResponse{
+		AccountID:      account.ID,
+		Currency:       account.Currency,
+		Interval:       interval,
+		From:           from,
+		To:             to,
+		OpeningBalance: opening,
+		Points:         make([]*entity.BalanceHistoryPoint, 0),
 	}
 
-	savingGoal, err := s.savingGoalRepo.GetAllSavingGoalsByUserIDAndAccountID(ctx, userID, accountID)
-	if err != nil {
-		s.logger.Error("Erreur récupération transactions", logger.Error(err))
-		return nil, fmt.Errorf("erreur récupération transactions: %w", err)
-	}
-	// sum all transactions amount
-	totalAmount := 0.0
-	for _, t := range transaction {
-		if t.Type == "income" {
-			totalAmount += t.Amount
-		} else {
-			totalAmount -= t.Amount
+	balance := opening
+	next := 0
+	for start := from; !start.After(to); {
+		end := intervalEnd(start, interval)
+		if end.After(to) {
+			end = to
+		}
+		previous := balance
+		for next < len(snapshots) && !truncateToDay(snapshots[next].Date).After(end) {
+			balance = snapshots[next].Balance
+			next++
 		}
+		history.Points = append(history.Points, &entity.BalanceHistoryPoint{
+			Start:   start,
+			End:     end,
+			Balance: balance,
+			Change:  balance.Sub(previous),
+		})
+		start = end.AddDate(0, 0, 1)
 	}
-	// sum all saving goals amount
-	for _, v := range savingGoal {
-		transactionBySavingGoal, err := s.transactionRepo.GetAllTransactionsBySavingGoalID(ctx, userID, v.ID)
-		for _, t := range transactionBySavingGoal {
-			totalAmount += t.Amount
+
+	return history, nil
+}
+
+// GetBalancesAsOf récupère le solde courant de chaque compte de l'utilisateur à la fin d'une journée
+// (YYYY-MM-DD, aujourd'hui par défaut)
+func (s *AccountService) GetBalancesAsOf(ctx context.Context, userID uuid.UUID, date string) (*entity.AccountBalancesAsOfResponse, error) {
+	day := truncateToDay(time.Now())
+	if date != "" {
+		parsed, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return nil, fmt.Errorf("%w: date invalide", entity.ErrInvalidBalanceHistoryQuery)
 		}
+		day = parsed
+	}
+
+	balances, err := s.historyRepo.GetBalancesAt(ctx, userID, day)
+	if err != nil {
+		s.logger.Error("Erreur calcul des soldes à date", logger.Error(err))
+		return nil, err
+	}
+
+	return &entity.AccountBalancesAsOfResponse{Date: day, Accounts: balances}, nil
+}
+
+// balanceHistoryRange valide l'intervalle et la période d'un historique de solde. Sans date de début, la période
+// couvre les 30 derniers jours, les 12 dernières semaines ou les 12 derniers mois selon l'intervalle.
+func balanceHistoryRange(query BalanceHistoryQuery, now time.Time) (string, time.Time, time.Time, error) {
+	interval := query.Interval
+	if interval == "" {
+		interval = "day"
+	}
+
+	to := truncateToDay(now)
+	if query.To != "" {
+		parsed, err := time.Parse("2006-01-02", query.To)
 		if err != nil {
-			s.logger.Error("Erreur récupération transactions par objectif d'épargne", logger.Error(err))
-			return nil, fmt.Errorf("erreur récupération transactions par objectif d'épargne: %w", err)
+			return "", time.Time{}, time.Time{}, fmt.Errorf("%w: date de fin invalide", entity.ErrInvalidBalanceHistoryQuery)
 		}
+		to = parsed
 	}
-	// update account balance
-	account.Balance = totalAmount
-	if err := s.accountRepo.Update(ctx, account); err != nil {
-		s.logger.Error("Erreur mise à jour compte", logger.Error(err))
-		return nil, fmt.Errorf("erreur mise à jour compte: %w", err)
+
+	var from time.Time
+	switch interval {
+	case "day":
+		from = to.AddDate(0, 0, -29)
+	case "week":
+		from = to.AddDate(0, 0, -7*12+1)
+	case "month":
+		from = to.AddDate(0, -12, 1)
+	default:
+		return "", time.Time{}, time.Time{}, fmt.Errorf("%w: intervalle non supporté: %s", entity.ErrInvalidBalanceHistoryQuery, interval)
+	}
+	if query.From != "" {
+		parsed, err := time.Parse("2006-01-02", query.From)
+		if err != nil {
+			return "", time.Time{}, time.Time{}, fmt.Errorf("%w: date de début invalide", entity.ErrInvalidBalanceHistoryQuery)
+		}
+		from = parsed
+	}
+	if to.Before(from) {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("%w: la date de fin précède la date de début", entity.ErrInvalidBalanceHistoryQuery)
 	}
 
-	return account, nil
+	points := 0
+	for start := from; !start.After(to) && points <= maxBalanceHistoryPoints; start = intervalEnd(start, interval).AddDate(0, 0, 1) {
+		points++
+	}
+	if points > maxBalanceHistoryPoints {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("%w: période trop longue pour un historique par %s", entity.ErrInvalidBalanceHistoryQuery, interval)
+	}
+	return interval, from, to, nil
+}
 
+// intervalEnd retourne le dernier jour de l'intervalle commençant à start : le jour même, le dimanche de sa
+// semaine ou le dernier jour de son mois
+func intervalEnd(start time.Time, interval string) time.Time {
+	switch interval {
+	case "week":
+		return start.AddDate(0, 0, 6-(int(start.Weekday())+6)%7)
+	case "month":
+		return time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, -1)
+	}
+	return start
+}
+
+// GetAccountsByUserID récupère tous les comptes d'un utilisateur
+func (s *AccountService) GetAccountsByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Account, error) {
+	accounts, err := s.accountRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("Erreur récupération comptes utilisateur", logger.Error(err))
+		return nil, fmt.Errorf("erreur récupération comptes: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// activeAccounts retourne les comptes non clôturés
+func activeAccounts(accounts []*entity.Account) []*entity.Account {
+	active := make([]*entity.Account, 0, len(accounts))
+	for _, account := range accounts {
+		if account.ArchivedAt == nil {
+			active = append(active, account)
+		}
+	}
+	return active
 }
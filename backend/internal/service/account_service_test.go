@@ -0,0 +1,77 @@
+package service
+
+import (
+	"backend/internal/domaine/entity"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBalanceHistoryRange(t *testing.T) {
+	now := time.Date(2025, time.March, 12, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		query        BalanceHistoryQuery
+		wantInterval string
+		from, to     time.Time
+	}{
+		{name: "30 derniers jours par défaut", wantInterval: "day", from: day(2025, time.February, 11), to: day(2025, time.March, 12)},
+		{name: "12 dernières semaines", query: BalanceHistoryQuery{Interval: "week"}, wantInterval: "week", from: day(2024, time.December, 19), to: day(2025, time.March, 12)},
+		{name: "12 derniers mois", query: BalanceHistoryQuery{Interval: "month"}, wantInterval: "month", from: day(2024, time.March, 13), to: day(2025, time.March, 12)},
+		{
+			name:         "dates explicites",
+			query:        BalanceHistoryQuery{From: "2024-01-15", To: "2024-06-30", Interval: "month"},
+			wantInterval: "month", from: day(2024, time.January, 15), to: day(2024, time.June, 30),
+		},
+		{name: "date de fin seule", query: BalanceHistoryQuery{To: "2025-01-31"}, wantInterval: "day", from: day(2025, time.January, 2), to: day(2025, time.January, 31)},
+		{name: "400 points au plus", query: BalanceHistoryQuery{From: "2024-02-07", To: "2025-03-12"}, wantInterval: "day", from: day(2024, time.February, 7), to: day(2025, time.March, 12)},
+	}
+
+	for _, tt := range tests {
+		interval, from, to, err := balanceHistoryRange(tt.query, now)
+		if err != nil {
+			t.Errorf("%s : balanceHistoryRange: %v", tt.name, err)
+			continue
+		}
+		if interval != tt.wantInterval || !from.Equal(tt.from) || !to.Equal(tt.to) {
+			t.Errorf("%s : balanceHistoryRange = %s, %s - %s, attendu %s, %s - %s", tt.name,
+				interval, from.Format("2006-01-02"), to.Format("2006-01-02"),
+				tt.wantInterval, tt.from.Format("2006-01-02"), tt.to.Format("2006-01-02"))
+		}
+	}
+
+	for name, query := range map[string]BalanceHistoryQuery{
+		"intervalle inconnu":     {Interval: "year"},
+		"date de début invalide": {From: "15/01/2025"},
+		"date de fin invalide":   {To: "2025-02-30"},
+		"période inversée":       {From: "2025-03-02", To: "2025-03-01"},
+		"plus de 400 points":     {From: "2024-02-06", To: "2025-03-12"},
+		"plus de 400 semaines":   {From: "2010-01-01", Interval: "week"},
+	} {
+		if _, _, _, err := balanceHistoryRange(query, now); !errors.Is(err, entity.ErrInvalidBalanceHistoryQuery) {
+			t.Errorf("%s : erreur = %v, attendu %v", name, err, entity.ErrInvalidBalanceHistoryQuery)
+		}
+	}
+}
+
+func TestIntervalEnd(t *testing.T) {
+	tests := []struct {
+		start    time.Time
+		interval string
+		want     time.Time
+	}{
+		{start: day(2025, time.March, 12), interval: "day", want: day(2025, time.March, 12)},
+		{start: day(2025, time.March, 12), interval: "week", want: day(2025, time.March, 16)},
+		{start: day(2025, time.March, 10), interval: "week", want: day(2025, time.March, 16)},
+		{start: day(2025, time.March, 16), interval: "week", want: day(2025, time.March, 16)},
+		{start: day(2025, time.February, 11), interval: "month", want: day(2025, time.February, 28)},
+		{start: day(2024, time.February, 1), interval: "month", want: day(2024, time.February, 29)},
+		{start: day(2024, time.December, 31), interval: "month", want: day(2024, time.December, 31)},
+	}
+
+	for _, tt := range tests {
+		if got := intervalEnd(tt.start, tt.interval); !got.Equal(tt.want) {
+			t.Errorf("intervalEnd(%s, %s) = %s, attendu %s", tt.start.Format("2006-01-02"), tt.interval, got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/internal/domaine/repository"
+	"backend/internal/infra/storage"
+	"backend/pkg/logger"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxAttachmentsPerTransaction borne le nombre de pièces jointes d'une transaction
+const maxAttachmentsPerTransaction = 10
+
+// attachmentExtensions associe les types de contenu acceptés (détectés sur le contenu du fichier,
+// pas sur l'extension déclarée) à l'extension du fichier stocké
+var attachmentExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+// AttachmentService gère les pièces jointes (reçus, factures) des transactions
+type AttachmentService struct {
+	attachmentRepo  repository.AttachmentRepository
+	transactionRepo repository.TransactionRepository
+	fileStorage     storage.Storage
+	maxFileSize     int64
+	logger          logger.Logger
+}
+
+// NewAttachmentService crée une nouvelle instance de AttachmentService
+func NewAttachmentService(
+	attachmentRepo repository.AttachmentRepository,
+	transactionRepo repository.TransactionRepository,
+	fileStorage storage.Storage,
+	maxFileSize int64,
+	logger logger.Logger,
+) *AttachmentService {
+	return &AttachmentService{
+		attachmentRepo:  attachmentRepo,
+		transactionRepo: transactionRepo,
+		fileStorage:     fileStorage,
+		maxFileSize:     maxFileSize,
+		logger:          logger,
+	}
+}
+
+// MaxFileSize retourne la taille maximale d'une pièce jointe, en octets
+func (s *AttachmentService) MaxFileSize() int64 {
+	return s.maxFileSize
+}
+
+// UploadAttachment ajoute une pièce jointe à une transaction. Le type est détecté sur le contenu
+// (images JPEG, PNG, GIF, WebP et PDF acceptés).
+func (s *AttachmentService) UploadAttachment(ctx context.Context, userID, transactionID uuid.UUID, fileName string, content io.Reader) (*entity.TransactionAttachment, error) {
+	if err := s.checkTransaction(ctx, userID, transactionID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.attachmentRepo.GetByTransactionID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxAttachmentsPerTransaction {
+		return nil, fmt.Errorf("%w: %d pièces jointes maximum", entity.ErrTooManyAttachments, maxAttachmentsPerTransaction)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(content, s.maxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("erreur lecture du fichier: %w", err)
+	}
+	if int64(len(data)) > s.maxFileSize {
+		return nil, fmt.Errorf("%w: %d Mo maximum", entity.ErrFileTooLarge, s.maxFileSize>>20)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: fichier vide", entity.ErrInvalidFileType)
+	}
+
+	contentType := http.DetectContentType(data)
+	extension, ok := attachmentExtensions[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s (images JPEG, PNG, GIF, WebP ou PDF acceptés)", entity.ErrInvalidFileType, contentType)
+	}
+
+	attachment := &entity.TransactionAttachment{
+		ID:            uuid.New(),
+		UserID:        userID,
+		TransactionID: transactionID,
+		FileName:      attachmentFileName(fileName, extension),
+		ContentType:   contentType,
+		Size:          int64(len(data)),
+		CreatedAt:     time.Now(),
+	}
+	attachment.StorageKey = fmt.Sprintf("attachments/%s/%s/%s%s", userID, transactionID, attachment.ID, extension)
+
+	if err := s.fileStorage.Put(ctx, attachment.StorageKey, bytes.NewReader(data), attachment.Size, contentType); err != nil {
+		return nil, fmt.Errorf("%w: %v", entity.ErrFileUploadFailed, err)
+	}
+	if err := s.attachmentRepo.Create(ctx, attachment); err != nil {
+		// Ne pas laisser de fichier orphelin dans le stockage
+		if deleteErr := s.fileStorage.Delete(context.WithoutCancel(ctx), attachment.StorageKey); deleteErr != nil {
+			s.logger.Warn("Erreur suppression fichier orphelin", logger.String("storage_key", attachment.StorageKey), logger.Error(deleteErr))
+		}
+		return nil, err
+	}
+
+	s.logger.Info("Pièce jointe ajoutée",
+		logger.String("attachment_id", attachment.ID.String()),
+		logger.String("transaction_id", transactionID.String()),
+		logger.String("content_type", contentType),
+		logger.Any("size", attachment.Size),
+	)
+
+	return attachment, nil
+}
+
+// GetAttachments liste les pièces jointes d'une transaction
+func (s *AttachmentService) GetAttachments(ctx context.Context, userID, transactionID uuid.UUID) ([]*entity.TransactionAttachment, error) {
+	if err := s.checkTransaction(ctx, userID, transactionID); err != nil {
+		return nil, err
+	}
+	return s.attachmentRepo.GetByTransactionID(ctx, transactionID)
+}
+
+// OpenAttachment retourne une pièce jointe et son contenu ; l'appelant doit fermer le contenu
+func (s *AttachmentService) OpenAttachment(ctx context.Context, userID, transactionID, attachmentID uuid.UUID) (*entity.TransactionAttachment, io.ReadCloser, error) {
+	attachment, err := s.getAttachment(ctx, userID, transactionID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.fileStorage.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, nil, fmt.Errorf("%w: fichier absent du stockage", entity.ErrFileNotFound)
+		}
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+// DeleteAttachment supprime une pièce jointe et son fichier
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, userID, transactionID, attachmentID uuid.UUID) error {
+	attachment, err := s.getAttachment(ctx, userID, transactionID, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.attachmentRepo.Delete(ctx, attachment.ID); err != nil {
+		return err
+	}
+	if err := s.fileStorage.Delete(ctx, attachment.StorageKey); err != nil {
+		s.logger.Warn("Erreur suppression fichier de pièce jointe",
+			logger.String("attachment_id", attachment.ID.String()),
+			logger.String("storage_key", attachment.StorageKey),
+			logger.Error(err),
+		)
+	}
+
+	s.logger.Info("Pièce jointe supprimée",
+		logger.String("attachment_id", attachment.ID.String()),
+		logger.String("transaction_id", transactionID.String()),
+	)
+	return nil
+}
+
+// checkTransaction vérifie que la transaction existe et appartient à l'utilisateur
+func (s *AttachmentService) checkTransaction(ctx context.Context, userID, transactionID uuid.UUID) error {
+	transaction, err := s.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil || transaction.UserID != userID {
+		return entity.ErrTransactionNotFound
+	}
+	return nil
+}
+
+// getAttachment récupère une pièce jointe de la transaction de l'utilisateur
+func (s *AttachmentService) getAttachment(ctx context.Context, userID, transactionID, attachmentID uuid.UUID) (*entity.TransactionAttachment, error) {
+	attachment, err := s.attachmentRepo.GetByID(ctx, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment.UserID != userID || attachment.TransactionID != transactionID {
+		return nil, entity.ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+// attachmentFileName nettoie le nom de fichier fourni par le client et l'aligne sur le type détecté
+func attachmentFileName(fileName, extension string) string {
+	name := strings.TrimSpace(filepath.Base(strings.ReplaceAll(fileName, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		name = "piece-jointe"
+	}
+	name = strings.TrimSuffix(name, filepath.Ext(name)) + extension
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[:255-len(extension)]) + extension
+	}
+	return name
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestAttachmentFileName(t *testing.T) {
+	tests := []struct {
+		fileName  string
+		extension string
+		want      string
+	}{
+		{fileName: "facture.PDF", extension: ".pdf", want: "facture.pdf"},
+		{fileName: "ticket", extension: ".png", want: "ticket.png"},
+		{fileName: "photo.jpeg", extension: ".jpg", want: "photo.jpg"},
+		{fileName: "archive.tar.gz", extension: ".pdf", want: "archive.tar.pdf"},
+		{fileName: "  reçu de caisse.jpg  ", extension: ".jpg", want: "reçu de caisse.jpg"},
+		{fileName: "../../etc/passwd", extension: ".png", want: "passwd.png"},
+		{fileName: `C:\Users\moi\reçu.jpeg`, extension: ".jpg", want: "reçu.jpg"},
+		{fileName: "dossier/", extension: ".pdf", want: "dossier.pdf"},
+		{fileName: "", extension: ".pdf", want: "piece-jointe.pdf"},
+		{fileName: "   ", extension: ".pdf", want: "piece-jointe.pdf"},
+		{fileName: "/", extension: ".pdf", want: "piece-jointe.pdf"},
+		{fileName: ".", extension: ".pdf", want: "piece-jointe.pdf"},
+	}
+
+	for _, tt := range tests {
+		if got := attachmentFileName(tt.fileName, tt.extension); got != tt.want {
+			t.Errorf("attachmentFileName(%q, %s) = %q, attendu %q", tt.fileName, tt.extension, got, tt.want)
+		}
+	}
+
+	// Un nom trop long est tronqué à 255 caractères en conservant l'extension
+	got := attachmentFileName(strings.Repeat("é", 300)+".jpeg", ".jpg")
+	if utf8.RuneCountInString(got) != 255 || !strings.HasSuffix(got, ".jpg") || !utf8.ValidString(got) {
+		t.Errorf("nom tronqué = %d caractères (%q...), attendu 255 terminés par .jpg", utf8.RuneCountInString(got), got[:10])
+	}
+}
@@ -13,22 +13,40 @@ import (
 
 // BudgetService gère la logique métier des budgets
 type BudgetService struct {
-	budgetRepo repository.BudgetRepository
-	logger     logger.Logger
+	budgetRepo    repository.BudgetRepository
+	exchangeRates *ExchangeRateService
+	logger        logger.Logger
 }
 
 // NewBudgetService crée une nouvelle instance de BudgetService
-func NewBudgetService(budgetRepo repository.BudgetRepository, logger logger.Logger) *BudgetService {
+func NewBudgetService(budgetRepo repository.BudgetRepository, exchangeRates *ExchangeRateService, logger logger.Logger) *BudgetService {
 	return &BudgetService{
-		budgetRepo: budgetRepo,
-		logger:     logger,
+		budgetRepo:    budgetRepo,
+		exchangeRates: exchangeRates,
+		logger:        logger,
 	}
 }
 
 // CreateBudget crée un nouveau budget
 func (s *BudgetService) CreateBudget(ctx context.Context, userID uuid.UUID, req entity.CreateBudgetRequest) (*entity.Budget, error) {
 	// Validation des données
-	if req.AmountPlanned <= 0 {
+	// Un budget est par défaut dans la devise de référence de l'utilisateur
+	currency := req.Currency
+	if currency == "" {
+		var err error
+		if currency, err = s.exchangeRates.GetBaseCurrency(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	if len(currency) != 3 {
+		return nil, fmt.Errorf("la devise doit avoir 3 caractères")
+	}
+
+	amountPlanned, err := req.AmountPlanned.Money(currency)
+	if err != nil {
+		return nil, err
+	}
+	if !amountPlanned.IsPositive() {
 		return nil, fmt.Errorf("le montant planifié doit être positif")
 	}
 
@@ -42,8 +60,9 @@ func (s *BudgetService) CreateBudget(ctx context.Context, userID uuid.UUID, req
 		UserID:        userID,
 		CategoryID:    req.CategoryID,
 		Name:          req.Name,
-		AmountPlanned: req.AmountPlanned,
-		AmountSpent:   0, // Initialiser à 0
+		AmountPlanned: amountPlanned,
+		AmountSpent:   entity.NewMoney(0, currency), // Initialiser à 0
+		Currency:      currency,
 		Period:        req.Period,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
@@ -144,17 +163,25 @@ func (s *BudgetService) UpdateBudget(ctx context.Context, userID uuid.UUID, budg
 
 	// Mettre à jour les champs
 	if req.AmountPlanned != nil {
-		if *req.AmountPlanned <= 0 {
+		amountPlanned, err := req.AmountPlanned.Money(budget.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if !amountPlanned.IsPositive() {
 			return nil, fmt.Errorf("le montant planifié doit être positif")
 		}
-		budget.AmountPlanned = *req.AmountPlanned
+		budget.AmountPlanned = amountPlanned
 	}
 
 	if req.AmountSpent != nil {
-		if *req.AmountSpent < 0 {
+		amountSpent, err := req.AmountSpent.Money(budget.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if amountSpent.IsNegative() {
 			return nil, fmt.Errorf("le montant dépensé ne peut pas être négatif")
 		}
-		budget.AmountSpent = *req.AmountSpent
+		budget.AmountSpent = amountSpent
 	}
 
 	budget.UpdatedAt = time.Now()
@@ -173,7 +200,7 @@ func (s *BudgetService) UpdateBudget(ctx context.Context, userID uuid.UUID, budg
 	return budget, nil
 }
 
-// DeleteBudget supprime un budget
+// DeleteBudget met un budget à la corbeille
 func (s *BudgetService) DeleteBudget(ctx context.Context, userID uuid.UUID, budgetID uuid.UUID) error {
 	// Récupérer le budget existant
 	budget, err := s.budgetRepo.GetByID(ctx, budgetID)
@@ -205,7 +232,31 @@ func (s *BudgetService) DeleteBudget(ctx context.Context, userID uuid.UUID, budg
 	return nil
 }
 
-// GetBudgetStats récupère les statistiques des budgets pour une période donnée
+// RestoreBudget sort un budget de la corbeille
+func (s *BudgetService) RestoreBudget(ctx context.Context, userID uuid.UUID, budgetID uuid.UUID) (*entity.Budget, error) {
+	budget, err := s.budgetRepo.GetDeletedByID(ctx, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	if budget.UserID != userID {
+		return nil, fmt.Errorf("%w: budget %s", entity.ErrTrashItemNotFound, budgetID)
+	}
+
+	if err := s.budgetRepo.Restore(ctx, budgetID); err != nil {
+		s.logger.Error("Erreur restauration budget", logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Budget restauré avec succès",
+		logger.String("budget_id", budgetID.String()),
+		logger.String("user_id", userID.String()),
+	)
+
+	return s.budgetRepo.GetByID(ctx, budgetID)
+}
+
+// GetBudgetStats récupère les statistiques des budgets pour une période donnée ; les montants sont convertis
+// dans la devise de référence de l'utilisateur au taux de change du jour
 func (s *BudgetService) GetBudgetStats(ctx context.Context, userID uuid.UUID, month, year int) (map[string]interface{}, error) {
 	// Récupérer tous les budgets de l'utilisateur pour la période
 	budgets, err := s.budgetRepo.GetByUserID(ctx, userID)
@@ -213,9 +264,15 @@ func (s *BudgetService) GetBudgetStats(ctx context.Context, userID uuid.UUID, mo
 		s.logger.Error("Erreur récupération statistiques budgets", logger.Error(err))
 		return nil, fmt.Errorf("erreur récupération statistiques: %w", err)
 	}
+	converter, err := s.exchangeRates.Converter(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération statistiques: %w", err)
+	}
+	today := time.Now()
 
 	// Filtrer par période et calculer les statistiques
-	var totalPlanned, totalSpent float64
+	totalPlanned := entity.NewMoney(0, converter.BaseCurrency())
+	totalSpent := entity.NewMoney(0, converter.BaseCurrency())
 	var budgetCount int
 	var overBudgetCount int
 
@@ -227,11 +284,11 @@ func (s *BudgetService) GetBudgetStats(ctx context.Context, userID uuid.UUID, mo
 
 	for _, budget := range budgets {
 		if budget.Period == periodFilter {
-			totalPlanned += budget.AmountPlanned
-			totalSpent += budget.AmountSpent
+			totalPlanned = totalPlanned.Add(converter.ToBase(budget.AmountPlanned, today))
+			totalSpent = totalSpent.Add(converter.ToBase(budget.AmountSpent, today))
 			budgetCount++
 
-			if budget.AmountSpent > budget.AmountPlanned {
+			if budget.AmountSpent.Cmp(budget.AmountPlanned) > 0 {
 				overBudgetCount++
 			}
 		}
@@ -239,16 +296,18 @@ func (s *BudgetService) GetBudgetStats(ctx context.Context, userID uuid.UUID, mo
 
 	stats := map[string]interface{}{
 		"period":            fmt.Sprintf("%d/%d", month, year),
+		"currency":          converter.BaseCurrency(),
+		"missing_rates":     converter.MissingRates(),
 		"total_planned":     totalPlanned,
 		"total_spent":       totalSpent,
-		"remaining":         totalPlanned - totalSpent,
+		"remaining":         totalPlanned.Sub(totalSpent),
 		"budget_count":      budgetCount,
 		"over_budget_count": overBudgetCount,
 		"utilization_rate":  0.0,
 	}
 
-	if totalPlanned > 0 {
-		stats["utilization_rate"] = (totalSpent / totalPlanned) * 100
+	if totalPlanned.IsPositive() {
+		stats["utilization_rate"] = float64(totalSpent.Minor) / float64(totalPlanned.Minor) * 100
 	}
 
 	return stats, nil
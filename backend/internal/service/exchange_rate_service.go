@@ -0,0 +1,414 @@
+package service
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/internal/domaine/repository"
+	"backend/pkg/logger"
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	exchangeRateSourceManual = "manual"
+	exchangeRateSourceImport = "import"
+)
+
+// exchangeRateDateFormats sont les formats de date acceptés dans un fichier de taux de change
+var exchangeRateDateFormats = []string{"2006-01-02", "02/01/2006"}
+
+// ExchangeRateService gère les taux de change de l'utilisateur, sa devise de référence et la conversion des montants
+type ExchangeRateService struct {
+	exchangeRateRepo repository.ExchangeRateRepository
+	userRepo         repository.UserRepository
+	logger           logger.Logger
+}
+
+// NewExchangeRateService crée une nouvelle instance de ExchangeRateService
+func NewExchangeRateService(
+	exchangeRateRepo repository.ExchangeRateRepository,
+	userRepo repository.UserRepository,
+	logger logger.Logger,
+) *ExchangeRateService {
+	return &ExchangeRateService{
+		exchangeRateRepo: exchangeRateRepo,
+		userRepo:         userRepo,
+		logger:           logger,
+	}
+}
+
+// GetBaseCurrency retourne la devise de référence de l'utilisateur, dans laquelle sont exprimés les totaux
+func (s *ExchangeRateService) GetBaseCurrency(ctx context.Context, userID uuid.UUID) (string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if user.BaseCurrency == "" {
+		return entity.DefaultCurrency, nil
+	}
+	return user.BaseCurrency, nil
+}
+
+// SetBaseCurrency change la devise de référence de l'utilisateur ; les montants enregistrés ne sont pas modifiés,
+// seuls les totaux convertis changent de devise
+func (s *ExchangeRateService) SetBaseCurrency(ctx context.Context, userID uuid.UUID, req entity.SetBaseCurrencyRequest) (string, error) {
+	currency, err := normalizeCurrency(req.Currency)
+	if err != nil {
+		return "", err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	user.BaseCurrency = currency
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		s.logger.Error("Erreur changement de la devise de référence", logger.Error(err))
+		return "", err
+	}
+
+	s.logger.Info("Devise de référence modifiée",
+		logger.String("user_id", userID.String()),
+		logger.String("currency", currency),
+	)
+	return currency, nil
+}
+
+// CreateRate enregistre un taux de change saisi par l'utilisateur ; un taux de la même paire à la même date est remplacé
+func (s *ExchangeRateService) CreateRate(ctx context.Context, userID uuid.UUID, req entity.CreateExchangeRateRequest) (*entity.ExchangeRate, error) {
+	rate, err := newExchangeRate(userID, req.FromCurrency, req.ToCurrency, req.Rate, req.Date, exchangeRateSourceManual)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.exchangeRateRepo.Upsert(ctx, rate); err != nil {
+		s.logger.Error("Erreur enregistrement taux de change", logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Taux de change enregistré",
+		logger.String("user_id", userID.String()),
+		logger.String("pair", rate.FromCurrency+"/"+rate.ToCurrency),
+		logger.String("rate", string(rate.Rate)),
+	)
+	return rate, nil
+}
+
+// GetRates récupère les taux de change de l'utilisateur, limités aux paires impliquant la devise donnée si elle
+// n'est pas vide
+func (s *ExchangeRateService) GetRates(ctx context.Context, userID uuid.UUID, currency string) ([]*entity.ExchangeRate, error) {
+	if currency != "" {
+		var err error
+		if currency, err = normalizeCurrency(currency); err != nil {
+			return nil, err
+		}
+	}
+	return s.exchangeRateRepo.GetByUserID(ctx, userID, currency)
+}
+
+// DeleteRate supprime un taux de change de l'utilisateur
+func (s *ExchangeRateService) DeleteRate(ctx context.Context, userID, rateID uuid.UUID) error {
+	rate, err := s.exchangeRateRepo.GetByID(ctx, rateID)
+	if err != nil {
+		return err
+	}
+	if rate.UserID != userID {
+		return entity.ErrExchangeRateNotFound
+	}
+	if err := s.exchangeRateRepo.Delete(ctx, rateID); err != nil {
+		s.logger.Error("Erreur suppression taux de change", logger.Error(err))
+		return err
+	}
+
+	s.logger.Info("Taux de change supprimé",
+		logger.String("rate_id", rateID.String()),
+		logger.String("user_id", userID.String()),
+	)
+	return nil
+}
+
+// ImportRates importe un fichier CSV de taux de change aux colonnes date, devise source, devise cible et taux
+// (séparateur virgule ou point-virgule, ligne d'en-tête facultative). Les lignes invalides sont signalées sans
+// interrompre l'import ; un taux existant pour la même paire et la même date est remplacé.
+func (s *ExchangeRateService) ImportRates(ctx context.Context, userID uuid.UUID, content io.Reader) (*entity.ExchangeRateImportResult, error) {
+	buffered := bufio.NewReader(content)
+	reader := csv.NewReader(buffered)
+	reader.Comma = exchangeRateDelimiter(buffered)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: fichier CSV invalide: %v", entity.ErrInvalidExchangeRateData, err)
+	}
+
+	result := &entity.ExchangeRateImportResult{Invalid: []*entity.ExchangeRateImportLine{}}
+	for index, record := range records {
+		if isBlankCSVRecord(record) {
+			continue
+		}
+		// Ligne d'en-tête : son dernier champ n'est pas un nombre
+		if index == 0 {
+			if _, err := entity.Decimal(strings.TrimSpace(record[len(record)-1])).Rat(); err != nil {
+				continue
+			}
+		}
+
+		result.TotalLines++
+		rate, err := parseExchangeRateRecord(userID, record)
+		if err == nil {
+			err = s.exchangeRateRepo.Upsert(ctx, rate)
+		}
+		if err != nil {
+			result.InvalidCount++
+			result.Invalid = append(result.Invalid, &entity.ExchangeRateImportLine{Line: index + 1, Error: err.Error()})
+			continue
+		}
+		result.ImportedCount++
+	}
+
+	s.logger.Info("Taux de change importés",
+		logger.String("user_id", userID.String()),
+		logger.Int("imported", result.ImportedCount),
+		logger.Int("invalid", result.InvalidCount),
+	)
+	return result, nil
+}
+
+// exchangeRateDelimiter détecte le séparateur d'un fichier de taux de change d'après sa première ligne
+func exchangeRateDelimiter(r *bufio.Reader) rune {
+	head, _ := r.Peek(r.Size())
+	line, _, _ := strings.Cut(string(head), "\n")
+	if strings.Count(line, ";") > strings.Count(line, ",") {
+		return ';'
+	}
+	return ','
+}
+
+// parseExchangeRateRecord lit une ligne de fichier de taux de change : date, devise source, devise cible, taux
+func parseExchangeRateRecord(userID uuid.UUID, record []string) (*entity.ExchangeRate, error) {
+	if len(record) < 4 {
+		return nil, fmt.Errorf("%w: 4 colonnes attendues (date, devise source, devise cible, taux)", entity.ErrInvalidExchangeRateData)
+	}
+
+	value := strings.TrimSpace(record[0])
+	var date time.Time
+	var err error
+	for _, layout := range exchangeRateDateFormats {
+		if date, err = time.Parse(layout, value); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: date invalide %q", entity.ErrInvalidExchangeRateData, value)
+	}
+
+	rate := entity.Decimal(strings.ReplaceAll(strings.TrimSpace(record[3]), ",", "."))
+	return newExchangeRate(userID, record[1], record[2], rate, date, exchangeRateSourceImport)
+}
+
+// newExchangeRate valide et construit un taux de change daté
+func newExchangeRate(userID uuid.UUID, from, to string, rate entity.Decimal, date time.Time, source string) (*entity.ExchangeRate, error) {
+	from, err := normalizeCurrency(from)
+	if err != nil {
+		return nil, err
+	}
+	to, err = normalizeCurrency(to)
+	if err != nil {
+		return nil, err
+	}
+	if from == to {
+		return nil, fmt.Errorf("%w: les devises source et cible doivent être différentes", entity.ErrInvalidExchangeRateData)
+	}
+	rate, err = entity.ParseDecimal(string(rate))
+	if err != nil {
+		return nil, fmt.Errorf("%w: le taux doit être un nombre positif", entity.ErrInvalidExchangeRateData)
+	}
+	if value, _ := rate.Rat(); value.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: le taux doit être un nombre positif", entity.ErrInvalidExchangeRateData)
+	}
+	if date.IsZero() {
+		return nil, fmt.Errorf("%w: la date est requise", entity.ErrInvalidExchangeRateData)
+	}
+
+	return &entity.ExchangeRate{
+		ID:           uuid.New(),
+		UserID:       userID,
+		FromCurrency: from,
+		ToCurrency:   to,
+		Rate:         rate,
+		Date:         truncateToDay(date),
+		Source:       source,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}, nil
+}
+
+// normalizeCurrency met un code de devise ISO 4217 en majuscules et vérifie qu'il compte trois lettres
+func normalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", fmt.Errorf("%w: %q", entity.ErrInvalidCurrency, code)
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", fmt.Errorf("%w: %q", entity.ErrInvalidCurrency, code)
+		}
+	}
+	return code, nil
+}
+
+// isBlankCSVRecord indique si tous les champs d'une ligne CSV sont vides
+func isBlankCSVRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Converter charge les taux de change et la devise de référence de l'utilisateur pour convertir des montants
+func (s *ExchangeRateService) Converter(ctx context.Context, userID uuid.UUID) (*CurrencyConverter, error) {
+	baseCurrency, err := s.GetBaseCurrency(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	rates, err := s.exchangeRateRepo.GetByUserID(ctx, userID, "")
+	if err != nil {
+		return nil, err
+	}
+	return newCurrencyConverter(baseCurrency, rates), nil
+}
+
+// Convert convertit un montant dans une autre devise au taux en vigueur à la date donnée
+func (s *ExchangeRateService) Convert(ctx context.Context, userID uuid.UUID, amount entity.Money, currency string, date time.Time) (entity.Money, error) {
+	converter, err := s.Converter(ctx, userID)
+	if err != nil {
+		return entity.Money{}, err
+	}
+	converted, ok := converter.Convert(amount, currency, date)
+	if !ok {
+		return entity.Money{}, fmt.Errorf("%w: %s/%s au %s", entity.ErrExchangeRateNotFound, amount.Currency, currency, date.Format("2006-01-02"))
+	}
+	return converted, nil
+}
+
+// currencyPair identifie le sens d'un taux de change
+type currencyPair struct {
+	from, to string
+}
+
+// datedRate est un taux de change en vigueur à partir de sa date
+type datedRate struct {
+	date    time.Time
+	rate    *big.Rat
+	inverse bool // déduit du taux de la paire inverse
+}
+
+// CurrencyConverter convertit des montants entre devises avec les taux de change d'un utilisateur. Le taux appliqué
+// à une date est le dernier taux de la paire (ou l'inverse du taux de la paire inverse) à cette date ou avant,
+// à défaut le plus ancien connu ; la fonction SQL exchange_rate applique la même règle.
+type CurrencyConverter struct {
+	baseCurrency string
+	rates        map[currencyPair][]datedRate
+	missing      map[string]bool
+}
+
+// newCurrencyConverter indexe les taux de change par paire, dans les deux sens
+func newCurrencyConverter(baseCurrency string, rates []*entity.ExchangeRate) *CurrencyConverter {
+	converter := &CurrencyConverter{
+		baseCurrency: baseCurrency,
+		rates:        map[currencyPair][]datedRate{},
+		missing:      map[string]bool{},
+	}
+	for _, rate := range rates {
+		value, err := rate.Rate.Rat()
+		if err != nil || value.Sign() <= 0 {
+			continue
+		}
+		direct := currencyPair{from: rate.FromCurrency, to: rate.ToCurrency}
+		inverse := currencyPair{from: rate.ToCurrency, to: rate.FromCurrency}
+		converter.rates[direct] = append(converter.rates[direct], datedRate{date: rate.Date, rate: value})
+		converter.rates[inverse] = append(converter.rates[inverse], datedRate{date: rate.Date, rate: new(big.Rat).Inv(value), inverse: true})
+	}
+	return converter
+}
+
+// BaseCurrency retourne la devise de référence de l'utilisateur
+func (c *CurrencyConverter) BaseCurrency() string {
+	return c.baseCurrency
+}
+
+// Convert convertit un montant dans une devise au taux en vigueur à la date donnée ; ok est faux sans taux connu
+func (c *CurrencyConverter) Convert(amount entity.Money, currency string, date time.Time) (entity.Money, bool) {
+	if amount.Currency == currency || amount.Currency == "" {
+		return entity.Money{Minor: amount.Minor, Currency: currency}, true
+	}
+	rate := c.rateAt(currencyPair{from: amount.Currency, to: currency}, truncateToDay(date))
+	if rate == nil {
+		return entity.NewMoney(0, currency), false
+	}
+	return amount.Convert(rate, currency), true
+}
+
+// ConvertTo convertit un montant dans une devise ; sans taux connu, le montant compte pour zéro
+// et sa devise est retenue dans MissingRates
+func (c *CurrencyConverter) ConvertTo(amount entity.Money, currency string, date time.Time) entity.Money {
+	converted, ok := c.Convert(amount, currency, date)
+	if !ok {
+		c.missing[amount.Currency] = true
+	}
+	return converted
+}
+
+// ToBase convertit un montant dans la devise de référence, comme ConvertTo
+func (c *CurrencyConverter) ToBase(amount entity.Money, date time.Time) entity.Money {
+	return c.ConvertTo(amount, c.baseCurrency, date)
+}
+
+// MissingRates liste, par ordre alphabétique, les devises des montants qui n'ont pu être convertis faute de taux
+func (c *CurrencyConverter) MissingRates() []string {
+	currencies := make([]string, 0, len(c.missing))
+	for currency := range c.missing {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+// rateAt retourne le taux d'une paire en vigueur à une date, nil sans taux
+func (c *CurrencyConverter) rateAt(pair currencyPair, date time.Time) *big.Rat {
+	var best *datedRate
+	for i := range c.rates[pair] {
+		candidate := &c.rates[pair][i]
+		if best == nil || rateBefore(best, candidate, date) {
+			best = candidate
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return best.rate
+}
+
+// rateBefore indique si le taux candidate doit être préféré à current pour une date : un taux en vigueur (daté du
+// jour ou avant) l'emporte, puis le plus proche de la date, puis un taux saisi dans ce sens plutôt qu'un inverse
+func rateBefore(current, candidate *datedRate, date time.Time) bool {
+	currentEffective, candidateEffective := !current.date.After(date), !candidate.date.After(date)
+	if currentEffective != candidateEffective {
+		return candidateEffective
+	}
+	currentGap, candidateGap := current.date.Sub(date).Abs(), candidate.date.Sub(date).Abs()
+	if currentGap != candidateGap {
+		return candidateGap < currentGap
+	}
+	return current.inverse && !candidate.inverse
+}
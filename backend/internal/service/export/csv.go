@@ -0,0 +1,81 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+// csvFlushEvery est le nombre de lignes écrites entre deux vidages du tampon vers le client
+const csvFlushEvery = 500
+
+// csvWriter écrit un export CSV (UTF-8 avec BOM pour l'ouverture directe dans un tableur)
+type csvWriter struct {
+	out     io.Writer
+	writer  *csv.Writer
+	started bool
+	count   int
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{out: w, writer: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) start() error {
+	if c.started {
+		return nil
+	}
+	c.started = true
+	if _, err := io.WriteString(c.out, "\ufeff"); err != nil {
+		return err
+	}
+	return c.writer.Write(columns)
+}
+
+// Write implémente Writer
+func (c *csvWriter) Write(row *Row) error {
+	if err := c.start(); err != nil {
+		return err
+	}
+	err := c.writer.Write([]string{
+		row.Date.Format("2006-01-02"),
+		row.Type,
+		csvText(row.Description),
+		row.Amount.String(),
+		row.Currency,
+		csvText(row.Account),
+		csvText(row.Category),
+		csvText(row.Splits),
+		csvText(row.Tags),
+		csvText(row.Reference),
+		row.ID,
+		row.Status,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.count++
+	if c.count%csvFlushEvery == 0 {
+		c.writer.Flush()
+		return c.writer.Error()
+	}
+	return nil
+}
+
+// Close implémente Writer
+func (c *csvWriter) Close() error {
+	if err := c.start(); err != nil {
+		return err
+	}
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+// csvText neutralise un texte libre qu'un tableur interpréterait comme une formule
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
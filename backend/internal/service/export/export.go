@@ -0,0 +1,67 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Formats d'export supportés
+const (
+	FormatCSV    = "csv"
+	FormatXLSX   = "xlsx"
+	FormatNDJSON = "ndjson"
+)
+
+// Row représente une transaction exportée
+type Row struct {
+	Date        time.Time   `json:"date"`
+	Type        string      `json:"type"`
+	Description string      `json:"description"`
+	Amount      json.Number `json:"amount"` // écriture décimale exacte, avec les décimales de la devise
+	Currency    string      `json:"currency"`
+	Account     string      `json:"account"`
+	Category    string      `json:"category"` // chemin de la catégorie, ex: "Alimentation > Courses"
+	Splits      string      `json:"splits"`   // ventilation, ex: "Alimentation > Courses: 12.50; Maison: 3.00"
+	Tags        string      `json:"tags"`
+	Reference   string      `json:"reference"`
+	ID          string      `json:"id"`
+	Status      string      `json:"status"` // scheduled, pending, cleared, reconciled
+}
+
+// columns est l'en-tête commun aux formats tabulaires, dans l'ordre des champs de Row
+var columns = []string{"date", "type", "description", "amount", "currency", "account", "category", "splits", "tags", "reference", "id", "status"}
+
+// Writer écrit les lignes d'un export au fil de l'eau ; Close termine le fichier
+// (en-tête compris lorsqu'aucune ligne n'a été écrite)
+type Writer interface {
+	Write(row *Row) error
+	Close() error
+}
+
+// NewWriter crée l'écrivain du format donné ; rien n'est écrit dans w avant la première ligne ou Close
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatXLSX:
+		return newXLSXWriter(w), nil
+	case FormatNDJSON:
+		return newNDJSONWriter(w), nil
+	default:
+		return nil, fmt.Errorf("format d'export non supporté: %s", format)
+	}
+}
+
+// ContentType retourne le type MIME d'un format d'export
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "application/x-ndjson"
+	}
+}
@@ -0,0 +1,36 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// ndjsonWriter écrit un export NDJSON : un objet JSON par ligne
+type ndjsonWriter struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	buffer := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	return &ndjsonWriter{buffer: buffer, encoder: encoder}
+}
+
+// ndjsonRow est la représentation JSON d'une ligne : date au format AAAA-MM-JJ
+type ndjsonRow struct {
+	Row
+	Date string `json:"date"`
+}
+
+// Write implémente Writer
+func (n *ndjsonWriter) Write(row *Row) error {
+	return n.encoder.Encode(ndjsonRow{Row: *row, Date: row.Date.Format("2006-01-02")})
+}
+
+// Close implémente Writer
+func (n *ndjsonWriter) Close() error {
+	return n.buffer.Flush()
+}
@@ -0,0 +1,175 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Parties fixes du classeur : une seule feuille, des styles pour l'en-tête, les dates et les montants
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/><Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/></Types>`
+
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Transactions" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`
+
+	// cellXfs : 0 défaut, 1 date (format 14), 2 montant (format 2 : 0.00), 3 en-tête en gras
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts><fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills><borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders><cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs><cellXfs count="4"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="14" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/><xf numFmtId="2" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs></styleSheet>`
+
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// Styles de cellule (index dans cellXfs)
+const (
+	xlsxStyleDate   = 1
+	xlsxStyleAmount = 2
+	xlsxStyleHeader = 3
+)
+
+// xlsxEpoch est l'origine des numéros de série de dates d'Excel
+var xlsxEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// xlsxWriter écrit un classeur XLSX au fil de l'eau : l'archive zip est produite séquentiellement
+// et la feuille est écrite ligne par ligne (chaînes en ligne, sans table de chaînes partagées)
+type xlsxWriter struct {
+	zip     *zip.Writer
+	sheet   *bufio.Writer
+	started bool
+	row     int
+}
+
+func newXLSXWriter(w io.Writer) *xlsxWriter {
+	return &xlsxWriter{zip: zip.NewWriter(w)}
+}
+
+func (x *xlsxWriter) start() error {
+	if x.started {
+		return nil
+	}
+	x.started = true
+
+	for _, part := range []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	} {
+		f, err := x.zip.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+
+	f, err := x.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	x.sheet = bufio.NewWriter(f)
+	if _, err := x.sheet.WriteString(xlsxSheetStart); err != nil {
+		return err
+	}
+
+	x.beginRow()
+	for i, column := range columns {
+		x.stringCell(i, column, xlsxStyleHeader)
+	}
+	return x.endRow()
+}
+
+// Write implémente Writer
+func (x *xlsxWriter) Write(row *Row) error {
+	if err := x.start(); err != nil {
+		return err
+	}
+
+	x.beginRow()
+	x.numberCell(0, strconv.Itoa(xlsxSerial(row.Date)), xlsxStyleDate)
+	x.stringCell(1, row.Type, 0)
+	x.stringCell(2, row.Description, 0)
+	x.numberCell(3, row.Amount.String(), xlsxStyleAmount)
+	x.stringCell(4, row.Currency, 0)
+	x.stringCell(5, row.Account, 0)
+	x.stringCell(6, row.Category, 0)
+	x.stringCell(7, row.Splits, 0)
+	x.stringCell(8, row.Tags, 0)
+	x.stringCell(9, row.Reference, 0)
+	x.stringCell(10, row.ID, 0)
+	x.stringCell(11, row.Status, 0)
+	return x.endRow()
+}
+
+// Close implémente Writer
+func (x *xlsxWriter) Close() error {
+	if err := x.start(); err != nil {
+		return err
+	}
+	if _, err := x.sheet.WriteString(xlsxSheetEnd); err != nil {
+		return err
+	}
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
+
+func (x *xlsxWriter) beginRow() {
+	x.row++
+	fmt.Fprintf(x.sheet, `<row r="%d">`, x.row)
+}
+
+func (x *xlsxWriter) endRow() error {
+	_, err := x.sheet.WriteString(`</row>`)
+	return err
+}
+
+// stringCell écrit une cellule texte ; les caractères interdits en XML sont remplacés
+func (x *xlsxWriter) stringCell(column int, value string, style int) {
+	if value == "" && style == 0 {
+		return
+	}
+	fmt.Fprintf(x.sheet, `<c r="%s%d" t="inlineStr"%s><is><t xml:space="preserve">`, xlsxColumn(column), x.row, xlsxStyle(style))
+	xml.EscapeText(x.sheet, []byte(value))
+	x.sheet.WriteString(`</t></is></c>`)
+}
+
+// numberCell écrit une cellule numérique (montant ou numéro de série de date)
+func (x *xlsxWriter) numberCell(column int, value string, style int) {
+	fmt.Fprintf(x.sheet, `<c r="%s%d"%s><v>%s</v></c>`, xlsxColumn(column), x.row, xlsxStyle(style), value)
+}
+
+// xlsxSerial retourne le numéro de série Excel du jour d'une date
+func xlsxSerial(date time.Time) int {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return int(day.Sub(xlsxEpoch).Hours() / 24)
+}
+
+// xlsxColumn retourne la lettre d'une colonne (A à Z, suffisant pour l'export)
+func xlsxColumn(index int) string {
+	return string(rune('A' + index))
+}
+
+func xlsxStyle(style int) string {
+	if style == 0 {
+		return ""
+	}
+	return fmt.Sprintf(` s="%d"`, style)
+}
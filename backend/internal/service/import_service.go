@@ -0,0 +1,303 @@
+package service
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/internal/domaine/repository"
+	"backend/internal/service/statement"
+	"backend/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// importDescriptionMaxLength est la longueur maximale d'un libellé de transaction
+const importDescriptionMaxLength = 255
+
+// ImportRequest regroupe les paramètres d'un import de relevé bancaire
+type ImportRequest struct {
+	AccountID uuid.UUID
+	FileName  string
+	Format    string // csv, ofx ou qif ; déduit de l'extension du fichier si vide
+	Content   io.Reader
+	Options   statement.Options
+}
+
+// ImportService gère l'import de relevés bancaires (CSV, OFX, QIF) dans un compte
+type ImportService struct {
+	importBatchRepo    repository.ImportBatchRepository
+	transactionRepo    repository.TransactionRepository
+	accountRepo        repository.AccountRepository
+	transactionService *TransactionService
+	txManager          repository.TxManager
+	logger             logger.Logger
+}
+
+// NewImportService crée une nouvelle instance de ImportService
+func NewImportService(
+	importBatchRepo repository.ImportBatchRepository,
+	transactionRepo repository.TransactionRepository,
+	accountRepo repository.AccountRepository,
+	transactionService *TransactionService,
+	txManager repository.TxManager,
+	logger logger.Logger,
+) *ImportService {
+	return &ImportService{
+		importBatchRepo:    importBatchRepo,
+		transactionRepo:    transactionRepo,
+		accountRepo:        accountRepo,
+		transactionService: transactionService,
+		txManager:          txManager,
+		logger:             logger,
+	}
+}
+
+// PreviewImport analyse un relevé sans rien enregistrer (dry-run) : chaque ligne est marquée
+// comme nouvelle, déjà importée ou invalide
+func (s *ImportService) PreviewImport(ctx context.Context, userID uuid.UUID, req ImportRequest) (*entity.ImportResult, error) {
+	result, err := s.analyze(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	result.DryRun = true
+	return result, nil
+}
+
+// ImportStatement importe les nouvelles lignes d'un relevé dans le compte. Chaque ligne est créée via
+// TransactionService (catégorisation automatique et mise à jour du solde comprises) et rattachée
+// au lot d'import, qui peut ensuite être annulé en bloc.
+func (s *ImportService) ImportStatement(ctx context.Context, userID uuid.UUID, req ImportRequest) (*entity.ImportResult, error) {
+	result, err := s.analyze(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &entity.ImportBatch{
+		ID:           uuid.New(),
+		UserID:       userID,
+		AccountID:    req.AccountID,
+		Format:       result.Format,
+		FileName:     req.FileName,
+		Status:       "completed",
+		TotalLines:   result.TotalLines,
+		SkippedCount: result.DuplicateCount,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.importBatchRepo.Create(ctx, batch); err != nil {
+		return nil, err
+	}
+
+	// Les transactions créées sont attribuées à l'import dans leur historique
+	ctx = withChangeSource(ctx, changeSourceImport)
+	accountID := req.AccountID
+	for _, line := range result.Lines {
+		if line.Status != "new" {
+			if line.Status == "invalid" {
+				batch.FailedCount++
+			}
+			continue
+		}
+
+		externalRef := line.ExternalRef
+		transaction, err := s.transactionService.CreateTransaction(ctx, userID, entity.CreateTransactionRequest{
+			AccountID:     &accountID,
+			Type:          line.Type,
+			Amount:        entity.DecimalOf(line.Amount),
+			Description:   line.Description,
+			Date:          *line.Date,
+			ExternalRef:   &externalRef,
+			ImportBatchID: &batch.ID,
+		})
+		if err != nil {
+			if errors.Is(err, entity.ErrDuplicateExternalRef) {
+				// Ligne importée entre l'aperçu et la confirmation
+				line.Status = "duplicate"
+				batch.SkippedCount++
+				continue
+			}
+			s.logger.Warn("Erreur import ligne de relevé",
+				logger.String("batch_id", batch.ID.String()),
+				logger.Int("line", line.Line),
+				logger.Error(err),
+			)
+			line.Status = "failed"
+			line.Error = err.Error()
+			batch.FailedCount++
+			continue
+		}
+
+		line.Status = "imported"
+		line.TransactionID = &transaction.ID
+		batch.ImportedCount++
+	}
+
+	if err := s.importBatchRepo.Update(ctx, batch); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Relevé importé",
+		logger.String("batch_id", batch.ID.String()),
+		logger.String("user_id", userID.String()),
+		logger.String("format", batch.Format),
+		logger.Int("imported", batch.ImportedCount),
+		logger.Int("skipped", batch.SkippedCount),
+		logger.Int("failed", batch.FailedCount),
+	)
+
+	result.Batch = batch
+	return result, nil
+}
+
+// GetImportBatches récupère les lots d'import de l'utilisateur, éventuellement limités à un compte
+func (s *ImportService) GetImportBatches(ctx context.Context, userID uuid.UUID, accountID *uuid.UUID) ([]*entity.ImportBatch, error) {
+	return s.importBatchRepo.GetByUserID(ctx, userID, accountID)
+}
+
+// GetImportBatch récupère un lot d'import de l'utilisateur
+func (s *ImportService) GetImportBatch(ctx context.Context, userID, batchID uuid.UUID) (*entity.ImportBatch, error) {
+	batch, err := s.importBatchRepo.GetByID(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if batch.UserID != userID {
+		return nil, entity.ErrImportBatchNotFound
+	}
+	return batch, nil
+}
+
+// UndoImport supprime toutes les transactions d'un lot d'import et rétablit les soldes,
+// dans une seule transaction SQL
+func (s *ImportService) UndoImport(ctx context.Context, userID, batchID uuid.UUID) (*entity.ImportBatch, error) {
+	var batch *entity.ImportBatch
+	err := s.txManager.WithinTransaction(withChangeSource(ctx, changeSourceImport), func(ctx context.Context) error {
+		var err error
+		batch, err = s.importBatchRepo.GetByIDForUpdate(ctx, batchID)
+		if err != nil {
+			return err
+		}
+		if batch.UserID != userID {
+			return entity.ErrImportBatchNotFound
+		}
+		if batch.Status == "undone" {
+			return entity.ErrImportAlreadyUndone
+		}
+
+		transactions, err := s.transactionRepo.GetByImportBatchID(ctx, batch.ID)
+		if err != nil {
+			return err
+		}
+		for _, transaction := range transactions {
+			if err := s.transactionService.DeleteTransaction(ctx, userID, transaction.ID); err != nil {
+				return fmt.Errorf("erreur suppression transaction importée %s: %w", transaction.ID, err)
+			}
+		}
+
+		now := time.Now()
+		batch.Status = "undone"
+		batch.UndoneAt = &now
+		return s.importBatchRepo.Update(ctx, batch)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Import annulé",
+		logger.String("batch_id", batch.ID.String()),
+		logger.String("user_id", userID.String()),
+	)
+	return batch, nil
+}
+
+// analyze lit le relevé et classe chaque ligne, sans effet de bord
+func (s *ImportService) analyze(ctx context.Context, userID uuid.UUID, req ImportRequest) (*entity.ImportResult, error) {
+	account, err := s.accountRepo.GetByID(ctx, req.AccountID)
+	if err != nil || account.UserID != userID {
+		return nil, fmt.Errorf("%w: compte non trouvé", entity.ErrInvalidImportData)
+	}
+
+	format := req.Format
+	if format == "" {
+		format = statement.DetectFormat(req.FileName)
+	}
+	if format != statement.FormatCSV && format != statement.FormatOFX && format != statement.FormatQIF {
+		return nil, fmt.Errorf("%w: formats acceptés: csv, ofx, qif", entity.ErrInvalidFileType)
+	}
+
+	// Un montant plus précis que la devise du compte (décimales en XAF) rend la ligne invalide
+	opts := req.Options
+	opts.Currency = account.Currency
+	lines, err := statement.Parse(format, req.Content, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", entity.ErrInvalidImportData, err)
+	}
+
+	refs := statement.Fingerprints(lines)
+	candidates := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if ref != "" {
+			candidates = append(candidates, ref)
+		}
+	}
+	existingRefs, err := s.transactionRepo.GetExistingExternalRefs(ctx, req.AccountID, candidates)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(existingRefs))
+	for _, ref := range existingRefs {
+		known[ref] = true
+	}
+
+	result := &entity.ImportResult{
+		Format:     format,
+		TotalLines: len(lines),
+		Lines:      make([]*entity.ImportLine, 0, len(lines)),
+	}
+	for i, line := range lines {
+		importLine := &entity.ImportLine{
+			Line:        line.Number,
+			Description: truncateDescription(line.Description),
+			ExternalRef: refs[i],
+			Error:       line.Error,
+		}
+		importLine.Amount = entity.NewMoney(line.Amount.Minor, account.Currency)
+		if importLine.Amount.IsNegative() {
+			importLine.Amount = importLine.Amount.Neg()
+		}
+
+		switch {
+		case importLine.Error != "":
+			importLine.Status = "invalid"
+			result.InvalidCount++
+		case known[refs[i]]:
+			importLine.Status = "duplicate"
+			result.DuplicateCount++
+		default:
+			date := line.Date
+			importLine.Date = &date
+			importLine.Type = "expense"
+			if line.Amount.IsPositive() {
+				importLine.Type = "income"
+			}
+			importLine.Status = "new"
+			result.NewCount++
+			// une même référence bancaire répétée dans le fichier n'est importée qu'une fois
+			known[refs[i]] = true
+		}
+
+		result.Lines = append(result.Lines, importLine)
+	}
+
+	return result, nil
+}
+
+// truncateDescription limite un libellé importé à la taille de la colonne description
+func truncateDescription(description string) string {
+	runes := []rune(description)
+	if len(runes) <= importDescriptionMaxLength {
+		return description
+	}
+	return string(runes[:importDescriptionMaxLength])
+}
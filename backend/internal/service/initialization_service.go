@@ -89,13 +89,22 @@ func (s *InitializationService) CreateBudgetsFromPreferences(ctx context.Context
 				continue
 			}
 
+			amountPlanned, err := entity.MoneyFromFloat(budgetData.amount, entity.DefaultCurrency)
+			if err != nil {
+				s.logger.Warn("Montant de budget invalide",
+					logger.String("category", budgetData.categoryName),
+					logger.Error(err))
+				continue
+			}
+
 			budget := &entity.Budget{
 				ID:            uuid.New(),
 				UserID:        userID,
 				CategoryID:    category.ID,
 				Name:          fmt.Sprintf("Budget %s %s", budgetData.categoryName, now.Format("2006-01")),
-				AmountPlanned: budgetData.amount,
-				AmountSpent:   0, // Nouveau budget, pas encore de dépenses
+				AmountPlanned: amountPlanned,
+				AmountSpent:   entity.NewMoney(0, entity.DefaultCurrency), // Nouveau budget, pas encore de dépenses
+				Currency:      entity.DefaultCurrency,
 				Period:        "monthly",
 				CreatedAt:     now,
 				UpdatedAt:     now,
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"backend/internal/domaine/entity"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Statuts d'un emprunt et de ses échéances
+const (
+	loanStatusActive  = "active"
+	loanStatusPaidOff = "paid_off"
+
+	installmentStatusUpcoming = "upcoming"
+	installmentStatusMissed   = "missed"
+	installmentStatusPaid     = "paid"
+)
+
+// loanMaxInstallments borne la longueur d'un échéancier (50 ans de mensualités)
+const loanMaxInstallments = 600
+
+// loanMonthlyRate retourne le taux mensuel d'un emprunt à partir de son taux annuel en pourcentage
+func loanMonthlyRate(annualRate entity.Decimal) (*big.Rat, error) {
+	rate, err := annualRate.Rat()
+	if err != nil {
+		return nil, fmt.Errorf("%w: taux d'intérêt invalide", entity.ErrInvalidLoanData)
+	}
+	if rate.Sign() < 0 || rate.Cmp(big.NewRat(100, 1)) > 0 {
+		return nil, fmt.Errorf("%w: le taux d'intérêt doit être compris entre 0 et 100 %%", entity.ErrInvalidLoanData)
+	}
+	return rate.Quo(rate, big.NewRat(1200, 1)), nil
+}
+
+// loanPayment calcule la mensualité constante qui rembourse le capital en months échéances au taux mensuel donné :
+// capital × r / (1 − (1 + r)^−n), arrondie à l'unité mineure (la dernière échéance absorbe l'arrondi)
+func loanPayment(principal entity.Money, rate *big.Rat, months int) entity.Money {
+	if rate.Sign() == 0 {
+		return principal.Convert(big.NewRat(1, int64(months)), principal.Currency)
+	}
+
+	one := big.NewRat(1, 1)
+	growth := new(big.Rat).Set(one)
+	factor := new(big.Rat).Add(one, rate)
+	for i := 0; i < months; i++ {
+		growth.Mul(growth, factor)
+	}
+
+	// r × (1 + r)^n / ((1 + r)^n − 1)
+	coefficient := new(big.Rat).Mul(rate, growth)
+	coefficient.Quo(coefficient, new(big.Rat).Sub(growth, one))
+	return principal.Convert(coefficient, principal.Currency)
+}
+
+// loanDueDate retourne la date de l'échéance numéro number : le jour de paiement du number-ième mois suivant le
+// déblocage, ramené au dernier jour du mois si besoin
+func loanDueDate(startDate time.Time, paymentDay, number int) time.Time {
+	month := time.Date(startDate.Year(), startDate.Month()+time.Month(number), 1, 0, 0, 0, 0, time.UTC)
+	return dateInMonth(month.Year(), month.Month(), paymentDay)
+}
+
+// amortize construit l'échéancier d'un emprunt à partir de l'échéance number et du capital restant dû avant elle :
+// chaque mensualité paie les intérêts du mois sur le capital restant, le reste amortit le capital. L'échéance
+// lastNumber (0 si la durée n'est pas imposée) solde le capital restant.
+func amortize(loan *entity.Loan, rate *big.Rat, number int, remaining entity.Money, lastNumber int) ([]*entity.LoanInstallment, error) {
+	var installments []*entity.LoanInstallment
+	for ; remaining.IsPositive(); number++ {
+		if number > loanMaxInstallments {
+			return nil, fmt.Errorf("%w: l'échéancier dépasse %d échéances", entity.ErrInvalidLoanData, loanMaxInstallments)
+		}
+
+		interest := remaining.Convert(rate, loan.Currency)
+		principal := loan.Payment.Sub(interest)
+		if !principal.IsPositive() && number != lastNumber {
+			return nil, fmt.Errorf("%w: la mensualité ne couvre pas les intérêts", entity.ErrInvalidLoanData)
+		}
+		if principal.Cmp(remaining) > 0 || number == lastNumber {
+			principal = remaining
+		}
+		remaining = remaining.Sub(principal)
+
+		installments = append(installments, &entity.LoanInstallment{
+			ID:                 uuid.New(),
+			LoanID:             loan.ID,
+			UserID:             loan.UserID,
+			Number:             number,
+			DueDate:            loanDueDate(loan.StartDate, loan.PaymentDay, number),
+			Payment:            principal.Add(interest),
+			Principal:          principal,
+			Interest:           interest,
+			ExtraPrincipal:     entity.NewMoney(0, loan.Currency),
+			RemainingPrincipal: remaining,
+			Currency:           loan.Currency,
+			Status:             installmentStatusUpcoming,
+		})
+	}
+	return installments, nil
+}
+
+// scheduleTotals retourne le total des intérêts d'échéances et la date de la dernière
+func scheduleTotals(installments []*entity.LoanInstallment, currency string) (entity.Money, *time.Time) {
+	interest := entity.NewMoney(0, currency)
+	var last *time.Time
+	for _, installment := range installments {
+		interest = interest.Add(installment.Interest)
+		last = &installment.DueDate
+	}
+	return interest, last
+}
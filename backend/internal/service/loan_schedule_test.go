@@ -0,0 +1,209 @@
+package service
+
+import (
+	"backend/internal/domaine/entity"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestLoanMonthlyRate(t *testing.T) {
+	tests := []struct {
+		annual  entity.Decimal
+		want    *big.Rat
+		wantErr bool
+	}{
+		{annual: "12", want: big.NewRat(1, 100)},
+		{annual: "9.5", want: big.NewRat(95, 12000)},
+		{annual: "0", want: big.NewRat(0, 1)},
+		{annual: "100", want: big.NewRat(1, 12)},
+		{annual: "100.01", wantErr: true},
+		{annual: "-1", wantErr: true},
+		{annual: "abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := loanMonthlyRate(tt.annual)
+		if tt.wantErr {
+			if !errors.Is(err, entity.ErrInvalidLoanData) {
+				t.Errorf("loanMonthlyRate(%s) erreur = %v, attendu ErrInvalidLoanData", tt.annual, err)
+			}
+			continue
+		}
+		if err != nil || got.Cmp(tt.want) != 0 {
+			t.Errorf("loanMonthlyRate(%s) = %v, %v, attendu %v", tt.annual, got, err, tt.want)
+		}
+	}
+}
+
+func TestLoanPayment(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal entity.Money
+		rate      *big.Rat
+		months    int
+		want      int64
+	}{
+		{name: "taux nul", principal: entity.NewMoney(100000, "XAF"), rate: new(big.Rat), months: 12, want: 8333},
+		{name: "taux nul arrondi au supérieur", principal: entity.NewMoney(100000, "XAF"), rate: new(big.Rat), months: 6, want: 16667},
+		{name: "taux nul une échéance", principal: entity.NewMoney(150000, "EUR"), rate: new(big.Rat), months: 1, want: 150000},
+		{name: "1 % par mois sur 12 mois", principal: entity.NewMoney(1000000, "XAF"), rate: big.NewRat(1, 100), months: 12, want: 88849},
+		{name: "1 % par mois en euros", principal: entity.NewMoney(1000000, "EUR"), rate: big.NewRat(1, 100), months: 12, want: 88849},
+		{name: "une échéance avec intérêts", principal: entity.NewMoney(100000, "XAF"), rate: big.NewRat(1, 100), months: 1, want: 101000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := loanPayment(tt.principal, tt.rate, tt.months)
+			if got.Minor != tt.want || got.Currency != tt.principal.Currency {
+				t.Errorf("loanPayment = %v, attendu %d %s", got, tt.want, tt.principal.Currency)
+			}
+		})
+	}
+}
+
+// testLoan construit un emprunt au taux mensuel donné dont la mensualité est calculée sur months échéances
+func testLoan(principal entity.Money, rate *big.Rat, months int) *entity.Loan {
+	return &entity.Loan{
+		ID:         uuid.New(),
+		UserID:     uuid.New(),
+		Principal:  principal,
+		TermMonths: months,
+		PaymentDay: 5,
+		StartDate:  time.Date(2025, time.January, 10, 0, 0, 0, 0, time.UTC),
+		Payment:    loanPayment(principal, rate, months),
+		Currency:   principal.Currency,
+	}
+}
+
+func TestAmortize(t *testing.T) {
+	tests := []struct {
+		name         string
+		principal    entity.Money
+		rate         *big.Rat
+		months       int
+		wantLast     int64 // mensualité de la dernière échéance
+		wantInterest int64
+	}{
+		{name: "taux nul : la dernière échéance absorbe l'arrondi", principal: entity.NewMoney(100000, "XAF"), rate: new(big.Rat), months: 12, wantLast: 8337, wantInterest: 0},
+		{name: "1 % par mois", principal: entity.NewMoney(1000000, "XAF"), rate: big.NewRat(1, 100), months: 12, wantLast: 88847, wantInterest: 66186},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := testLoan(tt.principal, tt.rate, tt.months)
+			installments, err := amortize(loan, tt.rate, 1, tt.principal, tt.months)
+			if err != nil {
+				t.Fatalf("amortize: %v", err)
+			}
+			if len(installments) != tt.months {
+				t.Fatalf("%d échéances, attendu %d", len(installments), tt.months)
+			}
+
+			principal := entity.NewMoney(0, loan.Currency)
+			for i, installment := range installments {
+				if installment.Number != i+1 {
+					t.Errorf("échéance %d numérotée %d", i+1, installment.Number)
+				}
+				if !installment.Payment.Add(installment.Interest.Neg()).Sub(installment.Principal).IsZero() {
+					t.Errorf("échéance %d : mensualité %v ≠ capital %v + intérêts %v", installment.Number, installment.Payment, installment.Principal, installment.Interest)
+				}
+				if i < len(installments)-1 && installment.Payment != loan.Payment {
+					t.Errorf("échéance %d : mensualité %v, attendu %v", installment.Number, installment.Payment, loan.Payment)
+				}
+				principal = principal.Add(installment.Principal)
+			}
+
+			last := installments[len(installments)-1]
+			if last.Payment.Minor != tt.wantLast {
+				t.Errorf("dernière mensualité = %v, attendu %d", last.Payment, tt.wantLast)
+			}
+			if !last.RemainingPrincipal.IsZero() {
+				t.Errorf("capital restant après la dernière échéance = %v", last.RemainingPrincipal)
+			}
+			if principal != tt.principal {
+				t.Errorf("capital amorti = %v, attendu %v", principal, tt.principal)
+			}
+			if interest, _ := scheduleTotals(installments, loan.Currency); interest.Minor != tt.wantInterest {
+				t.Errorf("intérêts = %v, attendu %d", interest, tt.wantInterest)
+			}
+		})
+	}
+}
+
+func TestAmortizeWithoutTerm(t *testing.T) {
+	// Sans durée imposée, le reliquat d'arrondi fait l'objet d'une échéance supplémentaire
+	loan := testLoan(entity.NewMoney(100000, "XAF"), new(big.Rat), 12)
+	installments, err := amortize(loan, new(big.Rat), 1, loan.Principal, 0)
+	if err != nil {
+		t.Fatalf("amortize: %v", err)
+	}
+	if len(installments) != 13 {
+		t.Fatalf("%d échéances, attendu 13", len(installments))
+	}
+	if last := installments[12]; last.Payment.Minor != 4 || !last.RemainingPrincipal.IsZero() {
+		t.Errorf("dernière échéance = %v (reste %v), attendu 4 XAF", last.Payment, last.RemainingPrincipal)
+	}
+}
+
+func TestAmortizePaymentBelowInterest(t *testing.T) {
+	rate := big.NewRat(1, 100)
+	loan := testLoan(entity.NewMoney(1000000, "XAF"), rate, 12)
+	loan.Payment = entity.NewMoney(10000, "XAF") // égale aux intérêts du premier mois
+
+	_, err := amortize(loan, rate, 1, loan.Principal, 0)
+	if !errors.Is(err, entity.ErrInvalidLoanData) || !strings.Contains(err.Error(), "ne couvre pas les intérêts") {
+		t.Errorf("amortize erreur = %v, attendu mensualité insuffisante", err)
+	}
+
+	// La dernière échéance d'une durée imposée solde le capital quelle que soit la mensualité
+	installments, err := amortize(loan, rate, 12, loan.Principal, 12)
+	if err != nil || len(installments) != 1 || installments[0].Principal != loan.Principal {
+		t.Errorf("amortize dernière échéance = %v, %v", installments, err)
+	}
+}
+
+func TestAmortizeTooManyInstallments(t *testing.T) {
+	loan := testLoan(entity.NewMoney(1000000, "XAF"), new(big.Rat), 12)
+	loan.Payment = entity.NewMoney(1, "XAF")
+
+	_, err := amortize(loan, new(big.Rat), 1, loan.Principal, 0)
+	if !errors.Is(err, entity.ErrInvalidLoanData) {
+		t.Errorf("amortize erreur = %v, attendu un échéancier trop long", err)
+	}
+}
+
+func TestLoanDueDate(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name       string
+		start      time.Time
+		paymentDay int
+		number     int
+		want       time.Time
+	}{
+		{name: "jour courant", start: date(2025, time.January, 10), paymentDay: 5, number: 1, want: date(2025, time.February, 5)},
+		{name: "31 en février", start: date(2025, time.January, 31), paymentDay: 31, number: 1, want: date(2025, time.February, 28)},
+		{name: "31 en février bissextile", start: date(2024, time.January, 31), paymentDay: 31, number: 1, want: date(2024, time.February, 29)},
+		{name: "31 revient après février", start: date(2025, time.January, 31), paymentDay: 31, number: 2, want: date(2025, time.March, 31)},
+		{name: "31 en avril", start: date(2025, time.January, 31), paymentDay: 31, number: 3, want: date(2025, time.April, 30)},
+		{name: "30 en février", start: date(2025, time.January, 15), paymentDay: 30, number: 1, want: date(2025, time.February, 28)},
+		{name: "déblocage le 31, paiement le 15", start: date(2025, time.January, 31), paymentDay: 15, number: 1, want: date(2025, time.February, 15)},
+		{name: "changement d'année", start: date(2025, time.November, 20), paymentDay: 31, number: 2, want: date(2026, time.January, 31)},
+		{name: "onzième échéance", start: date(2025, time.March, 1), paymentDay: 29, number: 11, want: date(2026, time.February, 28)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := loanDueDate(tt.start, tt.paymentDay, tt.number); !got.Equal(tt.want) {
+				t.Errorf("loanDueDate = %s, attendu %s", got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+			}
+		})
+	}
+}
@@ -0,0 +1,689 @@
+package service
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/internal/domaine/repository"
+	"backend/pkg/logger"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// loanReminderDaysAhead est le délai (en jours) avant l'échéance à partir duquel elle est rappelée
+	loanReminderDaysAhead = 3
+	// loanReminderBatchSize est le nombre maximum d'échéances traitées par passage du planificateur des rappels
+	loanReminderBatchSize = 500
+	// loanInterestCategoryName est la catégorie de dépense créée pour les intérêts si l'emprunt n'en précise pas
+	loanInterestCategoryName = "Intérêts"
+)
+
+// LoanService gère les emprunts : échéancier d'amortissement, règlement des échéances (capital transféré vers le
+// compte de l'emprunt, intérêts en dépense), projections de remboursement et rappels des échéances
+type LoanService struct {
+	loanRepo            repository.LoanRepository
+	accountRepo         repository.AccountRepository
+	categoryRepo        repository.CategoryRepository
+	transactionService  *TransactionService
+	exchangeRates       *ExchangeRateService
+	notificationService *NotificationService
+	txManager           repository.TxManager
+	logger              logger.Logger
+}
+
+// NewLoanService crée une nouvelle instance de LoanService
+func NewLoanService(
+	loanRepo repository.LoanRepository,
+	accountRepo repository.AccountRepository,
+	categoryRepo repository.CategoryRepository,
+	transactionService *TransactionService,
+	exchangeRates *ExchangeRateService,
+	notificationService *NotificationService,
+	txManager repository.TxManager,
+	logger logger.Logger,
+) *LoanService {
+	return &LoanService{
+		loanRepo:            loanRepo,
+		accountRepo:         accountRepo,
+		categoryRepo:        categoryRepo,
+		transactionService:  transactionService,
+		exchangeRates:       exchangeRates,
+		notificationService: notificationService,
+		txManager:           txManager,
+		logger:              logger,
+	}
+}
+
+// CreateLoan enregistre un emprunt, crée le compte de type debt qui le suit et génère son échéancier.
+// Avec un compte de déblocage, le capital y est transféré depuis le compte de l'emprunt à la date de déblocage ;
+// sinon le compte est ouvert au capital restant dû après les échéances déjà réglées, marquées payées sans transaction.
+func (s *LoanService) CreateLoan(ctx context.Context, userID uuid.UUID, req entity.CreateLoanRequest) (*entity.Loan, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, fmt.Errorf("%w: le nom est requis", entity.ErrInvalidLoanData)
+	}
+	if req.TermMonths < 1 || req.TermMonths > loanMaxInstallments {
+		return nil, fmt.Errorf("%w: la durée doit être comprise entre 1 et %d mois", entity.ErrInvalidLoanData, loanMaxInstallments)
+	}
+	if req.PaymentDay < 1 || req.PaymentDay > 31 {
+		return nil, fmt.Errorf("%w: le jour de paiement doit être compris entre 1 et 31", entity.ErrInvalidLoanData)
+	}
+	if req.StartDate.IsZero() {
+		return nil, fmt.Errorf("%w: la date de déblocage est requise", entity.ErrInvalidLoanData)
+	}
+	if req.InstallmentsPaid < 0 || req.InstallmentsPaid >= req.TermMonths {
+		return nil, fmt.Errorf("%w: le nombre d'échéances déjà réglées doit être inférieur à la durée", entity.ErrInvalidLoanData)
+	}
+	if req.InstallmentsPaid > 0 && req.DisbursementAccountID != nil {
+		return nil, fmt.Errorf("%w: un emprunt déjà en cours de remboursement ne peut pas être débloqué sur un compte", entity.ErrInvalidLoanData)
+	}
+
+	currency, err := s.loanCurrency(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	principal, err := req.Principal.Money(currency)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", entity.ErrInvalidLoanData, err)
+	}
+	if !principal.IsPositive() {
+		return nil, fmt.Errorf("%w: le capital doit être positif", entity.ErrInvalidLoanData)
+	}
+	interestRate, err := entity.ParseDecimal(string(req.InterestRate))
+	if err != nil {
+		return nil, fmt.Errorf("%w: taux d'intérêt invalide", entity.ErrInvalidLoanData)
+	}
+	rate, err := loanMonthlyRate(interestRate)
+	if err != nil {
+		return nil, err
+	}
+	if req.InterestCategoryID != nil {
+		category, err := s.categoryRepo.GetByID(ctx, userID, *req.InterestCategoryID)
+		if err != nil || category.UserID != userID || category.Type != "expense" {
+			return nil, fmt.Errorf("%w: catégorie de dépense non trouvée", entity.ErrInvalidLoanData)
+		}
+	}
+
+	loan := &entity.Loan{
+		ID:                 uuid.New(),
+		UserID:             userID,
+		AccountID:          uuid.New(),
+		Name:               strings.TrimSpace(req.Name),
+		Lender:             req.Lender,
+		Principal:          principal,
+		InterestRate:       interestRate,
+		TermMonths:         req.TermMonths,
+		PaymentDay:         req.PaymentDay,
+		StartDate:          truncateToDay(req.StartDate),
+		Payment:            loanPayment(principal, rate, req.TermMonths),
+		InterestPaid:       entity.NewMoney(0, currency),
+		Currency:           currency,
+		InterestCategoryID: req.InterestCategoryID,
+		Status:             loanStatusActive,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
+	}
+	installments, err := amortize(loan, rate, 1, principal, req.TermMonths)
+	if err != nil {
+		return nil, err
+	}
+
+	// Échéances réglées avant l'enregistrement de l'emprunt
+	loan.OutstandingPrincipal = principal
+	for _, installment := range installments[:req.InstallmentsPaid] {
+		paidAt := installment.DueDate
+		installment.Status = installmentStatusPaid
+		installment.PaidAt = &paidAt
+		loan.OutstandingPrincipal = installment.RemainingPrincipal
+		loan.InterestPaid = loan.InterestPaid.Add(installment.Interest)
+	}
+
+	openingBalance := loan.OutstandingPrincipal.Neg()
+	if req.DisbursementAccountID != nil {
+		openingBalance = entity.NewMoney(0, currency)
+	}
+	account := &entity.Account{
+		ID:               loan.AccountID,
+		UserID:           userID,
+		Name:             loan.Name,
+		Type:             "debt",
+		Balance:          openingBalance,
+		PendingBalance:   entity.NewMoney(0, currency),
+		AvailableBalance: openingBalance,
+		Currency:         currency,
+		Icon:             "fa5:hand-holding-usd",
+		Color:            "#D63031",
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+	}
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.accountRepo.Create(ctx, account); err != nil {
+			return err
+		}
+		if err := s.loanRepo.Create(ctx, loan); err != nil {
+			return err
+		}
+		if err := s.loanRepo.CreateInstallments(ctx, installments); err != nil {
+			return err
+		}
+
+		if req.DisbursementAccountID == nil {
+			return nil
+		}
+		_, err := s.transactionService.CreateTransaction(ctx, userID, entity.CreateTransactionRequest{
+			AccountID:      &loan.AccountID,
+			ToAccountID:    req.DisbursementAccountID,
+			Type:           "transfer",
+			Amount:         entity.DecimalOf(principal),
+			Description:    fmt.Sprintf("Déblocage %s", loan.Name),
+			Date:           loan.StartDate,
+			AllowDuplicate: true,
+		})
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Erreur création emprunt", logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Emprunt créé avec succès",
+		logger.String("loan_id", loan.ID.String()),
+		logger.String("user_id", userID.String()),
+		logger.String("principal", principal.String()),
+		logger.String("payment", loan.Payment.String()),
+	)
+
+	return s.GetLoan(ctx, userID, loan.ID)
+}
+
+// GetLoan récupère un emprunt avec son échéancier
+func (s *LoanService) GetLoan(ctx context.Context, userID, loanID uuid.UUID) (*entity.Loan, error) {
+	loan, err := s.loanRepo.GetByID(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+	if loan.UserID != userID {
+		return nil, entity.ErrLoanNotFound
+	}
+	return loan, nil
+}
+
+// GetLoans récupère les emprunts de l'utilisateur
+func (s *LoanService) GetLoans(ctx context.Context, userID uuid.UUID) ([]*entity.Loan, error) {
+	loans, err := s.loanRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("Erreur récupération emprunts", logger.Error(err))
+		return nil, err
+	}
+	return loans, nil
+}
+
+// DeleteLoan supprime un emprunt et son échéancier. Le compte de l'emprunt et les transactions des échéances
+// réglées sont conservés.
+func (s *LoanService) DeleteLoan(ctx context.Context, userID, loanID uuid.UUID) error {
+	if _, err := s.GetLoan(ctx, userID, loanID); err != nil {
+		return err
+	}
+
+	if err := s.loanRepo.Delete(ctx, loanID); err != nil {
+		s.logger.Error("Erreur suppression emprunt", logger.Error(err))
+		return err
+	}
+
+	s.logger.Info("Emprunt supprimé avec succès",
+		logger.String("loan_id", loanID.String()),
+		logger.String("user_id", userID.String()),
+	)
+	return nil
+}
+
+// PayInstallment règle la plus ancienne échéance non réglée d'un emprunt depuis un compte de l'utilisateur : le
+// capital est transféré vers le compte de l'emprunt et les intérêts enregistrés en dépense. Un remboursement
+// anticipé s'ajoute au capital transféré ; l'échéancier restant est alors recalculé à mensualité constante,
+// ce qui raccourcit la durée.
+func (s *LoanService) PayInstallment(ctx context.Context, userID, loanID uuid.UUID, req entity.PayLoanInstallmentRequest) (*entity.Loan, error) {
+	date := truncateToDay(time.Now())
+	if req.Date != nil {
+		date = truncateToDay(*req.Date)
+	}
+
+	var paid *entity.LoanInstallment
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		loan, err := s.lockLoan(ctx, userID, loanID)
+		if err != nil {
+			return err
+		}
+		if loan.Status == loanStatusPaidOff {
+			return entity.ErrLoanPaidOff
+		}
+
+		installments, err := s.loanRepo.GetInstallments(ctx, loanID)
+		if err != nil {
+			return err
+		}
+		for _, installment := range installments {
+			if installment.Status != installmentStatusPaid {
+				paid = installment
+				break
+			}
+		}
+		if paid == nil {
+			return entity.ErrLoanPaidOff
+		}
+
+		if req.FromAccountID == loan.AccountID {
+			return fmt.Errorf("%w: l'échéance doit être réglée depuis un autre compte que celui de l'emprunt", entity.ErrInvalidLoanData)
+		}
+		account, err := s.accountRepo.GetByID(ctx, req.FromAccountID)
+		if err != nil || account.UserID != userID {
+			return fmt.Errorf("%w: compte non trouvé", entity.ErrInvalidLoanData)
+		}
+		if account.Currency != loan.Currency {
+			return fmt.Errorf("%w: compte en %s, emprunt en %s", entity.ErrCurrencyMismatch, account.Currency, loan.Currency)
+		}
+
+		extra := entity.NewMoney(0, loan.Currency)
+		if req.ExtraPrincipal != "" {
+			if extra, err = req.ExtraPrincipal.Money(loan.Currency); err != nil {
+				return fmt.Errorf("%w: %v", entity.ErrInvalidLoanData, err)
+			}
+			if extra.IsNegative() {
+				return fmt.Errorf("%w: le remboursement anticipé doit être positif", entity.ErrInvalidLoanData)
+			}
+			if extra.Cmp(paid.RemainingPrincipal) > 0 {
+				return fmt.Errorf("%w: le remboursement anticipé dépasse le capital restant dû (%s)", entity.ErrInvalidLoanData, paid.RemainingPrincipal)
+			}
+		}
+
+		description := fmt.Sprintf("%s - échéance %d", loan.Name, paid.Number)
+		transfer, err := s.transactionService.CreateTransaction(ctx, userID, entity.CreateTransactionRequest{
+			AccountID:      &req.FromAccountID,
+			ToAccountID:    &loan.AccountID,
+			Type:           "transfer",
+			Amount:         entity.DecimalOf(paid.Principal.Add(extra)),
+			Description:    description + " (capital)",
+			Date:           date,
+			AllowDuplicate: true,
+		})
+		if err != nil {
+			return err
+		}
+		paid.PrincipalTransactionID = &transfer.ID
+
+		if paid.Interest.IsPositive() {
+			categoryID, err := s.interestCategory(ctx, loan)
+			if err != nil {
+				return err
+			}
+			expense, err := s.transactionService.CreateTransaction(ctx, userID, entity.CreateTransactionRequest{
+				AccountID:      &req.FromAccountID,
+				CategoryID:     categoryID,
+				Type:           "expense",
+				Amount:         entity.DecimalOf(paid.Interest),
+				Description:    description + " (intérêts)",
+				Date:           date,
+				AllowDuplicate: true,
+			})
+			if err != nil {
+				return err
+			}
+			paid.InterestTransactionID = &expense.ID
+		}
+
+		paid.Status = installmentStatusPaid
+		paid.PaidAt = &date
+		paid.ExtraPrincipal = extra
+		paid.RemainingPrincipal = paid.RemainingPrincipal.Sub(extra)
+		if err := s.loanRepo.UpdateInstallment(ctx, paid); err != nil {
+			return err
+		}
+
+		loan.OutstandingPrincipal = paid.RemainingPrincipal
+		loan.InterestPaid = loan.InterestPaid.Add(paid.Interest)
+		if extra.IsPositive() {
+			if err := s.reschedule(ctx, loan, paid.Number+1); err != nil {
+				return err
+			}
+		}
+		if loan.OutstandingPrincipal.IsZero() {
+			loan.Status = loanStatusPaidOff
+		}
+		loan.UpdatedAt = time.Now()
+		return s.loanRepo.Update(ctx, loan)
+	})
+	if err != nil {
+		s.logger.Error("Erreur règlement échéance", logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Échéance réglée",
+		logger.String("loan_id", loanID.String()),
+		logger.Int("number", paid.Number),
+		logger.String("principal", paid.Principal.Add(paid.ExtraPrincipal).String()),
+		logger.String("interest", paid.Interest.String()),
+	)
+
+	return s.GetLoan(ctx, userID, loanID)
+}
+
+// reschedule remplace les échéances non réglées d'un emprunt par l'échéancier du capital restant dû, à partir de
+// l'échéance number et à mensualité inchangée
+func (s *LoanService) reschedule(ctx context.Context, loan *entity.Loan, number int) error {
+	rate, err := loanMonthlyRate(loan.InterestRate)
+	if err != nil {
+		return err
+	}
+	installments, err := amortize(loan, rate, number, loan.OutstandingPrincipal, 0)
+	if err != nil {
+		return err
+	}
+
+	if err := s.loanRepo.DeleteUnpaidInstallments(ctx, loan.ID); err != nil {
+		return err
+	}
+	return s.loanRepo.CreateInstallments(ctx, installments)
+}
+
+// GetProjection projette le remboursement du capital restant dû d'un emprunt, au rythme de l'échéancier et avec un
+// versement supplémentaire mensuel éventuel, et le compare à l'échéancier en cours
+func (s *LoanService) GetProjection(ctx context.Context, userID, loanID uuid.UUID, extraPayment entity.Decimal) (*entity.LoanProjectionResponse, error) {
+	loan, err := s.GetLoan(ctx, userID, loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	extra := entity.NewMoney(0, loan.Currency)
+	if extraPayment != "" {
+		if extra, err = extraPayment.Money(loan.Currency); err != nil {
+			return nil, fmt.Errorf("%w: %v", entity.ErrInvalidLoanData, err)
+		}
+		if extra.IsNegative() {
+			return nil, fmt.Errorf("%w: le versement supplémentaire doit être positif", entity.ErrInvalidLoanData)
+		}
+	}
+
+	var unpaid []*entity.LoanInstallment
+	for _, installment := range loan.Installments {
+		if installment.Status != installmentStatusPaid {
+			unpaid = append(unpaid, installment)
+		}
+	}
+
+	projection := &entity.LoanProjectionResponse{
+		OutstandingPrincipal: loan.OutstandingPrincipal,
+		Payment:              loan.Payment,
+		ExtraPayment:         extra,
+		TotalInterest:        entity.NewMoney(0, loan.Currency),
+		ScheduledInterest:    entity.NewMoney(0, loan.Currency),
+		InterestSaved:        entity.NewMoney(0, loan.Currency),
+	}
+	if len(unpaid) == 0 {
+		return projection, nil
+	}
+
+	scheduledInterest, scheduledPayoff := scheduleTotals(unpaid, loan.Currency)
+	projection.ScheduledInterest = scheduledInterest
+	projection.ScheduledPayoffDate = *scheduledPayoff
+
+	rate, err := loanMonthlyRate(loan.InterestRate)
+	if err != nil {
+		return nil, err
+	}
+	projected := *loan
+	projected.Payment = loan.Payment.Add(extra)
+	installments, err := amortize(&projected, rate, unpaid[0].Number, loan.OutstandingPrincipal, 0)
+	if err != nil {
+		return nil, err
+	}
+	totalInterest, payoff := scheduleTotals(installments, loan.Currency)
+
+	projection.RemainingInstallments = len(installments)
+	projection.TotalInterest = totalInterest
+	projection.PayoffDate = *payoff
+	projection.InterestSaved = scheduledInterest.Sub(totalInterest)
+	projection.MonthsSaved = len(unpaid) - len(installments)
+	return projection, nil
+}
+
+// GetReminders récupère les échéances impayées et celles dues dans les jours à venir, par date d'échéance
+func (s *LoanService) GetReminders(ctx context.Context, userID uuid.UUID, days int) ([]*entity.LoanReminder, error) {
+	loans, err := s.loanRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uuid.UUID]string, len(loans))
+	for _, loan := range loans {
+		names[loan.ID] = loan.Name
+	}
+
+	installments, err := s.loanRepo.GetUnpaidInstallments(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := truncateToDay(time.Now())
+	limit := today.AddDate(0, 0, days)
+	reminders := []*entity.LoanReminder{}
+	for _, installment := range installments {
+		if installment.DueDate.After(limit) {
+			break
+		}
+		reminders = append(reminders, &entity.LoanReminder{
+			LoanID:      installment.LoanID,
+			LoanName:    names[installment.LoanID],
+			Installment: installment,
+			DaysUntil:   int(installment.DueDate.Sub(today).Hours() / 24),
+		})
+	}
+	return reminders, nil
+}
+
+// GetLoanSummaries récupère l'état du remboursement des emprunts de l'utilisateur, indexé par compte d'emprunt
+func (s *LoanService) GetLoanSummaries(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]*entity.LoanSummary, error) {
+	loans, err := s.loanRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	installments, err := s.loanRepo.GetUnpaidInstallments(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make(map[uuid.UUID]*entity.LoanSummary, len(loans))
+	byLoan := make(map[uuid.UUID]*entity.LoanSummary, len(loans))
+	for _, loan := range loans {
+		summary := &entity.LoanSummary{
+			LoanID:               loan.ID,
+			Name:                 loan.Name,
+			Payment:              loan.Payment,
+			OutstandingPrincipal: loan.OutstandingPrincipal,
+		}
+		summaries[loan.AccountID] = summary
+		byLoan[loan.ID] = summary
+	}
+
+	for _, installment := range installments {
+		summary, ok := byLoan[installment.LoanID]
+		if !ok {
+			continue
+		}
+		if summary.NextInstallment == nil {
+			summary.NextInstallment = installment
+		}
+		if installment.Status == installmentStatusMissed {
+			summary.MissedInstallments++
+		}
+		if summary.PayoffDate == nil || installment.DueDate.After(*summary.PayoffDate) {
+			dueDate := installment.DueDate
+			summary.PayoffDate = &dueDate
+		}
+	}
+	return summaries, nil
+}
+
+// StartReminderScheduler envoie périodiquement les rappels d'échéances jusqu'à l'annulation du contexte
+func (s *LoanService) StartReminderScheduler(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		s.logger.Warn("Planificateur des rappels d'échéances désactivé (intervalle invalide)")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.logger.Info("Planificateur des rappels d'échéances démarré", logger.String("interval", interval.String()))
+	for {
+		if sent, err := s.ProcessReminders(ctx, time.Now()); err != nil {
+			s.logger.Error("Erreur planificateur des rappels d'échéances", logger.Error(err))
+		} else if sent > 0 {
+			s.logger.Info("Rappels d'échéances envoyés", logger.Int("count", sent))
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Planificateur des rappels d'échéances arrêté")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessReminders marque impayées les échéances dépassées et les signale, puis rappelle les échéances dues dans
+// les prochains jours, et retourne le nombre de notifications envoyées. Une notification non envoyée est retentée
+// au passage suivant.
+func (s *LoanService) ProcessReminders(ctx context.Context, now time.Time) (int, error) {
+	today := truncateToDay(now)
+	loans := make(map[uuid.UUID]*entity.Loan)
+	sent := 0
+
+	missed, err := s.loanRepo.GetNewlyMissedInstallments(ctx, today, loanReminderBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for _, installment := range missed {
+		loan, err := s.reminderLoan(ctx, loans, installment.LoanID)
+		if err != nil {
+			s.logger.Error("Erreur récupération emprunt", logger.String("loan_id", installment.LoanID.String()), logger.Error(err))
+			continue
+		}
+		if err := s.notificationService.SendLoanInstallmentMissed(ctx, installment.UserID, loan.Name, installment.Payment, installment.DueDate); err != nil {
+			s.logger.Error("Erreur alerte échéance impayée", logger.String("installment_id", installment.ID.String()), logger.Error(err))
+			continue
+		}
+		installment.Status = installmentStatusMissed
+		if err := s.loanRepo.UpdateInstallment(ctx, installment); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+
+	upcoming, err := s.loanRepo.GetInstallmentsToRemind(ctx, today.AddDate(0, 0, loanReminderDaysAhead), loanReminderBatchSize)
+	if err != nil {
+		return sent, err
+	}
+	for _, installment := range upcoming {
+		loan, err := s.reminderLoan(ctx, loans, installment.LoanID)
+		if err != nil {
+			s.logger.Error("Erreur récupération emprunt", logger.String("loan_id", installment.LoanID.String()), logger.Error(err))
+			continue
+		}
+		if err := s.notificationService.SendLoanInstallmentReminder(ctx, installment.UserID, loan.Name, installment.Payment, installment.DueDate); err != nil {
+			s.logger.Error("Erreur rappel échéance", logger.String("installment_id", installment.ID.String()), logger.Error(err))
+			continue
+		}
+		remindedAt := now
+		installment.RemindedAt = &remindedAt
+		if err := s.loanRepo.UpdateInstallment(ctx, installment); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// reminderLoan récupère l'emprunt d'une échéance, en mémoire pour la durée d'un passage du planificateur
+func (s *LoanService) reminderLoan(ctx context.Context, loans map[uuid.UUID]*entity.Loan, loanID uuid.UUID) (*entity.Loan, error) {
+	if loan, ok := loans[loanID]; ok {
+		return loan, nil
+	}
+	loan, err := s.loanRepo.GetByID(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+	loans[loanID] = loan
+	return loan, nil
+}
+
+// loanCurrency détermine la devise d'un nouvel emprunt : celle du compte de déblocage, la devise demandée ou la
+// devise de référence de l'utilisateur
+func (s *LoanService) loanCurrency(ctx context.Context, userID uuid.UUID, req entity.CreateLoanRequest) (string, error) {
+	var currency string
+	if req.Currency != "" {
+		var err error
+		if currency, err = normalizeCurrency(req.Currency); err != nil {
+			return "", err
+		}
+	}
+
+	if req.DisbursementAccountID != nil {
+		account, err := s.accountRepo.GetByID(ctx, *req.DisbursementAccountID)
+		if err != nil || account.UserID != userID {
+			return "", fmt.Errorf("%w: compte de déblocage non trouvé", entity.ErrInvalidLoanData)
+		}
+		if currency != "" && currency != account.Currency {
+			return "", fmt.Errorf("%w: compte de déblocage en %s, emprunt en %s", entity.ErrCurrencyMismatch, account.Currency, currency)
+		}
+		return account.Currency, nil
+	}
+
+	if currency != "" {
+		return currency, nil
+	}
+	return s.exchangeRates.GetBaseCurrency(ctx, userID)
+}
+
+// interestCategory retourne la catégorie des dépenses d'intérêts d'un emprunt. À défaut, la catégorie de dépense
+// « Intérêts » de l'utilisateur est retenue (créée si besoin) et rattachée à l'emprunt.
+func (s *LoanService) interestCategory(ctx context.Context, loan *entity.Loan) (*uuid.UUID, error) {
+	if loan.InterestCategoryID != nil {
+		return loan.InterestCategoryID, nil
+	}
+
+	categories, err := s.categoryRepo.GetByUserID(ctx, loan.UserID)
+	if err != nil {
+		return nil, err
+	}
+	category := findCategoryByName(categories, loanInterestCategoryName, "expense")
+	if category == nil {
+		category = &entity.Category{
+			ID:     uuid.New(),
+			UserID: loan.UserID,
+			Name:   loanInterestCategoryName,
+			Type:   "expense",
+			Icon:   "fa5:percent",
+			Color:  "#D63031",
+		}
+		if err := s.categoryRepo.Create(ctx, category); err != nil {
+			return nil, fmt.Errorf("erreur création catégorie des intérêts: %w", err)
+		}
+	}
+
+	loan.InterestCategoryID = &category.ID
+	return loan.InterestCategoryID, nil
+}
+
+// lockLoan verrouille un emprunt et vérifie qu'il appartient à l'utilisateur
+func (s *LoanService) lockLoan(ctx context.Context, userID, loanID uuid.UUID) (*entity.Loan, error) {
+	loan, err := s.loanRepo.GetByIDForUpdate(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+	if loan.UserID != userID {
+		return nil, entity.ErrLoanNotFound
+	}
+	return loan, nil
+}
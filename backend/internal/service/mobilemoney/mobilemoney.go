@@ -0,0 +1,206 @@
+package mobilemoney
+
+import (
+	"backend/internal/domaine/entity"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Opérateurs de mobile money supportés
+const (
+	OperatorMTNMoMo     = "mtn_momo"
+	OperatorOrangeMoney = "orange_money"
+)
+
+// Natures d'opérations reconnues dans les SMS de confirmation
+const (
+	KindReceived   = "received"   // transfert reçu
+	KindSent       = "sent"       // transfert envoyé
+	KindPayment    = "payment"    // paiement marchand ou facture
+	KindWithdrawal = "withdrawal" // retrait chez un agent
+	KindDeposit    = "deposit"    // dépôt chez un agent
+)
+
+// Draft représente une opération extraite d'un SMS de confirmation
+type Draft struct {
+	Operator     string
+	Kind         string
+	Type         string          // income ou expense
+	Amount       entity.Decimal  // montant de l'opération en francs CFA, hors frais
+	Fees         entity.Decimal  // frais prélevés par l'opérateur, vide s'ils ne sont pas annoncés
+	Counterparty string          // nom et/ou numéro du correspondant
+	Reference    string          // identifiant de la transaction chez l'opérateur
+	BalanceAfter *entity.Decimal // solde annoncé après l'opération
+	Date         *time.Time      // date annoncée dans le SMS
+}
+
+// Parser reconnaît un format de SMS d'un opérateur
+type Parser interface {
+	Operator() string
+	Parse(message string) (*Draft, bool)
+}
+
+// parsers regroupe les formats connus ; les plus spécifiques d'un opérateur passent en premier
+var parsers = append(momoParsers(), orangeMoneyParsers()...)
+
+// Parse essaie chaque format connu et retourne l'opération extraite du premier qui reconnaît le SMS
+func Parse(message string) (*Draft, bool) {
+	for _, parser := range parsers {
+		if draft, ok := parser.Parse(message); ok {
+			return draft, true
+		}
+	}
+	return nil, false
+}
+
+// regexParser reconnaît un format de SMS par une expression régulière portant les groupes
+// nommés amount et, si le format l'annonce, counterparty. Le message doit aussi porter la
+// marque de l'opérateur ; les frais, le solde, la date et la référence sont recherchés dans
+// le reste du message.
+type regexParser struct {
+	operator  string
+	kind      string
+	marker    *regexp.Regexp
+	pattern   *regexp.Regexp
+	reference *regexp.Regexp
+}
+
+// newMarkedParser crée un format de SMS pour un opérateur
+func newMarkedParser(operator, kind string, marker, reference *regexp.Regexp, pattern string) Parser {
+	return &regexParser{
+		operator:  operator,
+		kind:      kind,
+		marker:    marker,
+		pattern:   regexp.MustCompile(pattern),
+		reference: reference,
+	}
+}
+
+// Operator retourne l'opérateur du format
+func (p *regexParser) Operator() string {
+	return p.operator
+}
+
+// Parse extrait l'opération d'un SMS s'il correspond au format
+func (p *regexParser) Parse(message string) (*Draft, bool) {
+	text := normalize(message)
+	if !p.marker.MatchString(text) {
+		return nil, false
+	}
+	match := p.pattern.FindStringSubmatch(text)
+	if match == nil {
+		return nil, false
+	}
+
+	amount, ok := parseAmount(match[p.pattern.SubexpIndex("amount")])
+	if !ok || amount == "0" {
+		return nil, false
+	}
+
+	draft := &Draft{
+		Operator: p.operator,
+		Kind:     p.kind,
+		Type:     "expense",
+		Amount:   amount,
+	}
+	if p.kind == KindReceived || p.kind == KindDeposit {
+		draft.Type = "income"
+	}
+	if index := p.pattern.SubexpIndex("counterparty"); index > 0 {
+		draft.Counterparty = strings.Trim(strings.TrimSpace(match[index]), ".,:")
+	}
+	if fees, ok := findAmount(feePattern, text); ok {
+		draft.Fees = fees
+	}
+	if balance, ok := findAmount(balancePattern, text); ok {
+		draft.BalanceAfter = &balance
+	}
+	if reference := p.reference.FindStringSubmatch(text); reference != nil {
+		draft.Reference = strings.TrimRight(reference[1], ".")
+	}
+	draft.Date = findDate(text)
+
+	return draft, true
+}
+
+// amountExpr capture un montant en francs CFA (5000, 5 000, 5.000, 5000.00)
+const amountExpr = `(?P<amount>\d[\d .,]*?)\s*(?:fcfa|xaf|cfa|f\b)`
+
+var (
+	feePattern     = regexp.MustCompile(`(?i)(?:frais|fee|fees)\s*(?:de|:|was|is)?\s*:?\s*(\d[\d .,]*?)\s*(?:fcfa|xaf|cfa|f\b)`)
+	balancePattern = regexp.MustCompile(`(?i)(?:nouveau solde|solde actuel|solde disponible|new balance|balance)\s*(?:est de|est|is|:)?\s*:?\s*(\d[\d .,]*?)\s*(?:fcfa|xaf|cfa|f\b)`)
+	datePatterns   = []struct {
+		pattern *regexp.Regexp
+		layouts []string
+	}{
+		{regexp.MustCompile(`(\d{4}-\d{2}-\d{2}[ T]\d{2}:\d{2}(?::\d{2})?)`), []string{"2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02T15:04"}},
+		{regexp.MustCompile(`(\d{2}/\d{2}/\d{4} \d{2}:\d{2}(?::\d{2})?)`), []string{"02/01/2006 15:04:05", "02/01/2006 15:04"}},
+		{regexp.MustCompile(`(\d{4}-\d{2}-\d{2})`), []string{"2006-01-02"}},
+		{regexp.MustCompile(`(\d{2}/\d{2}/\d{4})`), []string{"02/01/2006"}},
+	}
+)
+
+// normalize retire les accents et les espaces superflus pour simplifier les expressions régulières
+func normalize(message string) string {
+	replacer := strings.NewReplacer(
+		"é", "e", "è", "e", "ê", "e", "É", "E", "È", "E",
+		"à", "a", "â", "a", "À", "A",
+		"ç", "c", "Ç", "C",
+		"ô", "o", "î", "i", "û", "u", "ù", "u",
+		"\u00a0", " ", "\u202f", " ",
+		"’", "'",
+	)
+	return strings.Join(strings.Fields(replacer.Replace(message)), " ")
+}
+
+// findAmount retourne le premier montant capturé par l'expression
+func findAmount(pattern *regexp.Regexp, text string) (entity.Decimal, bool) {
+	match := pattern.FindStringSubmatch(text)
+	if match == nil {
+		return "", false
+	}
+	return parseAmount(match[1])
+}
+
+// findDate retourne la date annoncée dans le SMS, s'il y en a une
+func findDate(text string) *time.Time {
+	for _, candidate := range datePatterns {
+		match := candidate.pattern.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+		for _, layout := range candidate.layouts {
+			if date, err := time.Parse(layout, match[1]); err == nil {
+				return &date
+			}
+		}
+	}
+	return nil
+}
+
+// parseAmount lit un montant exact ; un séparateur suivi d'exactement deux chiffres en fin de
+// montant est décimal, les autres séparent les milliers
+func parseAmount(value string) (entity.Decimal, bool) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), " ", "")
+	value = strings.TrimRight(value, ".,")
+	if value == "" {
+		return "", false
+	}
+
+	decimals := ""
+	if index := strings.LastIndexAny(value, ".,"); index >= 0 && len(value)-index-1 == 2 {
+		decimals = value[index+1:]
+		value = value[:index]
+	}
+	value = strings.NewReplacer(",", "", ".", "").Replace(value)
+	if decimals != "" {
+		value += "." + decimals
+	}
+
+	amount, err := entity.ParseDecimal(value)
+	if err != nil {
+		return "", false
+	}
+	return amount, true
+}
@@ -0,0 +1,170 @@
+package mobilemoney
+
+import (
+	"backend/internal/domaine/entity"
+	"testing"
+)
+
+// parsedSMS résume une opération extraite d'un SMS pour les comparaisons
+type parsedSMS struct {
+	operator     string
+	kind         string
+	typ          string
+	amount       entity.Decimal
+	fees         entity.Decimal
+	counterparty string
+	reference    string
+	balance      entity.Decimal
+	date         string
+}
+
+func summarize(draft *Draft) parsedSMS {
+	summary := parsedSMS{
+		operator:     draft.Operator,
+		kind:         draft.Kind,
+		typ:          draft.Type,
+		amount:       draft.Amount,
+		fees:         draft.Fees,
+		counterparty: draft.Counterparty,
+		reference:    draft.Reference,
+	}
+	if draft.BalanceAfter != nil {
+		summary.balance = *draft.BalanceAfter
+	}
+	if draft.Date != nil {
+		summary.date = draft.Date.Format("2006-01-02 15:04:05")
+	}
+	return summary
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    parsedSMS
+	}{
+		{
+			name:    "MTN MoMo transfert reçu",
+			message: "Vous avez reçu 5000 FCFA de JEAN DUPONT (237670000000) le 2024-01-15 10:30:00. Message de l'expediteur: loyer. Votre nouveau solde est de: 25000 FCFA. Transaction Id: 1234567890.",
+			want: parsedSMS{operator: OperatorMTNMoMo, kind: KindReceived, typ: "income", amount: "5000", counterparty: "JEAN DUPONT (237670000000)",
+				reference: "1234567890", balance: "25000", date: "2024-01-15 10:30:00"},
+		},
+		{
+			name:    "MTN MoMo transfert envoyé en anglais",
+			message: "You have transferred 10,000 FCFA to MARIE NGO (237680000000) at 2024-01-16 08:15:22. Fee was 100 FCFA. Your new balance: 14,900 FCFA. Financial Transaction Id: 9876543210.",
+			want: parsedSMS{operator: OperatorMTNMoMo, kind: KindSent, typ: "expense", amount: "10000", fees: "100", counterparty: "MARIE NGO (237680000000)",
+				reference: "9876543210", balance: "14900", date: "2024-01-16 08:15:22"},
+		},
+		{
+			name:    "MTN MoMo paiement marchand",
+			message: "Votre paiement de 2 500 FCFA à ENEO CAMEROUN a été effectué avec succès le 2024-02-01 12:00:00. Frais: 0 FCFA. Nouveau solde: 12 400 FCFA. ID de transaction: 555444333.",
+			want: parsedSMS{operator: OperatorMTNMoMo, kind: KindPayment, typ: "expense", amount: "2500", fees: "0", counterparty: "ENEO CAMEROUN",
+				reference: "555444333", balance: "12400", date: "2024-02-01 12:00:00"},
+		},
+		{
+			name:    "MTN MoMo retrait chez un agent",
+			message: "MTN MoMo: Vous avez retiré 20 000 FCFA auprès de l'agent ETS BONHEUR (237650000000) le 03/02/2024 14:20. Frais: 350 FCFA. Nouveau solde: 5 150 FCFA. Transaction Id: 777888999",
+			want: parsedSMS{operator: OperatorMTNMoMo, kind: KindWithdrawal, typ: "expense", amount: "20000", fees: "350", counterparty: "ETS BONHEUR (237650000000)",
+				reference: "777888999", balance: "5150", date: "2024-02-03 14:20:00"},
+		},
+		{
+			name:    "MTN MoMo dépôt avec centimes",
+			message: "You have received a deposit of 15,000.00 FCFA from agent KAMGA SHOP on 2024-03-05 09:00:00. Your new balance: 30,000.00 FCFA. Transaction Id: 111222333.",
+			want: parsedSMS{operator: OperatorMTNMoMo, kind: KindDeposit, typ: "income", amount: "15000", counterparty: "KAMGA SHOP",
+				reference: "111222333", balance: "30000", date: "2024-03-05 09:00:00"},
+		},
+		{
+			name:    "Orange Money transfert envoyé",
+			message: "Transfert de 3000 FCFA vers le 237699112233 reussi. Frais: 50 FCFA. Nouveau solde: 7 950 FCFA. ID Trans: CI240115.1030.A12345. Orange Money",
+			want: parsedSMS{operator: OperatorOrangeMoney, kind: KindSent, typ: "expense", amount: "3000", fees: "50", counterparty: "237699112233",
+				reference: "CI240115.1030.A12345", balance: "7950"},
+		},
+		{
+			name:    "Orange Money transfert reçu",
+			message: "Vous avez reçu un transfert de 25 000 FCFA du 237699445566 JEANNE. Nouveau solde: 40 000 FCFA. ID Trans: PP240220.0815.B67890. Orange Money vous remercie.",
+			want: parsedSMS{operator: OperatorOrangeMoney, kind: KindReceived, typ: "income", amount: "25000", counterparty: "237699445566 JEANNE",
+				reference: "PP240220.0815.B67890", balance: "40000"},
+		},
+		{
+			name:    "Orange Money paiement",
+			message: "Orange Money: Paiement de 7 500 FCFA à CANAL+ effectué le 28/02/2024 19:45. Frais: 0 FCFA. Solde disponible: 2 450 FCFA. ID Trans: MP240228.1945.C11111",
+			want: parsedSMS{operator: OperatorOrangeMoney, kind: KindPayment, typ: "expense", amount: "7500", fees: "0", counterparty: "CANAL+",
+				reference: "MP240228.1945.C11111", balance: "2450", date: "2024-02-28 19:45:00"},
+		},
+		{
+			name:    "Orange Money retrait",
+			message: "Retrait de 10 000 FCFA chez l'agent BOUTIQUE ESPOIR le 01/03/2024 11:05. Frais: 200 FCFA. Nouveau solde: 4 800 FCFA. ID Trans: CO240301.1105.D22222. Orange Money",
+			want: parsedSMS{operator: OperatorOrangeMoney, kind: KindWithdrawal, typ: "expense", amount: "10000", fees: "200", counterparty: "BOUTIQUE ESPOIR",
+				reference: "CO240301.1105.D22222", balance: "4800", date: "2024-03-01 11:05:00"},
+		},
+		{
+			name:    "Orange Money dépôt",
+			message: "Depot de 50 000 FCFA effectue par l'agent AGENCE CENTRE. Nouveau solde: 60 000 FCFA. ID Trans: CI240305.0900.E33333. Orange Money",
+			want: parsedSMS{operator: OperatorOrangeMoney, kind: KindDeposit, typ: "income", amount: "50000", counterparty: "AGENCE CENTRE",
+				reference: "CI240305.0900.E33333", balance: "60000"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			draft, ok := Parse(tt.message)
+			if !ok {
+				t.Fatal("SMS non reconnu")
+			}
+			if got := summarize(draft); got != tt.want {
+				t.Errorf("Parse = %+v, attendu %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUnrecognized(t *testing.T) {
+	tests := map[string]string{
+		"publicité":               "Y'ello! Rechargez 1000 FCFA et recevez 500 Mo de bonus. MTN MoMo, partout avec vous.",
+		"transaction échouée":     "Orange Money: votre transaction de 5000 FCFA a echoue. Solde insuffisant.",
+		"sans marque d'opérateur": "Vous avez reçu 5000 FCFA de PAUL. Transaction: 123.",
+		"montant nul":             "Vous avez reçu 0 FCFA de JEAN. Transaction Id: 1.",
+		"code de confirmation":    "Votre code OTP est 482913. Ne le partagez pas.",
+		"message vide":            "",
+		"montant sans devise CFA": "Vous avez reçu 5000 EUR de JEAN DUPONT. Transaction Id: 42.",
+	}
+	for name, message := range tests {
+		if draft, ok := Parse(message); ok {
+			t.Errorf("%s : SMS reconnu à tort : %+v", name, summarize(draft))
+		}
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		value string
+		want  entity.Decimal
+		ok    bool
+	}{
+		{value: "5000", want: "5000", ok: true},
+		{value: "5 000", want: "5000", ok: true},
+		{value: "5.000", want: "5000", ok: true},
+		{value: "5,000", want: "5000", ok: true},
+		{value: "1.234.567", want: "1234567", ok: true},
+		{value: "5000.00", want: "5000", ok: true},
+		{value: "15,000.00", want: "15000", ok: true},
+		{value: "1.234,50", want: "1234.5", ok: true},
+		{value: "5000.", want: "5000", ok: true},
+		{value: "0", want: "0", ok: true},
+		{value: "", ok: false},
+		{value: " .", ok: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseAmount(tt.value)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseAmount(%q) = %q, %v, attendu %q, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	if got := normalize("  Vous avez reçu 5\u00a0000 FCFA   de  l’agent\u202fÉTS  "); got != "Vous avez recu 5 000 FCFA de l'agent ETS" {
+		t.Errorf("normalize = %q", got)
+	}
+}
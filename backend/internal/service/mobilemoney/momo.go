@@ -0,0 +1,33 @@
+package mobilemoney
+
+import "regexp"
+
+// counterpartyEnd marque la fin du nom du correspondant dans un SMS
+const counterpartyEnd = `(?:\s+(?:le|on|at)\s+\d|\s+a ete|\s+reussi|\s+effectue|\s+avec succes|\.(?:\s|$)|,\s|;|$)`
+
+var (
+	// momoMarker identifie un SMS MTN Mobile Money
+	momoMarker = regexp.MustCompile(`(?i)\bmomo\b|\bmtn\b|transaction id|id de (?:la )?transaction`)
+	// momoReference capture l'identifiant de transaction MTN MoMo
+	momoReference = regexp.MustCompile(`(?i)(?:financial transaction id|transaction id|id de (?:la )?transaction|id transaction)\s*:?\s*([0-9A-Za-z.]+)`)
+)
+
+// momoParsers retourne les formats de SMS MTN Mobile Money (français et anglais)
+func momoParsers() []Parser {
+	return []Parser{
+		newMarkedParser(OperatorMTNMoMo, KindDeposit, momoMarker, momoReference,
+			`(?i)(?:vous avez recu un depot de|depot de|you have received a deposit of|deposit of)\s+`+amountExpr+
+				`(?:\s+(?:effectue par|par|de|from|by)\s+(?:l'agent\s+|agent\s+)?(?P<counterparty>.+?)`+counterpartyEnd+`)?`),
+		newMarkedParser(OperatorMTNMoMo, KindReceived, momoMarker, momoReference,
+			`(?i)(?:vous avez recu|you have received)\s+`+amountExpr+`\s+(?:de|from)\s+(?P<counterparty>.+?)`+counterpartyEnd),
+		newMarkedParser(OperatorMTNMoMo, KindSent, momoMarker, momoReference,
+			`(?i)(?:vous avez transfere|vous avez envoye|votre transfert de|you have transferred|you have sent)\s+`+amountExpr+
+				`\s+(?:a|vers|to)\s+(?P<counterparty>.+?)`+counterpartyEnd),
+		newMarkedParser(OperatorMTNMoMo, KindPayment, momoMarker, momoReference,
+			`(?i)(?:votre paiement de|vous avez paye|your payment of|you have paid)\s+`+amountExpr+
+				`\s+(?:a|pour|to|for)\s+(?P<counterparty>.+?)`+counterpartyEnd),
+		newMarkedParser(OperatorMTNMoMo, KindWithdrawal, momoMarker, momoReference,
+			`(?i)(?:vous avez retire|retrait de|you have withdrawn|withdrawal of)\s+`+amountExpr+
+				`(?:\s+(?:aupres de|chez|de|from|at)\s+(?:l'agent\s+|agent\s+)?(?P<counterparty>.+?)`+counterpartyEnd+`)?`),
+	}
+}
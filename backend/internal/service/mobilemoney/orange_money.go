@@ -0,0 +1,30 @@
+package mobilemoney
+
+import "regexp"
+
+var (
+	// orangeMoneyMarker identifie un SMS Orange Money
+	orangeMoneyMarker = regexp.MustCompile(`(?i)orange|\bom\b|id trans`)
+	// orangeMoneyReference capture l'identifiant de transaction Orange Money (ex: CI240115.1030.A12345)
+	orangeMoneyReference = regexp.MustCompile(`(?i)(?:id trans(?:action)?|txn id|reference)\s*:?\s*([0-9A-Za-z.]+)`)
+)
+
+// orangeMoneyParsers retourne les formats de SMS Orange Money
+func orangeMoneyParsers() []Parser {
+	return []Parser{
+		newMarkedParser(OperatorOrangeMoney, KindDeposit, orangeMoneyMarker, orangeMoneyReference,
+			`(?i)(?:vous avez recu un depot de|depot de)\s+`+amountExpr+
+				`(?:\s+(?:recu de|effectue par|par|de)\s+(?:l'agent\s+|agent\s+)?(?P<counterparty>.+?)`+counterpartyEnd+`)?`),
+		newMarkedParser(OperatorOrangeMoney, KindReceived, orangeMoneyMarker, orangeMoneyReference,
+			`(?i)(?:vous avez recu un transfert de|transfert recu de|vous avez recu)\s+`+amountExpr+
+				`\s+(?:du|de la part de|de)\s+(?:le\s+)?(?P<counterparty>.+?)`+counterpartyEnd),
+		newMarkedParser(OperatorOrangeMoney, KindSent, orangeMoneyMarker, orangeMoneyReference,
+			`(?i)(?:transfert de|vous avez transfere|vous avez envoye)\s+`+amountExpr+
+				`\s+(?:vers|au|a)\s+(?:le\s+)?(?P<counterparty>.+?)`+counterpartyEnd),
+		newMarkedParser(OperatorOrangeMoney, KindPayment, orangeMoneyMarker, orangeMoneyReference,
+			`(?i)(?:paiement de|vous avez paye)\s+`+amountExpr+`\s+(?:au|a|pour|chez)\s+(?P<counterparty>.+?)`+counterpartyEnd),
+		newMarkedParser(OperatorOrangeMoney, KindWithdrawal, orangeMoneyMarker, orangeMoneyReference,
+			`(?i)(?:retrait de|vous avez retire)\s+`+amountExpr+
+				`(?:\s+(?:aupres de|chez|de)\s+(?:l'agent\s+|agent\s+)?(?P<counterparty>.+?)`+counterpartyEnd+`)?`),
+	}
+}
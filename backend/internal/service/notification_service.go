@@ -197,3 +197,27 @@ func (s *NotificationService) SendGoalAchievement(ctx context.Context, userID uu
 
 	return s.SendNotificationToUser(ctx, userID, title, message)
 }
+
+// SendLoanInstallmentReminder envoie le rappel d'une échéance d'emprunt à venir
+func (s *NotificationService) SendLoanInstallmentReminder(ctx context.Context, userID uuid.UUID, loanName string, payment entity.Money, dueDate time.Time) error {
+	title := "Échéance à venir"
+	message := fmt.Sprintf("Votre échéance de %s pour '%s' est due le %s", payment, loanName, dueDate.Format("02/01/2006"))
+
+	return s.SendNotificationToUser(ctx, userID, title, message)
+}
+
+// SendLoanInstallmentMissed envoie une alerte d'échéance d'emprunt impayée
+func (s *NotificationService) SendLoanInstallmentMissed(ctx context.Context, userID uuid.UUID, loanName string, payment entity.Money, dueDate time.Time) error {
+	title := "Échéance impayée"
+	message := fmt.Sprintf("Votre échéance de %s pour '%s' du %s n'a pas été réglée", payment, loanName, dueDate.Format("02/01/2006"))
+
+	return s.SendNotificationToUser(ctx, userID, title, message)
+}
+
+// SendTontineContributionReminder envoie le rappel d'une cotisation de tontine à venir
+func (s *NotificationService) SendTontineContributionReminder(ctx context.Context, userID uuid.UUID, tontineName string, amount entity.Money, dueDate time.Time) error {
+	title := "Cotisation à venir"
+	message := fmt.Sprintf("Votre cotisation de %s à la tontine '%s' est due le %s", amount, tontineName, dueDate.Format("02/01/2006"))
+
+	return s.SendNotificationToUser(ctx, userID, title, message)
+}
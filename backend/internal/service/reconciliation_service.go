@@ -0,0 +1,496 @@
+package service
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/internal/domaine/repository"
+	"backend/pkg/logger"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Statuts d'un rapprochement
+const (
+	reconciliationStatusOpen      = "open"
+	reconciliationStatusCompleted = "completed"
+	reconciliationStatusCancelled = "cancelled"
+)
+
+// ReconciliationService gère le rapprochement des comptes avec leurs relevés : l'utilisateur saisit le solde et la
+// date du relevé, pointe les transactions en attente, enregistre l'écart restant puis clôture, ce qui rapproche les
+// transactions passées jusqu'à la date du relevé et verrouille la période
+type ReconciliationService struct {
+	reconciliationRepo repository.ReconciliationRepository
+	accountRepo        repository.AccountRepository
+	transactionRepo    repository.TransactionRepository
+	transactionService *TransactionService
+	txManager          repository.TxManager
+	logger             logger.Logger
+}
+
+// NewReconciliationService crée une nouvelle instance de ReconciliationService
+func NewReconciliationService(
+	reconciliationRepo repository.ReconciliationRepository,
+	accountRepo repository.AccountRepository,
+	transactionRepo repository.TransactionRepository,
+	transactionService *TransactionService,
+	txManager repository.TxManager,
+	logger logger.Logger,
+) *ReconciliationService {
+	return &ReconciliationService{
+		reconciliationRepo: reconciliationRepo,
+		accountRepo:        accountRepo,
+		transactionRepo:    transactionRepo,
+		transactionService: transactionService,
+		txManager:          txManager,
+		logger:             logger,
+	}
+}
+
+// StartReconciliation ouvre le rapprochement d'un compte avec un relevé, postérieur à la période déjà rapprochée
+func (s *ReconciliationService) StartReconciliation(ctx context.Context, userID, accountID uuid.UUID, req entity.StartReconciliationRequest) (*entity.ReconciliationSummaryResponse, error) {
+	statementDate := truncateToDay(req.StatementDate)
+	if statementDate.IsZero() {
+		return nil, fmt.Errorf("%w: la date du relevé est requise", entity.ErrInvalidReconciliation)
+	}
+	if isFutureDate(statementDate, time.Now()) {
+		return nil, fmt.Errorf("%w: la date du relevé ne peut pas être dans le futur", entity.ErrInvalidReconciliation)
+	}
+
+	var summary *entity.ReconciliationSummaryResponse
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		account, err := s.lockAccount(ctx, userID, accountID)
+		if err != nil {
+			return err
+		}
+		if inLockedPeriod(account, statementDate) {
+			return fmt.Errorf("%w: le compte est déjà rapproché jusqu'au %s", entity.ErrInvalidReconciliation, account.ReconciledThrough.Format("2006-01-02"))
+		}
+		open, err := s.reconciliationRepo.GetOpenByAccountID(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		if open != nil {
+			return fmt.Errorf("%w: %s", entity.ErrReconciliationInProgress, open.ID)
+		}
+
+		statementBalance, err := req.StatementBalance.Money(account.Currency)
+		if err != nil {
+			return err
+		}
+
+		reconciliation := &entity.Reconciliation{
+			ID:               uuid.New(),
+			UserID:           userID,
+			AccountID:        accountID,
+			StatementDate:    statementDate,
+			StatementBalance: statementBalance,
+			ClearedBalance:   entity.NewMoney(0, account.Currency),
+			Currency:         account.Currency,
+			Status:           reconciliationStatusOpen,
+			CreatedAt:        time.Now(),
+			UpdatedAt:        time.Now(),
+		}
+		if err := s.reconciliationRepo.Create(ctx, reconciliation); err != nil {
+			return err
+		}
+
+		summary, err = s.summarize(ctx, account, reconciliation)
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Erreur ouverture rapprochement", logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Rapprochement ouvert",
+		logger.String("reconciliation_id", summary.Reconciliation.ID.String()),
+		logger.String("account_id", accountID.String()),
+		logger.String("difference", summary.Difference.String()),
+	)
+
+	return summary, nil
+}
+
+// GetReconciliations récupère les rapprochements d'un compte
+func (s *ReconciliationService) GetReconciliations(ctx context.Context, userID, accountID uuid.UUID) ([]*entity.Reconciliation, error) {
+	if _, err := s.getAccount(ctx, userID, accountID); err != nil {
+		return nil, err
+	}
+	return s.reconciliationRepo.GetByAccountID(ctx, accountID)
+}
+
+// GetReconciliation récupère l'état d'un rapprochement : solde calculé, écart et transactions en attente
+func (s *ReconciliationService) GetReconciliation(ctx context.Context, userID, accountID, reconciliationID uuid.UUID) (*entity.ReconciliationSummaryResponse, error) {
+	account, err := s.getAccount(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	reconciliation, err := s.reconciliationRepo.GetByID(ctx, reconciliationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkReconciliation(reconciliation, userID, accountID); err != nil {
+		return nil, err
+	}
+	return s.summarize(ctx, account, reconciliation)
+}
+
+// ClearItems pointe comme passées des transactions en attente du compte datées jusqu'au relevé.
+// Les transactions déjà passées ou rapprochées sont ignorées.
+func (s *ReconciliationService) ClearItems(ctx context.Context, userID, accountID, reconciliationID uuid.UUID, req entity.ClearReconciliationItemsRequest) (*entity.ReconciliationSummaryResponse, error) {
+	if len(req.TransactionIDs) == 0 {
+		return nil, fmt.Errorf("%w: aucune transaction à pointer", entity.ErrInvalidReconciliation)
+	}
+
+	var summary *entity.ReconciliationSummaryResponse
+	err := s.txManager.WithinTransaction(withChangeSource(ctx, changeSourceReconciliation), func(ctx context.Context) error {
+		reconciliation, err := s.lockOpenReconciliation(ctx, userID, accountID, reconciliationID)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range uniqueIDs(req.TransactionIDs) {
+			transaction, err := s.transactionRepo.GetByIDForUpdate(ctx, id)
+			if err != nil || transaction.UserID != userID || transaction.AccountID == nil || *transaction.AccountID != accountID {
+				return fmt.Errorf("%w: transaction %s non trouvée sur le compte", entity.ErrInvalidReconciliation, id)
+			}
+			if truncateToDay(transaction.Date).After(reconciliation.StatementDate) {
+				return fmt.Errorf("%w: la transaction %s est postérieure au relevé", entity.ErrInvalidReconciliation, id)
+			}
+			switch transaction.Status {
+			case transactionStatusCleared, transactionStatusReconciled:
+				continue
+			case transactionStatusScheduled:
+				return fmt.Errorf("%w: la transaction %s est planifiée", entity.ErrInvalidReconciliation, id)
+			}
+			if _, err := s.transactionService.changeStatus(ctx, transaction, transactionStatusCleared); err != nil {
+				return err
+			}
+		}
+
+		account, err := s.getAccount(ctx, userID, accountID)
+		if err != nil {
+			return err
+		}
+		summary, err = s.summarize(ctx, account, reconciliation)
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Erreur pointage des transactions du rapprochement", logger.Error(err))
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// PostAdjustment enregistre l'écart restant entre le relevé et le solde calculé comme une transaction passée
+// datée du relevé, ce qui équilibre le rapprochement
+func (s *ReconciliationService) PostAdjustment(ctx context.Context, userID, accountID, reconciliationID uuid.UUID, req entity.ReconciliationAdjustmentRequest) (*entity.ReconciliationSummaryResponse, error) {
+	var summary *entity.ReconciliationSummaryResponse
+	err := s.txManager.WithinTransaction(withChangeSource(ctx, changeSourceReconciliation), func(ctx context.Context) error {
+		reconciliation, err := s.lockOpenReconciliation(ctx, userID, accountID, reconciliationID)
+		if err != nil {
+			return err
+		}
+		account, err := s.lockAccount(ctx, userID, accountID)
+		if err != nil {
+			return err
+		}
+		current, err := s.summarize(ctx, account, reconciliation)
+		if err != nil {
+			return err
+		}
+
+		adjustment, err := s.transactionService.CreateReconciliationAdjustment(ctx, userID, accountID, current.Difference, reconciliation.StatementDate, req.Description)
+		if err != nil {
+			return err
+		}
+		reconciliation.AdjustmentTransactionID = &adjustment.ID
+		reconciliation.UpdatedAt = time.Now()
+		if err := s.reconciliationRepo.Update(ctx, reconciliation); err != nil {
+			return err
+		}
+
+		if account, err = s.getAccount(ctx, userID, accountID); err != nil {
+			return err
+		}
+		summary, err = s.summarize(ctx, account, reconciliation)
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Erreur ajustement du rapprochement", logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Ajustement de rapprochement enregistré",
+		logger.String("reconciliation_id", reconciliationID.String()),
+		logger.String("transaction_id", summary.Reconciliation.AdjustmentTransactionID.String()),
+	)
+
+	return summary, nil
+}
+
+// CompleteReconciliation clôture un rapprochement équilibré : les transactions passées du compte datées jusqu'au
+// relevé (et l'autre jambe de leurs transferts) sont rapprochées, puis la période est verrouillée. Les transactions
+// encore en attente restent en attente et pourront être pointées plus tard.
+func (s *ReconciliationService) CompleteReconciliation(ctx context.Context, userID, accountID, reconciliationID uuid.UUID) (*entity.ReconciliationSummaryResponse, error) {
+	var summary *entity.ReconciliationSummaryResponse
+	err := s.txManager.WithinTransaction(withChangeSource(ctx, changeSourceReconciliation), func(ctx context.Context) error {
+		reconciliation, err := s.lockOpenReconciliation(ctx, userID, accountID, reconciliationID)
+		if err != nil {
+			return err
+		}
+
+		// Les transactions sont verrouillées avant les comptes, comme lors d'une modification
+		cleared, err := s.transactionRepo.GetByAccountAndStatus(ctx, accountID, transactionStatusCleared, reconciliation.StatementDate)
+		if err != nil {
+			return err
+		}
+		reconciled := 0
+		for _, item := range cleared {
+			transaction, err := s.transactionRepo.GetByIDForUpdate(ctx, item.ID)
+			if err != nil {
+				return err
+			}
+			if transaction.Status != transactionStatusCleared {
+				continue
+			}
+			if _, err := s.transactionService.changeStatus(ctx, transaction, transactionStatusReconciled); err != nil {
+				return err
+			}
+			reconciled++
+		}
+
+		// Le rapprochement ne change pas le solde : l'écart est vérifié une fois le compte verrouillé,
+		// un rapprochement déséquilibré étant entièrement annulé
+		account, err := s.lockAccount(ctx, userID, accountID)
+		if err != nil {
+			return err
+		}
+		current, err := s.summarize(ctx, account, reconciliation)
+		if err != nil {
+			return err
+		}
+		if !current.Difference.IsZero() {
+			return fmt.Errorf("%w: écart de %s, pointer les transactions ou enregistrer un ajustement", entity.ErrReconciliationUnbalanced, current.Difference)
+		}
+
+		account.ReconciledThrough = &reconciliation.StatementDate
+		account.UpdatedAt = time.Now()
+		if err := s.accountRepo.Update(ctx, account); err != nil {
+			return fmt.Errorf("erreur verrouillage de la période rapprochée: %w", err)
+		}
+
+		now := time.Now()
+		reconciliation.Status = reconciliationStatusCompleted
+		reconciliation.ClearedBalance = current.ComputedBalance
+		reconciliation.ReconciledCount = reconciled
+		reconciliation.CompletedAt = &now
+		reconciliation.UpdatedAt = now
+		if err := s.reconciliationRepo.Update(ctx, reconciliation); err != nil {
+			return err
+		}
+
+		summary, err = s.summarize(ctx, account, reconciliation)
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Erreur clôture du rapprochement", logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Rapprochement clôturé",
+		logger.String("reconciliation_id", reconciliationID.String()),
+		logger.String("account_id", accountID.String()),
+		logger.Int("reconciled", summary.Reconciliation.ReconciledCount),
+	)
+
+	return summary, nil
+}
+
+// CancelReconciliation abandonne un rapprochement en cours. Les transactions pointées et l'ajustement sont conservés.
+func (s *ReconciliationService) CancelReconciliation(ctx context.Context, userID, accountID, reconciliationID uuid.UUID) error {
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		reconciliation, err := s.lockOpenReconciliation(ctx, userID, accountID, reconciliationID)
+		if err != nil {
+			return err
+		}
+		reconciliation.Status = reconciliationStatusCancelled
+		reconciliation.UpdatedAt = time.Now()
+		return s.reconciliationRepo.Update(ctx, reconciliation)
+	})
+	if err != nil {
+		s.logger.Error("Erreur abandon du rapprochement", logger.Error(err))
+		return err
+	}
+
+	s.logger.Info("Rapprochement abandonné", logger.String("reconciliation_id", reconciliationID.String()))
+	return nil
+}
+
+// ReopenReconciliation rouvre le dernier rapprochement clôturé d'un compte : la période verrouillée revient au
+// rapprochement précédent. Les transactions rapprochées le restent et se déverrouillent par leur statut.
+func (s *ReconciliationService) ReopenReconciliation(ctx context.Context, userID, accountID, reconciliationID uuid.UUID) (*entity.ReconciliationSummaryResponse, error) {
+	var summary *entity.ReconciliationSummaryResponse
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		reconciliation, err := s.reconciliationRepo.GetByIDForUpdate(ctx, reconciliationID)
+		if err != nil {
+			return err
+		}
+		if err := checkReconciliation(reconciliation, userID, accountID); err != nil {
+			return err
+		}
+		if reconciliation.Status != reconciliationStatusCompleted {
+			return fmt.Errorf("%w: seul un rapprochement clôturé peut être rouvert", entity.ErrInvalidReconciliation)
+		}
+		account, err := s.lockAccount(ctx, userID, accountID)
+		if err != nil {
+			return err
+		}
+
+		// Seul le dernier rapprochement clôturé, qui fixe la période verrouillée, peut être rouvert
+		reconciliations, err := s.reconciliationRepo.GetByAccountID(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		var previous *time.Time
+		for _, other := range reconciliations {
+			switch {
+			case other.Status == reconciliationStatusOpen:
+				return fmt.Errorf("%w: %s", entity.ErrReconciliationInProgress, other.ID)
+			case other.Status != reconciliationStatusCompleted || other.ID == reconciliation.ID:
+				continue
+			case !other.StatementDate.Before(reconciliation.StatementDate):
+				return fmt.Errorf("%w: seul le dernier rapprochement clôturé peut être rouvert", entity.ErrInvalidReconciliation)
+			case previous == nil || other.StatementDate.After(*previous):
+				date := other.StatementDate
+				previous = &date
+			}
+		}
+
+		account.ReconciledThrough = previous
+		account.UpdatedAt = time.Now()
+		if err := s.accountRepo.Update(ctx, account); err != nil {
+			return fmt.Errorf("erreur déverrouillage de la période rapprochée: %w", err)
+		}
+
+		reconciliation.Status = reconciliationStatusOpen
+		reconciliation.ClearedBalance = entity.NewMoney(0, reconciliation.Currency)
+		reconciliation.ReconciledCount = 0
+		reconciliation.CompletedAt = nil
+		reconciliation.UpdatedAt = time.Now()
+		if err := s.reconciliationRepo.Update(ctx, reconciliation); err != nil {
+			return err
+		}
+
+		summary, err = s.summarize(ctx, account, reconciliation)
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Erreur réouverture du rapprochement", logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Rapprochement rouvert", logger.String("reconciliation_id", reconciliationID.String()))
+	return summary, nil
+}
+
+// summarize calcule l'état d'un rapprochement : le solde calculé à la date du relevé est le solde courant du compte
+// moins l'effet des transactions passées datées après le relevé ; les transactions en attente jusqu'au relevé
+// restent à pointer
+func (s *ReconciliationService) summarize(ctx context.Context, account *entity.Account, reconciliation *entity.Reconciliation) (*entity.ReconciliationSummaryResponse, error) {
+	effectAfter, err := s.transactionRepo.GetClearedEffectAfter(ctx, account.ID, reconciliation.StatementDate)
+	if err != nil {
+		return nil, err
+	}
+	computed := account.Balance.Sub(entity.NewMoney(effectAfter, account.Currency))
+
+	uncleared, err := s.transactionRepo.GetByAccountAndStatus(ctx, account.ID, transactionStatusPending, reconciliation.StatementDate)
+	if err != nil {
+		return nil, err
+	}
+	unclearedTotal := entity.NewMoney(0, account.Currency)
+	for _, transaction := range uncleared {
+		unclearedTotal = unclearedTotal.Add(balanceDelta(transaction))
+	}
+
+	return &entity.ReconciliationSummaryResponse{
+		Reconciliation:  reconciliation,
+		ComputedBalance: computed,
+		Difference:      reconciliation.StatementBalance.Sub(computed),
+		UnclearedTotal:  unclearedTotal,
+		Uncleared:       uncleared,
+	}, nil
+}
+
+// getAccount récupère un compte de l'utilisateur
+func (s *ReconciliationService) getAccount(ctx context.Context, userID, accountID uuid.UUID) (*entity.Account, error) {
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil || account.UserID != userID {
+		return nil, entity.ErrAccountNotFound
+	}
+	return account, nil
+}
+
+// lockAccount récupère un compte de l'utilisateur en verrouillant sa ligne.
+// Doit être appelé à l'intérieur de TxManager.WithinTransaction.
+func (s *ReconciliationService) lockAccount(ctx context.Context, userID, accountID uuid.UUID) (*entity.Account, error) {
+	account, err := s.accountRepo.GetByIDForUpdate(ctx, accountID)
+	if err != nil || account.UserID != userID {
+		return nil, entity.ErrAccountNotFound
+	}
+	return account, nil
+}
+
+// lockOpenReconciliation verrouille un rapprochement en cours du compte.
+// Doit être appelé à l'intérieur de TxManager.WithinTransaction.
+func (s *ReconciliationService) lockOpenReconciliation(ctx context.Context, userID, accountID, reconciliationID uuid.UUID) (*entity.Reconciliation, error) {
+	reconciliation, err := s.reconciliationRepo.GetByIDForUpdate(ctx, reconciliationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkReconciliation(reconciliation, userID, accountID); err != nil {
+		return nil, err
+	}
+	if reconciliation.Status != reconciliationStatusOpen {
+		return nil, fmt.Errorf("%w: statut %s", entity.ErrReconciliationClosed, reconciliation.Status)
+	}
+	return reconciliation, nil
+}
+
+// checkReconciliation vérifie qu'un rapprochement porte sur le compte donné de l'utilisateur
+func checkReconciliation(reconciliation *entity.Reconciliation, userID, accountID uuid.UUID) error {
+	if reconciliation.UserID != userID || reconciliation.AccountID != accountID {
+		return fmt.Errorf("%w: %s", entity.ErrReconciliationNotFound, reconciliation.ID)
+	}
+	return nil
+}
+
+// inLockedPeriod indique si une date tombe dans la période rapprochée et verrouillée d'un compte
+func inLockedPeriod(account *entity.Account, date time.Time) bool {
+	return account.ReconciledThrough != nil && !truncateToDay(date).After(truncateToDay(*account.ReconciledThrough))
+}
+
+// checkPeriodLock refuse une écriture qui changerait l'effet d'une transaction dans la période verrouillée de son
+// compte : before est la version existante (nil pour une création ou une restauration), after la nouvelle (nil pour
+// une suppression). Les comptes doivent avoir été verrouillés via lockAccounts. Une modification sans effet sur le
+// solde (libellé, catégorie, étiquettes) reste permise ; le statut se change par SetTransactionStatus.
+func checkPeriodLock(accounts map[uuid.UUID]*entity.Account, before, after *entity.Transaction) error {
+	if before != nil && after != nil && sameAccount(before.AccountID, after.AccountID) && before.Status == after.Status &&
+		truncateToDay(before.Date).Equal(truncateToDay(after.Date)) && balanceDelta(before) == balanceDelta(after) {
+		return nil
+	}
+	for _, transaction := range []*entity.Transaction{before, after} {
+		if transaction == nil || transaction.AccountID == nil {
+			continue
+		}
+		account, ok := accounts[*transaction.AccountID]
+		if ok && inLockedPeriod(account, transaction.Date) {
+			return fmt.Errorf("%w: compte rapproché jusqu'au %s", entity.ErrReconciliationPeriodLocked, account.ReconciledThrough.Format("2006-01-02"))
+		}
+	}
+	return nil
+}
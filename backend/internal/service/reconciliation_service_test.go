@@ -0,0 +1,87 @@
+package service
+
+import (
+	"backend/internal/domaine/entity"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestInLockedPeriod(t *testing.T) {
+	reconciledThrough := time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC)
+	locked := &entity.Account{ReconciledThrough: &reconciledThrough}
+
+	tests := []struct {
+		name    string
+		account *entity.Account
+		date    time.Time
+		want    bool
+	}{
+		{name: "avant la date rapprochée", account: locked, date: day(2025, time.January, 15), want: true},
+		{name: "jour rapproché", account: locked, date: time.Date(2025, time.January, 31, 23, 59, 0, 0, time.UTC), want: true},
+		{name: "lendemain", account: locked, date: day(2025, time.February, 1), want: false},
+		{name: "compte jamais rapproché", account: &entity.Account{}, date: day(2020, time.January, 1), want: false},
+	}
+
+	for _, tt := range tests {
+		if got := inLockedPeriod(tt.account, tt.date); got != tt.want {
+			t.Errorf("%s : inLockedPeriod = %v, attendu %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckPeriodLock(t *testing.T) {
+	reconciledThrough := day(2025, time.January, 31)
+	lockedID, openID := uuid.New(), uuid.New()
+	accounts := map[uuid.UUID]*entity.Account{
+		lockedID: {ID: lockedID, ReconciledThrough: &reconciledThrough},
+		openID:   {ID: openID},
+	}
+	transaction := func(accountID uuid.UUID, date time.Time, minor int64) *entity.Transaction {
+		return &entity.Transaction{
+			AccountID: &accountID,
+			Type:      "expense",
+			Amount:    entity.NewMoney(minor, "EUR"),
+			Date:      date,
+			Status:    transactionStatusCleared,
+		}
+	}
+	lockedDate, openDate := day(2025, time.January, 20), day(2025, time.February, 10)
+	existing := transaction(lockedID, lockedDate, 1500)
+	withStatus := func(status string) *entity.Transaction {
+		updated := *existing
+		updated.Status = status
+		return &updated
+	}
+	withDescription := *existing
+	withDescription.Description = "Nouveau libellé"
+
+	tests := []struct {
+		name       string
+		before     *entity.Transaction
+		after      *entity.Transaction
+		wantLocked bool
+	}{
+		{name: "création dans la période verrouillée", after: existing, wantLocked: true},
+		{name: "création après la période", after: transaction(lockedID, openDate, 1500)},
+		{name: "création sur un compte non rapproché", after: transaction(openID, lockedDate, 1500)},
+		{name: "suppression dans la période verrouillée", before: existing, wantLocked: true},
+		{name: "libellé modifié", before: existing, after: &withDescription},
+		{name: "montant modifié", before: existing, after: transaction(lockedID, lockedDate, 2000), wantLocked: true},
+		{name: "sortie de la période verrouillée", before: existing, after: transaction(lockedID, openDate, 1500), wantLocked: true},
+		{name: "entrée dans la période verrouillée", before: transaction(lockedID, openDate, 1500), after: existing, wantLocked: true},
+		{name: "changement de compte", before: existing, after: transaction(openID, lockedDate, 1500), wantLocked: true},
+		{name: "statut modifié", before: existing, after: withStatus(transactionStatusPending), wantLocked: true},
+		{name: "modification hors période", before: transaction(lockedID, openDate, 1500), after: transaction(lockedID, openDate, 2500)},
+		{name: "compte non rapproché", before: transaction(openID, lockedDate, 1500), after: transaction(openID, lockedDate, 2500)},
+	}
+
+	for _, tt := range tests {
+		err := checkPeriodLock(accounts, tt.before, tt.after)
+		if locked := errors.Is(err, entity.ErrReconciliationPeriodLocked); locked != tt.wantLocked || (err != nil && !locked) {
+			t.Errorf("%s : checkPeriodLock = %v, verrou attendu : %v", tt.name, err, tt.wantLocked)
+		}
+	}
+}
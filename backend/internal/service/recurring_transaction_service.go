@@ -0,0 +1,635 @@
+package service
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/internal/domaine/repository"
+	"backend/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// recurringBatchSize est le nombre maximum de modèles traités par passage du planificateur
+	recurringBatchSize = 500
+	// recurringMaxCatchUp borne le nombre d'occurrences rattrapées en une fois pour un même modèle
+	recurringMaxCatchUp = 366
+)
+
+// RecurringTransactionService gère les modèles de transactions récurrentes et la création de leurs occurrences
+type RecurringTransactionService struct {
+	recurringRepo      repository.RecurringTransactionRepository
+	accountRepo        repository.AccountRepository
+	categoryRepo       repository.CategoryRepository
+	transactionService *TransactionService
+	txManager          repository.TxManager
+	logger             logger.Logger
+}
+
+// NewRecurringTransactionService crée une nouvelle instance de RecurringTransactionService
+func NewRecurringTransactionService(
+	recurringRepo repository.RecurringTransactionRepository,
+	accountRepo repository.AccountRepository,
+	categoryRepo repository.CategoryRepository,
+	transactionService *TransactionService,
+	txManager repository.TxManager,
+	logger logger.Logger,
+) *RecurringTransactionService {
+	return &RecurringTransactionService{
+		recurringRepo:      recurringRepo,
+		accountRepo:        accountRepo,
+		categoryRepo:       categoryRepo,
+		transactionService: transactionService,
+		txManager:          txManager,
+		logger:             logger,
+	}
+}
+
+// CreateRecurringTransaction crée un modèle de transaction récurrente.
+// Les occurrences déjà échues (date de début passée) sont créées immédiatement.
+func (s *RecurringTransactionService) CreateRecurringTransaction(ctx context.Context, userID uuid.UUID, req entity.CreateRecurringTransactionRequest) (*entity.RecurringTransaction, error) {
+	if req.Type != "income" && req.Type != "expense" {
+		return nil, fmt.Errorf("%w: type invalide: %s", entity.ErrInvalidRecurringTransactionData, req.Type)
+	}
+	if req.Description == "" {
+		return nil, fmt.Errorf("%w: la description est requise", entity.ErrInvalidRecurringTransactionData)
+	}
+	if req.StartDate.IsZero() {
+		return nil, fmt.Errorf("%w: la date de début est requise", entity.ErrInvalidRecurringTransactionData)
+	}
+
+	account, err := s.checkAccountAndCategory(ctx, userID, &req.AccountID, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := s.parseAmount(req.Amount, account.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	recurring := &entity.RecurringTransaction{
+		ID:             uuid.New(),
+		UserID:         userID,
+		AccountID:      req.AccountID,
+		CategoryID:     req.CategoryID,
+		Type:           req.Type,
+		Amount:         amount,
+		Currency:       account.Currency,
+		Description:    req.Description,
+		Frequency:      req.Frequency,
+		Interval:       req.Interval,
+		DayOfMonth:     req.DayOfMonth,
+		StartDate:      truncateToDay(req.StartDate),
+		EndDate:        truncateDatePtr(req.EndDate),
+		MaxOccurrences: req.MaxOccurrences,
+		Status:         "active",
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+	if recurring.Interval == 0 {
+		recurring.Interval = 1
+	}
+	if err := validateRecurringSchedule(recurring); err != nil {
+		return nil, err
+	}
+
+	scheduleNextOccurrence(recurring, firstOccurrence(recurring))
+	if recurring.Status == "completed" {
+		return nil, fmt.Errorf("%w: aucune occurrence avant la date de fin", entity.ErrInvalidRecurringTransactionData)
+	}
+
+	if err := s.recurringRepo.Create(ctx, recurring); err != nil {
+		s.logger.Error("Erreur création transaction récurrente", logger.Error(err))
+		return nil, fmt.Errorf("erreur création transaction récurrente: %w", err)
+	}
+
+	s.logger.Info("Transaction récurrente créée avec succès",
+		logger.String("recurring_id", recurring.ID.String()),
+		logger.String("user_id", userID.String()),
+		logger.String("frequency", recurring.Frequency),
+	)
+
+	// Rattraper les occurrences échues sans attendre le prochain passage du planificateur
+	if _, err := s.processRecurring(ctx, recurring.ID, truncateToDay(time.Now())); err != nil {
+		s.logger.Warn("Erreur création des occurrences échues",
+			logger.String("recurring_id", recurring.ID.String()),
+			logger.Error(err),
+		)
+	}
+
+	return s.GetRecurringTransaction(ctx, userID, recurring.ID)
+}
+
+// GetRecurringTransaction récupère un modèle de transaction récurrente par son ID
+func (s *RecurringTransactionService) GetRecurringTransaction(ctx context.Context, userID, recurringID uuid.UUID) (*entity.RecurringTransaction, error) {
+	recurring, err := s.recurringRepo.GetByID(ctx, recurringID)
+	if err != nil {
+		return nil, err
+	}
+	if recurring.UserID != userID {
+		return nil, entity.ErrRecurringTransactionNotFound
+	}
+	return recurring, nil
+}
+
+// GetRecurringTransactions récupère les modèles de transactions récurrentes de l'utilisateur
+func (s *RecurringTransactionService) GetRecurringTransactions(ctx context.Context, userID uuid.UUID) ([]*entity.RecurringTransaction, error) {
+	recurrings, err := s.recurringRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("Erreur récupération transactions récurrentes", logger.Error(err))
+		return nil, err
+	}
+	return recurrings, nil
+}
+
+// UpdateRecurringTransaction modifie les occurrences futures d'une transaction récurrente.
+// Les transactions déjà créées ne sont pas modifiées.
+func (s *RecurringTransactionService) UpdateRecurringTransaction(ctx context.Context, userID, recurringID uuid.UUID, req entity.UpdateRecurringTransactionRequest) (*entity.RecurringTransaction, error) {
+	if req.Description != nil && *req.Description == "" {
+		return nil, fmt.Errorf("%w: la description est requise", entity.ErrInvalidRecurringTransactionData)
+	}
+	account, err := s.checkAccountAndCategory(ctx, userID, req.AccountID, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		recurring, err := s.lockRecurring(ctx, userID, recurringID)
+		if err != nil {
+			return err
+		}
+
+		// Le montant est exprimé dans la devise du compte : il doit être fourni lors d'un changement de devise
+		currency := recurring.Currency
+		if account != nil {
+			currency = account.Currency
+		}
+		if req.Amount == nil && currency != recurring.Currency {
+			return fmt.Errorf("%w: le montant est requis pour un compte en %s", entity.ErrInvalidRecurringTransactionData, currency)
+		}
+
+		if req.AccountID != nil {
+			recurring.AccountID = *req.AccountID
+		}
+		if req.CategoryID != nil {
+			recurring.CategoryID = req.CategoryID
+		}
+		if req.Amount != nil {
+			amount, err := s.parseAmount(*req.Amount, currency)
+			if err != nil {
+				return err
+			}
+			recurring.Amount = amount
+			recurring.Currency = currency
+		}
+		if req.Description != nil {
+			recurring.Description = *req.Description
+		}
+
+		scheduleChanged := false
+		if req.Frequency != nil {
+			recurring.Frequency = *req.Frequency
+			if recurring.Frequency != "monthly" {
+				recurring.DayOfMonth = nil
+			}
+			scheduleChanged = true
+		}
+		if req.Interval != nil {
+			recurring.Interval = *req.Interval
+			scheduleChanged = true
+		}
+		if req.DayOfMonth != nil {
+			recurring.DayOfMonth = req.DayOfMonth
+			scheduleChanged = true
+		}
+		if req.EndDate != nil {
+			recurring.EndDate = truncateDatePtr(req.EndDate)
+			scheduleChanged = true
+		}
+		if req.MaxOccurrences != nil {
+			recurring.MaxOccurrences = req.MaxOccurrences
+			scheduleChanged = true
+		}
+
+		if scheduleChanged {
+			if err := validateRecurringSchedule(recurring); err != nil {
+				return err
+			}
+
+			// Recalculer la prochaine occurrence à partir de l'occurrence en attente (ou d'aujourd'hui si terminé)
+			from := truncateToDay(time.Now())
+			if recurring.NextOccurrence != nil {
+				from = *recurring.NextOccurrence
+			}
+			if recurring.Status == "completed" {
+				recurring.Status = "active"
+			}
+			scheduleNextOccurrence(recurring, firstOccurrenceFrom(recurring, from))
+		}
+
+		recurring.UpdatedAt = time.Now()
+		return s.recurringRepo.Update(ctx, recurring)
+	})
+	if err != nil {
+		s.logger.Error("Erreur mise à jour transaction récurrente", logger.Error(err))
+		return nil, err
+	}
+
+	return s.GetRecurringTransaction(ctx, userID, recurringID)
+}
+
+// PauseRecurringTransaction suspend la création des occurrences
+func (s *RecurringTransactionService) PauseRecurringTransaction(ctx context.Context, userID, recurringID uuid.UUID) (*entity.RecurringTransaction, error) {
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		recurring, err := s.lockRecurring(ctx, userID, recurringID)
+		if err != nil {
+			return err
+		}
+		if recurring.Status != "active" {
+			return fmt.Errorf("%w: seule une transaction récurrente active peut être suspendue", entity.ErrInvalidRecurringTransactionData)
+		}
+
+		recurring.Status = "paused"
+		recurring.UpdatedAt = time.Now()
+		return s.recurringRepo.Update(ctx, recurring)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetRecurringTransaction(ctx, userID, recurringID)
+}
+
+// ResumeRecurringTransaction reprend une transaction récurrente suspendue.
+// Les occurrences tombées pendant la suspension ne sont pas créées.
+func (s *RecurringTransactionService) ResumeRecurringTransaction(ctx context.Context, userID, recurringID uuid.UUID) (*entity.RecurringTransaction, error) {
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		recurring, err := s.lockRecurring(ctx, userID, recurringID)
+		if err != nil {
+			return err
+		}
+		if recurring.Status != "paused" {
+			return fmt.Errorf("%w: la transaction récurrente n'est pas suspendue", entity.ErrInvalidRecurringTransactionData)
+		}
+
+		from := truncateToDay(time.Now())
+		if recurring.NextOccurrence != nil && recurring.NextOccurrence.After(from) {
+			from = *recurring.NextOccurrence
+		}
+		recurring.Status = "active"
+		scheduleNextOccurrence(recurring, firstOccurrenceFrom(recurring, from))
+		recurring.UpdatedAt = time.Now()
+		return s.recurringRepo.Update(ctx, recurring)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetRecurringTransaction(ctx, userID, recurringID)
+}
+
+// SkipNextOccurrence saute la prochaine occurrence sans créer de transaction
+func (s *RecurringTransactionService) SkipNextOccurrence(ctx context.Context, userID, recurringID uuid.UUID) (*entity.RecurringTransaction, error) {
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		recurring, err := s.lockRecurring(ctx, userID, recurringID)
+		if err != nil {
+			return err
+		}
+		if recurring.Status == "completed" || recurring.NextOccurrence == nil {
+			return fmt.Errorf("%w: aucune occurrence à venir", entity.ErrInvalidRecurringTransactionData)
+		}
+
+		s.logger.Info("Occurrence sautée",
+			logger.String("recurring_id", recurring.ID.String()),
+			logger.String("date", recurring.NextOccurrence.Format("2006-01-02")),
+		)
+		scheduleNextOccurrence(recurring, nextOccurrenceAfter(recurring, *recurring.NextOccurrence))
+		recurring.UpdatedAt = time.Now()
+		return s.recurringRepo.Update(ctx, recurring)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetRecurringTransaction(ctx, userID, recurringID)
+}
+
+// GetUpcomingOccurrences retourne les dates des prochaines occurrences prévues
+func (s *RecurringTransactionService) GetUpcomingOccurrences(ctx context.Context, userID, recurringID uuid.UUID, count int) ([]time.Time, error) {
+	recurring, err := s.GetRecurringTransaction(ctx, userID, recurringID)
+	if err != nil {
+		return nil, err
+	}
+
+	dates := []time.Time{}
+	if recurring.NextOccurrence == nil {
+		return dates, nil
+	}
+
+	date := *recurring.NextOccurrence
+	for occurrences := recurring.OccurrenceCount; len(dates) < count; occurrences++ {
+		if recurring.EndDate != nil && date.After(*recurring.EndDate) {
+			break
+		}
+		if recurring.MaxOccurrences != nil && occurrences >= *recurring.MaxOccurrences {
+			break
+		}
+		dates = append(dates, date)
+		date = nextOccurrenceAfter(recurring, date)
+	}
+	return dates, nil
+}
+
+// DeleteRecurringTransaction supprime un modèle de transaction récurrente.
+// Les transactions déjà créées sont conservées.
+func (s *RecurringTransactionService) DeleteRecurringTransaction(ctx context.Context, userID, recurringID uuid.UUID) error {
+	if _, err := s.GetRecurringTransaction(ctx, userID, recurringID); err != nil {
+		return err
+	}
+
+	if err := s.recurringRepo.Delete(ctx, recurringID); err != nil {
+		s.logger.Error("Erreur suppression transaction récurrente", logger.Error(err))
+		return err
+	}
+
+	s.logger.Info("Transaction récurrente supprimée avec succès",
+		logger.String("recurring_id", recurringID.String()),
+		logger.String("user_id", userID.String()),
+	)
+	return nil
+}
+
+// StartScheduler crée périodiquement les occurrences échues jusqu'à l'annulation du contexte
+func (s *RecurringTransactionService) StartScheduler(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		s.logger.Warn("Planificateur des transactions récurrentes désactivé (intervalle invalide)")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.logger.Info("Planificateur des transactions récurrentes démarré", logger.String("interval", interval.String()))
+	for {
+		if created, err := s.ProcessDueOccurrences(ctx, time.Now()); err != nil {
+			s.logger.Error("Erreur planificateur des transactions récurrentes", logger.Error(err))
+		} else if created > 0 {
+			s.logger.Info("Occurrences récurrentes créées", logger.Int("count", created))
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Planificateur des transactions récurrentes arrêté")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDueOccurrences crée les occurrences échues à la date donnée et retourne leur nombre.
+// Chaque modèle est traité dans sa propre transaction SQL, ligne verrouillée : une occurrence
+// n'est créée qu'une fois même si plusieurs instances du planificateur tournent en parallèle.
+func (s *RecurringTransactionService) ProcessDueOccurrences(ctx context.Context, now time.Time) (int, error) {
+	today := truncateToDay(now)
+
+	ids, err := s.recurringRepo.GetDueIDs(ctx, today, recurringBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, id := range ids {
+		count, err := s.processRecurring(ctx, id, today)
+		if err != nil {
+			s.logger.Error("Erreur création occurrences récurrentes",
+				logger.String("recurring_id", id.String()),
+				logger.Error(err),
+			)
+			continue
+		}
+		created += count
+	}
+	return created, nil
+}
+
+// processRecurring crée les occurrences échues d'un modèle et avance sa prochaine occurrence.
+// Un échec qui se reproduirait à chaque passage (compte supprimé ou clôturé, période rapprochée) suspend
+// le modèle ; les occurrences créées avant l'échec sont conservées.
+func (s *RecurringTransactionService) processRecurring(ctx context.Context, recurringID uuid.UUID, today time.Time) (int, error) {
+	created := 0
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		created = 0
+		recurring, err := s.recurringRepo.GetByIDForUpdate(ctx, recurringID)
+		if err != nil {
+			return err
+		}
+
+		// Relire l'état après verrouillage : une autre instance a pu traiter le modèle entre-temps
+		paused := false
+		for created < recurringMaxCatchUp {
+			due := dueOccurrence(recurring, today)
+			if due == nil {
+				break
+			}
+			date := *due
+			if _, err := s.transactionService.CreateRecurringOccurrence(ctx, recurring, date); err != nil {
+				if !isPermanentRecurringError(err) {
+					return err
+				}
+				s.logger.Warn("Transaction récurrente suspendue après un échec permanent",
+					logger.String("recurring_id", recurring.ID.String()),
+					logger.String("date", date.Format("2006-01-02")),
+					logger.Error(err),
+				)
+				recurring.Status = "paused"
+				paused = true
+				break
+			}
+			created++
+			advanceOccurrence(recurring, date)
+		}
+
+		if created == 0 && !paused {
+			return nil
+		}
+		recurring.UpdatedAt = time.Now()
+		return s.recurringRepo.Update(ctx, recurring)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return created, nil
+}
+
+// isPermanentRecurringError indique si la création d'une occurrence échouerait de nouveau au passage suivant
+// du planificateur sans intervention de l'utilisateur
+func isPermanentRecurringError(err error) bool {
+	return errors.Is(err, entity.ErrAccountNotFound) ||
+		errors.Is(err, entity.ErrAccountArchived) ||
+		errors.Is(err, entity.ErrReconciliationPeriodLocked)
+}
+
+// lockRecurring verrouille un modèle et vérifie qu'il appartient à l'utilisateur
+func (s *RecurringTransactionService) lockRecurring(ctx context.Context, userID, recurringID uuid.UUID) (*entity.RecurringTransaction, error) {
+	recurring, err := s.recurringRepo.GetByIDForUpdate(ctx, recurringID)
+	if err != nil {
+		return nil, err
+	}
+	if recurring.UserID != userID {
+		return nil, entity.ErrRecurringTransactionNotFound
+	}
+	return recurring, nil
+}
+
+// checkAccountAndCategory vérifie que le compte et la catégorie fournis appartiennent à l'utilisateur
+// et retourne le compte (nil s'il n'est pas fourni)
+func (s *RecurringTransactionService) checkAccountAndCategory(ctx context.Context, userID uuid.UUID, accountID, categoryID *uuid.UUID) (*entity.Account, error) {
+	var account *entity.Account
+	if accountID != nil {
+		var err error
+		account, err = s.accountRepo.GetByID(ctx, *accountID)
+		if err != nil || account.UserID != userID {
+			return nil, fmt.Errorf("%w: compte non trouvé", entity.ErrInvalidRecurringTransactionData)
+		}
+	}
+	if categoryID != nil {
+		category, err := s.categoryRepo.GetByID(ctx, userID, *categoryID)
+		if err != nil || category.UserID != userID {
+			return nil, fmt.Errorf("%w: catégorie non trouvée", entity.ErrInvalidRecurringTransactionData)
+		}
+	}
+	return account, nil
+}
+
+// parseAmount convertit le montant saisi d'un modèle dans la devise de son compte
+func (s *RecurringTransactionService) parseAmount(value entity.Decimal, currency string) (entity.Money, error) {
+	amount, err := value.Money(currency)
+	if err != nil {
+		return entity.Money{}, fmt.Errorf("%w: %v", entity.ErrInvalidRecurringTransactionData, err)
+	}
+	if !amount.IsPositive() {
+		return entity.Money{}, fmt.Errorf("%w: le montant doit être positif", entity.ErrInvalidRecurringTransactionData)
+	}
+	return amount, nil
+}
+
+// validateRecurringSchedule vérifie la cohérence de la planification d'un modèle
+func validateRecurringSchedule(recurring *entity.RecurringTransaction) error {
+	switch recurring.Frequency {
+	case "daily", "weekly", "monthly", "yearly":
+	default:
+		return fmt.Errorf("%w: fréquence invalide: %s", entity.ErrInvalidRecurringTransactionData, recurring.Frequency)
+	}
+	if recurring.Interval < 1 {
+		return fmt.Errorf("%w: l'intervalle doit être supérieur ou égal à 1", entity.ErrInvalidRecurringTransactionData)
+	}
+	if recurring.DayOfMonth != nil {
+		if recurring.Frequency != "monthly" {
+			return fmt.Errorf("%w: le jour du mois ne s'applique qu'à une fréquence mensuelle", entity.ErrInvalidRecurringTransactionData)
+		}
+		if *recurring.DayOfMonth < 1 || *recurring.DayOfMonth > 31 {
+			return fmt.Errorf("%w: le jour du mois doit être compris entre 1 et 31", entity.ErrInvalidRecurringTransactionData)
+		}
+	}
+	if recurring.EndDate != nil && recurring.EndDate.Before(recurring.StartDate) {
+		return fmt.Errorf("%w: la date de fin précède la date de début", entity.ErrInvalidRecurringTransactionData)
+	}
+	if recurring.MaxOccurrences != nil && *recurring.MaxOccurrences < 1 {
+		return fmt.Errorf("%w: le nombre d'occurrences doit être supérieur ou égal à 1", entity.ErrInvalidRecurringTransactionData)
+	}
+	return nil
+}
+
+// dueOccurrence retourne la prochaine occurrence d'un modèle actif si elle est échue à la date donnée, nil sinon
+func dueOccurrence(recurring *entity.RecurringTransaction, today time.Time) *time.Time {
+	if recurring.Status != "active" || recurring.NextOccurrence == nil || recurring.NextOccurrence.After(today) {
+		return nil
+	}
+	return recurring.NextOccurrence
+}
+
+// advanceOccurrence compte l'occurrence créée à la date donnée et planifie la suivante
+func advanceOccurrence(recurring *entity.RecurringTransaction, date time.Time) {
+	recurring.OccurrenceCount++
+	scheduleNextOccurrence(recurring, nextOccurrenceAfter(recurring, date))
+}
+
+// scheduleNextOccurrence positionne la prochaine occurrence, ou termine le modèle
+// si la date de fin ou le nombre d'occurrences est atteint
+func scheduleNextOccurrence(recurring *entity.RecurringTransaction, next time.Time) {
+	if (recurring.EndDate != nil && next.After(*recurring.EndDate)) ||
+		(recurring.MaxOccurrences != nil && recurring.OccurrenceCount >= *recurring.MaxOccurrences) {
+		recurring.NextOccurrence = nil
+		recurring.Status = "completed"
+		return
+	}
+	recurring.NextOccurrence = &next
+}
+
+// firstOccurrence retourne la première occurrence d'un modèle, au plus tôt à sa date de début
+func firstOccurrence(recurring *entity.RecurringTransaction) time.Time {
+	if recurring.Frequency == "monthly" && recurring.DayOfMonth != nil {
+		first := dateInMonth(recurring.StartDate.Year(), recurring.StartDate.Month(), *recurring.DayOfMonth)
+		if first.Before(recurring.StartDate) {
+			first = nextOccurrenceAfter(recurring, first)
+		}
+		return first
+	}
+	return recurring.StartDate
+}
+
+// firstOccurrenceFrom retourne la première occurrence du modèle tombant à la date donnée ou après
+func firstOccurrenceFrom(recurring *entity.RecurringTransaction, from time.Time) time.Time {
+	date := firstOccurrence(recurring)
+	for date.Before(from) {
+		date = nextOccurrenceAfter(recurring, date)
+	}
+	return date
+}
+
+// nextOccurrenceAfter retourne l'occurrence qui suit la date donnée selon la fréquence du modèle.
+// Les échéances mensuelles et annuelles restent ancrées sur le jour prévu (ramené au dernier jour du mois si besoin).
+func nextOccurrenceAfter(recurring *entity.RecurringTransaction, date time.Time) time.Time {
+	switch recurring.Frequency {
+	case "daily":
+		return date.AddDate(0, 0, recurring.Interval)
+	case "weekly":
+		return date.AddDate(0, 0, 7*recurring.Interval)
+	case "yearly":
+		return dateInMonth(date.Year()+recurring.Interval, recurring.StartDate.Month(), recurring.StartDate.Day())
+	default:
+		day := recurring.StartDate.Day()
+		if recurring.DayOfMonth != nil {
+			day = *recurring.DayOfMonth
+		}
+		month := time.Date(date.Year(), date.Month()+time.Month(recurring.Interval), 1, 0, 0, 0, 0, time.UTC)
+		return dateInMonth(month.Year(), month.Month(), day)
+	}
+}
+
+// dateInMonth construit une date en ramenant le jour au dernier jour du mois s'il le dépasse
+func dateInMonth(year int, month time.Month, day int) time.Time {
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// truncateToDay ramène une date à minuit UTC
+func truncateToDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// truncateDatePtr applique truncateToDay à une date optionnelle
+func truncateDatePtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	day := truncateToDay(*t)
+	return &day
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"backend/internal/domaine/entity"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestDateInMonth(t *testing.T) {
+	tests := []struct {
+		year  int
+		month time.Month
+		day   int
+		want  time.Time
+	}{
+		{year: 2025, month: time.March, day: 15, want: day(2025, time.March, 15)},
+		{year: 2025, month: time.January, day: 31, want: day(2025, time.January, 31)},
+		{year: 2025, month: time.April, day: 31, want: day(2025, time.April, 30)},
+		{year: 2025, month: time.February, day: 31, want: day(2025, time.February, 28)},
+		{year: 2025, month: time.February, day: 29, want: day(2025, time.February, 28)},
+		{year: 2024, month: time.February, day: 29, want: day(2024, time.February, 29)},
+		{year: 2024, month: time.February, day: 30, want: day(2024, time.February, 29)},
+		{year: 2100, month: time.February, day: 29, want: day(2100, time.February, 28)},
+		{year: 2000, month: time.February, day: 29, want: day(2000, time.February, 29)},
+		{year: 2025, month: time.December, day: 31, want: day(2025, time.December, 31)},
+	}
+
+	for _, tt := range tests {
+		if got := dateInMonth(tt.year, tt.month, tt.day); !got.Equal(tt.want) {
+			t.Errorf("dateInMonth(%d, %s, %d) = %s, attendu %s", tt.year, tt.month, tt.day, got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestNextOccurrenceAfter(t *testing.T) {
+	dayOfMonth := func(d int) *int { return &d }
+	tests := []struct {
+		name      string
+		recurring entity.RecurringTransaction
+		date      time.Time
+		want      time.Time
+	}{
+		{
+			name:      "quotidienne",
+			recurring: entity.RecurringTransaction{Frequency: "daily", Interval: 1, StartDate: day(2025, time.February, 28)},
+			date:      day(2025, time.February, 28),
+			want:      day(2025, time.March, 1),
+		},
+		{
+			name:      "tous les 3 jours",
+			recurring: entity.RecurringTransaction{Frequency: "daily", Interval: 3, StartDate: day(2024, time.February, 27)},
+			date:      day(2024, time.February, 27),
+			want:      day(2024, time.March, 1),
+		},
+		{
+			name:      "toutes les deux semaines",
+			recurring: entity.RecurringTransaction{Frequency: "weekly", Interval: 2, StartDate: day(2025, time.December, 24)},
+			date:      day(2025, time.December, 24),
+			want:      day(2026, time.January, 7),
+		},
+		{
+			name:      "mensuelle le 31 en février",
+			recurring: entity.RecurringTransaction{Frequency: "monthly", Interval: 1, StartDate: day(2025, time.January, 31)},
+			date:      day(2025, time.January, 31),
+			want:      day(2025, time.February, 28),
+		},
+		{
+			name:      "mensuelle le 31 revient au 31 après février",
+			recurring: entity.RecurringTransaction{Frequency: "monthly", Interval: 1, StartDate: day(2025, time.January, 31)},
+			date:      day(2025, time.February, 28),
+			want:      day(2025, time.March, 31),
+		},
+		{
+			name:      "mensuelle le 31 en avril",
+			recurring: entity.RecurringTransaction{Frequency: "monthly", Interval: 1, StartDate: day(2025, time.January, 31)},
+			date:      day(2025, time.March, 31),
+			want:      day(2025, time.April, 30),
+		},
+		{
+			name:      "mensuelle le 30 en février bissextile",
+			recurring: entity.RecurringTransaction{Frequency: "monthly", Interval: 1, StartDate: day(2024, time.January, 30)},
+			date:      day(2024, time.January, 30),
+			want:      day(2024, time.February, 29),
+		},
+		{
+			name:      "jour du mois imposé",
+			recurring: entity.RecurringTransaction{Frequency: "monthly", Interval: 1, DayOfMonth: dayOfMonth(31), StartDate: day(2025, time.January, 10)},
+			date:      day(2025, time.January, 31),
+			want:      day(2025, time.February, 28),
+		},
+		{
+			name:      "trimestrielle avec changement d'année",
+			recurring: entity.RecurringTransaction{Frequency: "monthly", Interval: 3, StartDate: day(2025, time.November, 30)},
+			date:      day(2025, time.November, 30),
+			want:      day(2026, time.February, 28),
+		},
+		{
+			name:      "annuelle le 29 février",
+			recurring: entity.RecurringTransaction{Frequency: "yearly", Interval: 1, StartDate: day(2024, time.February, 29)},
+			date:      day(2024, time.February, 29),
+			want:      day(2025, time.February, 28),
+		},
+		{
+			name:      "annuelle le 29 février revient en année bissextile",
+			recurring: entity.RecurringTransaction{Frequency: "yearly", Interval: 1, StartDate: day(2024, time.February, 29)},
+			date:      day(2027, time.February, 28),
+			want:      day(2028, time.February, 29),
+		},
+		{
+			name:      "tous les quatre ans le 29 février",
+			recurring: entity.RecurringTransaction{Frequency: "yearly", Interval: 4, StartDate: day(2024, time.February, 29)},
+			date:      day(2024, time.February, 29),
+			want:      day(2028, time.February, 29),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextOccurrenceAfter(&tt.recurring, tt.date); !got.Equal(tt.want) {
+				t.Errorf("nextOccurrenceAfter = %s, attendu %s", got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestFirstOccurrence(t *testing.T) {
+	dayOfMonth := 5
+	recurring := &entity.RecurringTransaction{Frequency: "monthly", Interval: 1, DayOfMonth: &dayOfMonth, StartDate: day(2025, time.January, 20)}
+	if got := firstOccurrence(recurring); !got.Equal(day(2025, time.February, 5)) {
+		t.Errorf("firstOccurrence = %s, attendu 2025-02-05", got.Format("2006-01-02"))
+	}
+	if got := firstOccurrenceFrom(recurring, day(2025, time.June, 6)); !got.Equal(day(2025, time.July, 5)) {
+		t.Errorf("firstOccurrenceFrom = %s, attendu 2025-07-05", got.Format("2006-01-02"))
+	}
+}
+
+// catchUp rejoue la boucle de rattrapage de processRecurring sans créer de transactions
+func catchUp(recurring *entity.RecurringTransaction, today time.Time) []string {
+	var dates []string
+	for len(dates) < recurringMaxCatchUp {
+		due := dueOccurrence(recurring, today)
+		if due == nil {
+			break
+		}
+		date := *due
+		dates = append(dates, date.Format("2006-01-02"))
+		advanceOccurrence(recurring, date)
+	}
+	return dates
+}
+
+func TestRecurringCatchUp(t *testing.T) {
+	maxOccurrences := 3
+	endDate := day(2025, time.April, 15)
+	tests := []struct {
+		name       string
+		recurring  entity.RecurringTransaction
+		today      time.Time
+		want       []string
+		wantNext   string
+		wantStatus string
+	}{
+		{
+			name:       "mensuelle le 31 sans dérive",
+			recurring:  entity.RecurringTransaction{Frequency: "monthly", Interval: 1, StartDate: day(2025, time.January, 31)},
+			today:      day(2025, time.May, 31),
+			want:       []string{"2025-01-31", "2025-02-28", "2025-03-31", "2025-04-30", "2025-05-31"},
+			wantNext:   "2025-06-30",
+			wantStatus: "active",
+		},
+		{
+			name:       "annuelle le 29 février",
+			recurring:  entity.RecurringTransaction{Frequency: "yearly", Interval: 1, StartDate: day(2024, time.February, 29)},
+			today:      day(2028, time.March, 1),
+			want:       []string{"2024-02-29", "2025-02-28", "2026-02-28", "2027-02-28", "2028-02-29"},
+			wantNext:   "2029-02-28",
+			wantStatus: "active",
+		},
+		{
+			name:       "nombre d'occurrences atteint",
+			recurring:  entity.RecurringTransaction{Frequency: "weekly", Interval: 1, StartDate: day(2025, time.January, 1), MaxOccurrences: &maxOccurrences},
+			today:      day(2025, time.March, 1),
+			want:       []string{"2025-01-01", "2025-01-08", "2025-01-15"},
+			wantStatus: "completed",
+		},
+		{
+			name:       "date de fin atteinte",
+			recurring:  entity.RecurringTransaction{Frequency: "monthly", Interval: 1, StartDate: day(2025, time.January, 15), EndDate: &endDate},
+			today:      day(2025, time.December, 31),
+			want:       []string{"2025-01-15", "2025-02-15", "2025-03-15", "2025-04-15"},
+			wantStatus: "completed",
+		},
+		{
+			name:       "rien d'échu",
+			recurring:  entity.RecurringTransaction{Frequency: "daily", Interval: 1, StartDate: day(2025, time.June, 2)},
+			today:      day(2025, time.June, 1),
+			wantNext:   "2025-06-02",
+			wantStatus: "active",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recurring := tt.recurring
+			recurring.Status = "active"
+			scheduleNextOccurrence(&recurring, firstOccurrence(&recurring))
+
+			got := catchUp(&recurring, tt.today)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("occurrences = %v, attendu %v", got, tt.want)
+			}
+			if recurring.OccurrenceCount != len(tt.want) {
+				t.Errorf("OccurrenceCount = %d, attendu %d", recurring.OccurrenceCount, len(tt.want))
+			}
+			if recurring.Status != tt.wantStatus {
+				t.Errorf("Status = %s, attendu %s", recurring.Status, tt.wantStatus)
+			}
+			next := ""
+			if recurring.NextOccurrence != nil {
+				next = recurring.NextOccurrence.Format("2006-01-02")
+			}
+			if next != tt.wantNext {
+				t.Errorf("NextOccurrence = %q, attendu %q", next, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestRecurringCatchUpBounded(t *testing.T) {
+	recurring := entity.RecurringTransaction{Frequency: "daily", Interval: 1, StartDate: day(2020, time.January, 1), Status: "active"}
+	scheduleNextOccurrence(&recurring, firstOccurrence(&recurring))
+
+	got := catchUp(&recurring, day(2025, time.January, 1))
+	if len(got) != recurringMaxCatchUp {
+		t.Fatalf("%d occurrences rattrapées, attendu %d", len(got), recurringMaxCatchUp)
+	}
+	// Le passage suivant reprend là où le précédent s'est arrêté
+	if want := day(2020, time.January, 1).AddDate(0, 0, recurringMaxCatchUp); !recurring.NextOccurrence.Equal(want) {
+		t.Errorf("NextOccurrence = %s, attendu %s", recurring.NextOccurrence.Format("2006-01-02"), want.Format("2006-01-02"))
+	}
+}
+
+func TestIsPermanentRecurringError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{err: fmt.Errorf("compte non trouvé: %w", entity.ErrAccountNotFound), want: true},
+		{err: fmt.Errorf("%w: Épargne", entity.ErrAccountArchived), want: true},
+		{err: fmt.Errorf("%w: compte rapproché jusqu'au 2025-01-31", entity.ErrReconciliationPeriodLocked), want: true},
+		{err: errors.New("erreur verrouillage compte: connexion perdue"), want: false},
+		{err: fmt.Errorf("erreur création occurrence: %w", errors.New("timeout")), want: false},
+	}
+
+	for _, tt := range tests {
+		if got := isPermanentRecurringError(tt.err); got != tt.want {
+			t.Errorf("isPermanentRecurringError(%v) = %v, attendu %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -32,7 +32,12 @@ func (s *SavingGoalService) CreateSavingGoal(ctx context.Context, userID uuid.UU
 		return nil, fmt.Errorf("le titre est requis")
 	}
 
-	if req.TargetAmount <= 0 {
+	// L'objectif créé ici n'est rattaché à aucun compte : ses montants sont dans la devise par défaut
+	targetAmount, err := req.TargetAmount.Money(entity.DefaultCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if !targetAmount.IsPositive() {
 		return nil, fmt.Errorf("le montant cible doit être positif")
 	}
 
@@ -47,8 +52,9 @@ func (s *SavingGoalService) CreateSavingGoal(ctx context.Context, userID uuid.UU
 	savingGoal := &entity.SavingGoal{
 		UserID:        userID,
 		Title:         req.Title,
-		TargetAmount:  req.TargetAmount,
-		CurrentAmount: 0, // Initialiser à 0
+		TargetAmount:  targetAmount,
+		CurrentAmount: entity.NewMoney(0, entity.DefaultCurrency), // Initialiser à 0
+		Currency:      entity.DefaultCurrency,
 		Deadline:      req.Deadline,
 		IsAchieved:    false,
 		CreatedAt:     time.Now(),
@@ -69,7 +75,7 @@ func (s *SavingGoalService) CreateSavingGoal(ctx context.Context, userID uuid.UU
 		logger.String("saving_goal_id", savingGoal.ID.String()),
 		logger.String("user_id", userID.String()),
 		logger.String("title", savingGoal.Title),
-		logger.Float64("target_amount", savingGoal.TargetAmount),
+		logger.String("target_amount", savingGoal.TargetAmount.String()),
 	)
 
 	return savingGoal, nil
@@ -145,17 +151,25 @@ func (s *SavingGoalService) UpdateSavingGoal(ctx context.Context, userID uuid.UU
 	}
 
 	if req.TargetAmount != nil {
-		if *req.TargetAmount <= 0 {
+		targetAmount, err := req.TargetAmount.Money(savingGoal.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if !targetAmount.IsPositive() {
 			return nil, fmt.Errorf("le montant cible doit être positif")
 		}
-		savingGoal.TargetAmount = *req.TargetAmount
+		savingGoal.TargetAmount = targetAmount
 	}
 
 	if req.CurrentAmount != nil {
-		if *req.CurrentAmount < 0 {
+		currentAmount, err := req.CurrentAmount.Money(savingGoal.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if currentAmount.IsNegative() {
 			return nil, fmt.Errorf("le montant actuel ne peut pas être négatif")
 		}
-		savingGoal.CurrentAmount = *req.CurrentAmount
+		savingGoal.CurrentAmount = currentAmount
 	}
 
 	if req.Deadline != nil {
@@ -174,7 +188,7 @@ func (s *SavingGoalService) UpdateSavingGoal(ctx context.Context, userID uuid.UU
 	}
 
 	// Vérifier si l'objectif est atteint
-	if savingGoal.CurrentAmount >= savingGoal.TargetAmount {
+	if savingGoal.CurrentAmount.Cmp(savingGoal.TargetAmount) >= 0 {
 		savingGoal.IsAchieved = true
 	}
 
@@ -194,7 +208,7 @@ func (s *SavingGoalService) UpdateSavingGoal(ctx context.Context, userID uuid.UU
 	return savingGoal, nil
 }
 
-// DeleteSavingGoal supprime un objectif d'épargne
+// DeleteSavingGoal met un objectif d'épargne à la corbeille
 func (s *SavingGoalService) DeleteSavingGoal(ctx context.Context, userID uuid.UUID, goalID uuid.UUID) error {
 	// Récupérer l'objectif existant
 	savingGoal, err := s.savingGoalRepo.GetByID(ctx, goalID)
@@ -226,6 +240,33 @@ func (s *SavingGoalService) DeleteSavingGoal(ctx context.Context, userID uuid.UU
 	return nil
 }
 
+// RestoreSavingGoal sort un objectif d'épargne de la corbeille ; un objectif supprimé avec son compte
+// ne peut être restauré qu'avec lui
+func (s *SavingGoalService) RestoreSavingGoal(ctx context.Context, userID uuid.UUID, goalID uuid.UUID) (*entity.SavingGoal, error) {
+	savingGoal, err := s.savingGoalRepo.GetDeletedByID(ctx, goalID)
+	if err != nil {
+		return nil, err
+	}
+	if savingGoal.UserID != userID {
+		return nil, fmt.Errorf("%w: objectif d'épargne %s", entity.ErrTrashItemNotFound, goalID)
+	}
+	if savingGoal.DeletedWithAccountID != nil {
+		return nil, fmt.Errorf("%w: l'objectif a été supprimé avec son compte, restaurer le compte", entity.ErrRestoreNotPossible)
+	}
+
+	if err := s.savingGoalRepo.Restore(ctx, goalID); err != nil {
+		s.logger.Error("Erreur restauration objectif d'épargne", logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Objectif d'épargne restauré avec succès",
+		logger.String("goal_id", goalID.String()),
+		logger.String("user_id", userID.String()),
+	)
+
+	return s.savingGoalRepo.GetByID(ctx, goalID)
+}
+
 // GetSavingGoalsByUserID récupère tous les objectifs d'épargne d'un utilisateur
 func (s *SavingGoalService) GetSavingGoalsByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.SavingGoal, error) {
 	goals, err := s.savingGoalRepo.GetByUserID(ctx, userID)
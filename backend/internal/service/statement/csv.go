@@ -0,0 +1,208 @@
+package statement
+
+import (
+	"backend/internal/domaine/entity"
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CSVMapping décrit l'emplacement des champs dans un relevé CSV.
+// Chaque colonne est désignée par son nom d'en-tête ou par son index (à partir de 0).
+type CSVMapping struct {
+	Delimiter         string // séparateur de champs ; détecté automatiquement si vide
+	HasHeader         bool   // la première ligne contient les noms de colonnes
+	DateColumn        string
+	DescriptionColumn string
+	AmountColumn      string // montant signé ; sinon utiliser DebitColumn/CreditColumn
+	DebitColumn       string
+	CreditColumn      string
+	ReferenceColumn   string // optionnelle
+}
+
+// ParseCSV lit un relevé CSV selon la correspondance de colonnes fournie, les montants dans la devise donnée
+func ParseCSV(r io.Reader, mapping CSVMapping, dateFormat, currency string) ([]Line, error) {
+	buffered := bufio.NewReader(r)
+	delimiter, err := csvDelimiter(buffered, mapping.Delimiter)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.LazyQuotes = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("fichier CSV invalide: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("fichier CSV vide")
+	}
+
+	var header []string
+	start := 0
+	if mapping.HasHeader {
+		header = records[0]
+		start = 1
+	}
+
+	columns := map[string]int{}
+	for field, column := range map[string]string{
+		"date":        mapping.DateColumn,
+		"description": mapping.DescriptionColumn,
+		"amount":      mapping.AmountColumn,
+		"debit":       mapping.DebitColumn,
+		"credit":      mapping.CreditColumn,
+		"reference":   mapping.ReferenceColumn,
+	} {
+		if column == "" {
+			continue
+		}
+		index, err := csvColumnIndex(header, column)
+		if err != nil {
+			return nil, err
+		}
+		columns[field] = index
+	}
+
+	if _, ok := columns["date"]; !ok {
+		return nil, fmt.Errorf("la colonne de date est requise")
+	}
+	if _, ok := columns["description"]; !ok {
+		return nil, fmt.Errorf("la colonne de libellé est requise")
+	}
+	_, hasAmount := columns["amount"]
+	_, hasDebit := columns["debit"]
+	_, hasCredit := columns["credit"]
+	if !hasAmount && !hasDebit && !hasCredit {
+		return nil, fmt.Errorf("la colonne de montant (ou de débit/crédit) est requise")
+	}
+
+	lines := make([]Line, 0, len(records)-start)
+	for i, record := range records[start:] {
+		if isBlankRecord(record) {
+			continue
+		}
+
+		line := Line{Number: start + i + 1}
+		field := func(name string) string {
+			index, ok := columns[name]
+			if !ok || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+
+		line.Description = field("description")
+		line.Reference = field("reference")
+
+		date, err := parseDate(field("date"), dateFormat)
+		if err != nil {
+			line.Error = err.Error()
+			lines = append(lines, line)
+			continue
+		}
+		line.Date = date
+
+		if hasAmount {
+			line.Amount, err = parseAmount(field("amount"), currency)
+		} else {
+			line.Amount, err = debitCreditAmount(field("debit"), field("credit"), currency)
+		}
+		if err != nil {
+			line.Error = err.Error()
+		} else if line.Amount.IsZero() {
+			line.Error = "montant nul"
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// csvDelimiter retourne le séparateur configuré, ou le détecte sur la première ligne
+func csvDelimiter(r *bufio.Reader, configured string) (rune, error) {
+	switch configured {
+	case "":
+	case `\t`, "tab":
+		return '\t', nil
+	default:
+		runes := []rune(configured)
+		if len(runes) != 1 {
+			return 0, fmt.Errorf("séparateur CSV invalide: %s", configured)
+		}
+		return runes[0], nil
+	}
+
+	firstLine, err := r.Peek(4096)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return 0, fmt.Errorf("lecture du fichier CSV: %w", err)
+	}
+	if index := strings.IndexByte(string(firstLine), '\n'); index >= 0 {
+		firstLine = firstLine[:index]
+	}
+
+	best, bestCount := ',', 0
+	for _, candidate := range []rune{',', ';', '\t', '|'} {
+		if count := strings.Count(string(firstLine), string(candidate)); count > bestCount {
+			best, bestCount = candidate, count
+		}
+	}
+	return best, nil
+}
+
+// csvColumnIndex résout une colonne désignée par son nom d'en-tête ou son index
+func csvColumnIndex(header []string, column string) (int, error) {
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), strings.TrimSpace(column)) {
+			return i, nil
+		}
+	}
+	if index, err := strconv.Atoi(column); err == nil && index >= 0 {
+		return index, nil
+	}
+	return 0, fmt.Errorf("colonne introuvable: %s", column)
+}
+
+// debitCreditAmount combine des colonnes débit et crédit séparées en un montant signé
+func debitCreditAmount(debit, credit, currency string) (entity.Money, error) {
+	amount := entity.NewMoney(0, currency)
+	if debit != "" {
+		value, err := parseAmount(debit, currency)
+		if err != nil {
+			return entity.Money{}, err
+		}
+		if value.IsNegative() {
+			value = value.Neg()
+		}
+		amount = amount.Sub(value)
+	}
+	if credit != "" {
+		value, err := parseAmount(credit, currency)
+		if err != nil {
+			return entity.Money{}, err
+		}
+		if value.IsNegative() {
+			value = value.Neg()
+		}
+		amount = amount.Add(value)
+	}
+	return amount, nil
+}
+
+// isBlankRecord indique si un enregistrement CSV ne contient que des champs vides
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
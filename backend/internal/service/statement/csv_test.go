@@ -0,0 +1,211 @@
+package statement
+
+import (
+	"backend/internal/domaine/entity"
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestCSVDelimiter(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		configured string
+		want       rune
+		wantErr    bool
+	}{
+		{name: "point-virgule détecté", content: "date;libellé;montant\n15/01/2025;Café;-1500\n", want: ';'},
+		{name: "virgule détectée", content: "date,libellé,montant\n", want: ','},
+		{name: "tabulation détectée", content: "date\tlibellé\tmontant\n", want: '\t'},
+		{name: "barre verticale détectée", content: "date|libellé|montant", want: '|'},
+		{name: "seule la première ligne compte", content: "date;libellé;montant\n1,2,3,4,5,6\n", want: ';'},
+		{name: "virgule par défaut", content: "montant\n", want: ','},
+		{name: "fichier vide", content: "", want: ','},
+		{name: "séparateur imposé", content: "a,b;c;d\n", configured: ",", want: ','},
+		{name: "tabulation échappée", content: "a;b\n", configured: `\t`, want: '\t'},
+		{name: "tabulation nommée", content: "a;b\n", configured: "tab", want: '\t'},
+		{name: "séparateur de plusieurs caractères", content: "a;b\n", configured: ";;", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := csvDelimiter(bufio.NewReader(strings.NewReader(tt.content)), tt.configured)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("csvDelimiter = %q, attendu une erreur", got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("csvDelimiter = %q, %v, attendu %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVDelimiterKeepsContent(t *testing.T) {
+	// La détection lit la première ligne sans la consommer
+	reader := bufio.NewReader(strings.NewReader("date;montant\n"))
+	if _, err := csvDelimiter(reader, ""); err != nil {
+		t.Fatalf("csvDelimiter: %v", err)
+	}
+	if line, _ := reader.ReadString('\n'); line != "date;montant\n" {
+		t.Errorf("première ligne = %q après détection", line)
+	}
+}
+
+// csvLine résume une ligne de relevé pour les comparaisons
+type csvLine struct {
+	number      int
+	date        string
+	amount      int64
+	description string
+	reference   string
+	hasError    bool
+}
+
+func summarize(line Line) csvLine {
+	date := ""
+	if !line.Date.IsZero() {
+		date = line.Date.Format("2006-01-02")
+	}
+	return csvLine{
+		number:      line.Number,
+		date:        date,
+		amount:      line.Amount.Minor,
+		description: line.Description,
+		reference:   line.Reference,
+		hasError:    line.Error != "",
+	}
+}
+
+func TestParseCSVDebitCredit(t *testing.T) {
+	content := "Date;Libellé;Débit;Crédit;Référence\n" +
+		"15/01/2025;Salaire;;450 000;VIR001\n" +
+		"16/01/2025;Carrefour;12 500;;\n" +
+		"32/01/2025;Date invalide;100;;\n" +
+		"17/01/2025;Montant nul;0;;\n" +
+		";;;;\n" +
+		"18/01/2025;Remboursement;-1 500;;\n" +
+		"19/01/2025;Décimales;10,50;;\n"
+	mapping := CSVMapping{
+		HasHeader:         true,
+		DateColumn:        "date",
+		DescriptionColumn: "LIBELLÉ",
+		DebitColumn:       "Débit",
+		CreditColumn:      "Crédit",
+		ReferenceColumn:   "Référence",
+	}
+
+	lines, err := ParseCSV(strings.NewReader(content), mapping, "", "XAF")
+	if err != nil {
+		t.Fatalf("ParseCSV: %v", err)
+	}
+	want := []csvLine{
+		{number: 2, date: "2025-01-15", amount: 450000, description: "Salaire", reference: "VIR001"},
+		{number: 3, date: "2025-01-16", amount: -12500, description: "Carrefour"},
+		{number: 4, description: "Date invalide", hasError: true},
+		{number: 5, date: "2025-01-17", description: "Montant nul", hasError: true},
+		{number: 7, date: "2025-01-18", amount: -1500, description: "Remboursement"},
+		{number: 8, date: "2025-01-19", description: "Décimales", hasError: true}, // pas de décimales en XAF
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("%d lignes, attendu %d", len(lines), len(want))
+	}
+	for i, line := range lines {
+		if got := summarize(line); got != want[i] {
+			t.Errorf("ligne %d = %+v, attendu %+v", i, got, want[i])
+		}
+		if line.Error == "" && line.Amount.Currency != "XAF" {
+			t.Errorf("ligne %d : devise %q, attendu XAF", i, line.Amount.Currency)
+		}
+	}
+}
+
+func TestParseCSVSignedAmount(t *testing.T) {
+	content := "2025-03-01,\"Achat, magasin\",\"-1,234.56\"\n" +
+		"2025-03-02,Virement reçu,\"(2,00)\"\n" +
+		"03/03/2025,Format imposé,10\n"
+	mapping := CSVMapping{DateColumn: "0", DescriptionColumn: "1", AmountColumn: "2"}
+
+	lines, err := ParseCSV(strings.NewReader(content), mapping, "", "EUR")
+	if err != nil {
+		t.Fatalf("ParseCSV: %v", err)
+	}
+	want := []csvLine{
+		{number: 1, date: "2025-03-01", amount: -123456, description: "Achat, magasin"},
+		{number: 2, date: "2025-03-02", amount: -200, description: "Virement reçu"},
+		{number: 3, date: "2025-03-03", amount: 1000, description: "Format imposé"},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("%d lignes, attendu %d", len(lines), len(want))
+	}
+	for i, line := range lines {
+		if got := summarize(line); got != want[i] {
+			t.Errorf("ligne %d = %+v, attendu %+v", i, got, want[i])
+		}
+	}
+
+	// Avec un format de date imposé, les autres écritures sont refusées
+	lines, err = ParseCSV(strings.NewReader(content), mapping, "02/01/2006", "EUR")
+	if err != nil {
+		t.Fatalf("ParseCSV: %v", err)
+	}
+	if lines[0].Error == "" || lines[2].Error != "" {
+		t.Errorf("format de date imposé : erreurs %q, %q", lines[0].Error, lines[2].Error)
+	}
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		mapping CSVMapping
+	}{
+		{name: "fichier vide", content: "", mapping: CSVMapping{DateColumn: "0", DescriptionColumn: "1", AmountColumn: "2"}},
+		{name: "colonne introuvable", content: "date;libellé\n", mapping: CSVMapping{HasHeader: true, DateColumn: "date", DescriptionColumn: "libellé", AmountColumn: "montant"}},
+		{name: "colonne de date manquante", content: "a;b;c\n", mapping: CSVMapping{DescriptionColumn: "1", AmountColumn: "2"}},
+		{name: "colonne de libellé manquante", content: "a;b;c\n", mapping: CSVMapping{DateColumn: "0", AmountColumn: "2"}},
+		{name: "colonne de montant manquante", content: "a;b;c\n", mapping: CSVMapping{DateColumn: "0", DescriptionColumn: "1"}},
+		{name: "séparateur invalide", content: "a;b;c\n", mapping: CSVMapping{Delimiter: "::", DateColumn: "0", DescriptionColumn: "1", AmountColumn: "2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseCSV(strings.NewReader(tt.content), tt.mapping, "", "EUR"); err == nil {
+				t.Error("ParseCSV devrait échouer")
+			}
+		})
+	}
+}
+
+func TestDebitCreditAmount(t *testing.T) {
+	tests := []struct {
+		debit, credit string
+		want          int64
+		wantErr       bool
+	}{
+		{debit: "12,50", want: -1250},
+		{debit: "-12,50", want: -1250}, // le signe d'un débit est ignoré
+		{credit: "1 000,00", want: 100000},
+		{credit: "(3,00)", want: 300},
+		{debit: "2,00", credit: "5,00", want: 300},
+		{want: 0},
+		{debit: "abc", wantErr: true},
+		{credit: "1,001", want: 100100}, // 1,001 est lu comme mille un
+	}
+
+	for _, tt := range tests {
+		got, err := debitCreditAmount(tt.debit, tt.credit, "EUR")
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("debitCreditAmount(%q, %q) = %v, attendu une erreur", tt.debit, tt.credit, got)
+			}
+			continue
+		}
+		if err != nil || got != entity.NewMoney(tt.want, "EUR") {
+			t.Errorf("debitCreditAmount(%q, %q) = %v, %v, attendu %d EUR", tt.debit, tt.credit, got, err, tt.want)
+		}
+	}
+}
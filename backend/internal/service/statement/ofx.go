@@ -0,0 +1,108 @@
+package statement
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ofxFieldPattern capture la valeur d'une balise OFX, que le fichier soit en SGML (OFX 1.x,
+// balises non fermées) ou en XML (OFX 2.x)
+var ofxFieldPattern = regexp.MustCompile(`(?i)<([A-Z0-9.]+)>([^<\r\n]*)`)
+
+// ParseOFX lit les opérations (<STMTTRN>) d'un relevé OFX ou QFX, les montants dans la devise donnée
+func ParseOFX(r io.Reader, currency string) ([]Line, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("lecture du fichier OFX: %w", err)
+	}
+
+	text := string(content)
+	upper := strings.ToUpper(text)
+	if !strings.Contains(upper, "<OFX>") {
+		return nil, fmt.Errorf("fichier OFX invalide")
+	}
+
+	var lines []Line
+	for offset, number := 0, 1; ; number++ {
+		start := strings.Index(upper[offset:], "<STMTTRN>")
+		if start < 0 {
+			break
+		}
+		start += offset + len("<STMTTRN>")
+
+		end := strings.Index(upper[start:], "</STMTTRN>")
+		if end < 0 {
+			// SGML sans balise fermante : l'opération s'arrête à la suivante ou à la fin de la liste
+			end = strings.Index(upper[start:], "<STMTTRN>")
+			if listEnd := strings.Index(upper[start:], "</BANKTRANLIST>"); listEnd >= 0 && (end < 0 || listEnd < end) {
+				end = listEnd
+			}
+			if end < 0 {
+				end = len(upper) - start
+			}
+		}
+		block := text[start : start+end]
+		offset = start + end
+
+		lines = append(lines, parseOFXTransaction(block, number, currency))
+	}
+
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("aucune opération trouvée dans le fichier OFX")
+	}
+	return lines, nil
+}
+
+// parseOFXTransaction convertit le contenu d'une balise <STMTTRN> en ligne de relevé
+func parseOFXTransaction(block string, number int, currency string) Line {
+	fields := map[string]string{}
+	for _, match := range ofxFieldPattern.FindAllStringSubmatch(block, -1) {
+		fields[strings.ToUpper(match[1])] = strings.TrimSpace(match[2])
+	}
+
+	line := Line{
+		Number:      number,
+		Description: fields["NAME"],
+		Reference:   fields["FITID"],
+	}
+	if memo := fields["MEMO"]; memo != "" {
+		if line.Description == "" {
+			line.Description = memo
+		} else if !strings.EqualFold(memo, line.Description) {
+			line.Description += " - " + memo
+		}
+	}
+
+	date, err := parseOFXDate(fields["DTPOSTED"])
+	if err != nil {
+		line.Error = err.Error()
+		return line
+	}
+	line.Date = date
+
+	amount, err := parseAmount(fields["TRNAMT"], currency)
+	if err != nil {
+		line.Error = err.Error()
+		return line
+	}
+	if amount.IsZero() {
+		line.Error = "montant nul"
+	}
+	line.Amount = amount
+	return line
+}
+
+// parseOFXDate lit une date OFX (AAAAMMJJ, éventuellement suivie de l'heure et du fuseau)
+func parseOFXDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("date OFX invalide: %s", value)
+	}
+	date, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("date OFX invalide: %s", value)
+	}
+	return date, nil
+}
@@ -0,0 +1,140 @@
+package statement
+
+import (
+	"strings"
+	"testing"
+)
+
+// ofxSGML est un relevé OFX 1.x dont les balises ne sont pas fermées
+const ofxSGML = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>EUR
+<BANKTRANLIST>
+<DTSTART>20250101
+<DTEND>20250131
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20250115120000[+1:CET]
+<TRNAMT>-42.50
+<FITID>202501150001
+<NAME>CARREFOUR
+<MEMO>Carte 1234
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20250120
+<TRNAMT>1500,00
+<FITID>202501200002
+<NAME>SALAIRE
+<MEMO>salaire
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>2025
+<TRNAMT>-1.00
+<FITID>202501210003
+<NAME>DATE TRONQUEE
+</BANKTRANLIST>
+<LEDGERBAL><BALAMT>1457.50<DTASOF>20250131
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+
+// ofxXML est un relevé OFX 2.x en dinars koweïtiens (trois décimales)
+const ofxXML = `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="220"?>
+<OFX>
+  <BANKMSGSRSV1><STMTTRNRS><STMTRS>
+    <CURDEF>KWD</CURDEF>
+    <BANKTRANLIST>
+      <STMTTRN>
+        <TRNTYPE>DEBIT</TRNTYPE>
+        <DTPOSTED>20250301</DTPOSTED>
+        <TRNAMT>-3.750</TRNAMT>
+        <FITID>A1</FITID>
+        <MEMO>Boulangerie</MEMO>
+      </STMTTRN>
+      <STMTTRN>
+        <TRNTYPE>OTHER</TRNTYPE>
+        <DTPOSTED>20250302</DTPOSTED>
+        <TRNAMT>0.000</TRNAMT>
+        <FITID>A2</FITID>
+        <NAME>Frais annulés</NAME>
+      </STMTTRN>
+    </BANKTRANLIST>
+  </STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+
+func TestParseOFX(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		currency string
+		want     []csvLine
+	}{
+		{
+			name:     "SGML",
+			content:  ofxSGML,
+			currency: "EUR",
+			want: []csvLine{
+				{number: 1, date: "2025-01-15", amount: -4250, description: "CARREFOUR - Carte 1234", reference: "202501150001"},
+				{number: 2, date: "2025-01-20", amount: 150000, description: "SALAIRE", reference: "202501200002"},
+				{number: 3, description: "DATE TRONQUEE", reference: "202501210003", hasError: true},
+			},
+		},
+		{
+			name:     "XML à trois décimales",
+			content:  ofxXML,
+			currency: "KWD",
+			want: []csvLine{
+				{number: 1, date: "2025-03-01", amount: -3750, description: "Boulangerie", reference: "A1"},
+				{number: 2, date: "2025-03-02", description: "Frais annulés", reference: "A2", hasError: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := ParseOFX(strings.NewReader(tt.content), tt.currency)
+			if err != nil {
+				t.Fatalf("ParseOFX: %v", err)
+			}
+			if len(lines) != len(tt.want) {
+				t.Fatalf("%d opérations, attendu %d", len(lines), len(tt.want))
+			}
+			for i, line := range lines {
+				if got := summarize(line); got != tt.want[i] {
+					t.Errorf("opération %d = %+v, attendu %+v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseOFXErrors(t *testing.T) {
+	tests := map[string]string{
+		"pas un fichier OFX": "date;libellé;montant\n",
+		"aucune opération":   "<OFX><BANKTRANLIST></BANKTRANLIST></OFX>",
+	}
+	for name, content := range tests {
+		if _, err := ParseOFX(strings.NewReader(content), "EUR"); err == nil {
+			t.Errorf("%s : ParseOFX devrait échouer", name)
+		}
+	}
+}
+
+func TestParseOFXDate(t *testing.T) {
+	for _, value := range []string{"20250115", "20250115120000", "20250115120000.000[-5:EST]"} {
+		if got, err := parseOFXDate(value); err != nil || got.Format("2006-01-02") != "2025-01-15" {
+			t.Errorf("parseOFXDate(%q) = %s, %v, attendu 2025-01-15", value, got.Format("2006-01-02"), err)
+		}
+	}
+	for _, value := range []string{"", "2025", "2025AB15", "20251315"} {
+		if _, err := parseOFXDate(value); err == nil {
+			t.Errorf("parseOFXDate(%q) devrait échouer", value)
+		}
+	}
+}
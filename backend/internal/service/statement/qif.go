@@ -0,0 +1,109 @@
+package statement
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// qifDateFormats sont les formats de date QIF essayés par défaut (les apostrophes sont normalisées en /)
+var qifDateFormats = []string{"01/02/2006", "1/2/2006", "01/02/06", "1/2/06", "02/01/2006", "2006-01-02"}
+
+// ParseQIF lit les opérations d'un relevé QIF (sections !Type:Bank, !Type:Cash, !Type:CCard...), les montants
+// dans la devise donnée
+func ParseQIF(r io.Reader, dateFormat, currency string) ([]Line, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var (
+		lines   []Line
+		current Line
+		payee   string
+		memo    string
+		rawDate string
+		rawAmt  string
+		started bool
+	)
+
+	flush := func() {
+		if !started {
+			return
+		}
+		current.Number = len(lines) + 1
+		current.Description = payee
+		if memo != "" {
+			if current.Description == "" {
+				current.Description = memo
+			} else if !strings.EqualFold(memo, payee) {
+				current.Description += " - " + memo
+			}
+		}
+
+		if date, err := parseQIFDate(rawDate, dateFormat); err != nil {
+			current.Error = err.Error()
+		} else {
+			current.Date = date
+			if amount, err := parseAmount(rawAmt, currency); err != nil {
+				current.Error = err.Error()
+			} else if amount.IsZero() {
+				current.Error = "montant nul"
+			} else {
+				current.Amount = amount
+			}
+		}
+
+		lines = append(lines, current)
+		current, payee, memo, rawDate, rawAmt, started = Line{}, "", "", "", "", false
+	}
+
+	for scanner.Scan() {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if text == "" {
+			continue
+		}
+		if strings.HasPrefix(text, "!") {
+			// en-tête de section (!Type:Bank, !Option:...)
+			continue
+		}
+
+		code, value := text[0], strings.TrimSpace(text[1:])
+		switch code {
+		case '^':
+			flush()
+		case 'D':
+			rawDate, started = value, true
+		case 'T', 'U':
+			rawAmt, started = value, true
+		case 'P':
+			payee, started = value, true
+		case 'M':
+			memo, started = value, true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("lecture du fichier QIF: %w", err)
+	}
+	flush()
+
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("aucune opération trouvée dans le fichier QIF")
+	}
+	return lines, nil
+}
+
+// parseQIFDate lit une date QIF ; l'apostrophe des années abrégées (12/31'99) est acceptée
+func parseQIFDate(value, layout string) (time.Time, error) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), "'", "/")
+	value = strings.ReplaceAll(value, " ", "")
+	if layout != "" {
+		return parseDate(value, layout)
+	}
+	for _, candidate := range qifDateFormats {
+		if date, err := parseDate(value, candidate); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("date QIF invalide: %s", value)
+}
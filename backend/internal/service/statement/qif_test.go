@@ -0,0 +1,71 @@
+package statement
+
+import (
+	"strings"
+	"testing"
+)
+
+const qifBank = `!Type:Bank
+D01/15'25
+T-1,234.56
+PCarrefour
+MCourses
+^
+D1/20/2025
+U2,500.00
+T2,500.00
+PSalaire
+Msalaire
+^
+D13/45/2025
+T10.00
+PDate invalide
+^
+D01/22/2025
+T0.00
+PMontant nul
+^
+D01/23/2025
+MSans bénéficiaire
+T-5
+`
+
+func TestParseQIF(t *testing.T) {
+	lines, err := ParseQIF(strings.NewReader(qifBank), "", "EUR")
+	if err != nil {
+		t.Fatalf("ParseQIF: %v", err)
+	}
+	want := []csvLine{
+		{number: 1, date: "2025-01-15", amount: -123456, description: "Carrefour - Courses"},
+		{number: 2, date: "2025-01-20", amount: 250000, description: "Salaire"},
+		{number: 3, description: "Date invalide", hasError: true},
+		{number: 4, date: "2025-01-22", description: "Montant nul", hasError: true},
+		{number: 5, date: "2025-01-23", amount: -500, description: "Sans bénéficiaire"}, // sans ^ final
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("%d opérations, attendu %d", len(lines), len(want))
+	}
+	for i, line := range lines {
+		if got := summarize(line); got != want[i] {
+			t.Errorf("opération %d = %+v, attendu %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestParseQIFDateFormat(t *testing.T) {
+	content := "!Type:Cash\r\nD15/01/2025\r\nT-1 500\r\nPMarché\r\n^\r\n"
+	lines, err := ParseQIF(strings.NewReader(content), "02/01/2006", "XAF")
+	if err != nil {
+		t.Fatalf("ParseQIF: %v", err)
+	}
+	want := csvLine{number: 1, date: "2025-01-15", amount: -1500, description: "Marché"}
+	if len(lines) != 1 || summarize(lines[0]) != want {
+		t.Errorf("ParseQIF = %+v, attendu %+v", lines, want)
+	}
+}
+
+func TestParseQIFEmpty(t *testing.T) {
+	if _, err := ParseQIF(strings.NewReader("!Type:Bank\n"), "", "EUR"); err == nil {
+		t.Error("ParseQIF d'un fichier sans opération devrait échouer")
+	}
+}
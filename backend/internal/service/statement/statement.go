@@ -0,0 +1,153 @@
+package statement
+
+import (
+	"backend/internal/domaine/entity"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Formats de relevé supportés
+const (
+	FormatCSV = "csv"
+	FormatOFX = "ofx"
+	FormatQIF = "qif"
+)
+
+// Line représente une ligne de relevé bancaire normalisée
+type Line struct {
+	Number      int          // numéro de la ligne (ou de l'opération) dans le fichier
+	Date        time.Time    // date de l'opération
+	Amount      entity.Money // montant signé : positif pour un crédit, négatif pour un débit
+	Description string       // libellé de l'opération
+	Reference   string       // identifiant fourni par la banque (FITID, numéro de chèque...), peut être vide
+	Error       string       // erreur de lecture de la ligne, la ligne n'est alors pas importable
+}
+
+// Options regroupe les paramètres de lecture d'un relevé
+type Options struct {
+	Currency   string     // devise du compte : un montant plus précis qu'elle ne l'admet rend la ligne invalide
+	DateFormat string     // format Go des dates (CSV et QIF) ; plusieurs formats usuels sont essayés par défaut
+	CSV        CSVMapping // correspondance des colonnes pour un CSV
+}
+
+// Parse lit un relevé dans le format donné
+func Parse(format string, r io.Reader, opts Options) ([]Line, error) {
+	switch format {
+	case FormatCSV:
+		return ParseCSV(r, opts.CSV, opts.DateFormat, opts.Currency)
+	case FormatOFX:
+		return ParseOFX(r, opts.Currency)
+	case FormatQIF:
+		return ParseQIF(r, opts.DateFormat, opts.Currency)
+	default:
+		return nil, fmt.Errorf("format de relevé non supporté: %s", format)
+	}
+}
+
+// DetectFormat déduit le format d'un relevé à partir de l'extension du fichier
+func DetectFormat(fileName string) string {
+	name := strings.ToLower(fileName)
+	switch {
+	case strings.HasSuffix(name, ".csv"), strings.HasSuffix(name, ".txt"):
+		return FormatCSV
+	case strings.HasSuffix(name, ".ofx"), strings.HasSuffix(name, ".qfx"):
+		return FormatOFX
+	case strings.HasSuffix(name, ".qif"):
+		return FormatQIF
+	default:
+		return ""
+	}
+}
+
+// Fingerprints calcule pour chaque ligne une référence stable servant à détecter les lignes déjà importées.
+// La référence bancaire est utilisée quand elle existe ; sinon une empreinte de la date, du montant exact
+// en unités mineures et du libellé est calculée, complétée par le rang de la ligne parmi ses doublons exacts dans le fichier
+// (deux cafés identiques le même jour restent deux opérations distinctes).
+func Fingerprints(lines []Line) []string {
+	refs := make([]string, len(lines))
+	seen := make(map[string]int)
+	for i, line := range lines {
+		if line.Error != "" {
+			continue
+		}
+		if line.Reference != "" {
+			refs[i] = "stmt:" + line.Reference
+			continue
+		}
+
+		key := fmt.Sprintf("%s|%d|%s", line.Date.Format("2006-01-02"), line.Amount.Minor, strings.ToLower(strings.Join(strings.Fields(line.Description), " ")))
+		seen[key]++
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d", key, seen[key])))
+		refs[i] = "stmt:" + hex.EncodeToString(sum[:16])
+	}
+	return refs
+}
+
+// defaultDateFormats sont essayés lorsque aucun format de date n'est fourni
+var defaultDateFormats = []string{
+	"2006-01-02",
+	"02/01/2006",
+	"02-01-2006",
+	"02.01.2006",
+	"2006/01/02",
+	"20060102",
+	"02/01/06",
+}
+
+// parseDate lit une date avec le format fourni, ou à défaut avec les formats usuels
+func parseDate(value, layout string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if layout != "" {
+		return time.Parse(layout, value)
+	}
+	for _, candidate := range defaultDateFormats {
+		if date, err := time.Parse(candidate, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("date invalide: %s", value)
+}
+
+// parseAmount lit un montant dans la devise du compte en acceptant la virgule décimale, les espaces et
+// séparateurs de milliers
+func parseAmount(value, currency string) (entity.Money, error) {
+	value = strings.TrimSpace(value)
+	value = strings.NewReplacer(" ", "", "\u00a0", "", "\u202f", "").Replace(value)
+	if value == "" {
+		return entity.Money{}, fmt.Errorf("montant vide")
+	}
+
+	negative := false
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative = true
+		value = strings.Trim(value, "()")
+	}
+
+	// Le dernier séparateur rencontré est le séparateur décimal, sauf s'il est suivi de exactement trois
+	// chiffres sans autre séparateur (15,000 ou 12.500 sont des milliers) dans une devise qui n'a pas trois
+	// décimales (12.500 KWD vaut douze dinars et demi)
+	lastSeparator := strings.LastIndexAny(value, ",.")
+	if lastSeparator >= 0 {
+		separator := value[lastSeparator]
+		integerPart, decimalPart := value[:lastSeparator], value[lastSeparator+1:]
+		thousands := len(decimalPart) == 3 && !strings.ContainsAny(integerPart, ",.") && entity.CurrencyExponent(currency) < 3
+		if thousands || strings.Count(value, string(separator)) > 1 {
+			value = strings.NewReplacer(",", "", ".", "").Replace(value)
+		} else {
+			value = strings.NewReplacer(",", "", ".", "").Replace(integerPart) + "." + decimalPart
+		}
+	}
+
+	amount, err := entity.ParseMoney(value, currency)
+	if err != nil {
+		return entity.Money{}, err
+	}
+	if negative {
+		amount = amount.Neg()
+	}
+	return amount, nil
+}
@@ -0,0 +1,133 @@
+package statement
+
+import (
+	"backend/internal/domaine/entity"
+	"testing"
+	"time"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		value    string
+		currency string
+		want     int64
+		wantErr  bool
+	}{
+		{value: "1.234,56", currency: "EUR", want: 123456},
+		{value: "1,234.56", currency: "EUR", want: 123456},
+		{value: "(1,500)", currency: "XAF", want: -1500},
+		{value: "(12,50)", currency: "EUR", want: -1250},
+		{value: "15,000", currency: "XAF", want: 15000},
+		{value: "12.500", currency: "XAF", want: 12500},
+		{value: "12.500", currency: "EUR", want: 1250000},
+		{value: "12.500", currency: "KWD", want: 12500},
+		{value: "1,234.567", currency: "KWD", want: 1234567},
+		{value: "1.234.567", currency: "XAF", want: 1234567},
+		{value: "1 234,56", currency: "EUR", want: 123456},
+		{value: "1\u00a0234,56", currency: "EUR", want: 123456},
+		{value: "-2\u202f500", currency: "XAF", want: -2500},
+		{value: "(1\u00a0500)", currency: "XAF", want: -1500},
+		{value: " 450 000 ", currency: "XAF", want: 450000},
+		{value: "12,5", currency: "EUR", want: 1250},
+		{value: "-45.10", currency: "EUR", want: -4510},
+		{value: "+7", currency: "EUR", want: 700},
+		{value: "1500.00", currency: "XAF", want: 1500},
+		{value: "12.50", currency: "XAF", wantErr: true},
+		{value: "0.0001", currency: "EUR", wantErr: true},
+		{value: "", currency: "EUR", wantErr: true},
+		{value: "\u00a0", currency: "EUR", wantErr: true},
+		{value: "abc", currency: "EUR", wantErr: true},
+		{value: "12€", currency: "EUR", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseAmount(tt.value, tt.currency)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseAmount(%q, %s) = %v, attendu une erreur", tt.value, tt.currency, got)
+			}
+			continue
+		}
+		if err != nil || got != entity.NewMoney(tt.want, tt.currency) {
+			t.Errorf("parseAmount(%q, %s) = %v, %v, attendu %d %s", tt.value, tt.currency, got, err, tt.want, tt.currency)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	want := time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC)
+	for _, value := range []string{"2025-01-15", "15/01/2025", "15-01-2025", "15.01.2025", "2025/01/15", "20250115", "15/01/25", " 15/01/2025 "} {
+		if got, err := parseDate(value, ""); err != nil || !got.Equal(want) {
+			t.Errorf("parseDate(%q) = %s, %v, attendu 2025-01-15", value, got.Format("2006-01-02"), err)
+		}
+	}
+	if got, err := parseDate("01/15/2025", "01/02/2006"); err != nil || !got.Equal(want) {
+		t.Errorf("parseDate avec format = %s, %v, attendu 2025-01-15", got.Format("2006-01-02"), err)
+	}
+	if _, err := parseDate("32/01/2025", ""); err == nil {
+		t.Error("parseDate(32/01/2025) devrait échouer")
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := map[string]string{
+		"releve.csv":     FormatCSV,
+		"RELEVE.TXT":     FormatCSV,
+		"export.ofx":     FormatOFX,
+		"export.QFX":     FormatOFX,
+		"quicken.qif":    FormatQIF,
+		"releve.pdf":     "",
+		"sans_extension": "",
+	}
+	for fileName, want := range tests {
+		if got := DetectFormat(fileName); got != want {
+			t.Errorf("DetectFormat(%q) = %q, attendu %q", fileName, got, want)
+		}
+	}
+}
+
+func TestFingerprints(t *testing.T) {
+	date := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	line := func(minor int64, currency, description string) Line {
+		return Line{Date: date, Amount: entity.NewMoney(minor, currency), Description: description}
+	}
+
+	t.Run("référence bancaire", func(t *testing.T) {
+		withReference := line(-1500, "XAF", "Café")
+		withReference.Reference = "FITID42"
+		refs := Fingerprints([]Line{withReference, {Error: "date invalide"}})
+		if refs[0] != "stmt:FITID42" {
+			t.Errorf("référence = %q, attendu stmt:FITID42", refs[0])
+		}
+		if refs[1] != "" {
+			t.Errorf("une ligne invalide ne doit pas avoir de référence, obtenu %q", refs[1])
+		}
+	})
+
+	t.Run("doublons exacts dans le fichier", func(t *testing.T) {
+		refs := Fingerprints([]Line{line(-1500, "XAF", "Café"), line(-1500, "XAF", "Café")})
+		if refs[0] == refs[1] {
+			t.Errorf("deux opérations identiques du même fichier ont la même référence %q", refs[0])
+		}
+		again := Fingerprints([]Line{line(-1500, "XAF", "Café"), line(-1500, "XAF", "Café")})
+		if again[0] != refs[0] || again[1] != refs[1] {
+			t.Error("les références d'un même fichier doivent être stables d'un import à l'autre")
+		}
+	})
+
+	t.Run("libellé normalisé", func(t *testing.T) {
+		a := Fingerprints([]Line{line(-1500, "XAF", "  Café   du  Coin ")})
+		b := Fingerprints([]Line{line(-1500, "XAF", "CAFÉ DU COIN")})
+		if a[0] != b[0] {
+			t.Errorf("la casse et les espaces du libellé ne doivent pas changer la référence: %q, %q", a[0], b[0])
+		}
+	})
+
+	t.Run("montants à trois décimales", func(t *testing.T) {
+		// 1.231 et 1.234 KWD s'écrivent tous deux 1.23 avec deux décimales
+		refs := Fingerprints([]Line{line(-1231, "KWD", "Taxi"), line(-1234, "KWD", "Taxi")})
+		if refs[0] == refs[1] {
+			t.Errorf("des montants différents à la troisième décimale ont la même référence %q", refs[0])
+		}
+	})
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/internal/domaine/repository"
+	"backend/pkg/logger"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultTagColor est la couleur d'une étiquette créée sans couleur
+	defaultTagColor = "#95A5A6"
+	// maxBulkTagTransactions borne le nombre de transactions d'un étiquetage en masse
+	maxBulkTagTransactions = 500
+	// maxBulkTags borne le nombre d'étiquettes d'un étiquetage en masse
+	maxBulkTags = 20
+)
+
+// TagService gère les étiquettes de l'utilisateur et leur pose sur les transactions
+type TagService struct {
+	tagRepo         repository.TagRepository
+	transactionRepo repository.TransactionRepository
+	logger          logger.Logger
+}
+
+// NewTagService crée une nouvelle instance de TagService
+func NewTagService(
+	tagRepo repository.TagRepository,
+	transactionRepo repository.TransactionRepository,
+	logger logger.Logger,
+) *TagService {
+	return &TagService{
+		tagRepo:         tagRepo,
+		transactionRepo: transactionRepo,
+		logger:          logger,
+	}
+}
+
+// CreateTag crée une étiquette ; le nom est unique par utilisateur, sans tenir compte de la casse
+func (s *TagService) CreateTag(ctx context.Context, userID uuid.UUID, req entity.CreateTagRequest) (*entity.Tag, error) {
+	name, err := validateTagName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkNameAvailable(ctx, userID, name, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	color := strings.TrimSpace(req.Color)
+	if color == "" {
+		color = defaultTagColor
+	}
+
+	tag := &entity.Tag{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      name,
+		Color:     color,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := s.tagRepo.Create(ctx, tag); err != nil {
+		s.logger.Error("Erreur création étiquette", logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Étiquette créée avec succès",
+		logger.String("tag_id", tag.ID.String()),
+		logger.String("user_id", userID.String()),
+	)
+	return tag, nil
+}
+
+// GetTags récupère les étiquettes de l'utilisateur
+func (s *TagService) GetTags(ctx context.Context, userID uuid.UUID) ([]*entity.Tag, error) {
+	return s.tagRepo.GetByUserID(ctx, userID)
+}
+
+// GetTag récupère une étiquette de l'utilisateur
+func (s *TagService) GetTag(ctx context.Context, userID, tagID uuid.UUID) (*entity.Tag, error) {
+	tag, err := s.tagRepo.GetByID(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.UserID != userID {
+		return nil, entity.ErrTagNotFound
+	}
+	return tag, nil
+}
+
+// UpdateTag renomme ou recolore une étiquette
+func (s *TagService) UpdateTag(ctx context.Context, userID, tagID uuid.UUID, req entity.UpdateTagRequest) (*entity.Tag, error) {
+	tag, err := s.GetTag(ctx, userID, tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name, err := validateTagName(*req.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkNameAvailable(ctx, userID, name, tag.ID); err != nil {
+			return nil, err
+		}
+		tag.Name = name
+	}
+	if req.Color != nil {
+		tag.Color = strings.TrimSpace(*req.Color)
+		if tag.Color == "" {
+			tag.Color = defaultTagColor
+		}
+	}
+	tag.UpdatedAt = time.Now()
+
+	if err := s.tagRepo.Update(ctx, tag); err != nil {
+		s.logger.Error("Erreur mise à jour étiquette", logger.Error(err))
+		return nil, err
+	}
+	return tag, nil
+}
+
+// DeleteTag supprime une étiquette et la retire de toutes les transactions
+func (s *TagService) DeleteTag(ctx context.Context, userID, tagID uuid.UUID) error {
+	if _, err := s.GetTag(ctx, userID, tagID); err != nil {
+		return err
+	}
+	if err := s.tagRepo.Delete(ctx, tagID); err != nil {
+		s.logger.Error("Erreur suppression étiquette", logger.Error(err))
+		return err
+	}
+
+	s.logger.Info("Étiquette supprimée avec succès",
+		logger.String("tag_id", tagID.String()),
+		logger.String("user_id", userID.String()),
+	)
+	return nil
+}
+
+// TagTransactions pose des étiquettes sur plusieurs transactions ; les étiquettes déjà posées sont conservées
+func (s *TagService) TagTransactions(ctx context.Context, userID uuid.UUID, req entity.TagTransactionsRequest) (*entity.TagTransactionsResponse, error) {
+	transactionIDs, tagIDs, err := s.checkBulkRequest(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := s.tagRepo.AddToTransactions(ctx, transactionIDs, tagIDs)
+	if err != nil {
+		s.logger.Error("Erreur étiquetage des transactions", logger.Error(err))
+		return nil, err
+	}
+	return &entity.TagTransactionsResponse{TransactionCount: len(transactionIDs), AffectedCount: affected}, nil
+}
+
+// UntagTransactions retire des étiquettes de plusieurs transactions
+func (s *TagService) UntagTransactions(ctx context.Context, userID uuid.UUID, req entity.TagTransactionsRequest) (*entity.TagTransactionsResponse, error) {
+	transactionIDs, tagIDs, err := s.checkBulkRequest(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := s.tagRepo.RemoveFromTransactions(ctx, transactionIDs, tagIDs)
+	if err != nil {
+		s.logger.Error("Erreur retrait des étiquettes", logger.Error(err))
+		return nil, err
+	}
+	return &entity.TagTransactionsResponse{TransactionCount: len(transactionIDs), AffectedCount: affected}, nil
+}
+
+// checkBulkRequest valide un étiquetage en masse et vérifie que transactions et étiquettes appartiennent
+// à l'utilisateur ; retourne les IDs sans doublons
+func (s *TagService) checkBulkRequest(ctx context.Context, userID uuid.UUID, req entity.TagTransactionsRequest) ([]uuid.UUID, []uuid.UUID, error) {
+	transactionIDs, tagIDs := uniqueIDs(req.TransactionIDs), uniqueIDs(req.TagIDs)
+	if len(transactionIDs) == 0 || len(tagIDs) == 0 {
+		return nil, nil, fmt.Errorf("%w: transactions et étiquettes requises", entity.ErrInvalidTagData)
+	}
+	if len(transactionIDs) > maxBulkTagTransactions {
+		return nil, nil, fmt.Errorf("%w: %d transactions maximum", entity.ErrInvalidTagData, maxBulkTagTransactions)
+	}
+	if len(tagIDs) > maxBulkTags {
+		return nil, nil, fmt.Errorf("%w: %d étiquettes maximum", entity.ErrInvalidTagData, maxBulkTags)
+	}
+
+	tags, err := s.tagRepo.GetByIDs(ctx, userID, tagIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tags) != len(tagIDs) {
+		return nil, nil, fmt.Errorf("%w: étiquette inconnue", entity.ErrTagNotFound)
+	}
+
+	transactions, err := s.transactionRepo.GetByIDs(ctx, userID, transactionIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(transactions) != len(transactionIDs) {
+		return nil, nil, fmt.Errorf("%w: transaction inconnue", entity.ErrTransactionNotFound)
+	}
+
+	return transactionIDs, tagIDs, nil
+}
+
+// checkNameAvailable vérifie qu'aucune autre étiquette de l'utilisateur ne porte ce nom (casse ignorée)
+func (s *TagService) checkNameAvailable(ctx context.Context, userID uuid.UUID, name string, exceptID uuid.UUID) error {
+	tags, err := s.tagRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if tag.ID != exceptID && strings.EqualFold(tag.Name, name) {
+			return fmt.Errorf("%w: %s", entity.ErrTagAlreadyExists, tag.Name)
+		}
+	}
+	return nil
+}
+
+// validateTagName nettoie et valide le nom d'une étiquette
+func validateTagName(name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return "", fmt.Errorf("%w: le nom est requis", entity.ErrInvalidTagData)
+	}
+	if len([]rune(name)) > 50 {
+		return "", fmt.Errorf("%w: le nom ne doit pas dépasser 50 caractères", entity.ErrInvalidTagData)
+	}
+	return name, nil
+}
@@ -0,0 +1,620 @@
+package service
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/internal/domaine/repository"
+	"backend/pkg/logger"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Statuts d'une tontine, de ses tours et de ses cotisations
+const (
+	tontineStatusActive    = "active"
+	tontineStatusCompleted = "completed"
+
+	tontineCycleStatusOpen    = "open"
+	tontineCycleStatusPaidOut = "paid_out"
+
+	contributionStatusPending = "pending"
+	contributionStatusPaid    = "paid"
+)
+
+const (
+	// tontineMaxMembers borne le nombre de membres (et donc de tours) d'une tontine
+	tontineMaxMembers = 100
+	// tontineReminderDaysAhead est le délai (en jours) avant le tour à partir duquel la cotisation est rappelée
+	tontineReminderDaysAhead = 2
+	// tontineReminderBatchSize est le nombre maximum de cotisations traitées par passage du planificateur des rappels
+	tontineReminderBatchSize = 500
+)
+
+// TontineService gère les tontines : membres et ordre de passage, suivi des cotisations de chaque tour, cotisations
+// et cagnotte de l'utilisateur transférées entre ses comptes et le compte de la tontine, et rappels des cotisations
+type TontineService struct {
+	tontineRepo         repository.TontineRepository
+	accountRepo         repository.AccountRepository
+	transactionService  *TransactionService
+	exchangeRates       *ExchangeRateService
+	notificationService *NotificationService
+	txManager           repository.TxManager
+	logger              logger.Logger
+}
+
+// NewTontineService crée une nouvelle instance de TontineService
+func NewTontineService(
+	tontineRepo repository.TontineRepository,
+	accountRepo repository.AccountRepository,
+	transactionService *TransactionService,
+	exchangeRates *ExchangeRateService,
+	notificationService *NotificationService,
+	txManager repository.TxManager,
+	logger logger.Logger,
+) *TontineService {
+	return &TontineService{
+		tontineRepo:         tontineRepo,
+		accountRepo:         accountRepo,
+		transactionService:  transactionService,
+		exchangeRates:       exchangeRates,
+		notificationService: notificationService,
+		txManager:           txManager,
+		logger:              logger,
+	}
+}
+
+// CreateTontine enregistre une tontine, crée le compte de type tontine qui la suit et génère ses tours : un par
+// membre, dans l'ordre de passage, chacun attendant la cotisation de tous les membres
+func (s *TontineService) CreateTontine(ctx context.Context, userID uuid.UUID, req entity.CreateTontineRequest) (*entity.Tontine, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, fmt.Errorf("%w: le nom est requis", entity.ErrInvalidTontineData)
+	}
+	switch req.Frequency {
+	case "weekly", "biweekly", "monthly":
+	default:
+		return nil, fmt.Errorf("%w: fréquence invalide: %s", entity.ErrInvalidTontineData, req.Frequency)
+	}
+	if req.StartDate.IsZero() {
+		return nil, fmt.Errorf("%w: la date du premier tour est requise", entity.ErrInvalidTontineData)
+	}
+	if len(req.Members) < 2 || len(req.Members) > tontineMaxMembers {
+		return nil, fmt.Errorf("%w: une tontine compte entre 2 et %d membres", entity.ErrInvalidTontineData, tontineMaxMembers)
+	}
+	selfCount := 0
+	for _, member := range req.Members {
+		if strings.TrimSpace(member.Name) == "" {
+			return nil, fmt.Errorf("%w: le nom de chaque membre est requis", entity.ErrInvalidTontineData)
+		}
+		if member.IsSelf {
+			selfCount++
+		}
+	}
+	if selfCount != 1 {
+		return nil, fmt.Errorf("%w: exactement un membre doit désigner l'utilisateur", entity.ErrInvalidTontineData)
+	}
+
+	currency, err := s.tontineCurrency(ctx, userID, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := req.ContributionAmount.Money(currency)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", entity.ErrInvalidTontineData, err)
+	}
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("%w: la cotisation doit être positive", entity.ErrInvalidTontineData)
+	}
+
+	tontine := &entity.Tontine{
+		ID:                 uuid.New(),
+		UserID:             userID,
+		AccountID:          uuid.New(),
+		Name:               strings.TrimSpace(req.Name),
+		ContributionAmount: amount,
+		Frequency:          req.Frequency,
+		StartDate:          truncateToDay(req.StartDate),
+		Currency:           currency,
+		TotalContributed:   entity.NewMoney(0, currency),
+		TotalReceived:      entity.NewMoney(0, currency),
+		Status:             tontineStatusActive,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
+	}
+
+	members := make([]*entity.TontineMember, len(req.Members))
+	for i, member := range req.Members {
+		members[i] = &entity.TontineMember{
+			ID:          uuid.New(),
+			TontineID:   tontine.ID,
+			Name:        strings.TrimSpace(member.Name),
+			Phone:       member.Phone,
+			PayoutOrder: i + 1,
+			IsSelf:      member.IsSelf,
+			CreatedAt:   time.Now(),
+		}
+	}
+
+	payout := amount.Mul(int64(len(members)))
+	cycles := make([]*entity.TontineCycle, len(members))
+	var contributions []*entity.TontineContribution
+	for i, beneficiary := range members {
+		cycles[i] = &entity.TontineCycle{
+			ID:            uuid.New(),
+			TontineID:     tontine.ID,
+			Number:        i + 1,
+			DueDate:       tontineDueDate(tontine.StartDate, tontine.Frequency, i),
+			BeneficiaryID: beneficiary.ID,
+			PayoutAmount:  payout,
+			Currency:      currency,
+			Status:        tontineCycleStatusOpen,
+		}
+		for _, member := range members {
+			contributions = append(contributions, &entity.TontineContribution{
+				ID:        uuid.New(),
+				TontineID: tontine.ID,
+				CycleID:   cycles[i].ID,
+				MemberID:  member.ID,
+				UserID:    userID,
+				DueDate:   cycles[i].DueDate,
+				Amount:    amount,
+				Currency:  currency,
+				IsSelf:    member.IsSelf,
+				Status:    contributionStatusPending,
+			})
+		}
+	}
+
+	account := &entity.Account{
+		ID:               tontine.AccountID,
+		UserID:           userID,
+		Name:             tontine.Name,
+		Type:             "tontine",
+		Balance:          entity.NewMoney(0, currency),
+		PendingBalance:   entity.NewMoney(0, currency),
+		AvailableBalance: entity.NewMoney(0, currency),
+		Currency:         currency,
+		Icon:             "fa5:users",
+		Color:            "#00B894",
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+	}
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.accountRepo.Create(ctx, account); err != nil {
+			return err
+		}
+		if err := s.tontineRepo.Create(ctx, tontine); err != nil {
+			return err
+		}
+		if err := s.tontineRepo.CreateMembers(ctx, members); err != nil {
+			return err
+		}
+		if err := s.tontineRepo.CreateCycles(ctx, cycles); err != nil {
+			return err
+		}
+		return s.tontineRepo.CreateContributions(ctx, contributions)
+	})
+	if err != nil {
+		s.logger.Error("Erreur création tontine", logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Tontine créée avec succès",
+		logger.String("tontine_id", tontine.ID.String()),
+		logger.String("user_id", userID.String()),
+		logger.Int("members", len(members)),
+		logger.String("contribution", amount.String()),
+	)
+
+	return s.GetTontine(ctx, userID, tontine.ID)
+}
+
+// GetTontine récupère une tontine avec ses membres et ses tours
+func (s *TontineService) GetTontine(ctx context.Context, userID, tontineID uuid.UUID) (*entity.Tontine, error) {
+	tontine, err := s.tontineRepo.GetByID(ctx, tontineID)
+	if err != nil {
+		return nil, err
+	}
+	if tontine.UserID != userID {
+		return nil, entity.ErrTontineNotFound
+	}
+	return tontine, nil
+}
+
+// GetTontines récupère les tontines de l'utilisateur
+func (s *TontineService) GetTontines(ctx context.Context, userID uuid.UUID) ([]*entity.Tontine, error) {
+	tontines, err := s.tontineRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("Erreur récupération tontines", logger.Error(err))
+		return nil, err
+	}
+	return tontines, nil
+}
+
+// DeleteTontine supprime une tontine avec ses membres, ses tours et ses cotisations. Le compte de la tontine et les
+// transferts des cotisations et cagnottes de l'utilisateur sont conservés.
+func (s *TontineService) DeleteTontine(ctx context.Context, userID, tontineID uuid.UUID) error {
+	if _, err := s.GetTontine(ctx, userID, tontineID); err != nil {
+		return err
+	}
+
+	if err := s.tontineRepo.Delete(ctx, tontineID); err != nil {
+		s.logger.Error("Erreur suppression tontine", logger.Error(err))
+		return err
+	}
+
+	s.logger.Info("Tontine supprimée avec succès",
+		logger.String("tontine_id", tontineID.String()),
+		logger.String("user_id", userID.String()),
+	)
+	return nil
+}
+
+// RecordContribution enregistre la cotisation d'un membre pour un tour. La cotisation de l'utilisateur est
+// transférée de son compte vers le compte de la tontine ; celles des autres membres sont seulement pointées.
+func (s *TontineService) RecordContribution(ctx context.Context, userID, tontineID uuid.UUID, number int, req entity.RecordTontineContributionRequest) (*entity.Tontine, error) {
+	date := truncateToDay(time.Now())
+	if req.Date != nil {
+		date = truncateToDay(*req.Date)
+	}
+
+	var contribution *entity.TontineContribution
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		tontine, err := s.lockTontine(ctx, userID, tontineID)
+		if err != nil {
+			return err
+		}
+		cycle, err := s.tontineRepo.GetCycle(ctx, tontineID, number)
+		if err != nil {
+			return err
+		}
+		for _, candidate := range cycle.Contributions {
+			if candidate.MemberID == req.MemberID {
+				contribution = candidate
+				break
+			}
+		}
+		if contribution == nil {
+			return fmt.Errorf("%w: membre non trouvé", entity.ErrInvalidTontineData)
+		}
+		if contribution.Status == contributionStatusPaid {
+			return entity.ErrTontineContributionRecorded
+		}
+
+		if contribution.IsSelf {
+			if err := s.checkAccount(ctx, userID, tontine, req.AccountID); err != nil {
+				return err
+			}
+			transfer, err := s.transactionService.CreateTransaction(ctx, userID, entity.CreateTransactionRequest{
+				AccountID:      req.AccountID,
+				ToAccountID:    &tontine.AccountID,
+				Type:           "transfer",
+				Amount:         entity.DecimalOf(contribution.Amount),
+				Description:    fmt.Sprintf("Cotisation %s - tour %d", tontine.Name, number),
+				Date:           date,
+				AllowDuplicate: true,
+			})
+			if err != nil {
+				return err
+			}
+			contribution.TransactionID = &transfer.ID
+			tontine.TotalContributed = tontine.TotalContributed.Add(contribution.Amount)
+		} else if req.AccountID != nil {
+			return fmt.Errorf("%w: seule la cotisation de l'utilisateur est enregistrée sur un compte", entity.ErrInvalidTontineData)
+		}
+
+		contribution.Status = contributionStatusPaid
+		contribution.PaidAt = &date
+		if err := s.tontineRepo.UpdateContribution(ctx, contribution); err != nil {
+			return err
+		}
+		return s.updateTontine(ctx, tontine)
+	})
+	if err != nil {
+		s.logger.Error("Erreur enregistrement cotisation", logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Cotisation enregistrée",
+		logger.String("tontine_id", tontineID.String()),
+		logger.Int("cycle", number),
+		logger.String("member_id", req.MemberID.String()),
+	)
+
+	return s.GetTontine(ctx, userID, tontineID)
+}
+
+// RecordPayout enregistre le versement de la cagnotte d'un tour à son bénéficiaire. La cagnotte reçue par
+// l'utilisateur est transférée du compte de la tontine vers son compte.
+func (s *TontineService) RecordPayout(ctx context.Context, userID, tontineID uuid.UUID, number int, req entity.RecordTontinePayoutRequest) (*entity.Tontine, error) {
+	date := truncateToDay(time.Now())
+	if req.Date != nil {
+		date = truncateToDay(*req.Date)
+	}
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		tontine, err := s.lockTontine(ctx, userID, tontineID)
+		if err != nil {
+			return err
+		}
+		cycle, err := s.tontineRepo.GetCycle(ctx, tontineID, number)
+		if err != nil {
+			return err
+		}
+		if cycle.Status == tontineCycleStatusPaidOut {
+			return entity.ErrTontinePayoutRecorded
+		}
+
+		// Le bénéficiaire est l'utilisateur si sa propre cotisation du tour le désigne
+		selfBeneficiary := false
+		for _, contribution := range cycle.Contributions {
+			if contribution.MemberID == cycle.BeneficiaryID {
+				selfBeneficiary = contribution.IsSelf
+				break
+			}
+		}
+
+		if selfBeneficiary {
+			if err := s.checkAccount(ctx, userID, tontine, req.AccountID); err != nil {
+				return err
+			}
+			transfer, err := s.transactionService.CreateTransaction(ctx, userID, entity.CreateTransactionRequest{
+				AccountID:      &tontine.AccountID,
+				ToAccountID:    req.AccountID,
+				Type:           "transfer",
+				Amount:         entity.DecimalOf(cycle.PayoutAmount),
+				Description:    fmt.Sprintf("Cagnotte %s - tour %d", tontine.Name, number),
+				Date:           date,
+				AllowDuplicate: true,
+			})
+			if err != nil {
+				return err
+			}
+			cycle.PayoutTransactionID = &transfer.ID
+			tontine.TotalReceived = tontine.TotalReceived.Add(cycle.PayoutAmount)
+		} else if req.AccountID != nil {
+			return fmt.Errorf("%w: seule la cagnotte de l'utilisateur est enregistrée sur un compte", entity.ErrInvalidTontineData)
+		}
+
+		cycle.Status = tontineCycleStatusPaidOut
+		cycle.PaidOutAt = &date
+		if err := s.tontineRepo.UpdateCycle(ctx, cycle); err != nil {
+			return err
+		}
+		return s.updateTontine(ctx, tontine)
+	})
+	if err != nil {
+		s.logger.Error("Erreur versement cagnotte", logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Cagnotte versée",
+		logger.String("tontine_id", tontineID.String()),
+		logger.Int("cycle", number),
+	)
+
+	return s.GetTontine(ctx, userID, tontineID)
+}
+
+// updateTontine enregistre les totaux d'une tontine et la marque terminée quand toutes les cagnottes sont versées
+// et toutes les cotisations enregistrées
+func (s *TontineService) updateTontine(ctx context.Context, tontine *entity.Tontine) error {
+	full, err := s.tontineRepo.GetByID(ctx, tontine.ID)
+	if err != nil {
+		return err
+	}
+
+	tontine.Status = tontineStatusCompleted
+	for _, cycle := range full.Cycles {
+		if cycle.Status != tontineCycleStatusPaidOut {
+			tontine.Status = tontineStatusActive
+		}
+		for _, contribution := range cycle.Contributions {
+			if contribution.Status != contributionStatusPaid {
+				tontine.Status = tontineStatusActive
+			}
+		}
+	}
+
+	tontine.UpdatedAt = time.Now()
+	return s.tontineRepo.Update(ctx, tontine)
+}
+
+// GetPositions récupère la position nette de l'utilisateur dans ses tontines en cours, avec son tour de cagnotte et
+// sa prochaine cotisation
+func (s *TontineService) GetPositions(ctx context.Context, userID uuid.UUID) ([]*entity.TontinePosition, error) {
+	tontines, err := s.tontineRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := s.tontineRepo.GetPendingSelfContributions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	next := make(map[uuid.UUID]*entity.TontineContribution, len(tontines))
+	for _, contribution := range pending {
+		if _, ok := next[contribution.TontineID]; !ok {
+			next[contribution.TontineID] = contribution
+		}
+	}
+
+	positions := []*entity.TontinePosition{}
+	for _, tontine := range tontines {
+		if tontine.Status != tontineStatusActive {
+			continue
+		}
+		position := &entity.TontinePosition{
+			TontineID:        tontine.ID,
+			Name:             tontine.Name,
+			TotalContributed: tontine.TotalContributed,
+			TotalReceived:    tontine.TotalReceived,
+			NetPosition:      tontine.TotalReceived.Sub(tontine.TotalContributed),
+			PayoutReceived:   tontine.TotalReceived.IsPositive(),
+			NextContribution: next[tontine.ID],
+		}
+		for _, member := range tontine.Members {
+			if member.IsSelf {
+				position.PayoutCycle = member.PayoutOrder
+				position.PayoutDate = tontineDueDate(tontine.StartDate, tontine.Frequency, member.PayoutOrder-1)
+				break
+			}
+		}
+		positions = append(positions, position)
+	}
+	return positions, nil
+}
+
+// GetReminders récupère les cotisations de l'utilisateur en retard et celles dues dans les jours à venir, par date
+func (s *TontineService) GetReminders(ctx context.Context, userID uuid.UUID, days int) ([]*entity.TontineReminder, error) {
+	tontines, err := s.tontineRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uuid.UUID]string, len(tontines))
+	for _, tontine := range tontines {
+		names[tontine.ID] = tontine.Name
+	}
+
+	contributions, err := s.tontineRepo.GetPendingSelfContributions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := truncateToDay(time.Now())
+	limit := today.AddDate(0, 0, days)
+	reminders := []*entity.TontineReminder{}
+	for _, contribution := range contributions {
+		if contribution.DueDate.After(limit) {
+			break
+		}
+		reminders = append(reminders, &entity.TontineReminder{
+			TontineID:    contribution.TontineID,
+			TontineName:  names[contribution.TontineID],
+			Contribution: contribution,
+			DaysUntil:    int(contribution.DueDate.Sub(today).Hours() / 24),
+		})
+	}
+	return reminders, nil
+}
+
+// StartReminderScheduler envoie périodiquement les rappels de cotisations jusqu'à l'annulation du contexte
+func (s *TontineService) StartReminderScheduler(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		s.logger.Warn("Planificateur des rappels de cotisations désactivé (intervalle invalide)")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.logger.Info("Planificateur des rappels de cotisations démarré", logger.String("interval", interval.String()))
+	for {
+		if sent, err := s.ProcessReminders(ctx, time.Now()); err != nil {
+			s.logger.Error("Erreur planificateur des rappels de cotisations", logger.Error(err))
+		} else if sent > 0 {
+			s.logger.Info("Rappels de cotisations envoyés", logger.Int("count", sent))
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Planificateur des rappels de cotisations arrêté")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessReminders rappelle à l'utilisateur ses cotisations dues dans les prochains jours et retourne le nombre de
+// notifications envoyées. Une notification non envoyée est retentée au passage suivant.
+func (s *TontineService) ProcessReminders(ctx context.Context, now time.Time) (int, error) {
+	today := truncateToDay(now)
+	contributions, err := s.tontineRepo.GetContributionsToRemind(ctx, today.AddDate(0, 0, tontineReminderDaysAhead), tontineReminderBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	names := make(map[uuid.UUID]string)
+	sent := 0
+	for _, contribution := range contributions {
+		name, ok := names[contribution.TontineID]
+		if !ok {
+			tontine, err := s.tontineRepo.GetByID(ctx, contribution.TontineID)
+			if err != nil {
+				s.logger.Error("Erreur récupération tontine", logger.String("tontine_id", contribution.TontineID.String()), logger.Error(err))
+				continue
+			}
+			name = tontine.Name
+			names[contribution.TontineID] = name
+		}
+
+		if err := s.notificationService.SendTontineContributionReminder(ctx, contribution.UserID, name, contribution.Amount, contribution.DueDate); err != nil {
+			s.logger.Error("Erreur rappel cotisation", logger.String("contribution_id", contribution.ID.String()), logger.Error(err))
+			continue
+		}
+		remindedAt := now
+		contribution.RemindedAt = &remindedAt
+		if err := s.tontineRepo.UpdateContribution(ctx, contribution); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// checkAccount vérifie que le compte d'une cotisation ou d'une cagnotte de l'utilisateur lui appartient, est dans la
+// devise de la tontine et n'est pas le compte de la tontine
+func (s *TontineService) checkAccount(ctx context.Context, userID uuid.UUID, tontine *entity.Tontine, accountID *uuid.UUID) error {
+	if accountID == nil {
+		return fmt.Errorf("%w: le compte est requis", entity.ErrInvalidTontineData)
+	}
+	if *accountID == tontine.AccountID {
+		return fmt.Errorf("%w: le compte doit être différent de celui de la tontine", entity.ErrInvalidTontineData)
+	}
+	account, err := s.accountRepo.GetByID(ctx, *accountID)
+	if err != nil || account.UserID != userID {
+		return fmt.Errorf("%w: compte non trouvé", entity.ErrInvalidTontineData)
+	}
+	if account.Currency != tontine.Currency {
+		return fmt.Errorf("%w: compte en %s, tontine en %s", entity.ErrCurrencyMismatch, account.Currency, tontine.Currency)
+	}
+	return nil
+}
+
+// tontineCurrency détermine la devise d'une nouvelle tontine : la devise demandée ou la devise de référence
+func (s *TontineService) tontineCurrency(ctx context.Context, userID uuid.UUID, currency string) (string, error) {
+	if currency != "" {
+		return normalizeCurrency(currency)
+	}
+	return s.exchangeRates.GetBaseCurrency(ctx, userID)
+}
+
+// lockTontine verrouille une tontine et vérifie qu'elle appartient à l'utilisateur
+func (s *TontineService) lockTontine(ctx context.Context, userID, tontineID uuid.UUID) (*entity.Tontine, error) {
+	tontine, err := s.tontineRepo.GetByIDForUpdate(ctx, tontineID)
+	if err != nil {
+		return nil, err
+	}
+	if tontine.UserID != userID {
+		return nil, entity.ErrTontineNotFound
+	}
+	return tontine, nil
+}
+
+// tontineDueDate retourne la date du tour d'indice index (0 pour le premier) selon la fréquence de la tontine ; en
+// mensuel, le jour du premier tour est ramené au dernier jour du mois si besoin
+func tontineDueDate(startDate time.Time, frequency string, index int) time.Time {
+	switch frequency {
+	case "weekly":
+		return startDate.AddDate(0, 0, 7*index)
+	case "biweekly":
+		return startDate.AddDate(0, 0, 14*index)
+	default:
+		month := time.Date(startDate.Year(), startDate.Month()+time.Month(index), 1, 0, 0, 0, 0, time.UTC)
+		return dateInMonth(month.Year(), month.Month(), startDate.Day())
+	}
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTontineDueDate(t *testing.T) {
+	tests := []struct {
+		start     time.Time
+		frequency string
+		index     int
+		want      time.Time
+	}{
+		{start: day(2025, time.March, 3), frequency: "weekly", index: 0, want: day(2025, time.March, 3)},
+		{start: day(2025, time.March, 3), frequency: "weekly", index: 5, want: day(2025, time.April, 7)},
+		{start: day(2025, time.December, 29), frequency: "weekly", index: 1, want: day(2026, time.January, 5)},
+		{start: day(2025, time.March, 3), frequency: "biweekly", index: 0, want: day(2025, time.March, 3)},
+		{start: day(2025, time.March, 3), frequency: "biweekly", index: 3, want: day(2025, time.April, 14)},
+		{start: day(2025, time.January, 15), frequency: "monthly", index: 0, want: day(2025, time.January, 15)},
+		{start: day(2025, time.January, 15), frequency: "monthly", index: 13, want: day(2026, time.February, 15)},
+		{start: day(2025, time.January, 31), frequency: "monthly", index: 1, want: day(2025, time.February, 28)},
+		{start: day(2025, time.January, 31), frequency: "monthly", index: 2, want: day(2025, time.March, 31)},
+		{start: day(2025, time.January, 31), frequency: "monthly", index: 3, want: day(2025, time.April, 30)},
+		{start: day(2024, time.January, 30), frequency: "monthly", index: 1, want: day(2024, time.February, 29)},
+		{start: day(2025, time.November, 30), frequency: "monthly", index: 3, want: day(2026, time.February, 28)},
+	}
+
+	for _, tt := range tests {
+		if got := tontineDueDate(tt.start, tt.frequency, tt.index); !got.Equal(tt.want) {
+			t.Errorf("tontineDueDate(%s, %s, %d) = %s, attendu %s", tt.start.Format("2006-01-02"), tt.frequency, tt.index,
+				got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+		}
+	}
+}
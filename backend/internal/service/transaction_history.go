@@ -0,0 +1,325 @@
+package service
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/pkg/logger"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actions enregistrées dans l'historique d'une transaction
+const (
+	versionActionCreate  = "create"
+	versionActionUpdate  = "update"
+	versionActionDelete  = "delete"
+	versionActionRestore = "restore"
+	versionActionRevert  = "revert"
+)
+
+// Origines d'un changement de transaction
+const (
+	changeSourceAPI            = "api"
+	changeSourceImport         = "import"
+	changeSourceAI             = "ai"
+	changeSourceRecurring      = "recurring"
+	changeSourceSMS            = "sms"
+	changeSourceScheduler      = "scheduler"
+	changeSourceReconciliation = "reconciliation"
+	changeSourceBulk           = "bulk"
+)
+
+type changeSourceKey struct{}
+
+type revertedVersionKey struct{}
+
+// withChangeSource indique l'origine des changements de transactions effectués avec ce contexte
+func withChangeSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, changeSourceKey{}, source)
+}
+
+// changeSourceFrom retourne l'origine des changements portée par le contexte (API par défaut)
+func changeSourceFrom(ctx context.Context) string {
+	if source, ok := ctx.Value(changeSourceKey{}).(string); ok {
+		return source
+	}
+	return changeSourceAPI
+}
+
+// withRevertedVersion marque les mises à jour effectuées avec ce contexte comme un retour à la version donnée
+func withRevertedVersion(ctx context.Context, version int) context.Context {
+	return context.WithValue(ctx, revertedVersionKey{}, version)
+}
+
+// snapshotOf retourne l'état versionné d'une transaction avec sa ventilation et ses étiquettes
+func snapshotOf(transaction *entity.Transaction, splits []*entity.TransactionSplit, tags []*entity.Tag) *entity.TransactionSnapshot {
+	snapshot := &entity.TransactionSnapshot{
+		AccountID:        transaction.AccountID,
+		CategoryID:       transaction.CategoryID,
+		Type:             transaction.Type,
+		ToAccountID:      transaction.ToAccountID,
+		SavingGoalID:     transaction.SavingGoalID,
+		Amount:           transaction.Amount,
+		Currency:         transaction.Currency,
+		OriginalAmount:   transaction.OriginalAmount,
+		OriginalCurrency: transaction.OriginalCurrency,
+		Description:      transaction.Description,
+		Date:             transaction.Date,
+		Recurring:        transaction.Recurring,
+		ExternalRef:      transaction.ExternalRef,
+		RefundOfID:       transaction.RefundOfID,
+		RefundedAmount:   transaction.RefundedAmount,
+		Status:           transaction.Status,
+		Splits:           make([]entity.TransactionSplitRequest, 0, len(splits)),
+		TagIDs:           make([]uuid.UUID, 0, len(tags)),
+	}
+	for _, split := range splits {
+		snapshot.Splits = append(snapshot.Splits, entity.TransactionSplitRequest{
+			CategoryID: split.CategoryID,
+			Amount:     entity.DecimalOf(split.Amount),
+			Note:       split.Note,
+		})
+	}
+	// Ordre stable, indépendant du nom des étiquettes
+	for _, tag := range tags {
+		snapshot.TagIDs = append(snapshot.TagIDs, tag.ID)
+	}
+	sort.Slice(snapshot.TagIDs, func(i, j int) bool { return snapshot.TagIDs[i].String() < snapshot.TagIDs[j].String() })
+	return snapshot
+}
+
+// diffSnapshots liste les champs qui diffèrent entre deux états d'une transaction ;
+// sans état précédent, tous les champs de l'état courant sont listés
+func diffSnapshots(previous, current *entity.TransactionSnapshot) ([]*entity.TransactionFieldChange, error) {
+	fields := func(snapshot *entity.TransactionSnapshot) (map[string]json.RawMessage, error) {
+		values := map[string]json.RawMessage{}
+		if snapshot == nil {
+			return values, nil
+		}
+		raw, err := json.Marshal(snapshot)
+		if err != nil {
+			return nil, err
+		}
+		return values, json.Unmarshal(raw, &values)
+	}
+
+	oldValues, err := fields(previous)
+	if err != nil {
+		return nil, err
+	}
+	newValues, err := fields(current)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ordre stable : celui des champs du snapshot
+	changes := []*entity.TransactionFieldChange{}
+	for _, field := range []string{
+		"account_id", "category_id", "type", "to_account_id", "saving_goal_id", "amount", "currency", "original_amount",
+		"original_currency", "description", "date", "recurring", "external_ref", "refund_of_id", "refunded_amount", "status",
+		"splits", "tag_ids",
+	} {
+		oldValue, hadOld := oldValues[field]
+		newValue := newValues[field]
+		if hadOld && bytes.Equal(oldValue, newValue) {
+			continue
+		}
+		if !hadOld {
+			oldValue = json.RawMessage("null")
+			if bytes.Equal(newValue, oldValue) {
+				continue
+			}
+		}
+		changes = append(changes, &entity.TransactionFieldChange{Field: field, Old: oldValue, New: newValue})
+	}
+	return changes, nil
+}
+
+// recordVersion ajoute une version à l'historique de la transaction après un changement, avec les champs
+// modifiés depuis la version précédente. before (optionnel, ventilation dans Splits, étiquettes dans Tags si
+// chargées par loadTags) sert de référence à une transaction antérieure à l'historique. Une mise à jour sans effet
+// sur les champs versionnés n'est pas enregistrée.
+// Doit être appelé à l'intérieur de TxManager.WithinTransaction, la transaction étant verrouillée ou tout juste créée.
+func (s *TransactionService) recordVersion(ctx context.Context, action string, before, after *entity.Transaction) error {
+	splits, err := s.transactionRepo.GetSplits(ctx, after.ID)
+	if err != nil {
+		return err
+	}
+	tags, err := s.tagRepo.GetByTransactionID(ctx, after.ID)
+	if err != nil {
+		return err
+	}
+	snapshot := snapshotOf(after, splits, tags)
+
+	latest, err := s.versionRepo.GetLatest(ctx, after.ID)
+	if err != nil {
+		return err
+	}
+	var previous *entity.TransactionSnapshot
+	switch {
+	case latest != nil:
+		previous = latest.Snapshot
+		if previous.TagIDs == nil {
+			// Version antérieure au suivi des étiquettes : supposées inchangées
+			legacy := *previous
+			legacy.TagIDs = snapshot.TagIDs
+			previous = &legacy
+		}
+	case before != nil:
+		previous = snapshotOf(before, before.Splits, before.Tags)
+		if before.Tags == nil {
+			// Étiquettes de la référence non chargées : supposées inchangées
+			previous.TagIDs = snapshot.TagIDs
+		}
+	}
+
+	changes, err := diffSnapshots(previous, snapshot)
+	if err != nil {
+		return fmt.Errorf("erreur calcul des changements de la transaction: %w", err)
+	}
+
+	version := &entity.TransactionVersion{
+		ID:            uuid.New(),
+		TransactionID: after.ID,
+		UserID:        after.UserID,
+		Action:        action,
+		Source:        changeSourceFrom(ctx),
+		Changes:       changes,
+		Snapshot:      snapshot,
+		CreatedAt:     time.Now(),
+	}
+	if reverted, ok := ctx.Value(revertedVersionKey{}).(int); ok && action == versionActionUpdate {
+		version.Action = versionActionRevert
+		version.RevertedVersion = &reverted
+	} else if action == versionActionUpdate && len(changes) == 0 && previous != nil {
+		return nil
+	}
+	if actorID, ok := ctx.Value("user_id").(uuid.UUID); ok {
+		version.ActorID = &actorID
+	}
+
+	return s.versionRepo.Create(ctx, version)
+}
+
+// loadTags charge les étiquettes d'une transaction avant de les modifier, pour servir de référence à recordVersion
+func (s *TransactionService) loadTags(ctx context.Context, transaction *entity.Transaction) error {
+	tags, err := s.tagRepo.GetByTransactionID(ctx, transaction.ID)
+	if err != nil {
+		return err
+	}
+	// Non nil même sans étiquette : les étiquettes sont chargées
+	transaction.Tags = append([]*entity.Tag{}, tags...)
+	return nil
+}
+
+// GetTransactionHistory récupère l'historique des versions d'une transaction, y compris à la corbeille
+func (s *TransactionService) GetTransactionHistory(ctx context.Context, userID, transactionID uuid.UUID) ([]*entity.TransactionVersion, error) {
+	versions, err := s.versionRepo.GetByTransactionID(ctx, transactionID)
+	if err != nil {
+		s.logger.Error("Erreur récupération historique de transaction", logger.Error(err))
+		return nil, err
+	}
+	if len(versions) > 0 {
+		if versions[0].UserID != userID {
+			return nil, fmt.Errorf("transaction non trouvée")
+		}
+		return versions, nil
+	}
+
+	// Transaction antérieure à l'historique : elle doit exister et appartenir à l'utilisateur
+	if _, err := s.GetTransaction(ctx, userID, transactionID); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// RevertTransaction rétablit l'état d'une version antérieure d'une transaction (les deux jambes d'un transfert).
+// Le retour passe par la mise à jour, qui réajuste les soldes, et est enregistré comme une nouvelle version.
+// La catégorie et les étiquettes de la version sont rétablies, y compris leur absence ; les étiquettes supprimées
+// depuis sont ignorées et une version antérieure au suivi des étiquettes conserve les étiquettes actuelles. Le
+// statut, qui se change par SetTransactionStatus, ne suit que la date rétablie. Seule une version que la
+// validation refuse (ventilation, remboursement, devise...) est signalée comme impossible à rétablir.
+func (s *TransactionService) RevertTransaction(ctx context.Context, userID, transactionID uuid.UUID, version int) (*entity.Transaction, error) {
+	target, err := s.versionRepo.GetByVersion(ctx, transactionID, version)
+	if err != nil {
+		return nil, err
+	}
+	if target.UserID != userID {
+		return nil, fmt.Errorf("%w: version %d", entity.ErrTransactionVersionNotFound, version)
+	}
+	if target.Action == versionActionDelete {
+		return nil, fmt.Errorf("%w: la version %d est une suppression, restaurer la transaction depuis la corbeille", entity.ErrInvalidRevert, version)
+	}
+
+	snapshot := target.Snapshot
+	amount := entity.DecimalOf(snapshot.Amount)
+	req := entity.UpdateTransactionRequest{
+		AccountID:     snapshot.AccountID,
+		CategoryID:    snapshot.CategoryID,
+		ClearCategory: snapshot.CategoryID == nil,
+		SavingGoalID:  snapshot.SavingGoalID,
+		Amount:        &amount,
+		Description:   &snapshot.Description,
+		Date:          &snapshot.Date,
+		Recurring:     &snapshot.Recurring,
+	}
+	if snapshot.Type == "transfer" {
+		req.ToAccountID = snapshot.ToAccountID
+	} else {
+		req.Type = &snapshot.Type
+		req.Splits = snapshot.Splits
+		// Le montant d'origine absent de la version est retiré
+		originalCurrency := ""
+		if snapshot.OriginalAmount != nil {
+			originalAmount := entity.DecimalOf(*snapshot.OriginalAmount)
+			req.OriginalAmount = &originalAmount
+			originalCurrency = snapshot.OriginalCurrency
+		}
+		req.OriginalCurrency = &originalCurrency
+	}
+	if snapshot.TagIDs != nil {
+		tags, err := s.tagRepo.GetByIDs(ctx, userID, snapshot.TagIDs)
+		if err != nil {
+			return nil, err
+		}
+		req.TagIDs = make([]uuid.UUID, 0, len(tags))
+		for _, tag := range tags {
+			req.TagIDs = append(req.TagIDs, tag.ID)
+		}
+	}
+
+	transaction, err := s.UpdateTransaction(withRevertedVersion(ctx, version), userID, transactionID, req)
+	if err != nil {
+		if isTransactionValidationError(err) {
+			return nil, fmt.Errorf("%w: %w", entity.ErrInvalidRevert, err)
+		}
+		return nil, err
+	}
+
+	s.logger.Info("Transaction rétablie à une version antérieure",
+		logger.String("transaction_id", transactionID.String()),
+		logger.Int("version", version),
+	)
+
+	return transaction, nil
+}
+
+// isTransactionValidationError indique si une écriture de transaction a été refusée par la validation de ses données,
+// et non par un verrou (rapprochement, compte clôturé) ou une erreur technique
+func isTransactionValidationError(err error) bool {
+	for _, target := range []error{
+		entity.ErrInvalidTransactionData, entity.ErrInvalidAmount, entity.ErrInvalidRefund, entity.ErrRefundExceedsOriginal,
+		entity.ErrInvalidCurrency, entity.ErrExchangeRateNotFound, entity.ErrTagNotFound,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"backend/internal/domaine/entity"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSnapshotOf(t *testing.T) {
+	accountID, categoryID := uuid.New(), uuid.New()
+	original := entity.NewMoney(-1234, "KWD")
+	transaction := &entity.Transaction{
+		AccountID:        &accountID,
+		CategoryID:       &categoryID,
+		Type:             "expense",
+		Amount:           entity.NewMoney(250000, "XAF"),
+		Currency:         "XAF",
+		OriginalAmount:   &original,
+		OriginalCurrency: "KWD",
+		Description:      "Hôtel",
+		Date:             day(2025, time.March, 1),
+		RefundedAmount:   entity.NewMoney(0, "XAF"),
+		Status:           transactionStatusCleared,
+	}
+	splits := []*entity.TransactionSplit{
+		{CategoryID: categoryID, Amount: entity.NewMoney(200000, "XAF"), Note: "Chambre"},
+		{CategoryID: uuid.New(), Amount: entity.NewMoney(50000, "XAF")},
+	}
+	tagA, tagB := uuid.New(), uuid.New()
+	if tagB.String() < tagA.String() {
+		tagA, tagB = tagB, tagA
+	}
+	tags := []*entity.Tag{{ID: tagB, Name: "a"}, {ID: tagA, Name: "b"}}
+
+	snapshot := snapshotOf(transaction, splits, tags)
+	if snapshot.Amount != transaction.Amount || snapshot.Currency != "XAF" || *snapshot.OriginalAmount != original ||
+		snapshot.OriginalCurrency != "KWD" || *snapshot.CategoryID != categoryID || snapshot.Status != transactionStatusCleared {
+		t.Errorf("snapshotOf = %+v", snapshot)
+	}
+	if len(snapshot.Splits) != 2 || snapshot.Splits[0].Amount != "200000" || snapshot.Splits[0].Note != "Chambre" || snapshot.Splits[1].Amount != "50000" {
+		t.Errorf("ventilation = %+v, attendu 200000 et 50000", snapshot.Splits)
+	}
+	if len(snapshot.TagIDs) != 2 || snapshot.TagIDs[0] != tagA || snapshot.TagIDs[1] != tagB {
+		t.Errorf("étiquettes = %v, attendu %v triées par identifiant", snapshot.TagIDs, []uuid.UUID{tagA, tagB})
+	}
+
+	// Sans étiquette, la liste est vide et non absente : elle se distingue d'une version sans suivi des étiquettes
+	empty := snapshotOf(transaction, nil, nil)
+	if empty.TagIDs == nil || len(empty.TagIDs) != 0 || empty.Splits == nil {
+		t.Errorf("snapshotOf sans ventilation ni étiquette = %+v", empty)
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	accountID, categoryID := uuid.New(), uuid.New()
+	base := func() *entity.TransactionSnapshot {
+		return &entity.TransactionSnapshot{
+			AccountID:      &accountID,
+			CategoryID:     &categoryID,
+			Type:           "expense",
+			Amount:         entity.NewMoney(1500, "EUR"),
+			Currency:       "EUR",
+			Description:    "Café",
+			Date:           day(2025, time.March, 1),
+			RefundedAmount: entity.NewMoney(0, "EUR"),
+			Status:         transactionStatusCleared,
+			Splits:         []entity.TransactionSplitRequest{},
+			TagIDs:         []uuid.UUID{},
+		}
+	}
+	fields := func(changes []*entity.TransactionFieldChange) []string {
+		names := make([]string, 0, len(changes))
+		for _, change := range changes {
+			names = append(names, change.Field)
+		}
+		return names
+	}
+
+	tests := []struct {
+		name   string
+		change func(*entity.TransactionSnapshot)
+		want   []string
+	}{
+		{name: "aucun changement", change: func(*entity.TransactionSnapshot) {}, want: []string{}},
+		{name: "montant", change: func(s *entity.TransactionSnapshot) { s.Amount = entity.NewMoney(1600, "EUR") }, want: []string{"amount"}},
+		{name: "catégorie retirée", change: func(s *entity.TransactionSnapshot) { s.CategoryID = nil }, want: []string{"category_id"}},
+		{
+			name: "plusieurs champs dans l'ordre du snapshot",
+			change: func(s *entity.TransactionSnapshot) {
+				s.TagIDs = []uuid.UUID{uuid.New()}
+				s.Description = "Thé"
+				s.Type = "income"
+			},
+			want: []string{"type", "description", "tag_ids"},
+		},
+		{
+			name: "montant d'origine ajouté",
+			change: func(s *entity.TransactionSnapshot) {
+				original := entity.NewMoney(2000, "USD")
+				s.OriginalAmount, s.OriginalCurrency = &original, "USD"
+			},
+			want: []string{"original_amount", "original_currency"},
+		},
+	}
+
+	for _, tt := range tests {
+		current := base()
+		tt.change(current)
+		changes, err := diffSnapshots(base(), current)
+		if err != nil {
+			t.Fatalf("%s : diffSnapshots: %v", tt.name, err)
+		}
+		if got := fields(changes); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s : champs modifiés = %v, attendu %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Le changement porte les valeurs JSON avant et après
+	current := base()
+	current.Amount = entity.NewMoney(1600, "EUR")
+	changes, err := diffSnapshots(base(), current)
+	if err != nil || len(changes) != 1 {
+		t.Fatalf("diffSnapshots(montant) = %v, %v, attendu un changement", fields(changes), err)
+	}
+	if string(changes[0].Old) != "15.00" || string(changes[0].New) != "16.00" {
+		t.Errorf("montant : %s -> %s, attendu 15.00 -> 16.00", changes[0].Old, changes[0].New)
+	}
+
+	// Sans état précédent, tous les champs renseignés sont listés avec une ancienne valeur nulle
+	changes, err = diffSnapshots(nil, base())
+	if err != nil {
+		t.Fatalf("diffSnapshots(nil): %v", err)
+	}
+	got := fields(changes)
+	if len(got) == 0 || got[0] != "account_id" {
+		t.Errorf("diffSnapshots(nil) = %v, attendu les champs de l'état courant", got)
+	}
+	for _, change := range changes {
+		if string(change.Old) != "null" || !json.Valid(change.New) {
+			t.Errorf("diffSnapshots(nil) : %s = %s -> %s", change.Field, change.Old, change.New)
+		}
+		if string(change.New) == "null" {
+			t.Errorf("diffSnapshots(nil) : le champ nul %s ne doit pas être listé", change.Field)
+		}
+	}
+}
@@ -4,33 +4,90 @@ import (
 	"backend/internal/domaine/entity"
 	"backend/internal/domaine/repository"
 	"backend/internal/service/ai"
+	"backend/internal/service/export"
+	"backend/internal/service/mobilemoney"
 	"backend/pkg/logger"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 )
 
+// duplicateWindowDays est l'écart de dates (en jours) en deçà duquel deux transactions similaires
+// sont considérées comme des doublons
+const duplicateWindowDays = 2
+
+// maxDuplicateWindowDays borne la fenêtre de recherche de doublons demandée par le client
+const maxDuplicateWindowDays = 31
+
 // TransactionQuery représente les paramètres de requête pour les transactions
 type TransactionQuery struct {
 	Type       string
 	CategoryID *uuid.UUID
 	AccountID  *uuid.UUID
+	TagIDs     []uuid.UUID
+	TagMatch   string // any (défaut), all
 	StartDate  string
 	EndDate    string
-	Page       int
+	MinAmount  *entity.Decimal
+	MaxAmount  *entity.Decimal
+	Search     string
+	Status     string // scheduled, pending, cleared, reconciled
+	SortBy     string // date (défaut), amount, created_at
+	SortOrder  string // desc (défaut), asc
+	Cursor     string // curseur opaque renvoyé par la page précédente
 	Limit      int
 }
 
+// TransactionStatsQuery représente les paramètres de requête des statistiques de transactions
+type TransactionStatsQuery struct {
+	Period         string // week, month (défaut), year ; ignorée si une date est fournie
+	StartDate      string
+	EndDate        string // aujourd'hui par défaut lorsque seule la date de début est fournie
+	Granularity    string // day, week, month ; déduite de la période si vide
+	AccountID      *uuid.UUID
+	IncludePending bool // compte les transactions en attente en plus des transactions passées et rapprochées
+}
+
+// maxStatsBuckets borne le nombre d'intervalles d'une série temporelle de statistiques
+const maxStatsBuckets = 400
+
+// maxBulkTransactions borne le nombre de transactions d'une opération en masse
+const maxBulkTransactions = 500
+
+// Statuts du résultat d'une opération en masse pour une transaction
+const (
+	bulkStatusSucceeded  = "succeeded"
+	bulkStatusFailed     = "failed"
+	bulkStatusSkipped    = "skipped"
+	bulkStatusRolledBack = "rolled_back"
+)
+
 // TransactionService gère la logique métier des transactions
 type TransactionService struct {
 	transactionRepo repository.TransactionRepository
 	accountRepo     repository.AccountRepository
 	categoryRepo    repository.CategoryRepository
+	savingGoalRepo  repository.SavingGoalRepository
+	tagRepo         repository.TagRepository
+	versionRepo     repository.TransactionVersionRepository
+	txManager       repository.TxManager
 	aiService       *ai.AIService
 	logger          logger.Logger
 	accountService  *AccountService
+	exchangeRates   *ExchangeRateService
 }
 
 // NewTransactionService crée une nouvelle instance de TransactionService
@@ -38,16 +95,26 @@ func NewTransactionService(
 	transactionRepo repository.TransactionRepository,
 	accountRepo repository.AccountRepository,
 	categoryRepo repository.CategoryRepository,
+	savingGoalRepo repository.SavingGoalRepository,
+	tagRepo repository.TagRepository,
+	versionRepo repository.TransactionVersionRepository,
+	txManager repository.TxManager,
 	aiService *ai.AIService,
 	accountService *AccountService,
+	exchangeRates *ExchangeRateService,
 	logger logger.Logger,
 ) *TransactionService {
 	return &TransactionService{
 		transactionRepo: transactionRepo,
 		accountRepo:     accountRepo,
 		categoryRepo:    categoryRepo,
+		savingGoalRepo:  savingGoalRepo,
+		tagRepo:         tagRepo,
+		versionRepo:     versionRepo,
+		txManager:       txManager,
 		aiService:       aiService,
 		accountService:  accountService,
+		exchangeRates:   exchangeRates,
 		logger:          logger,
 	}
 }
@@ -55,10 +122,6 @@ func NewTransactionService(
 // CreateTransaction crée une nouvelle transaction
 func (s *TransactionService) CreateTransaction(ctx context.Context, userID uuid.UUID, req entity.CreateTransactionRequest) (*entity.Transaction, error) {
 	// Validation des données
-	if req.Amount <= 0 {
-		return nil, fmt.Errorf("le montant doit être positif")
-	}
-
 	validTypes := []string{"income", "expense", "transfer", "saving", "refund"}
 	isValidType := false
 	for _, validType := range validTypes {
@@ -69,38 +132,141 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, userID uuid.
 	}
 	s.logger.Info("req", logger.Any("req", req))
 	if !isValidType {
-		return nil, fmt.Errorf("le type doit être l'un des suivants: %v", validTypes)
+		return nil, fmt.Errorf("%w: le type doit être l'un des suivants: %v", entity.ErrInvalidTransactionData, validTypes)
 	}
 
-	// Vérifier que le compte existe et appartient à l'utilisateur (si spécifié)
-	if req.AccountID != nil {
-		account, err := s.accountRepo.GetByID(ctx, *req.AccountID)
-
-		s.logger.Info("Compte récupéré", logger.String("account_id", account.ID.String()), logger.String("user_id", userID.String()), logger.Float64("account_name", account.Balance))
+	// Un remboursement porte sur une dépense de l'utilisateur, dont il reprend par défaut le compte et la catégorie
+	var original *entity.Transaction
+	if req.Type == "refund" {
+		var err error
+		original, err = s.getRefundOriginal(ctx, userID, req.RefundOfID)
 		if err != nil {
-			return nil, fmt.Errorf("compte non trouvé")
+			return nil, err
 		}
-		if account.UserID != userID {
-			return nil, fmt.Errorf("accès non autorisé au compte")
+		if req.AccountID == nil {
+			req.AccountID = original.AccountID
+		}
+		if req.CategoryID == nil && len(req.Splits) == 0 {
+			req.CategoryID = original.CategoryID
+		}
+	} else if req.RefundOfID != nil {
+		return nil, fmt.Errorf("%w: seul un remboursement référence une dépense d'origine", entity.ErrInvalidRefund)
+	}
+
+	// Toute écriture comptable porte sur un compte
+	if req.AccountID == nil {
+		return nil, fmt.Errorf("le compte est requis")
+	}
+	if req.Type == "transfer" {
+		if req.ToAccountID == nil {
+			return nil, fmt.Errorf("le compte de destination est requis pour un transfert")
+		}
+		if *req.ToAccountID == *req.AccountID {
+			return nil, fmt.Errorf("%w: les comptes source et destination doivent être différents", entity.ErrInvalidTransactionData)
+		}
+	}
+	if req.Type == "saving" && req.SavingGoalID == nil {
+		return nil, fmt.Errorf("%w: l'objectif d'épargne est requis pour une épargne", entity.ErrInvalidTransactionData)
+	}
+	status, err := initialTransactionStatus(req.Status, req.Date, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// Vérifier que le compte existe et appartient à l'utilisateur
+	account, err := s.accountRepo.GetByID(ctx, *req.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("compte non trouvé")
+	}
+	if account.UserID != userID {
+		return nil, fmt.Errorf("accès non autorisé au compte")
+	}
+	if account.ArchivedAt != nil {
+		return nil, fmt.Errorf("%w: %s", entity.ErrAccountArchived, account.Name)
+	}
+
+	// Le montant est saisi dans la devise du compte ; un paiement en devise étrangère conserve son montant
+	// d'origine, converti au taux de sa date si le montant dans la devise du compte n'est pas fourni
+	var originalAmount *entity.Money
+	if req.OriginalAmount != "" {
+		if req.Type == "transfer" {
+			return nil, fmt.Errorf("%w: un transfert ne peut pas avoir de montant d'origine en devise étrangère", entity.ErrInvalidTransactionData)
+		}
+		if originalAmount, err = parseOriginalAmount(req.OriginalAmount, req.OriginalCurrency, account.Currency); err != nil {
+			return nil, err
+		}
+	}
+	var amount entity.Money
+	if req.Amount != "" || originalAmount == nil {
+		amount, err = req.Amount.Money(account.Currency)
+	} else {
+		amount, err = s.exchangeRates.Convert(ctx, userID, *originalAmount, account.Currency, req.Date)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("%w: le montant doit être positif", entity.ErrInvalidTransactionData)
+	}
+	if original != nil {
+		if original.Currency != amount.Currency {
+			return nil, fmt.Errorf("%w: le remboursement doit être dans la devise de la dépense (%s)", entity.ErrInvalidRefund, original.Currency)
+		}
+		if remaining := original.NetAmount(); amount.Cmp(remaining) > 0 {
+			return nil, fmt.Errorf("%w: %s restant", entity.ErrRefundExceedsOriginal, remaining)
 		}
 	}
 
+	// Ventilation éventuelle sur plusieurs catégories
+	transactionID := uuid.New()
+	splits, err := s.buildSplits(ctx, userID, transactionID, req.Type, amount, req.Splits)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := s.resolveTags(ctx, userID, req.TagIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	// Gestion de la catégorie
 	var categoryID *uuid.UUID = req.CategoryID
+	categorizedByAI := false
+	if categoryID == nil && len(splits) > 0 {
+		// Une transaction ventilée porte la catégorie de sa ligne principale
+		categoryID = &mainSplit(splits).CategoryID
+	}
 	// s.logger.Info("categoryID avant", logger.String("categoryID", req.CategoryID))
 
 	// Si aucune catégorie n'est spécifiée, utiliser l'IA pour en créer une automatiquement
-	if categoryID == nil && req.Description != "" {
+	// (un transfert n'est ni une dépense ni un revenu : il n'est pas catégorisé automatiquement ;
+	// un remboursement garde la catégorie de la dépense d'origine)
+	if categoryID == nil && req.Description != "" && req.Type != "transfer" && req.Type != "refund" {
 		// Déterminer le type de catégorie basé sur le type de transaction
 		categoryType := "expense"
 		if req.Type == "income" {
 			categoryType = "revenue"
 		}
 
+		// Catégories existantes de l'utilisateur, proposées à l'IA et réutilisées si elle en choisit une
+		existingCategories, err := s.categoryRepo.GetByUserID(ctx, userID)
+		if err != nil {
+			s.logger.Warn("Erreur récupération catégories existantes", logger.Error(err))
+		}
+		existingNames := make([]string, 0, len(existingCategories))
+		for _, category := range existingCategories {
+			if category.Type == categoryType {
+				existingNames = append(existingNames, category.Name)
+			}
+		}
+
 		// Utiliser l'IA pour catégoriser automatiquement
-		categoryResponse, err := s.aiService.GenerateCatherorie([]string{}, req.Description)
+		categoryResponse, err := s.aiService.GenerateCatherorie(existingNames, req.Description)
 		if err != nil {
 			s.logger.Warn("Erreur catégorisation automatique, transaction créée sans catégorie", logger.Error(err))
+		} else if existing := findCategoryByName(existingCategories, categoryResponse.CategoryName, categoryType); existing != nil {
+			categoryID = &existing.ID
+			categorizedByAI = true
 		} else {
 			// Log de debug pour voir la réponse de l'IA
 			s.logger.Info("Réponse IA catégorisation",
@@ -133,6 +299,7 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, userID uuid.
 				s.logger.Warn("Erreur création catégorie automatique", logger.Error(err))
 			} else {
 				categoryID = &newCategory.ID
+				categorizedByAI = true
 				s.logger.Info("Catégorie créée automatiquement",
 					logger.String("category_name", categoryResponse.CategoryName),
 					logger.String("category_id", newCategory.ID.String()),
@@ -151,123 +318,102 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, userID uuid.
 		}
 	}
 
-	s.logger.Info("categoryID", logger.String("categoryID", categoryID.String()))
-
 	if req.Type == "transfer" {
-		account, err := s.accountRepo.GetByID(ctx, *req.AccountID)
-		if err != nil {
-			s.logger.Error("Erreur récupération compte", logger.Error(err))
-			return nil, fmt.Errorf("erreur récupération compte: %w", err)
-		}
+		return s.createTransfer(ctx, userID, categoryID, tags, amount, status, req)
+	}
 
-		//expense accountID transaction
-		transaction := &entity.Transaction{
-			ID:          uuid.New(),
-			UserID:      userID,
-			CategoryID:  categoryID,
-			AccountID:   req.AccountID,
-			Type:        "expense",
-			Amount:      req.Amount,
-			Description: req.Description,
-			Date:        req.Date,
-			Recurring:   false,
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-		}
+	transaction := &entity.Transaction{
+		ID:            transactionID,
+		UserID:        userID,
+		AccountID:     req.AccountID,
+		CategoryID:    categoryID,
+		Type:          req.Type,
+		SavingGoalID:  req.SavingGoalID,
+		Status:        status,
+		Description:   req.Description,
+		Date:          req.Date,
+		Recurring:     req.Recurring,
+		ExternalRef:   req.ExternalRef,
+		ImportBatchID: req.ImportBatchID,
+		RefundOfID:    req.RefundOfID,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+	transaction.SetAmount(amount)
+	transaction.SetOriginalAmount(originalAmount)
 
-		if err := s.transactionRepo.Create(ctx, transaction); err != nil {
-			s.logger.Error("Erreur création transaction", logger.Error(err))
-			return nil, fmt.Errorf("erreur création transaction: %w", err)
-		}
+	// Une catégorie choisie par l'IA pour une saisie manuelle est attribuée à l'IA dans l'historique
+	if categorizedByAI && changeSourceFrom(ctx) == changeSourceAPI {
+		ctx = withChangeSource(ctx, changeSourceAI)
+	}
 
-		// Mettre à jour la balance du compte source (expense)
-		account.Balance -= req.Amount
-		if err := s.accountRepo.Update(ctx, account); err != nil {
-			s.logger.Error("Erreur mise à jour balance du compte source", logger.Error(err))
+	// Écriture de la transaction et de ses effets sur les soldes dans une seule transaction SQL
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// La dépense d'origine est verrouillée avant les comptes, comme lors d'une modification
+		if transaction.RefundOfID != nil {
+			if err := s.applyRefund(ctx, *transaction.RefundOfID, transaction.Amount); err != nil {
+				return err
+			}
 		}
 
-		//income toAccountID transaction
-		toAccount, err := s.accountRepo.GetByID(ctx, *req.ToAccountID)
+		accounts, err := s.lockAccounts(ctx, userID, *req.AccountID)
 		if err != nil {
-			s.logger.Error("Erreur récupération compte destination", logger.Error(err))
-			return nil, fmt.Errorf("erreur récupération compte destination: %w", err)
+			return err
 		}
-
-		transaction2 := &entity.Transaction{
-			ID:          uuid.New(),
-			UserID:      userID,
-			AccountID:   req.ToAccountID,
-			CategoryID:  categoryID,
-			Type:        "income",
-			Amount:      req.Amount,
-			Description: req.Description,
-			Date:        req.Date,
-			Recurring:   false,
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
+		if err := checkPeriodLock(accounts, nil, transaction); err != nil {
+			return err
 		}
 
-		if err := s.transactionRepo.Create(ctx, transaction2); err != nil {
-			s.logger.Error("Erreur création transaction destination", logger.Error(err))
-			return nil, fmt.Errorf("erreur création transaction destination: %w", err)
+		// Le compte étant verrouillé, la vérification de la référence externe ne peut pas être doublée
+		if req.ExternalRef != nil {
+			existing, err := s.transactionRepo.GetExistingExternalRefs(ctx, *req.AccountID, []string{*req.ExternalRef})
+			if err != nil {
+				return err
+			}
+			if len(existing) > 0 {
+				return fmt.Errorf("%w: %s", entity.ErrDuplicateExternalRef, *req.ExternalRef)
+			}
 		}
 
-		// Mettre à jour la balance du compte destination (income)
-		toAccount.Balance += req.Amount
-		if err := s.accountRepo.Update(ctx, toAccount); err != nil {
-			s.logger.Error("Erreur mise à jour balance du compte destination", logger.Error(err))
+		// Saisie répétée ou nouvel envoi hors ligne : refuser une transaction similaire à une transaction
+		// existante, sauf confirmation du client. Les lignes portant une référence externe sont déjà
+		// dédoublonnées par cette référence.
+		if !req.AllowDuplicate && req.ExternalRef == nil {
+			if err := s.checkDuplicate(ctx, transaction); err != nil {
+				return err
+			}
 		}
 
-		return transaction, nil
-	}
+		if err := s.transactionRepo.Create(ctx, transaction); err != nil {
+			return fmt.Errorf("erreur création transaction: %w", err)
+		}
+		if len(splits) > 0 {
+			if err := s.transactionRepo.ReplaceSplits(ctx, transaction.ID, splits); err != nil {
+				return err
+			}
+			transaction.Splits = splits
+		}
+		if len(tags) > 0 {
+			if err := s.setTags(ctx, tags, transaction); err != nil {
+				return err
+			}
+		}
+		if err := s.recordVersion(ctx, versionActionCreate, nil, transaction); err != nil {
+			return err
+		}
 
-	// Création de la transaction
-	account, err := s.accountRepo.GetByID(ctx, *req.AccountID)
+		return s.applyTransactionEffect(ctx, userID, transaction, accounts, 1)
+	})
 	if err != nil {
-		s.logger.Error("Erreur récupération compte", logger.Error(err))
-		return nil, fmt.Errorf("erreur récupération compte: %w", err)
-	}
-
-	transaction := &entity.Transaction{
-		ID:           uuid.New(),
-		UserID:       userID,
-		AccountID:    req.AccountID,
-		CategoryID:   categoryID,
-		Type:         req.Type,
-		SavingGoalID: req.SavingGoalID,
-		Amount:       req.Amount,
-		Description:  req.Description,
-		Date:         req.Date,
-		Recurring:    req.Recurring,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
-	}
-
-	if err := s.transactionRepo.Create(ctx, transaction); err != nil {
 		s.logger.Error("Erreur création transaction", logger.Error(err))
-		return nil, fmt.Errorf("erreur création transaction: %w", err)
-	}
-
-	// Mettre à jour la balance du compte selon le type de transaction
-	switch req.Type {
-	case "income", "refund":
-		account.Balance += req.Amount
-	case "expense", "saving":
-		account.Balance -= req.Amount
-	}
-
-	// Sauvegarder la mise à jour du compte
-	if err := s.accountRepo.Update(ctx, account); err != nil {
-		s.logger.Error("Erreur mise à jour balance du compte", logger.Error(err))
-		// Note: On ne fait pas échouer la création de transaction si la mise à jour de balance échoue
-		// Mais on log l'erreur pour le debugging
+		return nil, err
 	}
 
 	s.logger.Info("Transaction créée avec succès",
 		logger.String("transaction_id", transaction.ID.String()),
 		logger.String("user_id", userID.String()),
 		logger.String("type", transaction.Type),
-		logger.Float64("amount", transaction.Amount),
+		logger.String("amount", transaction.Amount.String()),
 		logger.String("category_id", func() string {
 			if categoryID != nil {
 				return categoryID.String()
@@ -279,222 +425,2408 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, userID uuid.
 	return transaction, nil
 }
 
-// GetTransaction récupère une transaction par son ID
-func (s *TransactionService) GetTransaction(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID) (*entity.Transaction, error) {
-	transaction, err := s.transactionRepo.GetByID(ctx, transactionID)
+// parseOriginalAmount lit le montant d'origine d'une transaction payée dans une devise autre que celle du compte
+func parseOriginalAmount(value entity.Decimal, currency, accountCurrency string) (*entity.Money, error) {
+	currency, err := normalizeCurrency(currency)
 	if err != nil {
-		s.logger.Error("Erreur récupération transaction", logger.Error(err))
-		return nil, fmt.Errorf("erreur récupération transaction: %w", err)
+		return nil, err
 	}
-
-	// Vérifier que la transaction appartient à l'utilisateur
-	if transaction.UserID != userID {
-		s.logger.Warn("Tentative d'accès non autorisé à une transaction",
-			logger.String("user_id", userID.String()),
-			logger.String("transaction_id", transactionID.String()),
-		)
-		return nil, fmt.Errorf("accès non autorisé")
+	if currency == accountCurrency {
+		return nil, fmt.Errorf("%w: la devise d'origine doit différer de celle du compte (%s)", entity.ErrInvalidCurrency, accountCurrency)
 	}
-
-	return transaction, nil
-}
-
-// GetTransactions récupère les transactions avec filtres et pagination
-func (s *TransactionService) GetTransactions(ctx context.Context, userID uuid.UUID, query TransactionQuery) ([]*entity.Transaction, int64, error) {
-	// Pour l'instant, récupérer toutes les transactions de l'utilisateur
-	// TODO: Implémenter les filtres quand le repository sera créé
-	transactions, err := s.transactionRepo.GetByUserID(ctx, userID)
+	original, err := value.Money(currency)
 	if err != nil {
-		s.logger.Error("Erreur récupération transactions", logger.Error(err))
-		return nil, 0, fmt.Errorf("erreur récupération transactions: %w", err)
+		return nil, err
+	}
+	if !original.IsPositive() {
+		return nil, fmt.Errorf("le montant d'origine doit être positif")
 	}
+	return &original, nil
+}
 
-	// Calculer le total
-	total := int64(len(transactions))
+// createTransfer crée les deux jambes d'un transfert (débit du compte source, crédit du compte destination),
+// reliées par un même TransferGroupID, et met à jour les deux soldes dans une seule transaction SQL.
+// Le compte destination doit être dans la devise du montant ; les deux jambes ont le même statut.
+func (s *TransactionService) createTransfer(ctx context.Context, userID uuid.UUID, categoryID *uuid.UUID, tags []*entity.Tag, amount entity.Money, status string, req entity.CreateTransactionRequest) (*entity.Transaction, error) {
+	transferGroupID := uuid.New()
 
-	// Appliquer la pagination basique
-	start := (query.Page - 1) * query.Limit
-	end := start + query.Limit
-	if int64(start) >= total {
-		return []*entity.Transaction{}, total, nil
+	// jambe débitrice sur le compte source
+	transaction := &entity.Transaction{
+		ID:              uuid.New(),
+		UserID:          userID,
+		CategoryID:      categoryID,
+		AccountID:       req.AccountID,
+		ToAccountID:     req.ToAccountID,
+		TransferGroupID: &transferGroupID,
+		Type:            "transfer",
+		Status:          status,
+		Description:     req.Description,
+		Date:            req.Date,
+		Recurring:       false,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
 	}
-	if int64(end) > total {
-		end = int(total)
+
+	transaction.SetAmount(amount)
+
+	// jambe créditrice sur le compte destination
+	transaction2 := &entity.Transaction{
+		ID:              uuid.New(),
+		UserID:          userID,
+		AccountID:       req.ToAccountID,
+		ToAccountID:     req.ToAccountID,
+		TransferGroupID: &transferGroupID,
+		CategoryID:      categoryID,
+		Type:            "transfer",
+		Status:          status,
+		Description:     req.Description,
+		Date:            req.Date,
+		Recurring:       false,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
 	}
 
-	return transactions[start:end], total, nil
-}
+	transaction2.SetAmount(amount)
 
-// UpdateTransaction met à jour une transaction
-func (s *TransactionService) UpdateTransaction(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID, req entity.UpdateTransactionRequest) (*entity.Transaction, error) {
-	// Récupérer la transaction existante
-	transaction, err := s.transactionRepo.GetByID(ctx, transactionID)
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		accounts, err := s.lockAccounts(ctx, userID, *req.AccountID, *req.ToAccountID)
+		if err != nil {
+			return err
+		}
+		to := accounts[*req.ToAccountID]
+		if to.Currency != amount.Currency {
+			return fmt.Errorf("%w: compte destination en %s, montant en %s", entity.ErrCurrencyMismatch, to.Currency, amount.Currency)
+		}
+		if to.ArchivedAt != nil {
+			return fmt.Errorf("%w: %s", entity.ErrAccountArchived, to.Name)
+		}
+
+		for _, leg := range []*entity.Transaction{transaction, transaction2} {
+			if err := checkPeriodLock(accounts, nil, leg); err != nil {
+				return err
+			}
+			if err := s.transactionRepo.Create(ctx, leg); err != nil {
+				return fmt.Errorf("erreur création jambe du transfert: %w", err)
+			}
+			if err := s.recordVersion(ctx, versionActionCreate, nil, leg); err != nil {
+				return err
+			}
+			if err := s.applyTransactionEffect(ctx, userID, leg, accounts, 1); err != nil {
+				return err
+			}
+		}
+		if len(tags) > 0 {
+			return s.setTags(ctx, tags, transaction, transaction2)
+		}
+		return nil
+	})
 	if err != nil {
-		s.logger.Error("Erreur récupération transaction pour mise à jour", logger.Error(err))
-		return nil, fmt.Errorf("transaction non trouvée")
+		s.logger.Error("Erreur création transfert", logger.Error(err))
+		return nil, err
 	}
 
-	// Vérifier que la transaction appartient à l'utilisateur
-	if transaction.UserID != userID {
-		s.logger.Warn("Tentative de mise à jour non autorisée d'une transaction",
-			logger.String("user_id", userID.String()),
-			logger.String("transaction_id", transactionID.String()),
-		)
-		return nil, fmt.Errorf("accès non autorisé")
+	s.logger.Info("Transfert créé avec succès",
+		logger.String("transfer_group_id", transferGroupID.String()),
+		logger.String("user_id", userID.String()),
+		logger.String("amount", amount.String()),
+	)
+
+	return transaction, nil
+}
+
+// CreateRecurringOccurrence crée l'occurrence d'une transaction récurrente à la date donnée
+// et applique son effet sur le solde du compte. Appelée par le planificateur des transactions récurrentes.
+func (s *TransactionService) CreateRecurringOccurrence(ctx context.Context, recurring *entity.RecurringTransaction, date time.Time) (*entity.Transaction, error) {
+	transaction := &entity.Transaction{
+		ID:          uuid.New(),
+		UserID:      recurring.UserID,
+		AccountID:   &recurring.AccountID,
+		CategoryID:  recurring.CategoryID,
+		Type:        recurring.Type,
+		RecurringID: &recurring.ID,
+		Status:      transactionStatusCleared,
+		Description: recurring.Description,
+		Date:        date,
+		Recurring:   true,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
 	}
+	transaction.SetAmount(recurring.Amount)
 
-	// Mettre à jour les champs
-	if req.Amount != nil {
-		if *req.Amount <= 0 {
-			return nil, fmt.Errorf("le montant doit être positif")
+	err := s.txManager.WithinTransaction(withChangeSource(ctx, changeSourceRecurring), func(ctx context.Context) error {
+		accounts, err := s.lockAccounts(ctx, recurring.UserID, recurring.AccountID)
+		if err != nil {
+			return err
+		}
+		if account := accounts[recurring.AccountID]; account.ArchivedAt != nil {
+			return fmt.Errorf("%w: %s", entity.ErrAccountArchived, account.Name)
+		}
+		if err := checkPeriodLock(accounts, nil, transaction); err != nil {
+			return err
 		}
-		transaction.Amount = *req.Amount
-	}
 
-	if req.Type != nil {
-		if *req.Type != "income" && *req.Type != "expense" {
-			return nil, fmt.Errorf("le type doit être 'income' ou 'expense'")
+		if err := s.transactionRepo.Create(ctx, transaction); err != nil {
+			return fmt.Errorf("erreur création occurrence: %w", err)
+		}
+		if err := s.recordVersion(ctx, versionActionCreate, nil, transaction); err != nil {
+			return err
 		}
-		transaction.Type = *req.Type
-	}
 
-	if req.Description != nil {
-		transaction.Description = *req.Description
+		return s.applyTransactionEffect(ctx, recurring.UserID, transaction, accounts, 1)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if req.Date != nil {
-		transaction.Date = *req.Date
+	return transaction, nil
+}
+
+// CreateReconciliationAdjustment enregistre l'écart d'un rapprochement comme une transaction passée à la date
+// du relevé : un revenu pour un écart positif, une dépense pour un écart négatif
+func (s *TransactionService) CreateReconciliationAdjustment(ctx context.Context, userID, accountID uuid.UUID, difference entity.Money, date time.Time, description string) (*entity.Transaction, error) {
+	if difference.IsZero() {
+		return nil, fmt.Errorf("%w: aucun écart à ajuster", entity.ErrInvalidReconciliation)
+	}
+	txType, amount := "income", difference
+	if difference.IsNegative() {
+		txType, amount = "expense", difference.Neg()
+	}
+	if description == "" {
+		description = "Ajustement de rapprochement"
 	}
 
-	if req.CategoryID != nil {
-		transaction.CategoryID = req.CategoryID
+	transaction := &entity.Transaction{
+		ID:          uuid.New(),
+		UserID:      userID,
+		AccountID:   &accountID,
+		Type:        txType,
+		Status:      transactionStatusCleared,
+		Description: description,
+		Date:        date,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
 	}
+	transaction.SetAmount(amount)
 
-	if req.AccountID != nil {
-		// Vérifier que le nouveau compte appartient à l'utilisateur
-		account, err := s.accountRepo.GetByID(ctx, *req.AccountID)
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		accounts, err := s.lockAccounts(ctx, userID, accountID)
 		if err != nil {
-			return nil, fmt.Errorf("compte non trouvé")
+			return err
 		}
-		if account.UserID != userID {
-			return nil, fmt.Errorf("accès non autorisé au compte")
+		if err := checkPeriodLock(accounts, nil, transaction); err != nil {
+			return err
 		}
-		transaction.AccountID = req.AccountID
-	}
 
-	transaction.UpdatedAt = time.Now()
+		if err := s.transactionRepo.Create(ctx, transaction); err != nil {
+			return fmt.Errorf("erreur création ajustement: %w", err)
+		}
+		if err := s.recordVersion(ctx, versionActionCreate, nil, transaction); err != nil {
+			return err
+		}
 
-	// Sauvegarder les modifications
-	if err := s.transactionRepo.Update(ctx, transaction); err != nil {
-		s.logger.Error("Erreur mise à jour transaction", logger.Error(err))
-		return nil, fmt.Errorf("erreur mise à jour transaction: %w", err)
+		return s.applyTransactionEffect(ctx, userID, transaction, accounts, 1)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	s.logger.Info("Transaction mise à jour avec succès",
-		logger.String("transaction_id", transaction.ID.String()),
-		logger.String("user_id", userID.String()),
-	)
-
 	return transaction, nil
 }
 
-// DeleteTransaction supprime une transaction
-func (s *TransactionService) DeleteTransaction(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID) error {
-	// Récupérer la transaction existante
+// GetTransaction récupère une transaction par son ID
+func (s *TransactionService) GetTransaction(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID) (*entity.Transaction, error) {
 	transaction, err := s.transactionRepo.GetByID(ctx, transactionID)
 	if err != nil {
-		s.logger.Error("Erreur récupération transaction pour suppression", logger.Error(err))
-		return fmt.Errorf("transaction non trouvée")
+		s.logger.Error("Erreur récupération transaction", logger.Error(err))
+		return nil, fmt.Errorf("erreur récupération transaction: %w", err)
 	}
 
 	// Vérifier que la transaction appartient à l'utilisateur
 	if transaction.UserID != userID {
-		s.logger.Warn("Tentative de suppression non autorisée d'une transaction",
+		s.logger.Warn("Tentative d'accès non autorisé à une transaction",
 			logger.String("user_id", userID.String()),
 			logger.String("transaction_id", transactionID.String()),
 		)
-		return fmt.Errorf("accès non autorisé")
+		return nil, fmt.Errorf("accès non autorisé")
 	}
 
-	// Supprimer la transaction
-	if err := s.transactionRepo.Delete(ctx, transactionID); err != nil {
-		s.logger.Error("Erreur suppression transaction", logger.Error(err))
-		return fmt.Errorf("erreur suppression transaction: %w", err)
+	return transaction, nil
+}
+
+// GetTransactions récupère une page de transactions filtrées et triées en base.
+// La pagination se fait par curseur : la page suivante est demandée avec le next_cursor
+// de la page courante, ce qui garde les résultats stables malgré les insertions.
+func (s *TransactionService) GetTransactions(ctx context.Context, userID uuid.UUID, query TransactionQuery) (*entity.TransactionPageResponse, error) {
+	filter, err := buildTransactionFilter(query)
+	if err != nil {
+		return nil, err
 	}
 
-	s.logger.Info("Transaction supprimée avec succès",
-		logger.String("transaction_id", transactionID.String()),
-		logger.String("user_id", userID.String()),
-	)
+	// Lire un élément de plus pour savoir s'il existe une page suivante
+	limit := filter.Limit
+	filter.Limit = limit + 1
+	transactions, err := s.transactionRepo.List(ctx, userID, filter)
+	if err != nil {
+		s.logger.Error("Erreur récupération transactions", logger.Error(err))
+		return nil, fmt.Errorf("erreur récupération transactions: %w", err)
+	}
+	filter.Limit = limit
 
-	return nil
+	total, err := s.transactionRepo.Count(ctx, userID, filter)
+	if err != nil {
+		s.logger.Error("Erreur comptage transactions", logger.Error(err))
+		return nil, fmt.Errorf("erreur comptage transactions: %w", err)
+	}
+
+	page := &entity.TransactionPageResponse{
+		Transactions: transactions,
+		Pagination: entity.CursorPagination{
+			Limit: limit,
+			Total: total,
+		},
+	}
+	if len(transactions) > limit {
+		page.Transactions = transactions[:limit]
+		page.Pagination.HasMore = true
+		page.Pagination.NextCursor, err = encodeTransactionCursor(page.Transactions[limit-1], filter.SortBy)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if page.Transactions == nil {
+		page.Transactions = []*entity.Transaction{}
+	}
+
+	return page, nil
 }
 
-// GetTransactionStats récupère les statistiques des transactions
-func (s *TransactionService) GetTransactionStats(ctx context.Context, userID uuid.UUID, period string) (map[string]interface{}, error) {
-	// Validation de la période
-	if period != "week" && period != "month" && period != "year" {
-		period = "month" // Valeur par défaut
+// buildTransactionFilter valide les paramètres de requête et les convertit en filtre de repository
+func buildTransactionFilter(query TransactionQuery) (*entity.TransactionFilter, error) {
+	filter := &entity.TransactionFilter{
+		Type:       query.Type,
+		CategoryID: query.CategoryID,
+		AccountID:  query.AccountID,
+		TagIDs:     query.TagIDs,
+		TagMatch:   query.TagMatch,
+		MinAmount:  query.MinAmount,
+		MaxAmount:  query.MaxAmount,
+		Search:     strings.TrimSpace(query.Search),
+		Status:     query.Status,
+		SortBy:     query.SortBy,
+		SortOrder:  query.SortOrder,
+		Limit:      query.Limit,
 	}
 
-	// Pour l'instant, retourner des statistiques basiques
-	// TODO: Implémenter les vraies statistiques quand le repository sera créé
-	transactions, err := s.transactionRepo.GetByUserID(ctx, userID)
-	if err != nil {
-		s.logger.Error("Erreur récupération statistiques transactions", logger.Error(err))
-		return nil, fmt.Errorf("erreur récupération statistiques: %w", err)
+	if filter.SortBy == "" {
+		filter.SortBy = "date"
+	}
+	if filter.SortBy != "date" && filter.SortBy != "amount" && filter.SortBy != "created_at" {
+		return nil, fmt.Errorf("%w: tri non supporté: %s", entity.ErrInvalidTransactionQuery, filter.SortBy)
+	}
+	if filter.SortOrder == "" {
+		filter.SortOrder = "desc"
+	}
+	if filter.SortOrder != "asc" && filter.SortOrder != "desc" {
+		return nil, fmt.Errorf("%w: ordre de tri non supporté: %s", entity.ErrInvalidTransactionQuery, filter.SortOrder)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 10
+	}
+	if filter.TagMatch == "" {
+		filter.TagMatch = "any"
+	}
+	if filter.TagMatch != "any" && filter.TagMatch != "all" {
+		return nil, fmt.Errorf("%w: correspondance d'étiquettes non supportée: %s", entity.ErrInvalidTransactionQuery, filter.TagMatch)
+	}
+	if filter.Status != "" && !isValidTransactionStatus(filter.Status) {
+		return nil, fmt.Errorf("%w: statut non supporté: %s", entity.ErrInvalidTransactionQuery, filter.Status)
 	}
 
-	// Calculer les statistiques basiques
-	var totalIncome, totalExpense float64
-	for _, tx := range transactions {
-		if tx.Type == "income" {
-			totalIncome += tx.Amount
-		} else if tx.Type == "expense" {
-			totalExpense += tx.Amount
+	if query.StartDate != "" {
+		startDate, err := time.Parse("2006-01-02", query.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: date de début invalide", entity.ErrInvalidTransactionQuery)
+		}
+		filter.StartDate = &startDate
+	}
+	if query.EndDate != "" {
+		endDate, err := time.Parse("2006-01-02", query.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: date de fin invalide", entity.ErrInvalidTransactionQuery)
 		}
+		filter.EndDate = &endDate
+	}
+	if filter.StartDate != nil && filter.EndDate != nil && filter.EndDate.Before(*filter.StartDate) {
+		return nil, fmt.Errorf("%w: la date de fin précède la date de début", entity.ErrInvalidTransactionQuery)
+	}
+	minAmount, err := amountBound(filter.MinAmount, "minimum")
+	if err != nil {
+		return nil, err
+	}
+	maxAmount, err := amountBound(filter.MaxAmount, "maximum")
+	if err != nil {
+		return nil, err
+	}
+	if minAmount != nil && maxAmount != nil && maxAmount.Cmp(minAmount) < 0 {
+		return nil, fmt.Errorf("%w: le montant maximum est inférieur au montant minimum", entity.ErrInvalidTransactionQuery)
 	}
 
-	stats := map[string]interface{}{
-		"period":        period,
-		"total_income":  totalIncome,
-		"total_expense": totalExpense,
-		"net_amount":    totalIncome - totalExpense,
-		"count":         len(transactions),
+	if query.Cursor != "" {
+		cursor, err := decodeTransactionCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.Cursor = cursor
 	}
 
-	return stats, nil
+	return filter, nil
 }
 
-// GetTransactionsByDateRange récupère les transactions dans une plage de dates
-func (s *TransactionService) GetTransactionsByDateRange(ctx context.Context, userID uuid.UUID, startDate, endDate string) ([]*entity.Transaction, error) {
-	transactions, err := s.transactionRepo.GetByDateRange(ctx, userID, startDate, endDate)
+// amountBound valide une borne de montant du filtre et retourne sa valeur exacte (nil sans borne)
+func amountBound(bound *entity.Decimal, label string) (*big.Rat, error) {
+	if bound == nil {
+		return nil, nil
+	}
+	value, err := bound.Rat()
 	if err != nil {
-		s.logger.Error("Erreur récupération transactions par plage de dates", logger.Error(err))
-		return nil, fmt.Errorf("erreur récupération transactions: %w", err)
+		return nil, fmt.Errorf("%w: montant %s invalide", entity.ErrInvalidTransactionQuery, label)
 	}
-
-	return transactions, nil
+	return value, nil
 }
 
-// GetTransactionsByUserID récupère toutes les transactions d'un utilisateur
-func (s *TransactionService) GetTransactionsByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Transaction, error) {
-	transactions, err := s.transactionRepo.GetByUserID(ctx, userID)
-	if err != nil {
-		s.logger.Error("Erreur récupération transactions utilisateur", logger.Error(err))
-		return nil, fmt.Errorf("erreur récupération transactions: %w", err)
+// encodeTransactionCursor construit le curseur opaque pointant après la transaction donnée
+func encodeTransactionCursor(t *entity.Transaction, sortBy string) (string, error) {
+	cursor := entity.TransactionCursor{CreatedAt: t.CreatedAt, ID: t.ID}
+	switch sortBy {
+	case "date":
+		cursor.SortValue = t.Date.Format("2006-01-02")
+	case "amount":
+		cursor.SortValue = t.Amount.Decimal()
 	}
 
-	return transactions, nil
+	raw, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("erreur encodage curseur: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
 }
 
-// GetTransactionsByAccountID récupère toutes les transactions d'un compte spécifique avec les détails de la catégorie
-func (s *TransactionService) GetTransactionsByAccountID(ctx context.Context, userID uuid.UUID, accountID uuid.UUID) ([]*entity.Transaction, error) {
-	transactions, err := s.transactionRepo.GetByAccountIDWithCategoryDetails(ctx, userID, &accountID)
+// decodeTransactionCursor relit un curseur produit par encodeTransactionCursor
+func decodeTransactionCursor(value string) (*entity.TransactionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
 	if err != nil {
-		s.logger.Error("Erreur récupération transactions par compte", logger.Error(err))
-		return nil, fmt.Errorf("erreur récupération transactions: %w", err)
+		return nil, fmt.Errorf("%w: curseur invalide", entity.ErrInvalidTransactionQuery)
 	}
+	var cursor entity.TransactionCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID == uuid.Nil {
+		return nil, fmt.Errorf("%w: curseur invalide", entity.ErrInvalidTransactionQuery)
+	}
+	return &cursor, nil
+}
 
-	return transactions, nil
+// UpdateTransaction met à jour une transaction.
+// L'effet de l'ancienne version sur les soldes (et l'objectif d'épargne) est annulé puis celui
+// de la nouvelle version est appliqué, y compris lorsque la transaction change de compte (de même devise).
+func (s *TransactionService) UpdateTransaction(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID, req entity.UpdateTransactionRequest) (*entity.Transaction, error) {
+	// Validation des champs modifiés
+	if req.Type != nil {
+		validTypes := []string{"income", "expense", "transfer", "saving", "refund"}
+		isValidType := false
+		for _, validType := range validTypes {
+			if *req.Type == validType {
+				isValidType = true
+				break
+			}
+		}
+		if !isValidType {
+			return nil, fmt.Errorf("%w: le type doit être l'un des suivants: %v", entity.ErrInvalidTransactionData, validTypes)
+		}
+	}
+
+	var tags []*entity.Tag
+	if req.TagIDs != nil {
+		var err error
+		if tags, err = s.resolveTags(ctx, userID, req.TagIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	var transaction *entity.Transaction
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Récupérer et verrouiller la transaction existante
+		existing, err := s.transactionRepo.GetByIDForUpdate(ctx, transactionID)
+		if err != nil {
+			return fmt.Errorf("transaction non trouvée")
+		}
+
+		// Vérifier que la transaction appartient à l'utilisateur
+		if existing.UserID != userID {
+			s.logger.Warn("Tentative de mise à jour non autorisée d'une transaction",
+				logger.String("user_id", userID.String()),
+				logger.String("transaction_id", transactionID.String()),
+			)
+			return fmt.Errorf("accès non autorisé")
+		}
+		if existing.Status == transactionStatusReconciled {
+			return fmt.Errorf("%w: repasser la transaction en cleared pour la modifier", entity.ErrTransactionReconciled)
+		}
+
+		// Le nouveau montant est saisi dans la devise de la transaction
+		var amount *entity.Money
+		if req.Amount != nil {
+			parsed, err := req.Amount.Money(existing.Currency)
+			if err != nil {
+				return err
+			}
+			if !parsed.IsPositive() {
+				return fmt.Errorf("%w: le montant doit être positif", entity.ErrInvalidTransactionData)
+			}
+			amount = &parsed
+		}
+
+		// Montant d'origine en devise étrangère : remplacé, retiré par une devise vide, ou conservé.
+		// Un nouveau montant d'origine sans montant dans la devise du compte est converti au taux de la date.
+		originalChanged := req.OriginalAmount != nil || req.OriginalCurrency != nil
+		originalAmount := existing.OriginalAmount
+		if originalChanged {
+			if existing.TransferGroupID != nil {
+				return fmt.Errorf("%w: un transfert ne peut pas avoir de montant d'origine en devise étrangère", entity.ErrInvalidTransactionData)
+			}
+			currency := existing.OriginalCurrency
+			if req.OriginalCurrency != nil {
+				currency = *req.OriginalCurrency
+			}
+			switch {
+			case currency == "":
+				originalAmount = nil
+			case req.OriginalAmount == nil:
+				return fmt.Errorf("%w: le montant d'origine est requis avec sa devise", entity.ErrInvalidTransactionData)
+			default:
+				if originalAmount, err = parseOriginalAmount(*req.OriginalAmount, currency, existing.Currency); err != nil {
+					return err
+				}
+				if amount == nil {
+					date := existing.Date
+					if req.Date != nil {
+						date = *req.Date
+					}
+					converted, err := s.exchangeRates.Convert(ctx, userID, *originalAmount, existing.Currency, date)
+					if err != nil {
+						return err
+					}
+					if !converted.IsPositive() {
+						return fmt.Errorf("%w: le montant doit être positif", entity.ErrInvalidTransactionData)
+					}
+					amount = &converted
+				}
+			}
+		}
+
+		// Un transfert se modifie sur ses deux jambes
+		if existing.TransferGroupID != nil {
+			transaction, err = s.updateTransfer(ctx, userID, existing, tags, amount, req)
+			return err
+		}
+
+		if req.Type != nil && *req.Type == "transfer" {
+			return fmt.Errorf("%w: une transaction ne peut pas être convertie en transfert", entity.ErrInvalidTransactionData)
+		}
+
+		// Un remboursement reste lié à sa dépense d'origine, qui ne peut passer sous le montant déjà remboursé
+		if req.Type != nil && *req.Type != existing.Type && (*req.Type == "refund" || existing.Type == "refund") {
+			return fmt.Errorf("%w: le type d'un remboursement ne peut pas être modifié", entity.ErrInvalidRefund)
+		}
+		if existing.RefundedAmount.IsPositive() {
+			if req.Type != nil && *req.Type != existing.Type {
+				return fmt.Errorf("%w: le type d'une dépense remboursée ne peut pas être modifié", entity.ErrInvalidRefund)
+			}
+			if amount != nil && amount.Cmp(existing.RefundedAmount) < 0 {
+				return fmt.Errorf("%w: le montant ne peut pas être inférieur au total remboursé (%s)", entity.ErrInvalidRefund, existing.RefundedAmount)
+			}
+		}
+
+		existingSplits, err := s.transactionRepo.GetSplits(ctx, existing.ID)
+		if err != nil {
+			return err
+		}
+		if req.TagIDs != nil {
+			if err := s.loadTags(ctx, existing); err != nil {
+				return err
+			}
+		}
+
+		// Construire la nouvelle version à partir de l'existante
+		updated := *existing
+
+		if amount != nil {
+			updated.SetAmount(*amount)
+		}
+
+		if originalChanged {
+			updated.SetOriginalAmount(originalAmount)
+		}
+
+		if req.Type != nil {
+			updated.Type = *req.Type
+		}
+
+		if req.Description != nil {
+			updated.Description = *req.Description
+		}
+
+		if req.Date != nil {
+			updated.Date = *req.Date
+			updated.Status = statusForDate(updated.Status, updated.Date, time.Now())
+		}
+
+		if req.CategoryID != nil {
+			updated.CategoryID = req.CategoryID
+		} else if req.ClearCategory {
+			updated.CategoryID = nil
+		}
+
+		if req.AccountID != nil {
+			updated.AccountID = req.AccountID
+		}
+
+		if req.SavingGoalID != nil {
+			updated.SavingGoalID = req.SavingGoalID
+		}
+
+		if req.Recurring != nil {
+			updated.Recurring = *req.Recurring
+		}
+
+		// Seule une épargne est rattachée à un objectif d'épargne
+		if updated.Type != "saving" {
+			updated.SavingGoalID = nil
+		} else if updated.SavingGoalID == nil {
+			return fmt.Errorf("%w: l'objectif d'épargne est requis pour une épargne", entity.ErrInvalidTransactionData)
+		}
+
+		// La ventilation doit rester cohérente avec le montant et le type
+		splitsChanged := req.Splits != nil
+		splitRequests := req.Splits
+		if !splitsChanged && len(existingSplits) > 0 && (updated.Amount != existing.Amount || updated.Type != existing.Type) {
+			if updated.Type != existing.Type {
+				return fmt.Errorf("%w: la ventilation doit être fournie ou supprimée lors d'un changement de type", entity.ErrInvalidTransactionData)
+			}
+			return fmt.Errorf("%w: la ventilation doit être mise à jour avec le montant", entity.ErrInvalidTransactionData)
+		}
+		updated.Splits = existingSplits
+		if splitsChanged {
+			updated.Splits, err = s.buildSplits(ctx, userID, updated.ID, updated.Type, updated.Amount, splitRequests)
+			if err != nil {
+				return err
+			}
+			if len(updated.Splits) > 0 && req.CategoryID == nil {
+				updated.CategoryID = &mainSplit(updated.Splits).CategoryID
+			}
+		}
+
+		if existing.RefundOfID != nil && updated.Amount != existing.Amount {
+			if err := s.applyRefund(ctx, *existing.RefundOfID, updated.Amount.Sub(existing.Amount)); err != nil {
+				return err
+			}
+		}
+
+		// Verrouiller l'ancien et le nouveau compte (vérifie aussi l'appartenance du nouveau compte)
+		accounts, err := s.lockAccounts(ctx, userID, accountIDsOf(existing, &updated)...)
+		if err != nil {
+			return err
+		}
+		if err := checkTargetAccount(accounts, existing, &updated); err != nil {
+			return err
+		}
+		if err := checkPeriodLock(accounts, existing, &updated); err != nil {
+			return err
+		}
+
+		// Annuler l'effet de l'ancienne version puis appliquer celui de la nouvelle
+		if err := s.applyTransactionEffect(ctx, userID, existing, accounts, -1); err != nil {
+			return err
+		}
+		if err := s.applyTransactionEffect(ctx, userID, &updated, accounts, 1); err != nil {
+			return err
+		}
+
+		updated.UpdatedAt = time.Now()
+
+		// Sauvegarder les modifications
+		if err := s.transactionRepo.Update(ctx, &updated); err != nil {
+			return fmt.Errorf("erreur mise à jour transaction: %w", err)
+		}
+		if splitsChanged {
+			if err := s.transactionRepo.ReplaceSplits(ctx, updated.ID, updated.Splits); err != nil {
+				return err
+			}
+		}
+		if req.TagIDs != nil {
+			if err := s.setTags(ctx, tags, &updated); err != nil {
+				return err
+			}
+		}
+		before := *existing
+		before.Splits = existingSplits
+		if err := s.recordVersion(ctx, versionActionUpdate, &before, &updated); err != nil {
+			return err
+		}
+
+		transaction = &updated
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Erreur mise à jour transaction", logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Transaction mise à jour avec succès",
+		logger.String("transaction_id", transaction.ID.String()),
+		logger.String("user_id", userID.String()),
+	)
+
+	return transaction, nil
+}
+
+// DeleteTransaction met une transaction à la corbeille et annule son effet sur le solde du compte
+func (s *TransactionService) DeleteTransaction(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID) error {
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Récupérer et verrouiller la transaction existante
+		transaction, err := s.transactionRepo.GetByIDForUpdate(ctx, transactionID)
+		if err != nil {
+			return fmt.Errorf("transaction non trouvée")
+		}
+
+		// Vérifier que la transaction appartient à l'utilisateur
+		if transaction.UserID != userID {
+			s.logger.Warn("Tentative de suppression non autorisée d'une transaction",
+				logger.String("user_id", userID.String()),
+				logger.String("transaction_id", transactionID.String()),
+			)
+			return fmt.Errorf("accès non autorisé")
+		}
+		if transaction.Status == transactionStatusReconciled {
+			return fmt.Errorf("%w: repasser la transaction en cleared pour la supprimer", entity.ErrTransactionReconciled)
+		}
+
+		// Une dépense remboursée garde ses remboursements ; supprimer un remboursement le retire de sa dépense
+		if transaction.RefundedAmount.IsPositive() {
+			return fmt.Errorf("%w: supprimer d'abord les remboursements", entity.ErrTransactionHasRefunds)
+		}
+		if transaction.RefundOfID != nil {
+			if err := s.applyRefund(ctx, *transaction.RefundOfID, transaction.Amount.Neg()); err != nil {
+				return err
+			}
+		}
+
+		// Supprimer un transfert revient à supprimer ses deux jambes
+		legs := []*entity.Transaction{transaction}
+		if transaction.TransferGroupID != nil {
+			legs, err = s.transactionRepo.GetByTransferGroupIDForUpdate(ctx, *transaction.TransferGroupID)
+			if err != nil {
+				return err
+			}
+		}
+
+		accounts, err := s.lockAccounts(ctx, userID, accountIDsOf(legs...)...)
+		if err != nil {
+			return err
+		}
+
+		for _, leg := range legs {
+			if err := checkPeriodLock(accounts, leg, nil); err != nil {
+				return err
+			}
+			if err := s.applyTransactionEffect(ctx, userID, leg, accounts, -1); err != nil {
+				return err
+			}
+
+			// Mettre la transaction à la corbeille ; ses pièces jointes sont conservées jusqu'à la purge
+			if err := s.transactionRepo.Delete(ctx, leg.ID); err != nil {
+				return fmt.Errorf("erreur suppression transaction: %w", err)
+			}
+			if err := s.recordVersion(ctx, versionActionDelete, leg, leg); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Erreur suppression transaction", logger.Error(err))
+		return err
+	}
+
+	s.logger.Info("Transaction supprimée avec succès",
+		logger.String("transaction_id", transactionID.String()),
+		logger.String("user_id", userID.String()),
+	)
+
+	return nil
+}
+
+// RestoreTransaction sort une transaction de la corbeille et réapplique son effet sur le solde du compte
+// (et sur l'objectif d'épargne ou la dépense remboursée). Un transfert est restauré avec ses deux jambes ;
+// une transaction supprimée avec son compte ne peut être restaurée qu'avec lui.
+func (s *TransactionService) RestoreTransaction(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID) (*entity.Transaction, error) {
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		transaction, err := s.transactionRepo.GetDeletedByIDForUpdate(ctx, transactionID)
+		if err != nil {
+			return err
+		}
+		if transaction.UserID != userID {
+			return fmt.Errorf("%w: transaction %s", entity.ErrTrashItemNotFound, transactionID)
+		}
+		if transaction.DeletedWithAccountID != nil {
+			return fmt.Errorf("%w: la transaction a été supprimée avec son compte, restaurer le compte", entity.ErrRestoreNotPossible)
+		}
+
+		legs := []*entity.Transaction{transaction}
+		if transaction.TransferGroupID != nil {
+			legs, err = s.transactionRepo.GetDeletedByTransferGroupIDForUpdate(ctx, *transaction.TransferGroupID)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Une référence externe reprise entre-temps par une autre transaction du compte bloque la restauration
+		for _, leg := range legs {
+			if leg.ExternalRef == nil || leg.AccountID == nil {
+				continue
+			}
+			existing, err := s.transactionRepo.GetExistingExternalRefs(ctx, *leg.AccountID, []string{*leg.ExternalRef})
+			if err != nil {
+				return err
+			}
+			if len(existing) > 0 {
+				return fmt.Errorf("%w: %s", entity.ErrDuplicateExternalRef, *leg.ExternalRef)
+			}
+		}
+
+		if transaction.RefundOfID != nil {
+			if err := s.applyRefund(ctx, *transaction.RefundOfID, transaction.Amount); err != nil {
+				return fmt.Errorf("%w: dépense d'origine: %v", entity.ErrRestoreNotPossible, err)
+			}
+		}
+
+		accounts, err := s.lockAccounts(ctx, userID, accountIDsOf(legs...)...)
+		if err != nil {
+			return fmt.Errorf("%w: %v", entity.ErrRestoreNotPossible, err)
+		}
+
+		for _, leg := range legs {
+			if err := checkPeriodLock(accounts, nil, leg); err != nil {
+				return fmt.Errorf("%w: %v", entity.ErrRestoreNotPossible, err)
+			}
+			if err := s.applyTransactionEffect(ctx, userID, leg, accounts, 1); err != nil {
+				return fmt.Errorf("%w: %v", entity.ErrRestoreNotPossible, err)
+			}
+			if err := s.transactionRepo.Restore(ctx, leg.ID); err != nil {
+				return err
+			}
+			if err := s.recordVersion(ctx, versionActionRestore, leg, leg); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Erreur restauration transaction", logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Transaction restaurée avec succès",
+		logger.String("transaction_id", transactionID.String()),
+		logger.String("user_id", userID.String()),
+	)
+
+	return s.transactionRepo.GetByID(ctx, transactionID)
+}
+
+// updateTransfer applique une modification à une jambe de transfert sur les deux jambes.
+// Modifier le compte d'une jambe change le compte source ou destination du transfert.
+// Doit être appelé à l'intérieur de TxManager.WithinTransaction.
+func (s *TransactionService) updateTransfer(ctx context.Context, userID uuid.UUID, existing *entity.Transaction, tags []*entity.Tag, amount *entity.Money, req entity.UpdateTransactionRequest) (*entity.Transaction, error) {
+	if req.Type != nil && *req.Type != "transfer" {
+		return nil, fmt.Errorf("le type d'un transfert ne peut pas être modifié")
+	}
+
+	legs, err := s.transactionRepo.GetByTransferGroupIDForUpdate(ctx, *existing.TransferGroupID)
+	if err != nil {
+		return nil, err
+	}
+	out, in := splitTransferLegs(legs)
+	if out == nil || in == nil {
+		return nil, fmt.Errorf("transfert incomplet: jambes introuvables")
+	}
+
+	fromID, toID := *out.AccountID, *in.AccountID
+	if req.ToAccountID != nil {
+		toID = *req.ToAccountID
+	}
+	if req.AccountID != nil {
+		if existing.ID == in.ID {
+			toID = *req.AccountID
+		} else {
+			fromID = *req.AccountID
+		}
+	}
+	if fromID == toID {
+		return nil, fmt.Errorf("%w: les comptes source et destination doivent être différents", entity.ErrInvalidTransactionData)
+	}
+
+	updatedOut, updatedIn := *out, *in
+	for _, leg := range []*entity.Transaction{&updatedOut, &updatedIn} {
+		if amount != nil {
+			leg.SetAmount(*amount)
+		}
+		if req.Description != nil {
+			leg.Description = *req.Description
+		}
+		if req.Date != nil {
+			leg.Date = *req.Date
+			leg.Status = statusForDate(leg.Status, leg.Date, time.Now())
+		}
+		if req.CategoryID != nil {
+			leg.CategoryID = req.CategoryID
+		} else if req.ClearCategory {
+			leg.CategoryID = nil
+		}
+		leg.ToAccountID = &toID
+		leg.UpdatedAt = time.Now()
+	}
+	updatedOut.AccountID = &fromID
+	updatedIn.AccountID = &toID
+
+	accounts, err := s.lockAccounts(ctx, userID, accountIDsOf(out, in, &updatedOut, &updatedIn)...)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTargetAccount(accounts, out, &updatedOut); err != nil {
+		return nil, err
+	}
+	if err := checkTargetAccount(accounts, in, &updatedIn); err != nil {
+		return nil, err
+	}
+	if err := checkPeriodLock(accounts, out, &updatedOut); err != nil {
+		return nil, err
+	}
+	if err := checkPeriodLock(accounts, in, &updatedIn); err != nil {
+		return nil, err
+	}
+
+	// Annuler les deux anciennes jambes puis appliquer les nouvelles
+	for _, leg := range []*entity.Transaction{out, in} {
+		if err := s.applyTransactionEffect(ctx, userID, leg, accounts, -1); err != nil {
+			return nil, err
+		}
+	}
+	if req.TagIDs != nil {
+		for _, leg := range []*entity.Transaction{out, in} {
+			if err := s.loadTags(ctx, leg); err != nil {
+				return nil, err
+			}
+		}
+		if err := s.setTags(ctx, tags, &updatedOut, &updatedIn); err != nil {
+			return nil, err
+		}
+	}
+	for i, leg := range []*entity.Transaction{&updatedOut, &updatedIn} {
+		if err := s.applyTransactionEffect(ctx, userID, leg, accounts, 1); err != nil {
+			return nil, err
+		}
+		if err := s.transactionRepo.Update(ctx, leg); err != nil {
+			return nil, fmt.Errorf("erreur mise à jour jambe du transfert: %w", err)
+		}
+		if err := s.recordVersion(ctx, versionActionUpdate, []*entity.Transaction{out, in}[i], leg); err != nil {
+			return nil, err
+		}
+	}
+
+	if existing.ID == in.ID {
+		return &updatedIn, nil
+	}
+	return &updatedOut, nil
+}
+
+// resolveTags vérifie que les étiquettes existent et appartiennent à l'utilisateur
+func (s *TransactionService) resolveTags(ctx context.Context, userID uuid.UUID, tagIDs []uuid.UUID) ([]*entity.Tag, error) {
+	tagIDs = uniqueIDs(tagIDs)
+	tags, err := s.tagRepo.GetByIDs(ctx, userID, tagIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) != len(tagIDs) {
+		return nil, fmt.Errorf("%w: étiquette inconnue", entity.ErrTagNotFound)
+	}
+	return tags, nil
+}
+
+// setTags remplace les étiquettes des transactions données
+func (s *TransactionService) setTags(ctx context.Context, tags []*entity.Tag, transactions ...*entity.Transaction) error {
+	tagIDs := make([]uuid.UUID, 0, len(tags))
+	for _, tag := range tags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	for _, transaction := range transactions {
+		if err := s.tagRepo.ReplaceTransactionTags(ctx, transaction.ID, tagIDs); err != nil {
+			return err
+		}
+		transaction.Tags = tags
+	}
+	return nil
+}
+
+// copyTags ajoute à une transaction les étiquettes d'une autre
+func (s *TransactionService) copyTags(ctx context.Context, fromID, toID uuid.UUID) error {
+	tags, err := s.tagRepo.GetByTransactionID(ctx, fromID)
+	if err != nil || len(tags) == 0 {
+		return err
+	}
+	tagIDs := make([]uuid.UUID, 0, len(tags))
+	for _, tag := range tags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	_, err = s.tagRepo.AddToTransactions(ctx, []uuid.UUID{toID}, tagIDs)
+	return err
+}
+
+// uniqueIDs retourne les IDs sans doublons, dans leur ordre d'apparition
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// GetRefunds récupère les remboursements liés à une dépense de l'utilisateur
+func (s *TransactionService) GetRefunds(ctx context.Context, userID, transactionID uuid.UUID) ([]*entity.Transaction, error) {
+	if _, err := s.GetTransaction(ctx, userID, transactionID); err != nil {
+		return nil, err
+	}
+	return s.transactionRepo.GetRefunds(ctx, transactionID)
+}
+
+// getRefundOriginal récupère la dépense d'origine d'un remboursement et vérifie qu'elle appartient à l'utilisateur
+func (s *TransactionService) getRefundOriginal(ctx context.Context, userID uuid.UUID, originalID *uuid.UUID) (*entity.Transaction, error) {
+	if originalID == nil {
+		return nil, fmt.Errorf("%w: la dépense d'origine est requise", entity.ErrInvalidRefund)
+	}
+	original, err := s.transactionRepo.GetByID(ctx, *originalID)
+	if err != nil || original.UserID != userID {
+		return nil, fmt.Errorf("%w: dépense d'origine non trouvée", entity.ErrInvalidRefund)
+	}
+	if original.Type != "expense" {
+		return nil, fmt.Errorf("%w: seule une dépense peut être remboursée", entity.ErrInvalidRefund)
+	}
+	return original, nil
+}
+
+// applyRefund ajoute delta au total remboursé de la dépense d'origine, verrouillée jusqu'à la fin de la
+// transaction SQL ; le total remboursé ne peut pas dépasser le montant de la dépense.
+// Doit être appelé à l'intérieur de TxManager.WithinTransaction.
+func (s *TransactionService) applyRefund(ctx context.Context, originalID uuid.UUID, delta entity.Money) error {
+	original, err := s.transactionRepo.GetByIDForUpdate(ctx, originalID)
+	if err != nil {
+		return err
+	}
+	if delta.Currency != original.Currency {
+		return fmt.Errorf("%w: remboursement en %s d'une dépense en %s", entity.ErrCurrencyMismatch, delta.Currency, original.Currency)
+	}
+
+	refunded := original.RefundedAmount.Add(delta)
+	if refunded.Cmp(original.Amount) > 0 {
+		return fmt.Errorf("%w: %s restant", entity.ErrRefundExceedsOriginal, original.NetAmount())
+	}
+	if refunded.IsNegative() {
+		refunded = entity.NewMoney(0, original.Currency)
+	}
+
+	before := *original
+	original.RefundedAmount = refunded
+	original.UpdatedAt = time.Now()
+	if err := s.transactionRepo.Update(ctx, original); err != nil {
+		return fmt.Errorf("erreur mise à jour du total remboursé: %w", err)
+	}
+	return s.recordVersion(ctx, versionActionUpdate, &before, original)
+}
+
+// GetTransfers récupère les transferts de l'utilisateur, chacun présenté comme un mouvement unique
+func (s *TransactionService) GetTransfers(ctx context.Context, userID uuid.UUID) ([]*entity.Transfer, error) {
+	legs, err := s.transactionRepo.GetTransfers(ctx, userID)
+	if err != nil {
+		s.logger.Error("Erreur récupération transferts", logger.Error(err))
+		return nil, fmt.Errorf("erreur récupération transferts: %w", err)
+	}
+
+	// Regrouper les jambes par transfert en conservant l'ordre (date décroissante)
+	var order []uuid.UUID
+	groups := make(map[uuid.UUID][]*entity.Transaction)
+	for _, leg := range legs {
+		groupID := *leg.TransferGroupID
+		if _, ok := groups[groupID]; !ok {
+			order = append(order, groupID)
+		}
+		groups[groupID] = append(groups[groupID], leg)
+	}
+
+	transfers := make([]*entity.Transfer, 0, len(order))
+	for _, groupID := range order {
+		out, in := splitTransferLegs(groups[groupID])
+		if out == nil || in == nil {
+			s.logger.Warn("Transfert incomplet ignoré", logger.String("transfer_group_id", groupID.String()))
+			continue
+		}
+		transfers = append(transfers, &entity.Transfer{
+			ID:                groupID,
+			FromAccountID:     *out.AccountID,
+			ToAccountID:       *in.AccountID,
+			FromTransactionID: out.ID,
+			ToTransactionID:   in.ID,
+			CategoryID:        out.CategoryID,
+			Amount:            out.Amount,
+			Description:       out.Description,
+			Date:              out.Date,
+			CreatedAt:         out.CreatedAt,
+		})
+	}
+
+	return transfers, nil
+}
+
+// ScanDuplicates recherche les transactions existantes probablement en double : même compte, même type,
+// même montant, libellé équivalent et dates distantes d'au plus windowDays jours (transferts exclus)
+func (s *TransactionService) ScanDuplicates(ctx context.Context, userID uuid.UUID, windowDays int) (*entity.DuplicateScanResponse, error) {
+	if windowDays == 0 {
+		windowDays = duplicateWindowDays
+	}
+	if windowDays < 0 || windowDays > maxDuplicateWindowDays {
+		return nil, fmt.Errorf("%w: la fenêtre doit être comprise entre 1 et %d jours", entity.ErrInvalidTransactionQuery, maxDuplicateWindowDays)
+	}
+
+	transactions, err := s.transactionRepo.GetForDuplicateScan(ctx, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Regroupement par empreinte, dans l'ordre des transactions (déjà triées par date)
+	var fingerprints []string
+	byFingerprint := make(map[string][]*entity.Transaction)
+	for _, transaction := range transactions {
+		fingerprint := duplicateFingerprint(transaction)
+		if _, ok := byFingerprint[fingerprint]; !ok {
+			fingerprints = append(fingerprints, fingerprint)
+		}
+		byFingerprint[fingerprint] = append(byFingerprint[fingerprint], transaction)
+	}
+
+	// Dans une même empreinte, les transactions séparées de moins de windowDays jours forment un groupe
+	window := time.Duration(windowDays) * 24 * time.Hour
+	result := &entity.DuplicateScanResponse{WindowDays: windowDays, Groups: []*entity.DuplicateGroup{}}
+	for _, fingerprint := range fingerprints {
+		var current []*entity.Transaction
+		flush := func() {
+			if len(current) > 1 {
+				result.Groups = append(result.Groups, &entity.DuplicateGroup{Fingerprint: fingerprint, Transactions: current})
+				result.DuplicateCount += len(current) - 1
+			}
+			current = nil
+		}
+		for _, transaction := range byFingerprint[fingerprint] {
+			if len(current) > 0 && transaction.Date.Sub(current[len(current)-1].Date) > window {
+				flush()
+			}
+			current = append(current, transaction)
+		}
+		flush()
+	}
+
+	return result, nil
+}
+
+// MergeDuplicates fusionne des doublons dans la transaction conservée : les doublons sont supprimés (et leurs
+// effets sur les soldes annulés), la transaction conservée récupère la catégorie et la référence externe qui
+// lui manquent. Le tout est fait dans une seule transaction SQL.
+func (s *TransactionService) MergeDuplicates(ctx context.Context, userID uuid.UUID, req entity.MergeDuplicatesRequest) (*entity.MergeDuplicatesResponse, error) {
+	if len(req.DuplicateIDs) == 0 {
+		return nil, fmt.Errorf("%w: aucun doublon fourni", entity.ErrInvalidDuplicateMerge)
+	}
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		keep, err := s.transactionRepo.GetByIDForUpdate(ctx, req.KeepID)
+		if err != nil || keep.UserID != userID {
+			return fmt.Errorf("%w: transaction à conserver non trouvée", entity.ErrInvalidDuplicateMerge)
+		}
+		if keep.TransferGroupID != nil {
+			return fmt.Errorf("%w: un transfert ne peut pas être fusionné", entity.ErrInvalidDuplicateMerge)
+		}
+		before := *keep
+		if err := s.loadTags(ctx, &before); err != nil {
+			return err
+		}
+
+		for _, duplicateID := range req.DuplicateIDs {
+			if duplicateID == keep.ID {
+				return fmt.Errorf("%w: la transaction conservée ne peut pas être un doublon", entity.ErrInvalidDuplicateMerge)
+			}
+			duplicate, err := s.transactionRepo.GetByIDForUpdate(ctx, duplicateID)
+			if err != nil || duplicate.UserID != userID {
+				return fmt.Errorf("%w: doublon %s non trouvé", entity.ErrInvalidDuplicateMerge, duplicateID)
+			}
+			if duplicate.TransferGroupID != nil || !sameAccount(duplicate.AccountID, keep.AccountID) ||
+				duplicate.Type != keep.Type || duplicate.Amount != keep.Amount {
+				return fmt.Errorf("%w: la transaction %s n'est pas un doublon de %s", entity.ErrInvalidDuplicateMerge, duplicateID, keep.ID)
+			}
+
+			if keep.CategoryID == nil && duplicate.CategoryID != nil {
+				keep.CategoryID = duplicate.CategoryID
+			}
+			if keep.ExternalRef == nil && duplicate.ExternalRef != nil {
+				keep.ExternalRef = duplicate.ExternalRef
+			}
+			if err := s.copyTags(ctx, duplicate.ID, keep.ID); err != nil {
+				return err
+			}
+
+			if err := s.DeleteTransaction(ctx, userID, duplicate.ID); err != nil {
+				return err
+			}
+		}
+
+		keep.UpdatedAt = time.Now()
+		if err := s.transactionRepo.Update(ctx, keep); err != nil {
+			return err
+		}
+		return s.recordVersion(ctx, versionActionUpdate, &before, keep)
+	})
+	if err != nil {
+		s.logger.Error("Erreur fusion des doublons", logger.Error(err))
+		return nil, err
+	}
+
+	transaction, err := s.transactionRepo.GetByID(ctx, req.KeepID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération transaction fusionnée: %w", err)
+	}
+
+	s.logger.Info("Doublons fusionnés",
+		logger.String("transaction_id", req.KeepID.String()),
+		logger.String("user_id", userID.String()),
+		logger.Int("merged", len(req.DuplicateIDs)),
+	)
+
+	return &entity.MergeDuplicatesResponse{Transaction: transaction, MergedCount: len(req.DuplicateIDs)}, nil
+}
+
+// BulkTransactions applique une même opération (recatégorisation, étiquettes, changement de compte ou suppression)
+// à des transactions sélectionnées par IDs ou par filtre. Chaque transaction passe par la mise à jour ou la
+// suppression unitaire, qui ajustent les soldes ; elle a son propre résultat. En mode tout ou rien, l'opération
+// s'exécute dans une seule transaction SQL annulée au premier échec (erreur ErrBulkOperationRolledBack).
+func (s *TransactionService) BulkTransactions(ctx context.Context, userID uuid.UUID, req entity.BulkTransactionRequest) (*entity.BulkTransactionResponse, error) {
+	ctx = withChangeSource(ctx, changeSourceBulk)
+	tagIDs, err := s.checkBulkAction(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	ids, transactions, err := s.selectBulkTransactions(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entity.BulkTransactionResponse{
+		Action:       req.Action,
+		AllOrNothing: req.AllOrNothing,
+		Total:        len(ids),
+		Results:      make([]*entity.BulkTransactionResult, 0, len(ids)),
+	}
+	results := make(map[uuid.UUID]*entity.BulkTransactionResult, len(ids))
+	for _, id := range ids {
+		item := &entity.BulkTransactionResult{TransactionID: id}
+		if _, ok := transactions[id]; !ok {
+			item.Status, item.Error = bulkStatusFailed, entity.ErrTransactionNotFound.Error()
+		}
+		result.Results = append(result.Results, item)
+		results[id] = item
+	}
+
+	// Les remboursements sont supprimés avant leur dépense d'origine, qui ne peut pas l'être tant qu'ils existent
+	ordered := make([]*entity.Transaction, 0, len(transactions))
+	for _, id := range ids {
+		if transaction, ok := transactions[id]; ok {
+			ordered = append(ordered, transaction)
+		}
+	}
+	if req.Action == "delete" {
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return ordered[i].RefundOfID != nil && ordered[j].RefundOfID == nil
+		})
+	}
+
+	// apply traite les transactions dans l'ordre et s'arrête au premier échec si stopOnError.
+	// Un transfert est recatégorisé, étiqueté ou supprimé sur ses deux jambes : la seconde jambe sélectionnée
+	// reprend le résultat de la première ; elle n'est pas déplacée sur le même compte que la première.
+	apply := func(ctx context.Context, stopOnError bool) error {
+		handledGroups := make(map[uuid.UUID]*entity.BulkTransactionResult)
+		for _, transaction := range ordered {
+			item := results[transaction.ID]
+			if transaction.TransferGroupID != nil {
+				if first, ok := handledGroups[*transaction.TransferGroupID]; ok {
+					if req.Action == "move" {
+						item.Status, item.Error = bulkStatusSkipped, "l'autre jambe du transfert a déjà été déplacée"
+					} else {
+						item.Status, item.Error = first.Status, first.Error
+					}
+					continue
+				}
+				handledGroups[*transaction.TransferGroupID] = item
+			}
+
+			if err := s.applyBulkAction(ctx, userID, req, tagIDs, transaction); err != nil {
+				item.Status, item.Error = bulkStatusFailed, err.Error()
+				if stopOnError {
+					return err
+				}
+				continue
+			}
+			item.Status = bulkStatusSucceeded
+		}
+		return nil
+	}
+
+	if req.AllOrNothing {
+		for _, item := range result.Results {
+			if item.Status == bulkStatusFailed {
+				err = fmt.Errorf("%w: transaction %s non trouvée", entity.ErrBulkOperationRolledBack, item.TransactionID)
+			}
+		}
+		if err == nil {
+			if err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error { return apply(ctx, true) }); err != nil {
+				err = fmt.Errorf("%w: %v", entity.ErrBulkOperationRolledBack, err)
+			}
+		}
+		if err != nil {
+			for _, item := range result.Results {
+				switch item.Status {
+				case bulkStatusSucceeded:
+					item.Status = bulkStatusRolledBack
+				case "":
+					item.Status = bulkStatusSkipped
+				}
+			}
+		}
+	} else {
+		err = apply(ctx, false)
+	}
+
+	for _, item := range result.Results {
+		switch item.Status {
+		case bulkStatusSucceeded:
+			result.Succeeded++
+		case bulkStatusFailed:
+			result.Failed++
+		default:
+			result.Skipped++
+		}
+	}
+
+	s.logger.Info("Opération en masse sur les transactions",
+		logger.String("user_id", userID.String()),
+		logger.String("action", req.Action),
+		logger.Bool("all_or_nothing", req.AllOrNothing),
+		logger.Int("total", result.Total),
+		logger.Int("succeeded", result.Succeeded),
+		logger.Int("failed", result.Failed),
+	)
+
+	return result, err
+}
+
+// checkBulkAction valide l'action d'une opération en masse et ses paramètres ; retourne les étiquettes à poser
+func (s *TransactionService) checkBulkAction(ctx context.Context, userID uuid.UUID, req entity.BulkTransactionRequest) ([]uuid.UUID, error) {
+	switch req.Action {
+	case "recategorize":
+		if req.CategoryID == nil {
+			return nil, fmt.Errorf("%w: la catégorie est requise", entity.ErrInvalidBulkOperation)
+		}
+		if _, err := s.categoryRepo.GetByID(ctx, userID, *req.CategoryID); err != nil {
+			return nil, fmt.Errorf("%w: catégorie non trouvée", entity.ErrInvalidBulkOperation)
+		}
+	case "retag", "tag", "untag":
+		if req.TagIDs == nil || (req.Action != "retag" && len(req.TagIDs) == 0) {
+			return nil, fmt.Errorf("%w: les étiquettes sont requises", entity.ErrInvalidBulkOperation)
+		}
+		if len(req.TagIDs) > maxBulkTags {
+			return nil, fmt.Errorf("%w: %d étiquettes maximum", entity.ErrInvalidBulkOperation, maxBulkTags)
+		}
+		tags, err := s.resolveTags(ctx, userID, req.TagIDs)
+		if err != nil {
+			return nil, err
+		}
+		tagIDs := make([]uuid.UUID, 0, len(tags))
+		for _, tag := range tags {
+			tagIDs = append(tagIDs, tag.ID)
+		}
+		return tagIDs, nil
+	case "move":
+		if req.AccountID == nil {
+			return nil, fmt.Errorf("%w: le compte est requis", entity.ErrInvalidBulkOperation)
+		}
+		account, err := s.accountRepo.GetByID(ctx, *req.AccountID)
+		if err != nil || account.UserID != userID {
+			return nil, fmt.Errorf("%w: compte non trouvé", entity.ErrInvalidBulkOperation)
+		}
+		if account.ArchivedAt != nil {
+			return nil, fmt.Errorf("%w: %s", entity.ErrAccountArchived, account.Name)
+		}
+	case "delete":
+	default:
+		return nil, fmt.Errorf("%w: action non supportée: %s", entity.ErrInvalidBulkOperation, req.Action)
+	}
+	return nil, nil
+}
+
+// selectBulkTransactions retourne les IDs sélectionnés, dans l'ordre, et les transactions correspondantes
+// de l'utilisateur ; un ID inconnu est absent de la table des transactions
+func (s *TransactionService) selectBulkTransactions(ctx context.Context, userID uuid.UUID, req entity.BulkTransactionRequest) ([]uuid.UUID, map[uuid.UUID]*entity.Transaction, error) {
+	var transactions []*entity.Transaction
+	var ids []uuid.UUID
+
+	switch {
+	case len(req.TransactionIDs) > 0:
+		ids = uniqueIDs(req.TransactionIDs)
+		if len(ids) > maxBulkTransactions {
+			return nil, nil, fmt.Errorf("%w: %d transactions maximum", entity.ErrInvalidBulkOperation, maxBulkTransactions)
+		}
+		var err error
+		if transactions, err = s.transactionRepo.GetByIDs(ctx, userID, ids); err != nil {
+			return nil, nil, err
+		}
+	case req.Filter != nil:
+		filter, err := buildTransactionFilter(TransactionQuery{
+			Type:       req.Filter.Type,
+			CategoryID: req.Filter.CategoryID,
+			AccountID:  req.Filter.AccountID,
+			TagIDs:     req.Filter.TagIDs,
+			TagMatch:   req.Filter.TagMatch,
+			StartDate:  req.Filter.StartDate,
+			EndDate:    req.Filter.EndDate,
+			MinAmount:  req.Filter.MinAmount,
+			MaxAmount:  req.Filter.MaxAmount,
+			Search:     req.Filter.Search,
+			SortOrder:  "asc",
+			Limit:      maxBulkTransactions + 1,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", entity.ErrInvalidBulkOperation, err)
+		}
+		if transactions, err = s.transactionRepo.List(ctx, userID, filter); err != nil {
+			return nil, nil, err
+		}
+		if len(transactions) > maxBulkTransactions {
+			return nil, nil, fmt.Errorf("%w: le filtre sélectionne plus de %d transactions", entity.ErrInvalidBulkOperation, maxBulkTransactions)
+		}
+		for _, transaction := range transactions {
+			ids = append(ids, transaction.ID)
+		}
+	default:
+		return nil, nil, fmt.Errorf("%w: sélectionner les transactions par IDs ou par filtre", entity.ErrInvalidBulkOperation)
+	}
+
+	byID := make(map[uuid.UUID]*entity.Transaction, len(transactions))
+	for _, transaction := range transactions {
+		byID[transaction.ID] = transaction
+	}
+	return ids, byID, nil
+}
+
+// applyBulkAction applique l'action d'une opération en masse à une transaction
+func (s *TransactionService) applyBulkAction(ctx context.Context, userID uuid.UUID, req entity.BulkTransactionRequest, tagIDs []uuid.UUID, transaction *entity.Transaction) error {
+	// Les étiquettes sont posées directement : le verrou d'une transaction rapprochée est vérifié ici pour toutes les actions
+	if transaction.Status == transactionStatusReconciled {
+		return fmt.Errorf("%w: transaction %s", entity.ErrTransactionReconciled, transaction.ID)
+	}
+	var err error
+	switch req.Action {
+	case "recategorize":
+		if len(transaction.Splits) > 0 {
+			return fmt.Errorf("%w: transaction ventilée, modifier sa ventilation", entity.ErrInvalidBulkOperation)
+		}
+		_, err = s.UpdateTransaction(ctx, userID, transaction.ID, entity.UpdateTransactionRequest{CategoryID: req.CategoryID})
+	case "retag":
+		_, err = s.UpdateTransaction(ctx, userID, transaction.ID, entity.UpdateTransactionRequest{TagIDs: tagIDs})
+	case "tag", "untag":
+		err = s.changeTags(ctx, transaction.ID, tagIDs, req.Action == "tag")
+	case "move":
+		_, err = s.UpdateTransaction(ctx, userID, transaction.ID, entity.UpdateTransactionRequest{AccountID: req.AccountID})
+	case "delete":
+		err = s.DeleteTransaction(ctx, userID, transaction.ID)
+	}
+	return err
+}
+
+// changeTags ajoute (add) ou retire des étiquettes d'une transaction, ou des deux jambes d'un transfert, et
+// enregistre la nouvelle version de chacune
+func (s *TransactionService) changeTags(ctx context.Context, transactionID uuid.UUID, tagIDs []uuid.UUID, add bool) error {
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		transaction, err := s.transactionRepo.GetByIDForUpdate(ctx, transactionID)
+		if err != nil {
+			return err
+		}
+		legs := []*entity.Transaction{transaction}
+		if transaction.TransferGroupID != nil {
+			if legs, err = s.transactionRepo.GetByTransferGroupIDForUpdate(ctx, *transaction.TransferGroupID); err != nil {
+				return err
+			}
+		}
+
+		for _, leg := range legs {
+			before := *leg
+			if before.Splits, err = s.transactionRepo.GetSplits(ctx, leg.ID); err != nil {
+				return err
+			}
+			if err := s.loadTags(ctx, &before); err != nil {
+				return err
+			}
+
+			if add {
+				_, err = s.tagRepo.AddToTransactions(ctx, []uuid.UUID{leg.ID}, tagIDs)
+			} else {
+				_, err = s.tagRepo.RemoveFromTransactions(ctx, []uuid.UUID{leg.ID}, tagIDs)
+			}
+			if err != nil {
+				return err
+			}
+			if err := s.recordVersion(ctx, versionActionUpdate, &before, leg); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// checkDuplicate retourne une DuplicateTransactionError si des transactions similaires existent déjà
+func (s *TransactionService) checkDuplicate(ctx context.Context, transaction *entity.Transaction) error {
+	window := time.Duration(duplicateWindowDays) * 24 * time.Hour
+	candidates, err := s.transactionRepo.GetSimilar(ctx, *transaction.AccountID, transaction.Type, transaction.Amount,
+		transaction.Date.Add(-window), transaction.Date.Add(window))
+	if err != nil {
+		return err
+	}
+
+	description := normalizeDescription(transaction.Description)
+	var matches []*entity.Transaction
+	for _, candidate := range candidates {
+		if normalizeDescription(candidate.Description) == description {
+			matches = append(matches, candidate)
+		}
+	}
+	if len(matches) > 0 {
+		return &entity.DuplicateTransactionError{Matches: matches}
+	}
+	return nil
+}
+
+// duplicateFingerprint calcule l'empreinte d'une transaction (compte, type, montant, libellé normalisé)
+func duplicateFingerprint(transaction *entity.Transaction) string {
+	accountID := ""
+	if transaction.AccountID != nil {
+		accountID = transaction.AccountID.String()
+	}
+	key := fmt.Sprintf("%s|%s|%d|%s", accountID, transaction.Type, transaction.Amount.Minor, normalizeDescription(transaction.Description))
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+// descriptionAccents remplace les lettres accentuées courantes par leur lettre de base
+var descriptionAccents = strings.NewReplacer(
+	"à", "a", "â", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"î", "i", "ï", "i",
+	"ô", "o", "ö", "o",
+	"ù", "u", "û", "u", "ü", "u",
+	"ç", "c",
+)
+
+// normalizeDescription ramène un libellé à sa forme comparable : minuscules sans accents, lettres et chiffres
+// uniquement, espaces simples
+func normalizeDescription(description string) string {
+	description = descriptionAccents.Replace(strings.ToLower(description))
+	return strings.Join(strings.FieldsFunc(description, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// sameAccount indique si deux références de compte désignent le même compte
+func sameAccount(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// maxSMSPerRequest borne le nombre de SMS analysés en une requête
+const maxSMSPerRequest = 50
+
+// mobileMoneyOperators associe chaque opérateur à son libellé et aux mots du nom de compte qui le désignent
+var mobileMoneyOperators = map[string]struct {
+	label    string
+	keywords []string
+}{
+	mobilemoney.OperatorMTNMoMo:     {label: "MTN MoMo", keywords: []string{"MOMO", "MTN"}},
+	mobilemoney.OperatorOrangeMoney: {label: "Orange Money", keywords: []string{"OM", "ORANGE"}},
+}
+
+// smsKindLabels sont les libellés de transaction par nature d'opération mobile money
+var smsKindLabels = map[string]string{
+	mobilemoney.KindReceived:   "Transfert reçu",
+	mobilemoney.KindSent:       "Transfert envoyé",
+	mobilemoney.KindPayment:    "Paiement",
+	mobilemoney.KindWithdrawal: "Retrait",
+	mobilemoney.KindDeposit:    "Dépôt",
+}
+
+// ParseMobileMoneySMS transforme des SMS de confirmation MTN MoMo / Orange Money en brouillons de transactions,
+// rattachés au compte mobile money de l'opérateur. La référence de l'opérateur sert de référence externe :
+// un SMS déjà enregistré est signalé comme doublon. Si req.Confirm est vrai, les transactions (et leurs frais)
+// sont créées.
+func (s *TransactionService) ParseMobileMoneySMS(ctx context.Context, userID uuid.UUID, req entity.ParseSMSRequest) (*entity.ParseSMSResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("%w: aucun SMS fourni", entity.ErrInvalidSMSData)
+	}
+	if len(req.Messages) > maxSMSPerRequest {
+		return nil, fmt.Errorf("%w: %d SMS maximum par requête", entity.ErrInvalidSMSData, maxSMSPerRequest)
+	}
+
+	accounts, err := s.accountRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération comptes: %w", err)
+	}
+	var forcedAccount *entity.Account
+	if req.AccountID != nil {
+		for _, account := range accounts {
+			if account.ID == *req.AccountID {
+				forcedAccount = account
+				break
+			}
+		}
+		if forcedAccount == nil {
+			return nil, fmt.Errorf("%w: compte non trouvé", entity.ErrInvalidSMSData)
+		}
+	}
+
+	result := &entity.ParseSMSResponse{Drafts: make([]*entity.SMSDraft, 0, len(req.Messages))}
+	seen := make(map[string]bool)
+	for i, message := range req.Messages {
+		draft := &entity.SMSDraft{Message: i}
+		result.Drafts = append(result.Drafts, draft)
+
+		parsed, ok := mobilemoney.Parse(message)
+		if !ok {
+			draft.Status = "unrecognized"
+			result.UnrecognizedCount++
+			continue
+		}
+
+		draft.Operator = parsed.Operator
+		draft.Kind = parsed.Kind
+		draft.Type = parsed.Type
+		draft.Counterparty = parsed.Counterparty
+		draft.Reference = parsed.Reference
+		draft.Date = parsed.Date
+		draft.Description = smsDescription(parsed)
+
+		account := forcedAccount
+		if account == nil {
+			account = mobileMoneyAccount(accounts, parsed.Operator)
+		}
+		currency := entity.DefaultCurrency
+		if account != nil {
+			currency = account.Currency
+		}
+		if err := setSMSAmounts(draft, parsed, currency); err != nil {
+			draft.Status = "invalid"
+			draft.Error = err.Error()
+			continue
+		}
+		if account == nil {
+			draft.Status = "invalid"
+			draft.Error = "aucun compte mobile money ne correspond à l'opérateur"
+			continue
+		}
+		draft.AccountID = &account.ID
+		draft.AccountName = account.Name
+
+		if parsed.Reference == "" {
+			draft.Status = "invalid"
+			draft.Error = "référence de transaction introuvable dans le SMS"
+			continue
+		}
+
+		externalRef := "sms:" + parsed.Operator + ":" + parsed.Reference
+		existing, err := s.transactionRepo.GetExistingExternalRefs(ctx, account.ID, []string{externalRef})
+		if err != nil {
+			return nil, err
+		}
+		if len(existing) > 0 || seen[account.ID.String()+externalRef] {
+			draft.Status = "duplicate"
+			result.DuplicateCount++
+			continue
+		}
+		seen[account.ID.String()+externalRef] = true
+
+		draft.Status = "draft"
+		if !req.Confirm {
+			continue
+		}
+
+		if err := s.createSMSTransactions(ctx, userID, account.ID, draft, externalRef); err != nil {
+			if errors.Is(err, entity.ErrDuplicateExternalRef) {
+				draft.Status = "duplicate"
+				result.DuplicateCount++
+				continue
+			}
+			s.logger.Warn("Erreur création transaction depuis un SMS", logger.Int("message", i), logger.Error(err))
+			draft.Status = "failed"
+			draft.Error = err.Error()
+			continue
+		}
+		draft.Status = "created"
+		result.CreatedCount++
+	}
+
+	return result, nil
+}
+
+// createSMSTransactions crée la transaction d'un brouillon SMS et, s'il y a des frais, la dépense de frais
+// associée, dans une seule transaction SQL
+func (s *TransactionService) createSMSTransactions(ctx context.Context, userID, accountID uuid.UUID, draft *entity.SMSDraft, externalRef string) error {
+	date := time.Now()
+	if draft.Date != nil {
+		date = *draft.Date
+	}
+
+	return s.txManager.WithinTransaction(withChangeSource(ctx, changeSourceSMS), func(ctx context.Context) error {
+		transaction, err := s.CreateTransaction(ctx, userID, entity.CreateTransactionRequest{
+			AccountID:   &accountID,
+			Type:        draft.Type,
+			Amount:      entity.DecimalOf(draft.Amount),
+			Description: draft.Description,
+			Date:        date,
+			ExternalRef: &externalRef,
+		})
+		if err != nil {
+			return err
+		}
+		draft.TransactionID = &transaction.ID
+
+		if !draft.Fees.IsPositive() {
+			return nil
+		}
+		feeRef := externalRef + ":frais"
+		fee, err := s.CreateTransaction(ctx, userID, entity.CreateTransactionRequest{
+			AccountID:   &accountID,
+			Type:        "expense",
+			Amount:      entity.DecimalOf(draft.Fees),
+			Description: truncateDescription("Frais " + mobileMoneyOperators[draft.Operator].label + " - " + draft.Description),
+			Date:        date,
+			ExternalRef: &feeRef,
+		})
+		if err != nil {
+			return err
+		}
+		draft.FeeTransactionID = &fee.ID
+		return nil
+	})
+}
+
+// setSMSAmounts convertit les montants annoncés par le SMS dans la devise du compte ; un montant plus précis
+// que la devise n'en admet est refusé
+func setSMSAmounts(draft *entity.SMSDraft, parsed *mobilemoney.Draft, currency string) error {
+	amount, err := parsed.Amount.Money(currency)
+	if err != nil {
+		return err
+	}
+	draft.Amount = amount
+	draft.Fees = entity.NewMoney(0, currency)
+	if parsed.Fees != "" {
+		if draft.Fees, err = parsed.Fees.Money(currency); err != nil {
+			return err
+		}
+	}
+	if parsed.BalanceAfter != nil {
+		balance, err := parsed.BalanceAfter.Money(currency)
+		if err != nil {
+			return err
+		}
+		draft.BalanceAfter = &balance
+	}
+	return nil
+}
+
+// mobileMoneyAccount retourne le compte mobile money de l'utilisateur correspondant à l'opérateur
+// (comptes "MOMO" et "OM" créés par défaut, ou tout compte dont le nom cite l'opérateur)
+func mobileMoneyAccount(accounts []*entity.Account, operator string) *entity.Account {
+	keywords := mobileMoneyOperators[operator].keywords
+	for _, account := range accounts {
+		if account.Type != "mobile_money" || account.ArchivedAt != nil {
+			continue
+		}
+		words := strings.FieldsFunc(strings.ToUpper(account.Name), func(r rune) bool {
+			return !('A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+		})
+		for _, word := range words {
+			for _, keyword := range keywords {
+				if word == keyword {
+					return account
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// smsDescription construit le libellé d'une transaction extraite d'un SMS
+func smsDescription(draft *mobilemoney.Draft) string {
+	description := smsKindLabels[draft.Kind] + " " + mobileMoneyOperators[draft.Operator].label
+	if draft.Counterparty != "" {
+		description += " - " + draft.Counterparty
+	}
+	return truncateDescription(description)
+}
+
+// GetTransactionStats calcule les statistiques des transactions d'une période par agrégats SQL : totaux,
+// série temporelle, répartition par catégorie racine et par compte, et comparaison avec la période précédente.
+// Les montants sont exprimés dans la devise de référence de l'utilisateur, au taux de change de chaque transaction.
+func (s *TransactionService) GetTransactionStats(ctx context.Context, userID uuid.UUID, query TransactionStatsQuery) (*entity.TransactionStatsResponse, error) {
+	period, current, previous, err := statsRanges(query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	current.AccountID, previous.AccountID = query.AccountID, query.AccountID
+	current.IncludePending, previous.IncludePending = query.IncludePending, query.IncludePending
+
+	granularity, err := statsGranularity(query.Granularity, period, current)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.transactionRepo.GetStatsTotals(ctx, userID, current)
+	if err != nil {
+		s.logger.Error("Erreur calcul statistiques transactions", logger.Error(err))
+		return nil, fmt.Errorf("erreur récupération statistiques: %w", err)
+	}
+	series, err := s.transactionRepo.GetStatsSeries(ctx, userID, current, granularity)
+	if err != nil {
+		s.logger.Error("Erreur calcul série des transactions", logger.Error(err))
+		return nil, fmt.Errorf("erreur récupération statistiques: %w", err)
+	}
+	lines, err := s.transactionRepo.GetStatsByCategory(ctx, userID, current)
+	if err != nil {
+		s.logger.Error("Erreur calcul statistiques par catégorie", logger.Error(err))
+		return nil, fmt.Errorf("erreur récupération statistiques: %w", err)
+	}
+	accounts, err := s.transactionRepo.GetStatsByAccount(ctx, userID, current)
+	if err != nil {
+		s.logger.Error("Erreur calcul statistiques par compte", logger.Error(err))
+		return nil, fmt.Errorf("erreur récupération statistiques: %w", err)
+	}
+	tagStats, err := s.transactionRepo.GetStatsByTag(ctx, userID, current)
+	if err != nil {
+		s.logger.Error("Erreur calcul statistiques par étiquette", logger.Error(err))
+		return nil, fmt.Errorf("erreur récupération statistiques: %w", err)
+	}
+	previousTotals, err := s.transactionRepo.GetStatsTotals(ctx, userID, previous)
+	if err != nil {
+		s.logger.Error("Erreur calcul statistiques de la période précédente", logger.Error(err))
+		return nil, fmt.Errorf("erreur récupération statistiques: %w", err)
+	}
+	// Les montants sont convertis dans la devise de référence ; les devises sans taux sont signalées
+	baseCurrency, err := s.exchangeRates.GetBaseCurrency(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération statistiques: %w", err)
+	}
+	missingRates, err := s.transactionRepo.GetStatsMissingRates(ctx, userID, current)
+	if err != nil {
+		s.logger.Error("Erreur recherche des taux de change manquants", logger.Error(err))
+		return nil, fmt.Errorf("erreur récupération statistiques: %w", err)
+	}
+	statsInCurrency(baseCurrency, &totals.Income, &totals.Expense, &totals.Net)
+	statsInCurrency(baseCurrency, &previousTotals.Income, &previousTotals.Expense, &previousTotals.Net)
+	for _, bucket := range series {
+		statsInCurrency(baseCurrency, &bucket.Income, &bucket.Expense, &bucket.Net)
+	}
+	for _, line := range lines {
+		line.Amount.Currency = baseCurrency
+	}
+	for _, account := range accounts {
+		statsInCurrency(baseCurrency, &account.Income, &account.Expense, &account.Net)
+	}
+	for _, tag := range tagStats {
+		statsInCurrency(baseCurrency, &tag.Income, &tag.Expense, &tag.Net)
+	}
+
+	return &entity.TransactionStatsResponse{
+		Period:         period,
+		Granularity:    granularity,
+		StartDate:      current.StartDate,
+		EndDate:        current.EndDate,
+		AccountID:      query.AccountID,
+		IncludePending: query.IncludePending,
+		Currency:       baseCurrency,
+		MissingRates:   missingRates,
+		Totals:         *totals,
+		Series:         series,
+		ByCategory:     buildCategoryStats(lines, totals),
+		ByAccount:      accounts,
+		ByTag:          tagStats,
+		Previous: &entity.TransactionStatsComparison{
+			StartDate:     previous.StartDate,
+			EndDate:       previous.EndDate,
+			Totals:        *previousTotals,
+			IncomeChange:  percentChange(totals.Income, previousTotals.Income),
+			ExpenseChange: percentChange(totals.Expense, previousTotals.Expense),
+			NetChange:     totals.Net.Sub(previousTotals.Net),
+		},
+	}, nil
+}
+
+// statsInCurrency rattache la devise de référence aux revenus, dépenses et solde net lus en unités mineures
+func statsInCurrency(currency string, income, expense, net *entity.Money) {
+	income.Currency, expense.Currency, net.Currency = currency, currency, currency
+}
+
+// statsRanges détermine la période courante et la période précédente à comparer.
+// Avec des dates explicites, la période précédente a la même durée et se termine la veille du début ;
+// sinon la période est la semaine (du lundi), le mois ou l'année civile en cours, comparée à la précédente.
+func statsRanges(query TransactionStatsQuery, now time.Time) (string, *entity.TransactionStatsFilter, *entity.TransactionStatsFilter, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	if query.StartDate != "" || query.EndDate != "" {
+		if query.StartDate == "" {
+			return "", nil, nil, fmt.Errorf("%w: date de début requise avec une date de fin", entity.ErrInvalidTransactionQuery)
+		}
+		startDate, err := time.Parse("2006-01-02", query.StartDate)
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("%w: date de début invalide", entity.ErrInvalidTransactionQuery)
+		}
+		endDate := today
+		if query.EndDate != "" {
+			if endDate, err = time.Parse("2006-01-02", query.EndDate); err != nil {
+				return "", nil, nil, fmt.Errorf("%w: date de fin invalide", entity.ErrInvalidTransactionQuery)
+			}
+		}
+		if endDate.Before(startDate) {
+			return "", nil, nil, fmt.Errorf("%w: la date de fin précède la date de début", entity.ErrInvalidTransactionQuery)
+		}
+
+		days := int(endDate.Sub(startDate).Hours()/24) + 1
+		previousEnd := startDate.AddDate(0, 0, -1)
+		return "custom",
+			&entity.TransactionStatsFilter{StartDate: startDate, EndDate: endDate},
+			&entity.TransactionStatsFilter{StartDate: previousEnd.AddDate(0, 0, 1-days), EndDate: previousEnd},
+			nil
+	}
+
+	var startDate, previousStart time.Time
+	period := query.Period
+	switch period {
+	case "", "month":
+		period = "month"
+		startDate = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+		previousStart = startDate.AddDate(0, -1, 0)
+	case "week":
+		startDate = today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		previousStart = startDate.AddDate(0, 0, -7)
+	case "year":
+		startDate = time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+		previousStart = startDate.AddDate(-1, 0, 0)
+	default:
+		return "", nil, nil, fmt.Errorf("%w: période non supportée: %s", entity.ErrInvalidTransactionQuery, query.Period)
+	}
+
+	var endDate time.Time
+	switch period {
+	case "week":
+		endDate = startDate.AddDate(0, 0, 6)
+	case "month":
+		endDate = startDate.AddDate(0, 1, -1)
+	case "year":
+		endDate = startDate.AddDate(1, 0, -1)
+	}
+
+	return period,
+		&entity.TransactionStatsFilter{StartDate: startDate, EndDate: endDate},
+		&entity.TransactionStatsFilter{StartDate: previousStart, EndDate: startDate.AddDate(0, 0, -1)},
+		nil
+}
+
+// statsGranularity valide la granularité de la série temporelle ou la déduit de la période :
+// par jour pour une semaine ou un mois, par mois pour une année, selon la durée pour des dates explicites
+func statsGranularity(granularity, period string, current *entity.TransactionStatsFilter) (string, error) {
+	days := int(current.EndDate.Sub(current.StartDate).Hours()/24) + 1
+
+	if granularity == "" {
+		switch {
+		case period == "year":
+			granularity = "month"
+		case days <= 62:
+			granularity = "day"
+		case days <= 366:
+			granularity = "week"
+		default:
+			granularity = "month"
+		}
+	}
+
+	var buckets int
+	switch granularity {
+	case "day":
+		buckets = days
+	case "week":
+		buckets = days/7 + 2
+	case "month":
+		buckets = (current.EndDate.Year()-current.StartDate.Year())*12 + int(current.EndDate.Month()-current.StartDate.Month()) + 1
+	default:
+		return "", fmt.Errorf("%w: granularité non supportée: %s", entity.ErrInvalidTransactionQuery, granularity)
+	}
+	if buckets > maxStatsBuckets {
+		return "", fmt.Errorf("%w: période trop longue pour une série par %s", entity.ErrInvalidTransactionQuery, granularity)
+	}
+	return granularity, nil
+}
+
+// buildCategoryStats regroupe les totaux par catégorie sous leur catégorie racine, les sous-catégories
+// détaillant la part qui leur est directement imputée, et calcule la part de chacune dans le total de son type
+func buildCategoryStats(lines []*entity.CategoryStatsLine, totals *entity.TransactionStatsTotals) []*entity.CategoryStats {
+	stats := make([]*entity.CategoryStats, 0)
+	roots := make(map[string]*entity.CategoryStats)
+	for _, line := range lines {
+		key := line.Type
+		if line.RootID != nil {
+			key += ":" + line.RootID.String()
+		}
+		root, ok := roots[key]
+		if !ok {
+			root = &entity.CategoryStats{
+				CategoryID: line.RootID,
+				Name:       line.RootName,
+				Icon:       line.RootIcon,
+				Color:      line.RootColor,
+				Type:       line.Type,
+				Amount:     entity.NewMoney(0, line.Amount.Currency),
+			}
+			if line.RootID == nil {
+				root.Name = "Sans catégorie"
+			}
+			roots[key] = root
+			stats = append(stats, root)
+		}
+
+		root.Amount = root.Amount.Add(line.Amount)
+		root.Count += line.Count
+		if line.CategoryID != nil && line.RootID != nil && *line.CategoryID != *line.RootID {
+			root.Subcategories = append(root.Subcategories, &entity.CategoryStats{
+				CategoryID: line.CategoryID,
+				Name:       line.CategoryName,
+				Type:       line.Type,
+				Amount:     line.Amount,
+				Count:      line.Count,
+			})
+		}
+	}
+
+	for _, root := range stats {
+		total := totals.Expense
+		if root.Type == "income" {
+			total = totals.Income
+		}
+		root.Share = sharePercent(root.Amount, total)
+		for _, subcategory := range root.Subcategories {
+			subcategory.Share = sharePercent(subcategory.Amount, total)
+		}
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		if stats[i].Type != stats[j].Type {
+			return stats[i].Type == "expense"
+		}
+		return stats[i].Amount.Cmp(stats[j].Amount) > 0
+	})
+	return stats
+}
+
+// percentChange retourne la variation en pourcentage entre deux montants de même devise, nil si le montant de
+// référence est nul
+func percentChange(current, previous entity.Money) *float64 {
+	if previous.IsZero() {
+		return nil
+	}
+	change := math.Round(float64(current.Minor-previous.Minor)/float64(previous.Minor)*10000) / 100
+	return &change
+}
+
+// sharePercent retourne la part en pourcentage d'un montant dans un total de même devise
+func sharePercent(amount, total entity.Money) float64 {
+	if total.IsZero() {
+		return 0
+	}
+	return math.Round(float64(amount.Minor)/float64(total.Minor)*10000) / 100
+}
+
+// ExportTransactions écrit dans w les transactions filtrées, par ordre chronologique, au format csv, xlsx
+// ou ndjson. Les transactions sont lues et écrites au fil de l'eau, sans être chargées en mémoire ; les
+// paramètres sont validés avant toute écriture. Les paramètres de tri et de pagination sont ignorés.
+func (s *TransactionService) ExportTransactions(ctx context.Context, userID uuid.UUID, format string, query TransactionQuery, w io.Writer) error {
+	query.SortBy, query.SortOrder, query.Cursor = "", "", ""
+	filter, err := buildTransactionFilter(query)
+	if err != nil {
+		return err
+	}
+	writer, err := export.NewWriter(format, w)
+	if err != nil {
+		return fmt.Errorf("%w: %v", entity.ErrInvalidTransactionQuery, err)
+	}
+
+	categories, err := s.categoryRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("erreur récupération catégories: %w", err)
+	}
+	paths := categoryPaths(categories)
+
+	count := 0
+	err = s.transactionRepo.StreamForExport(ctx, userID, filter, func(line *entity.TransactionExportRow) error {
+		row := &export.Row{
+			Date:        line.Date,
+			Type:        line.Type,
+			Description: line.Description,
+			Amount:      json.Number(line.Amount.Decimal()),
+			Currency:    line.Currency,
+			Account:     line.AccountName,
+			Tags:        line.Tags,
+			ID:          line.ID.String(),
+		}
+		if line.CategoryID != nil {
+			row.Category = paths[*line.CategoryID]
+		}
+		if line.ExternalRef != nil {
+			row.Reference = *line.ExternalRef
+		}
+		splits := make([]string, 0, len(line.SplitCategoryIDs))
+		for i, categoryID := range line.SplitCategoryIDs {
+			if i >= len(line.SplitAmounts) {
+				break
+			}
+			id, _ := uuid.Parse(categoryID)
+			splits = append(splits, fmt.Sprintf("%s: %s", paths[id], entity.NewMoney(line.SplitAmounts[i], line.Currency).Decimal()))
+		}
+		row.Splits = strings.Join(splits, "; ")
+
+		count++
+		return writer.Write(row)
+	})
+	if err != nil {
+		s.logger.Error("Erreur export des transactions", logger.Error(err), logger.Int("exported", count))
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("erreur finalisation de l'export: %w", err)
+	}
+
+	s.logger.Info("Transactions exportées",
+		logger.String("user_id", userID.String()),
+		logger.String("format", format),
+		logger.Int("count", count),
+	)
+	return nil
+}
+
+// categoryPaths associe à chaque catégorie son chemin depuis la catégorie racine, ex: "Alimentation > Courses"
+func categoryPaths(categories []*entity.Category) map[uuid.UUID]string {
+	byID := make(map[uuid.UUID]*entity.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	paths := make(map[uuid.UUID]string, len(categories))
+	for _, category := range categories {
+		names := []string{category.Name}
+		seen := map[uuid.UUID]bool{category.ID: true}
+		for parentID := category.ParentID; parentID != nil && !seen[*parentID]; {
+			parent, ok := byID[*parentID]
+			if !ok {
+				break
+			}
+			seen[parent.ID] = true
+			names = append([]string{parent.Name}, names...)
+			parentID = parent.ParentID
+		}
+		paths[category.ID] = strings.Join(names, " > ")
+	}
+	return paths
+}
+
+// GetTransactionsByDateRange récupère les transactions dans une plage de dates
+func (s *TransactionService) GetTransactionsByDateRange(ctx context.Context, userID uuid.UUID, startDate, endDate string) ([]*entity.Transaction, error) {
+	transactions, err := s.transactionRepo.GetByDateRange(ctx, userID, startDate, endDate)
+	if err != nil {
+		s.logger.Error("Erreur récupération transactions par plage de dates", logger.Error(err))
+		return nil, fmt.Errorf("erreur récupération transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// GetTransactionsByUserID récupère toutes les transactions d'un utilisateur
+func (s *TransactionService) GetTransactionsByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Transaction, error) {
+	transactions, err := s.transactionRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("Erreur récupération transactions utilisateur", logger.Error(err))
+		return nil, fmt.Errorf("erreur récupération transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// GetTransactionsByAccountID récupère toutes les transactions d'un compte spécifique avec les détails de la catégorie
+func (s *TransactionService) GetTransactionsByAccountID(ctx context.Context, userID uuid.UUID, accountID uuid.UUID) ([]*entity.Transaction, error) {
+	transactions, err := s.transactionRepo.GetByAccountIDWithCategoryDetails(ctx, userID, &accountID)
+	if err != nil {
+		s.logger.Error("Erreur récupération transactions par compte", logger.Error(err))
+		return nil, fmt.Errorf("erreur récupération transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// balanceDelta retourne l'effet signé d'une transaction sur le solde de son compte
+func balanceDelta(transaction *entity.Transaction) entity.Money {
+	switch transaction.Type {
+	case "income", "refund":
+		return transaction.Amount
+	case "expense", "saving":
+		return transaction.Amount.Neg()
+	case "transfer":
+		if isIncomingTransferLeg(transaction) {
+			return transaction.Amount
+		}
+		return transaction.Amount.Neg()
+	}
+	return entity.NewMoney(0, transaction.Currency)
+}
+
+// isIncomingTransferLeg indique si une jambe de transfert crédite son compte (son compte est le compte destination)
+func isIncomingTransferLeg(transaction *entity.Transaction) bool {
+	return transaction.AccountID != nil && transaction.ToAccountID != nil && *transaction.AccountID == *transaction.ToAccountID
+}
+
+// splitTransferLegs sépare la jambe débitrice et la jambe créditrice d'un transfert
+func splitTransferLegs(legs []*entity.Transaction) (out *entity.Transaction, in *entity.Transaction) {
+	for _, leg := range legs {
+		if leg.AccountID == nil {
+			continue
+		}
+		if isIncomingTransferLeg(leg) {
+			in = leg
+		} else {
+			out = leg
+		}
+	}
+	return out, in
+}
+
+// lockAccounts verrouille les comptes donnés (SELECT ... FOR UPDATE) et vérifie qu'ils appartiennent à l'utilisateur.
+// Les verrous sont pris dans un ordre stable pour éviter les interblocages entre transferts croisés.
+// Doit être appelé à l'intérieur de TxManager.WithinTransaction.
+func (s *TransactionService) lockAccounts(ctx context.Context, userID uuid.UUID, accountIDs ...uuid.UUID) (map[uuid.UUID]*entity.Account, error) {
+	ids := make([]uuid.UUID, 0, len(accountIDs))
+	seen := make(map[uuid.UUID]bool, len(accountIDs))
+	for _, id := range accountIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+
+	accounts := make(map[uuid.UUID]*entity.Account, len(ids))
+	for _, id := range ids {
+		account, err := s.accountRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("compte non trouvé: %w", err)
+		}
+		if account.UserID != userID {
+			return nil, fmt.Errorf("accès non autorisé au compte")
+		}
+		accounts[id] = account
+	}
+	return accounts, nil
+}
+
+// accountIDsOf retourne les comptes touchés par les transactions données
+func accountIDsOf(transactions ...*entity.Transaction) []uuid.UUID {
+	var ids []uuid.UUID
+	for _, t := range transactions {
+		if t.AccountID != nil {
+			ids = append(ids, *t.AccountID)
+		}
+	}
+	return ids
+}
+
+// checkTargetAccount refuse de déplacer une transaction (before) vers un compte archivé (after). Les comptes
+// doivent avoir été verrouillés via lockAccounts ; une transaction qui reste sur son compte n'est pas concernée.
+func checkTargetAccount(accounts map[uuid.UUID]*entity.Account, before, after *entity.Transaction) error {
+	if after.AccountID == nil || sameAccount(before.AccountID, after.AccountID) {
+		return nil
+	}
+	if account, ok := accounts[*after.AccountID]; ok && account.ArchivedAt != nil {
+		return fmt.Errorf("%w: %s", entity.ErrAccountArchived, account.Name)
+	}
+	return nil
+}
+
+// applyTransactionEffect applique (sign = 1) ou annule (sign = -1) l'effet d'une transaction sur le solde
+// de son compte et, pour une épargne, sur son objectif. Le compte doit avoir été verrouillé via lockAccounts.
+// Selon son statut, une transaction n'a pas encore d'effet (planifiée), ne touche que le solde en attente
+// du compte (en attente) ou touche son solde courant, son historique de solde et l'objectif d'épargne (passée ou
+// rapprochée).
+func (s *TransactionService) applyTransactionEffect(ctx context.Context, userID uuid.UUID, transaction *entity.Transaction, accounts map[uuid.UUID]*entity.Account, sign int64) error {
+	if transaction.Status == transactionStatusScheduled {
+		return nil
+	}
+	pending := transaction.Status == transactionStatusPending
+
+	if transaction.AccountID != nil {
+		account, ok := accounts[*transaction.AccountID]
+		if !ok {
+			return fmt.Errorf("compte %s non verrouillé", transaction.AccountID.String())
+		}
+		delta := balanceDelta(transaction).Mul(sign)
+		if err := s.applyBalance(ctx, account, delta, pending); err != nil {
+			return err
+		}
+		if !pending {
+			if err := s.accountService.recordBalanceChange(ctx, account, transaction.Date, delta); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !pending && transaction.Type == "saving" && transaction.SavingGoalID != nil {
+		return s.applySavingContribution(ctx, userID, *transaction.SavingGoalID, transaction.Amount.Mul(sign))
+	}
+	return nil
+}
+
+// applyBalance ajoute delta au solde courant d'un compte verrouillé, ou à son solde en attente, et le sauvegarde
+func (s *TransactionService) applyBalance(ctx context.Context, account *entity.Account, delta entity.Money, pending bool) error {
+	if delta.Currency != account.Currency {
+		return fmt.Errorf("%w: montant en %s sur un compte en %s", entity.ErrCurrencyMismatch, delta.Currency, account.Currency)
+	}
+	if pending {
+		account.PendingBalance = account.PendingBalance.Add(delta)
+	} else {
+		account.Balance = account.Balance.Add(delta)
+	}
+	account.AvailableBalance = account.Balance.Add(account.PendingBalance)
+	account.UpdatedAt = time.Now()
+	if err := s.accountRepo.Update(ctx, account); err != nil {
+		return fmt.Errorf("erreur mise à jour balance du compte: %w", err)
+	}
+	return nil
+}
+
+// applySavingContribution ajoute delta au montant épargné d'un objectif, en verrouillant sa ligne
+func (s *TransactionService) applySavingContribution(ctx context.Context, userID uuid.UUID, goalID uuid.UUID, delta entity.Money) error {
+	goal, err := s.savingGoalRepo.GetByIDForUpdate(ctx, goalID)
+	if err != nil {
+		return fmt.Errorf("objectif d'épargne non trouvé: %w", err)
+	}
+	if goal.UserID != userID {
+		return fmt.Errorf("accès non autorisé à l'objectif d'épargne")
+	}
+
+	if delta.Currency != goal.Currency {
+		return fmt.Errorf("%w: montant en %s sur un objectif en %s", entity.ErrCurrencyMismatch, delta.Currency, goal.Currency)
+	}
+	goal.CurrentAmount = goal.CurrentAmount.Add(delta)
+	goal.IsAchieved = goal.CurrentAmount.Cmp(goal.TargetAmount) >= 0
+	goal.UpdatedAt = time.Now()
+	if err := s.savingGoalRepo.Update(ctx, goal); err != nil {
+		return fmt.Errorf("erreur mise à jour objectif d'épargne: %w", err)
+	}
+	return nil
+}
+
+// buildSplits valide les lignes de ventilation demandées et les convertit en entités.
+// Seules les dépenses et les revenus peuvent être ventilés ; la somme des lignes, dans la devise du montant,
+// doit lui être égale.
+func (s *TransactionService) buildSplits(ctx context.Context, userID, transactionID uuid.UUID, txType string, amount entity.Money, requests []entity.TransactionSplitRequest) ([]*entity.TransactionSplit, error) {
+	if len(requests) == 0 {
+		return nil, nil
+	}
+	if txType != "expense" && txType != "income" {
+		return nil, fmt.Errorf("%w: seules les dépenses et les revenus peuvent être ventilés", entity.ErrInvalidTransactionData)
+	}
+	if len(requests) < 2 {
+		return nil, fmt.Errorf("%w: une ventilation comporte au moins deux lignes", entity.ErrInvalidTransactionData)
+	}
+
+	splits := make([]*entity.TransactionSplit, 0, len(requests))
+	total := entity.NewMoney(0, amount.Currency)
+	now := time.Now()
+	for i, req := range requests {
+		splitAmount, err := req.Amount.Money(amount.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("ligne de ventilation %d: %w", i+1, err)
+		}
+		if !splitAmount.IsPositive() {
+			return nil, fmt.Errorf("%w: ligne de ventilation %d: le montant doit être positif", entity.ErrInvalidTransactionData, i+1)
+		}
+		category, err := s.categoryRepo.GetByID(ctx, userID, req.CategoryID)
+		if err != nil || category.UserID != userID {
+			return nil, fmt.Errorf("%w: ligne de ventilation %d: catégorie non trouvée", entity.ErrInvalidTransactionData, i+1)
+		}
+
+		total = total.Add(splitAmount)
+		splits = append(splits, &entity.TransactionSplit{
+			ID:            uuid.New(),
+			TransactionID: transactionID,
+			CategoryID:    req.CategoryID,
+			Amount:        splitAmount,
+			Currency:      amount.Currency,
+			Note:          req.Note,
+			CreatedAt:     now.Add(time.Duration(i) * time.Microsecond), // conserve l'ordre de saisie
+		})
+	}
+
+	if total.Cmp(amount) != 0 {
+		return nil, fmt.Errorf("%w: la somme des lignes de ventilation (%s) doit être égale au montant (%s)", entity.ErrInvalidTransactionData, total, amount)
+	}
+	return splits, nil
+}
+
+// mainSplit retourne la ligne de ventilation au montant le plus élevé
+func mainSplit(splits []*entity.TransactionSplit) *entity.TransactionSplit {
+	main := splits[0]
+	for _, split := range splits[1:] {
+		if split.Amount.Cmp(main.Amount) > 0 {
+			main = split
+		}
+	}
+	return main
+}
+
+// findCategoryByName recherche une catégorie d'un type donné par son nom, sans tenir compte de la casse
+func findCategoryByName(categories []*entity.Category, name, categoryType string) *entity.Category {
+	for _, category := range categories {
+		if category.Type == categoryType && strings.EqualFold(strings.TrimSpace(category.Name), strings.TrimSpace(name)) {
+			return category
+		}
+	}
+	return nil
 }
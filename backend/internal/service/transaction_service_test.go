@@ -0,0 +1,418 @@
+package service
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/internal/domaine/repository"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestBalanceDelta(t *testing.T) {
+	from, to := uuid.New(), uuid.New()
+	amount := entity.NewMoney(1500, "XAF")
+	tests := []struct {
+		name        string
+		transaction entity.Transaction
+		want        int64
+	}{
+		{name: "revenu", transaction: entity.Transaction{Type: "income", Amount: amount}, want: 1500},
+		{name: "remboursement", transaction: entity.Transaction{Type: "refund", Amount: amount}, want: 1500},
+		{name: "dépense", transaction: entity.Transaction{Type: "expense", Amount: amount}, want: -1500},
+		{name: "épargne", transaction: entity.Transaction{Type: "saving", Amount: amount}, want: -1500},
+		{name: "jambe débitrice", transaction: entity.Transaction{Type: "transfer", AccountID: &from, ToAccountID: &to, Amount: amount}, want: -1500},
+		{name: "jambe créditrice", transaction: entity.Transaction{Type: "transfer", AccountID: &to, ToAccountID: &to, Amount: amount}, want: 1500},
+		{name: "transfert sans destination", transaction: entity.Transaction{Type: "transfer", AccountID: &from, Amount: amount}, want: -1500},
+		{name: "type inconnu", transaction: entity.Transaction{Type: "autre", Amount: amount, Currency: "XAF"}, want: 0},
+	}
+
+	for _, tt := range tests {
+		got := balanceDelta(&tt.transaction)
+		if got != entity.NewMoney(tt.want, "XAF") {
+			t.Errorf("%s : balanceDelta = %v, attendu %d XAF", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSplitTransferLegs(t *testing.T) {
+	from, to := uuid.New(), uuid.New()
+	out := &entity.Transaction{ID: uuid.New(), Type: "transfer", AccountID: &from, ToAccountID: &to}
+	in := &entity.Transaction{ID: uuid.New(), Type: "transfer", AccountID: &to, ToAccountID: &to}
+	orphan := &entity.Transaction{ID: uuid.New(), Type: "transfer", ToAccountID: &to}
+
+	tests := []struct {
+		name    string
+		legs    []*entity.Transaction
+		wantOut *entity.Transaction
+		wantIn  *entity.Transaction
+	}{
+		{name: "ordre débit puis crédit", legs: []*entity.Transaction{out, in}, wantOut: out, wantIn: in},
+		{name: "ordre crédit puis débit", legs: []*entity.Transaction{in, out}, wantOut: out, wantIn: in},
+		{name: "jambe sans compte ignorée", legs: []*entity.Transaction{orphan, in}, wantIn: in},
+		{name: "jambe créditrice seule", legs: []*entity.Transaction{in}, wantIn: in},
+		{name: "aucune jambe", legs: nil},
+	}
+
+	for _, tt := range tests {
+		gotOut, gotIn := splitTransferLegs(tt.legs)
+		if gotOut != tt.wantOut || gotIn != tt.wantIn {
+			t.Errorf("%s : splitTransferLegs = %v, %v, attendu %v, %v", tt.name, gotOut, gotIn, tt.wantOut, tt.wantIn)
+		}
+	}
+}
+
+func TestBuildTransactionFilter(t *testing.T) {
+	decimal := func(value string) *entity.Decimal {
+		d := entity.Decimal(value)
+		return &d
+	}
+
+	filter, err := buildTransactionFilter(TransactionQuery{Search: "  café  "})
+	if err != nil {
+		t.Fatalf("buildTransactionFilter: %v", err)
+	}
+	if filter.SortBy != "date" || filter.SortOrder != "desc" || filter.Limit != 10 || filter.TagMatch != "any" || filter.Search != "café" {
+		t.Errorf("valeurs par défaut = %+v", filter)
+	}
+
+	filter, err = buildTransactionFilter(TransactionQuery{StartDate: "2025-01-01", EndDate: "2025-01-31", MinAmount: decimal("10"), MaxAmount: decimal("10.5")})
+	if err != nil {
+		t.Fatalf("buildTransactionFilter: %v", err)
+	}
+	if !filter.StartDate.Equal(day(2025, time.January, 1)) || !filter.EndDate.Equal(day(2025, time.January, 31)) {
+		t.Errorf("période = %s - %s, attendu 2025-01-01 - 2025-01-31", filter.StartDate, filter.EndDate)
+	}
+
+	tests := []struct {
+		name  string
+		query TransactionQuery
+	}{
+		{name: "tri inconnu", query: TransactionQuery{SortBy: "description"}},
+		{name: "ordre inconnu", query: TransactionQuery{SortOrder: "up"}},
+		{name: "correspondance d'étiquettes inconnue", query: TransactionQuery{TagMatch: "none"}},
+		{name: "statut inconnu", query: TransactionQuery{Status: "done"}},
+		{name: "date de début invalide", query: TransactionQuery{StartDate: "01/01/2025"}},
+		{name: "date de fin invalide", query: TransactionQuery{EndDate: "2025-02-30"}},
+		{name: "période inversée", query: TransactionQuery{StartDate: "2025-02-01", EndDate: "2025-01-31"}},
+		{name: "montant minimum invalide", query: TransactionQuery{MinAmount: decimal("abc")}},
+		{name: "montants inversés", query: TransactionQuery{MinAmount: decimal("10.5"), MaxAmount: decimal("10.49")}},
+		{name: "curseur invalide", query: TransactionQuery{Cursor: "pas-un-curseur"}},
+	}
+	for _, tt := range tests {
+		if _, err := buildTransactionFilter(tt.query); !errors.Is(err, entity.ErrInvalidTransactionQuery) {
+			t.Errorf("%s : erreur = %v, attendu %v", tt.name, err, entity.ErrInvalidTransactionQuery)
+		}
+	}
+}
+
+func TestTransactionCursorRoundTrip(t *testing.T) {
+	transaction := &entity.Transaction{
+		ID:        uuid.New(),
+		Amount:    entity.NewMoney(-1234, "KWD"),
+		Date:      day(2025, time.March, 14),
+		CreatedAt: time.Date(2025, time.March, 14, 9, 30, 0, 123456000, time.UTC),
+	}
+	tests := map[string]string{"date": "2025-03-14", "amount": "-1.234", "created_at": ""}
+
+	for sortBy, wantSortValue := range tests {
+		encoded, err := encodeTransactionCursor(transaction, sortBy)
+		if err != nil {
+			t.Fatalf("encodeTransactionCursor(%s): %v", sortBy, err)
+		}
+		cursor, err := decodeTransactionCursor(encoded)
+		if err != nil {
+			t.Fatalf("decodeTransactionCursor(%s): %v", sortBy, err)
+		}
+		if cursor.ID != transaction.ID || !cursor.CreatedAt.Equal(transaction.CreatedAt) || cursor.SortValue != wantSortValue {
+			t.Errorf("tri %s : curseur relu = %+v, attendu %s / %s / %q", sortBy, cursor, transaction.ID, transaction.CreatedAt, wantSortValue)
+		}
+	}
+
+	// Un curseur illisible ou sans identifiant est refusé
+	for _, value := range []string{"", "e30", "%%%"} {
+		if _, err := decodeTransactionCursor(value); !errors.Is(err, entity.ErrInvalidTransactionQuery) {
+			t.Errorf("decodeTransactionCursor(%q) = %v, attendu %v", value, err, entity.ErrInvalidTransactionQuery)
+		}
+	}
+}
+
+// categoriesByID est un CategoryRepository en mémoire limité à GetByID
+type categoriesByID struct {
+	repository.CategoryRepository
+	categories map[uuid.UUID]*entity.Category
+}
+
+func (r categoriesByID) GetByID(_ context.Context, _ uuid.UUID, id uuid.UUID) (*entity.Category, error) {
+	if category, ok := r.categories[id]; ok {
+		return category, nil
+	}
+	return nil, errors.New("catégorie non trouvée")
+}
+
+func TestBuildSplits(t *testing.T) {
+	userID := uuid.New()
+	food, home, foreign := uuid.New(), uuid.New(), uuid.New()
+	service := &TransactionService{categoryRepo: categoriesByID{categories: map[uuid.UUID]*entity.Category{
+		food:    {ID: food, UserID: userID},
+		home:    {ID: home, UserID: userID},
+		foreign: {ID: foreign, UserID: uuid.New()},
+	}}}
+	line := func(categoryID uuid.UUID, amount string) entity.TransactionSplitRequest {
+		return entity.TransactionSplitRequest{CategoryID: categoryID, Amount: entity.Decimal(amount)}
+	}
+
+	transactionID := uuid.New()
+	splits, err := service.buildSplits(context.Background(), userID, transactionID, "expense", entity.NewMoney(12345, "KWD"),
+		[]entity.TransactionSplitRequest{line(food, "10.005"), line(home, "2.34")})
+	if err != nil {
+		t.Fatalf("buildSplits: %v", err)
+	}
+	if len(splits) != 2 || splits[0].Amount != entity.NewMoney(10005, "KWD") || splits[1].Amount != entity.NewMoney(2340, "KWD") {
+		t.Fatalf("buildSplits = %+v, attendu 10.005 et 2.340 KWD", splits)
+	}
+	for i, split := range splits {
+		if split.TransactionID != transactionID || split.Currency != "KWD" || split.ID == uuid.Nil {
+			t.Errorf("ligne %d = %+v", i, split)
+		}
+	}
+	if !splits[0].CreatedAt.Before(splits[1].CreatedAt) {
+		t.Error("l'ordre de saisie des lignes doit être conservé")
+	}
+
+	if splits, err := service.buildSplits(context.Background(), userID, transactionID, "transfer", entity.NewMoney(100, "EUR"), nil); splits != nil || err != nil {
+		t.Errorf("sans ligne : buildSplits = %v, %v, attendu aucune ventilation", splits, err)
+	}
+
+	tests := []struct {
+		name    string
+		txType  string
+		amount  int64
+		lines   []entity.TransactionSplitRequest
+		wantErr error
+	}{
+		{name: "transfert", txType: "transfer", amount: 1000, lines: []entity.TransactionSplitRequest{line(food, "5"), line(home, "5")}, wantErr: entity.ErrInvalidTransactionData},
+		{name: "une seule ligne", txType: "expense", amount: 1000, lines: []entity.TransactionSplitRequest{line(food, "10")}, wantErr: entity.ErrInvalidTransactionData},
+		{name: "ligne nulle", txType: "income", amount: 1000, lines: []entity.TransactionSplitRequest{line(food, "10"), line(home, "0")}, wantErr: entity.ErrInvalidTransactionData},
+		{name: "ligne négative", txType: "expense", amount: 1000, lines: []entity.TransactionSplitRequest{line(food, "15"), line(home, "-5")}, wantErr: entity.ErrInvalidTransactionData},
+		{name: "trop de décimales", txType: "expense", amount: 1000, lines: []entity.TransactionSplitRequest{line(food, "9.995"), line(home, "0.005")}, wantErr: entity.ErrInvalidAmount},
+		{name: "catégorie inconnue", txType: "expense", amount: 1000, lines: []entity.TransactionSplitRequest{line(food, "5"), line(uuid.New(), "5")}, wantErr: entity.ErrInvalidTransactionData},
+		{name: "catégorie d'un autre utilisateur", txType: "expense", amount: 1000, lines: []entity.TransactionSplitRequest{line(food, "5"), line(foreign, "5")}, wantErr: entity.ErrInvalidTransactionData},
+		{name: "somme différente du montant", txType: "expense", amount: 1000, lines: []entity.TransactionSplitRequest{line(food, "5"), line(home, "4.99")}, wantErr: entity.ErrInvalidTransactionData},
+	}
+	for _, tt := range tests {
+		_, err := service.buildSplits(context.Background(), userID, transactionID, tt.txType, entity.NewMoney(tt.amount, "EUR"), tt.lines)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s : erreur = %v, attendu %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestMainSplit(t *testing.T) {
+	split := func(minor int64) *entity.TransactionSplit {
+		return &entity.TransactionSplit{ID: uuid.New(), Amount: entity.NewMoney(minor, "EUR")}
+	}
+	small, large, tie := split(500), split(1500), split(1500)
+
+	tests := []struct {
+		name   string
+		splits []*entity.TransactionSplit
+		want   *entity.TransactionSplit
+	}{
+		{name: "plus grande ligne en premier", splits: []*entity.TransactionSplit{large, small}, want: large},
+		{name: "plus grande ligne en dernier", splits: []*entity.TransactionSplit{small, large}, want: large},
+		{name: "égalité : première ligne saisie", splits: []*entity.TransactionSplit{small, large, tie}, want: large},
+		{name: "ligne unique", splits: []*entity.TransactionSplit{small}, want: small},
+	}
+	for _, tt := range tests {
+		if got := mainSplit(tt.splits); got != tt.want {
+			t.Errorf("%s : mainSplit = %v, attendu %v", tt.name, got.Amount, tt.want.Amount)
+		}
+	}
+}
+
+func TestNormalizeDescription(t *testing.T) {
+	tests := map[string]string{
+		"  Café   du  Coin ":     "cafe du coin",
+		"CARREFOUR MARKET #1234": "carrefour market 1234",
+		"Orange Money - Retrait": "orange money retrait",
+		"Noël à l'hôtel":         "noel a l hotel",
+		"ÇA COÛTE":               "ca coute",
+		"Paiement\tcarte\n":      "paiement carte",
+		"Virement/SEPA:REF_42":   "virement sepa ref 42",
+		"***":                    "",
+		"":                       "",
+	}
+	for description, want := range tests {
+		if got := normalizeDescription(description); got != want {
+			t.Errorf("normalizeDescription(%q) = %q, attendu %q", description, got, want)
+		}
+	}
+}
+
+func TestDuplicateFingerprint(t *testing.T) {
+	accountID := uuid.New()
+	transaction := func(minor int64, description string) *entity.Transaction {
+		return &entity.Transaction{AccountID: &accountID, Type: "expense", Amount: entity.NewMoney(minor, "XAF"), Description: description}
+	}
+
+	reference := duplicateFingerprint(transaction(1500, "Café du Coin"))
+	if got := duplicateFingerprint(transaction(1500, "  CAFE du coin!")); got != reference {
+		t.Errorf("la casse, les accents et la ponctuation ne doivent pas changer l'empreinte : %s, %s", got, reference)
+	}
+	if got := duplicateFingerprint(transaction(1501, "Café du Coin")); got == reference {
+		t.Error("deux montants différents doivent avoir des empreintes différentes")
+	}
+	income := transaction(1500, "Café du Coin")
+	income.Type = "income"
+	if got := duplicateFingerprint(income); got == reference {
+		t.Error("deux types différents doivent avoir des empreintes différentes")
+	}
+}
+
+func TestStatsRanges(t *testing.T) {
+	now := time.Date(2025, time.March, 12, 15, 0, 0, 0, time.UTC) // un mercredi
+	tests := []struct {
+		name                                   string
+		query                                  TransactionStatsQuery
+		wantPeriod                             string
+		start, end, previousStart, previousEnd time.Time
+	}{
+		{
+			name: "mois par défaut", query: TransactionStatsQuery{}, wantPeriod: "month",
+			start: day(2025, time.March, 1), end: day(2025, time.March, 31),
+			previousStart: day(2025, time.February, 1), previousEnd: day(2025, time.February, 28),
+		},
+		{
+			name: "semaine du lundi", query: TransactionStatsQuery{Period: "week"}, wantPeriod: "week",
+			start: day(2025, time.March, 10), end: day(2025, time.March, 16),
+			previousStart: day(2025, time.March, 3), previousEnd: day(2025, time.March, 9),
+		},
+		{
+			name: "année civile", query: TransactionStatsQuery{Period: "year"}, wantPeriod: "year",
+			start: day(2025, time.January, 1), end: day(2025, time.December, 31),
+			previousStart: day(2024, time.January, 1), previousEnd: day(2024, time.December, 31),
+		},
+		{
+			name: "dates explicites", query: TransactionStatsQuery{Period: "year", StartDate: "2025-01-10", EndDate: "2025-01-19"}, wantPeriod: "custom",
+			start: day(2025, time.January, 10), end: day(2025, time.January, 19),
+			previousStart: day(2024, time.December, 31), previousEnd: day(2025, time.January, 9),
+		},
+		{
+			name: "date de début seule", query: TransactionStatsQuery{StartDate: "2025-03-01"}, wantPeriod: "custom",
+			start: day(2025, time.March, 1), end: day(2025, time.March, 12),
+			previousStart: day(2025, time.February, 17), previousEnd: day(2025, time.February, 28),
+		},
+	}
+
+	for _, tt := range tests {
+		period, current, previous, err := statsRanges(tt.query, now)
+		if err != nil {
+			t.Errorf("%s : statsRanges: %v", tt.name, err)
+			continue
+		}
+		if period != tt.wantPeriod || !current.StartDate.Equal(tt.start) || !current.EndDate.Equal(tt.end) ||
+			!previous.StartDate.Equal(tt.previousStart) || !previous.EndDate.Equal(tt.previousEnd) {
+			t.Errorf("%s : statsRanges = %s, %s - %s, précédente %s - %s", tt.name, period,
+				current.StartDate.Format("2006-01-02"), current.EndDate.Format("2006-01-02"),
+				previous.StartDate.Format("2006-01-02"), previous.EndDate.Format("2006-01-02"))
+		}
+	}
+
+	for name, query := range map[string]TransactionStatsQuery{
+		"date de fin seule":      {EndDate: "2025-03-01"},
+		"date de début invalide": {StartDate: "2025-13-01"},
+		"date de fin invalide":   {StartDate: "2025-03-01", EndDate: "demain"},
+		"période inversée":       {StartDate: "2025-03-02", EndDate: "2025-03-01"},
+		"période inconnue":       {Period: "day"},
+	} {
+		if _, _, _, err := statsRanges(query, now); !errors.Is(err, entity.ErrInvalidTransactionQuery) {
+			t.Errorf("%s : erreur = %v, attendu %v", name, err, entity.ErrInvalidTransactionQuery)
+		}
+	}
+}
+
+func TestStatsGranularity(t *testing.T) {
+	filter := func(start, end time.Time) *entity.TransactionStatsFilter {
+		return &entity.TransactionStatsFilter{StartDate: start, EndDate: end}
+	}
+	march := filter(day(2025, time.March, 1), day(2025, time.March, 31))
+	year := filter(day(2025, time.January, 1), day(2025, time.December, 31))
+
+	tests := []struct {
+		name        string
+		granularity string
+		period      string
+		current     *entity.TransactionStatsFilter
+		want        string
+		wantErr     bool
+	}{
+		{name: "mois", period: "month", current: march, want: "day"},
+		{name: "année", period: "year", current: year, want: "month"},
+		{name: "trois mois", period: "custom", current: filter(day(2025, time.January, 1), day(2025, time.March, 31)), want: "week"},
+		{name: "deux ans", period: "custom", current: filter(day(2023, time.January, 1), day(2024, time.December, 31)), want: "month"},
+		{name: "granularité demandée", granularity: "week", period: "year", current: year, want: "week"},
+		{name: "granularité inconnue", granularity: "hour", period: "month", current: march, wantErr: true},
+		{name: "trop d'intervalles", granularity: "day", period: "custom", current: filter(day(2020, time.January, 1), day(2025, time.January, 1)), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := statsGranularity(tt.granularity, tt.period, tt.current)
+		if tt.wantErr {
+			if !errors.Is(err, entity.ErrInvalidTransactionQuery) {
+				t.Errorf("%s : statsGranularity = %q, %v, attendu une erreur", tt.name, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s : statsGranularity = %q, %v, attendu %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestPercentChange(t *testing.T) {
+	eur := func(minor int64) entity.Money { return entity.NewMoney(minor, "EUR") }
+	tests := []struct {
+		current, previous int64
+		want              *float64
+	}{
+		{current: 1500, previous: 1000, want: floatPtr(50)},
+		{current: 500, previous: 1000, want: floatPtr(-50)},
+		{current: 1, previous: 3, want: floatPtr(-66.67)},
+		{current: -500, previous: -1000, want: floatPtr(-50)},
+		{current: 1000, previous: 0, want: nil},
+	}
+
+	for _, tt := range tests {
+		got := percentChange(eur(tt.current), eur(tt.previous))
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("percentChange(%d, %d) = %v, attendu %v", tt.current, tt.previous, got, tt.want)
+		}
+	}
+}
+
+func TestSharePercent(t *testing.T) {
+	eur := func(minor int64) entity.Money { return entity.NewMoney(minor, "EUR") }
+	tests := []struct {
+		amount, total int64
+		want          float64
+	}{
+		{amount: 2500, total: 10000, want: 25},
+		{amount: 1, total: 3, want: 33.33},
+		{amount: 2, total: 3, want: 66.67},
+		{amount: 10000, total: 10000, want: 100},
+		{amount: 500, total: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := sharePercent(eur(tt.amount), eur(tt.total)); got != tt.want {
+			t.Errorf("sharePercent(%d, %d) = %v, attendu %v", tt.amount, tt.total, got, tt.want)
+		}
+	}
+}
+
+func floatPtr(value float64) *float64 {
+	return &value
+}
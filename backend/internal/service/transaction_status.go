@@ -0,0 +1,227 @@
+package service
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/pkg/logger"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Statuts d'une transaction
+const (
+	transactionStatusScheduled  = "scheduled"  // datée dans le futur, sans effet sur les soldes
+	transactionStatusPending    = "pending"    // en attente, comptée dans le solde disponible seulement
+	transactionStatusCleared    = "cleared"    // passée, comptée dans le solde courant
+	transactionStatusReconciled = "reconciled" // rapprochée d'un relevé, verrouillée contre les modifications
+)
+
+// scheduledBatchSize borne le nombre de transactions planifiées échues traitées par passage du planificateur
+const scheduledBatchSize = 100
+
+// isValidTransactionStatus indique si un statut de transaction est connu
+func isValidTransactionStatus(status string) bool {
+	switch status {
+	case transactionStatusScheduled, transactionStatusPending, transactionStatusCleared, transactionStatusReconciled:
+		return true
+	}
+	return false
+}
+
+// isFutureDate indique si une date tombe après le jour donné
+func isFutureDate(date, now time.Time) bool {
+	return truncateToDay(date).After(truncateToDay(now))
+}
+
+// initialTransactionStatus détermine le statut d'une nouvelle transaction : celui demandé, ou passée par défaut.
+// Une transaction datée dans le futur est planifiée ; une transaction ne peut être rapprochée qu'après sa création.
+func initialTransactionStatus(requested *string, date, now time.Time) (string, error) {
+	if requested == nil || *requested == "" {
+		if isFutureDate(date, now) {
+			return transactionStatusScheduled, nil
+		}
+		return transactionStatusCleared, nil
+	}
+	if *requested == transactionStatusReconciled || !isValidTransactionStatus(*requested) {
+		return "", fmt.Errorf("%w: %s (scheduled, pending ou cleared à la création)", entity.ErrInvalidTransactionStatus, *requested)
+	}
+	return *requested, checkStatusDate(*requested, date, now)
+}
+
+// checkStatusDate vérifie qu'un statut est cohérent avec la date de la transaction : seule une transaction
+// datée dans le futur est planifiée, et elle ne peut pas être autrement
+func checkStatusDate(status string, date, now time.Time) error {
+	future := isFutureDate(date, now)
+	if status == transactionStatusScheduled && !future {
+		return fmt.Errorf("%w: seule une transaction datée dans le futur peut être planifiée", entity.ErrInvalidTransactionStatus)
+	}
+	if status != transactionStatusScheduled && future {
+		return fmt.Errorf("%w: une transaction datée dans le futur reste planifiée", entity.ErrInvalidTransactionStatus)
+	}
+	return nil
+}
+
+// statusForDate ajuste le statut d'une transaction dont la date change : une date future la planifie,
+// une transaction planifiée ramenée à une date échue passe en attente
+func statusForDate(status string, date, now time.Time) string {
+	switch {
+	case isFutureDate(date, now):
+		return transactionStatusScheduled
+	case status == transactionStatusScheduled:
+		return transactionStatusPending
+	}
+	return status
+}
+
+// SetTransactionStatus change le statut d'une transaction (des deux jambes d'un transfert) et déplace son effet
+// entre le solde courant et le solde en attente. C'est le seul moyen de déverrouiller une transaction rapprochée.
+func (s *TransactionService) SetTransactionStatus(ctx context.Context, userID, transactionID uuid.UUID, status string) (*entity.Transaction, error) {
+	if !isValidTransactionStatus(status) {
+		return nil, fmt.Errorf("%w: %s", entity.ErrInvalidTransactionStatus, status)
+	}
+
+	var transaction *entity.Transaction
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.transactionRepo.GetByIDForUpdate(ctx, transactionID)
+		if err != nil {
+			return fmt.Errorf("transaction non trouvée")
+		}
+		if existing.UserID != userID {
+			return fmt.Errorf("accès non autorisé")
+		}
+		if err := checkStatusDate(status, existing.Date, time.Now()); err != nil {
+			return err
+		}
+
+		transaction, err = s.changeStatus(ctx, existing, status)
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Erreur changement de statut de transaction", logger.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Statut de transaction modifié",
+		logger.String("transaction_id", transactionID.String()),
+		logger.String("status", status),
+	)
+
+	return transaction, nil
+}
+
+// changeStatus passe une transaction verrouillée (et l'autre jambe d'un transfert) au statut donné : l'effet
+// de l'ancien statut sur les soldes est annulé puis celui du nouveau est appliqué.
+// Doit être appelé à l'intérieur de TxManager.WithinTransaction.
+func (s *TransactionService) changeStatus(ctx context.Context, existing *entity.Transaction, status string) (*entity.Transaction, error) {
+	if existing.Status == status {
+		return existing, nil
+	}
+
+	legs := []*entity.Transaction{existing}
+	if existing.TransferGroupID != nil {
+		var err error
+		if legs, err = s.transactionRepo.GetByTransferGroupIDForUpdate(ctx, *existing.TransferGroupID); err != nil {
+			return nil, err
+		}
+	}
+
+	accounts, err := s.lockAccounts(ctx, existing.UserID, accountIDsOf(legs...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *entity.Transaction
+	for _, leg := range legs {
+		updated := *leg
+		updated.Status = status
+		updated.UpdatedAt = time.Now()
+
+		if err := s.applyTransactionEffect(ctx, leg.UserID, leg, accounts, -1); err != nil {
+			return nil, err
+		}
+		if err := s.applyTransactionEffect(ctx, leg.UserID, &updated, accounts, 1); err != nil {
+			return nil, err
+		}
+		if err := s.transactionRepo.Update(ctx, &updated); err != nil {
+			return nil, fmt.Errorf("erreur mise à jour statut de la transaction: %w", err)
+		}
+		if err := s.recordVersion(ctx, versionActionUpdate, leg, &updated); err != nil {
+			return nil, err
+		}
+		if leg.ID == existing.ID {
+			result = &updated
+		}
+	}
+	return result, nil
+}
+
+// StartScheduledPosting passe périodiquement en attente les transactions planifiées arrivées à échéance,
+// jusqu'à l'annulation du contexte
+func (s *TransactionService) StartScheduledPosting(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		s.logger.Warn("Échéance des transactions planifiées désactivée (intervalle invalide)")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.logger.Info("Échéance des transactions planifiées démarrée", logger.String("interval", interval.String()))
+	for {
+		if posted, err := s.PostDueScheduled(ctx, time.Now()); err != nil {
+			s.logger.Error("Erreur échéance des transactions planifiées", logger.Error(err))
+		} else if posted > 0 {
+			s.logger.Info("Transactions planifiées passées en attente", logger.Int("count", posted))
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Échéance des transactions planifiées arrêtée")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PostDueScheduled passe en attente les transactions planifiées échues à la date donnée et retourne leur nombre.
+// Chaque transaction est traitée dans sa propre transaction SQL, ligne verrouillée et statut revérifié.
+func (s *TransactionService) PostDueScheduled(ctx context.Context, now time.Time) (int, error) {
+	ids, err := s.transactionRepo.GetDueScheduledIDs(ctx, truncateToDay(now), scheduledBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	posted := 0
+	for _, id := range ids {
+		changed := false
+		err := s.txManager.WithinTransaction(withChangeSource(ctx, changeSourceScheduler), func(ctx context.Context) error {
+			changed = false
+			transaction, err := s.transactionRepo.GetByIDForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			// Déjà traitée (autre jambe du même transfert, autre instance du planificateur)
+			if transaction.Status != transactionStatusScheduled || isFutureDate(transaction.Date, now) {
+				return nil
+			}
+			if _, err := s.changeStatus(ctx, transaction, transactionStatusPending); err != nil {
+				return err
+			}
+			changed = true
+			return nil
+		})
+		if err != nil {
+			s.logger.Error("Erreur échéance d'une transaction planifiée",
+				logger.String("transaction_id", id.String()),
+				logger.Error(err),
+			)
+			continue
+		}
+		if changed {
+			posted++
+		}
+	}
+	return posted, nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"backend/internal/domaine/entity"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInitialTransactionStatus(t *testing.T) {
+	now := time.Date(2025, time.March, 12, 18, 30, 0, 0, time.UTC)
+	today, tomorrow, yesterday := day(2025, time.March, 12), day(2025, time.March, 13), day(2025, time.March, 11)
+	status := func(value string) *string { return &value }
+
+	tests := []struct {
+		name      string
+		requested *string
+		date      time.Time
+		want      string
+		wantErr   bool
+	}{
+		{name: "passée par défaut", date: yesterday, want: transactionStatusCleared},
+		{name: "datée du jour", date: today, want: transactionStatusCleared},
+		{name: "statut vide", requested: status(""), date: today, want: transactionStatusCleared},
+		{name: "future planifiée par défaut", date: tomorrow, want: transactionStatusScheduled},
+		{name: "en attente demandée", requested: status(transactionStatusPending), date: yesterday, want: transactionStatusPending},
+		{name: "planifiée demandée", requested: status(transactionStatusScheduled), date: tomorrow, want: transactionStatusScheduled},
+		{name: "planifiée dans le passé", requested: status(transactionStatusScheduled), date: today, wantErr: true},
+		{name: "passée dans le futur", requested: status(transactionStatusCleared), date: tomorrow, wantErr: true},
+		{name: "rapprochée à la création", requested: status(transactionStatusReconciled), date: yesterday, wantErr: true},
+		{name: "statut inconnu", requested: status("done"), date: yesterday, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := initialTransactionStatus(tt.requested, tt.date, now)
+		if tt.wantErr {
+			if !errors.Is(err, entity.ErrInvalidTransactionStatus) {
+				t.Errorf("%s : initialTransactionStatus = %q, %v, attendu une erreur", tt.name, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s : initialTransactionStatus = %q, %v, attendu %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestCheckStatusDate(t *testing.T) {
+	now := time.Date(2025, time.March, 12, 8, 0, 0, 0, time.UTC)
+	past, future := day(2025, time.March, 12), day(2025, time.March, 13)
+
+	tests := []struct {
+		status  string
+		date    time.Time
+		wantErr bool
+	}{
+		{status: transactionStatusScheduled, date: future},
+		{status: transactionStatusScheduled, date: past, wantErr: true},
+		{status: transactionStatusPending, date: past},
+		{status: transactionStatusPending, date: future, wantErr: true},
+		{status: transactionStatusCleared, date: past},
+		{status: transactionStatusReconciled, date: past},
+		{status: transactionStatusReconciled, date: future, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		err := checkStatusDate(tt.status, tt.date, now)
+		if tt.wantErr != (err != nil) {
+			t.Errorf("checkStatusDate(%s, %s) = %v, erreur attendue : %v", tt.status, tt.date.Format("2006-01-02"), err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, entity.ErrInvalidTransactionStatus) {
+			t.Errorf("checkStatusDate(%s, %s) = %v, attendu %v", tt.status, tt.date.Format("2006-01-02"), err, entity.ErrInvalidTransactionStatus)
+		}
+	}
+}
+
+func TestStatusForDate(t *testing.T) {
+	now := time.Date(2025, time.March, 12, 23, 59, 0, 0, time.UTC)
+	past, future := day(2025, time.March, 1), day(2025, time.April, 1)
+
+	tests := []struct {
+		status string
+		date   time.Time
+		want   string
+	}{
+		{status: transactionStatusCleared, date: future, want: transactionStatusScheduled},
+		{status: transactionStatusPending, date: future, want: transactionStatusScheduled},
+		{status: transactionStatusScheduled, date: future, want: transactionStatusScheduled},
+		{status: transactionStatusScheduled, date: past, want: transactionStatusPending},
+		{status: transactionStatusScheduled, date: day(2025, time.March, 12), want: transactionStatusPending},
+		{status: transactionStatusCleared, date: past, want: transactionStatusCleared},
+		{status: transactionStatusPending, date: past, want: transactionStatusPending},
+		{status: transactionStatusReconciled, date: past, want: transactionStatusReconciled},
+	}
+
+	for _, tt := range tests {
+		if got := statusForDate(tt.status, tt.date, now); got != tt.want {
+			t.Errorf("statusForDate(%s, %s) = %s, attendu %s", tt.status, tt.date.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
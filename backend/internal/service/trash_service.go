@@ -0,0 +1,162 @@
+package service
+
+import (
+	"backend/internal/domaine/entity"
+	"backend/internal/domaine/repository"
+	"backend/internal/infra/storage"
+	"backend/pkg/logger"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Types d'éléments de la corbeille
+const (
+	trashTypeTransaction = "transaction"
+	trashTypeAccount     = "account"
+	trashTypeBudget      = "budget"
+	trashTypeSavingGoal  = "saving_goal"
+)
+
+// TrashService gère la corbeille : liste, restauration et purge des transactions, comptes, budgets
+// et objectifs d'épargne supprimés. La restauration est déléguée au service de chaque élément,
+// qui réapplique ses effets (soldes, objectifs, remboursements).
+type TrashService struct {
+	trashRepo          repository.TrashRepository
+	transactionService *TransactionService
+	accountService     *AccountService
+	budgetService      *BudgetService
+	savingGoalService  *SavingGoalService
+	fileStorage        storage.Storage
+	txManager          repository.TxManager
+	retention          time.Duration
+	logger             logger.Logger
+}
+
+// NewTrashService crée une nouvelle instance de TrashService ; les éléments sont purgés après retention
+func NewTrashService(
+	trashRepo repository.TrashRepository,
+	transactionService *TransactionService,
+	accountService *AccountService,
+	budgetService *BudgetService,
+	savingGoalService *SavingGoalService,
+	fileStorage storage.Storage,
+	txManager repository.TxManager,
+	retention time.Duration,
+	logger logger.Logger,
+) *TrashService {
+	return &TrashService{
+		trashRepo:          trashRepo,
+		transactionService: transactionService,
+		accountService:     accountService,
+		budgetService:      budgetService,
+		savingGoalService:  savingGoalService,
+		fileStorage:        fileStorage,
+		txManager:          txManager,
+		retention:          retention,
+		logger:             logger,
+	}
+}
+
+// ListTrash récupère les éléments de la corbeille de l'utilisateur avec leur date de purge
+func (s *TrashService) ListTrash(ctx context.Context, userID uuid.UUID) ([]*entity.TrashItem, error) {
+	items, err := s.trashRepo.List(ctx, userID)
+	if err != nil {
+		s.logger.Error("Erreur récupération corbeille", logger.Error(err))
+		return nil, err
+	}
+	for _, item := range items {
+		item.PurgeAt = item.DeletedAt.Add(s.retention)
+	}
+	return items, nil
+}
+
+// Restore sort un élément de la corbeille et retourne l'élément restauré
+func (s *TrashService) Restore(ctx context.Context, userID uuid.UUID, itemType string, id uuid.UUID) (interface{}, error) {
+	switch itemType {
+	case trashTypeTransaction:
+		return s.transactionService.RestoreTransaction(ctx, userID, id)
+	case trashTypeAccount:
+		return s.accountService.RestoreAccount(ctx, userID, id)
+	case trashTypeBudget:
+		return s.budgetService.RestoreBudget(ctx, userID, id)
+	case trashTypeSavingGoal:
+		return s.savingGoalService.RestoreSavingGoal(ctx, userID, id)
+	default:
+		return nil, fmt.Errorf("%w: %s", entity.ErrInvalidTrashType, itemType)
+	}
+}
+
+// Purge supprime définitivement les éléments restés dans la corbeille au-delà de la durée de rétention,
+// puis les fichiers de leurs pièces jointes une fois la suppression validée
+func (s *TrashService) Purge(ctx context.Context, now time.Time) (*entity.TrashPurgeResult, error) {
+	var result *entity.TrashPurgeResult
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = s.trashRepo.Purge(ctx, now.Add(-s.retention))
+		if err != nil {
+			return err
+		}
+		if len(result.StorageKeys) > 0 {
+			keys := result.StorageKeys
+			s.txManager.AfterCommit(ctx, func() {
+				s.deleteFiles(context.WithoutCancel(ctx), keys)
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// deleteFiles supprime du stockage les fichiers de pièces jointes purgées ;
+// un échec est journalisé sans interrompre la suppression des autres fichiers
+func (s *TrashService) deleteFiles(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.fileStorage.Delete(ctx, key); err != nil {
+			s.logger.Warn("Erreur suppression fichier de pièce jointe",
+				logger.String("storage_key", key),
+				logger.Error(err),
+			)
+		}
+	}
+}
+
+// StartPurgeScheduler purge périodiquement la corbeille jusqu'à l'annulation du contexte
+func (s *TrashService) StartPurgeScheduler(ctx context.Context, interval time.Duration) {
+	if interval <= 0 || s.retention <= 0 {
+		s.logger.Warn("Purge de la corbeille désactivée (intervalle ou rétention invalide)")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.logger.Info("Purge de la corbeille démarrée",
+		logger.String("interval", interval.String()),
+		logger.String("retention", s.retention.String()),
+	)
+	for {
+		if result, err := s.Purge(ctx, time.Now()); err != nil {
+			s.logger.Error("Erreur purge de la corbeille", logger.Error(err))
+		} else if total := result.Transactions + result.Accounts + result.Budgets + result.SavingGoals; total > 0 {
+			s.logger.Info("Corbeille purgée",
+				logger.Int("transactions", result.Transactions),
+				logger.Int("accounts", result.Accounts),
+				logger.Int("budgets", result.Budgets),
+				logger.Int("saving_goals", result.SavingGoals),
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Purge de la corbeille arrêtée")
+			return
+		case <-ticker.C:
+		}
+	}
+}
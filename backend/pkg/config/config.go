@@ -18,6 +18,8 @@ type Config struct {
 	ExternalAPI ExternalAPIConfig `mapstructure:"external_api"`
 	JWT         JWTConfig         `mapstructure:"jwt"`
 	AI          AIConfig          `mapstructure:"ai"`
+	Scheduler   SchedulerConfig   `mapstructure:"scheduler"`
+	Trash       TrashConfig       `mapstructure:"trash"`
 }
 
 type ServerConfig struct {
@@ -48,16 +50,19 @@ type LoggerConfig struct {
 }
 
 type StorageConfig struct {
-	Type      string   `mapstructure:"type"` // local, s3
-	LocalPath string   `mapstructure:"local_path"`
-	S3Config  S3Config `mapstructure:"s3"`
+	Type          string   `mapstructure:"type"` // local, s3
+	LocalPath     string   `mapstructure:"local_path"`
+	MaxFileSizeMB int      `mapstructure:"max_file_size_mb"` // taille maximale d'une pièce jointe
+	S3Config      S3Config `mapstructure:"s3"`
 }
 
 type S3Config struct {
-	Bucket    string `mapstructure:"bucket"`
-	Region    string `mapstructure:"region"`
-	AccessKey string `mapstructure:"access_key"`
-	SecretKey string `mapstructure:"secret_key"`
+	Bucket       string `mapstructure:"bucket"`
+	Region       string `mapstructure:"region"`
+	AccessKey    string `mapstructure:"access_key"`
+	SecretKey    string `mapstructure:"secret_key"`
+	Endpoint     string `mapstructure:"endpoint"`       // vide pour AWS, ex: http://localhost:9000 pour MinIO
+	UsePathStyle bool   `mapstructure:"use_path_style"` // requis par MinIO
 }
 
 type ExternalAPIConfig struct {
@@ -75,6 +80,17 @@ type AIConfig struct {
 	Model string `mapstructure:"model"`
 }
 
+type SchedulerConfig struct {
+	RecurringInterval int `mapstructure:"recurring_interval"` // en minutes
+	ScheduledInterval int `mapstructure:"scheduled_interval"` // échéance des transactions planifiées, en minutes
+	ReminderInterval  int `mapstructure:"reminder_interval"`  // rappels des échéances d'emprunts et des cotisations de tontines, en minutes
+}
+
+type TrashConfig struct {
+	RetentionDays int `mapstructure:"retention_days"` // durée de conservation avant suppression définitive
+	PurgeInterval int `mapstructure:"purge_interval"` // en minutes
+}
+
 func Load() (*Config, error) {
 	// Charger le fichier .env si disponible
 	if err := godotenv.Load(); err != nil {
@@ -140,6 +156,7 @@ func setDefaults() {
 	// Storage
 	viper.SetDefault("storage.type", "local")
 	viper.SetDefault("storage.local_path", "./uploads")
+	viper.SetDefault("storage.max_file_size_mb", 10)
 
 	// External API
 	viper.SetDefault("external_api.base_url", "https://jsonplaceholder.typicode.com")
@@ -149,6 +166,15 @@ func setDefaults() {
 	viper.SetDefault("jwt.secret_key", "your-secret-key-change-in-production")
 	viper.SetDefault("jwt.expiration_hours", 24)
 	viper.SetDefault("jwt.refresh_expiration_hours", 168) // 7 jours
+
+	// Scheduler
+	viper.SetDefault("scheduler.recurring_interval", 15)
+	viper.SetDefault("scheduler.scheduled_interval", 15)
+	viper.SetDefault("scheduler.reminder_interval", 60)
+
+	// Corbeille
+	viper.SetDefault("trash.retention_days", 30)
+	viper.SetDefault("trash.purge_interval", 60)
 }
 
 func overrideWithEnv(config *Config) {
@@ -181,6 +207,41 @@ func overrideWithEnv(config *Config) {
 		config.Redis.Password = password
 	}
 
+	// Storage
+	if storageType := getEnv("STORAGE_TYPE", ""); storageType != "" {
+		config.Storage.Type = storageType
+	}
+	if localPath := getEnv("STORAGE_LOCAL_PATH", ""); localPath != "" {
+		config.Storage.LocalPath = localPath
+	}
+	if bucket := getEnv("S3_BUCKET", ""); bucket != "" {
+		config.Storage.S3Config.Bucket = bucket
+	}
+	if region := getEnv("S3_REGION", ""); region != "" {
+		config.Storage.S3Config.Region = region
+	}
+	if endpoint := getEnv("S3_ENDPOINT", ""); endpoint != "" {
+		config.Storage.S3Config.Endpoint = endpoint
+	}
+	if usePathStyle := getEnv("S3_USE_PATH_STYLE", ""); usePathStyle != "" {
+		if value, err := strconv.ParseBool(usePathStyle); err == nil {
+			config.Storage.S3Config.UsePathStyle = value
+		}
+	}
+	if accessKey := getEnv("S3_ACCESS_KEY", ""); accessKey != "" {
+		config.Storage.S3Config.AccessKey = accessKey
+	}
+	if secretKey := getEnv("S3_SECRET_KEY", ""); secretKey != "" {
+		config.Storage.S3Config.SecretKey = secretKey
+	}
+
+	// Corbeille
+	if retentionDays := getEnv("TRASH_RETENTION_DAYS", ""); retentionDays != "" {
+		if days, err := strconv.Atoi(retentionDays); err == nil {
+			config.Trash.RetentionDays = days
+		}
+	}
+
 	// JWT
 	if secretKey := getEnv("JWT_SECRET_KEY", ""); secretKey != "" {
 		config.JWT.SecretKey = secretKey
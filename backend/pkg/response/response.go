@@ -16,11 +16,12 @@ type Response struct {
 
 // ErrorResponse représente une réponse d'erreur
 type ErrorResponse struct {
-	Success   bool      `json:"success" example:"false"`
-	Message   string    `json:"message" example:"Une erreur est survenue"`
-	Error     string    `json:"error,omitempty" example:"Détails de l'erreur"`
-	Code      string    `json:"code,omitempty" example:"USER_NOT_FOUND"`
-	Timestamp time.Time `json:"timestamp" example:"2023-01-01T12:00:00Z"`
+	Success   bool        `json:"success" example:"false"`
+	Message   string      `json:"message" example:"Une erreur est survenue"`
+	Error     string      `json:"error,omitempty" example:"Détails de l'erreur"`
+	Code      string      `json:"code,omitempty" example:"USER_NOT_FOUND"`
+	Data      interface{} `json:"data,omitempty"`
+	Timestamp time.Time   `json:"timestamp" example:"2023-01-01T12:00:00Z"`
 }
 
 // ValidationError représente une erreur de validation
@@ -89,6 +90,26 @@ func ErrorWithCode(w http.ResponseWriter, statusCode int, message string, code s
 	json.NewEncoder(w).Encode(response)
 }
 
+// ErrorWithData envoie une réponse d'erreur avec un code et des données permettant au client de réagir
+func ErrorWithData(w http.ResponseWriter, statusCode int, message string, code string, err error, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := ErrorResponse{
+		Success:   false,
+		Message:   message,
+		Code:      code,
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+
+	if err != nil {
+		response.Error = err.Error()
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
 // ValidationError envoie une réponse d'erreur de validation
 func ValidationErrors(w http.ResponseWriter, message string, errors []ValidationError) {
 	w.Header().Set("Content-Type", "application/json")